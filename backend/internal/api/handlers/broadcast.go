@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strconv"
+
+	"yardpass/internal/domain"
+	"yardpass/internal/errors"
+	"yardpass/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BroadcastHandler struct {
+	broadcastService *service.BroadcastService
+}
+
+func NewBroadcastHandler(broadcastService *service.BroadcastService) *BroadcastHandler {
+	return &BroadcastHandler{
+		broadcastService: broadcastService,
+	}
+}
+
+type CreateBroadcastRequest struct {
+	BuildingID   *int64  `json:"building_id,omitempty"`
+	Text         string  `json:"text" binding:"required"`
+	Floors       []int   `json:"floors,omitempty"`
+	ApartmentIDs []int64 `json:"apartment_ids,omitempty"`
+}
+
+// ownBuilding returns the building an admin is limited to, or nil for a
+// superuser. ok is false when the response has already been written.
+func ownBuilding(c *gin.Context) (buildingID *int64, ok bool) {
+	role, _ := c.Get("role")
+	if role == "superuser" {
+		return nil, true
+	}
+
+	if value, exists := c.Get("building_id"); exists {
+		if id, isInt := value.(int64); isInt {
+			return &id, true
+		}
+	}
+
+	errors.Forbidden(c, "NO_BUILDING", "User is not assigned to a building")
+	return nil, false
+}
+
+func (h *BroadcastHandler) Create(c *gin.Context) {
+	var req CreateBroadcastRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.BadRequest(c, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	own, ok := ownBuilding(c)
+	if !ok {
+		return
+	}
+
+	buildingID := req.BuildingID
+	if own != nil {
+		if buildingID != nil && *buildingID != *own {
+			errors.Forbidden(c, "FORBIDDEN_BUILDING", "Cannot send broadcasts to another building")
+			return
+		}
+		buildingID = own
+	}
+	if buildingID == nil {
+		errors.BadRequest(c, "MISSING_BUILDING_ID", "building_id is required")
+		return
+	}
+
+	createReq := domain.CreateBroadcastRequest{
+		BuildingID:   *buildingID,
+		Text:         req.Text,
+		Floors:       req.Floors,
+		ApartmentIDs: req.ApartmentIDs,
+	}
+	if userID, exists := c.Get("user_id"); exists {
+		if id, ok := userID.(int64); ok {
+			createReq.CreatedBy = &id
+		}
+	}
+
+	broadcast, err := h.broadcastService.CreateBroadcast(c.Request.Context(), createReq)
+	if err != nil {
+		if stderrors.Is(err, service.ErrNoBroadcastRecipients) {
+			errors.BadRequest(c, "NO_RECIPIENTS", err.Error())
+			return
+		}
+		errors.BadRequest(c, "CREATE_FAILED", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, broadcast)
+}
+
+func (h *BroadcastHandler) List(c *gin.Context) {
+	own, ok := ownBuilding(c)
+	if !ok {
+		return
+	}
+
+	filters := domain.BroadcastFilters{BuildingID: own}
+	if own == nil {
+		if buildingIDStr := c.Query("building_id"); buildingIDStr != "" {
+			if id, err := strconv.ParseInt(buildingIDStr, 10, 64); err == nil {
+				filters.BuildingID = &id
+			}
+		}
+	}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil {
+			filters.Limit = limit
+		}
+	}
+
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if offset, err := strconv.Atoi(offsetStr); err == nil {
+			filters.Offset = offset
+		}
+	}
+
+	broadcasts, err := h.broadcastService.ListBroadcasts(c.Request.Context(), filters)
+	if err != nil {
+		errors.InternalServerError(c, "FETCH_FAILED", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"broadcasts": broadcasts,
+		"count":      len(broadcasts),
+	})
+}
+
+func (h *BroadcastHandler) GetByID(c *gin.Context) {
+	broadcast, ok := h.getBroadcast(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, broadcast)
+}
+
+func (h *BroadcastHandler) ListRecipients(c *gin.Context) {
+	broadcast, ok := h.getBroadcast(c)
+	if !ok {
+		return
+	}
+
+	var filters domain.RecipientFilters
+
+	if status := c.Query("status"); status != "" {
+		filters.Status = &status
+	}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil {
+			filters.Limit = limit
+		}
+	}
+
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if offset, err := strconv.Atoi(offsetStr); err == nil {
+			filters.Offset = offset
+		}
+	}
+
+	recipients, err := h.broadcastService.ListRecipients(c.Request.Context(), broadcast.ID, filters)
+	if err != nil {
+		errors.InternalServerError(c, "FETCH_FAILED", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"recipients": recipients,
+		"count":      len(recipients),
+	})
+}
+
+// getBroadcast loads the broadcast of the :id parameter. Broadcasts of other
+// buildings are reported as not found to admins.
+func (h *BroadcastHandler) getBroadcast(c *gin.Context) (*domain.Broadcast, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		errors.BadRequest(c, "INVALID_ID", "Invalid broadcast ID format")
+		return nil, false
+	}
+
+	own, ok := ownBuilding(c)
+	if !ok {
+		return nil, false
+	}
+
+	broadcast, err := h.broadcastService.GetBroadcast(c.Request.Context(), id)
+	if err != nil {
+		if stderrors.Is(err, service.ErrBroadcastNotFound) {
+			errors.NotFound(c, "BROADCAST_NOT_FOUND", "Broadcast not found")
+			return nil, false
+		}
+		errors.InternalServerError(c, "FETCH_FAILED", err.Error())
+		return nil, false
+	}
+
+	if own != nil && broadcast.BuildingID != *own {
+		errors.NotFound(c, "BROADCAST_NOT_FOUND", "Broadcast not found")
+		return nil, false
+	}
+
+	return broadcast, true
+}
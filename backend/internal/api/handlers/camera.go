@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"yardpass/internal/domain"
+	"yardpass/internal/errors"
+	"yardpass/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CameraHandler struct {
+	cameraService *service.CameraService
+}
+
+func NewCameraHandler(cameraService *service.CameraService) *CameraHandler {
+	return &CameraHandler{
+		cameraService: cameraService,
+	}
+}
+
+type CreateCameraRequest struct {
+	// BuildingID is required for superusers; admins add cameras to their
+	// own building.
+	BuildingID    *int64   `json:"building_id,omitempty"`
+	GateID        string   `json:"gate_id" binding:"required"`
+	Name          string   `json:"name" binding:"required"`
+	MinConfidence *float64 `json:"min_confidence,omitempty"`
+}
+
+// Create registers a gate camera. The API key is in the response only.
+func (h *CameraHandler) Create(c *gin.Context) {
+	var req CreateCameraRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.BadRequest(c, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	own, ok := ownBuilding(c)
+	if !ok {
+		return
+	}
+
+	buildingID := req.BuildingID
+	if own != nil {
+		if buildingID != nil && *buildingID != *own {
+			errors.Forbidden(c, "FORBIDDEN_BUILDING", "Cannot add cameras to another building")
+			return
+		}
+		buildingID = own
+	}
+	if buildingID == nil {
+		errors.BadRequest(c, "MISSING_BUILDING_ID", "building_id is required")
+		return
+	}
+
+	createReq := domain.CreateGateCameraRequest{
+		BuildingID:    *buildingID,
+		GateID:        req.GateID,
+		Name:          req.Name,
+		MinConfidence: req.MinConfidence,
+	}
+	if userID, exists := c.Get("user_id"); exists {
+		if id, ok := userID.(int64); ok {
+			createReq.CreatedBy = &id
+		}
+	}
+
+	camera, apiKey, err := h.cameraService.AddCamera(c.Request.Context(), createReq)
+	if err != nil {
+		switch {
+		case stderrors.Is(err, service.ErrCameraExists):
+			errors.BadRequest(c, "CAMERA_EXISTS", err.Error())
+		case stderrors.Is(err, service.ErrCameraNameRequired),
+			stderrors.Is(err, service.ErrGateIDRequired),
+			stderrors.Is(err, service.ErrInvalidConfidence):
+			errors.BadRequest(c, "INVALID_REQUEST", err.Error())
+		default:
+			errors.InternalServerError(c, "CREATE_FAILED", err.Error())
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"camera":  camera,
+		"api_key": apiKey,
+	})
+}
+
+// List returns the cameras of the admin's building.
+func (h *CameraHandler) List(c *gin.Context) {
+	own, ok := ownBuilding(c)
+	if !ok {
+		return
+	}
+
+	buildingID := own
+	if own == nil {
+		if buildingIDStr := c.Query("building_id"); buildingIDStr != "" {
+			if id, err := strconv.ParseInt(buildingIDStr, 10, 64); err == nil {
+				buildingID = &id
+			}
+		}
+	}
+
+	cameras, err := h.cameraService.ListCameras(c.Request.Context(), buildingID)
+	if err != nil {
+		errors.InternalServerError(c, "FETCH_FAILED", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"cameras": cameras,
+		"count":   len(cameras),
+	})
+}
+
+func (h *CameraHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		errors.BadRequest(c, "INVALID_ID", "Invalid camera ID format")
+		return
+	}
+
+	own, ok := ownBuilding(c)
+	if !ok {
+		return
+	}
+
+	if err := h.cameraService.RemoveCamera(c.Request.Context(), id, own); err != nil {
+		if stderrors.Is(err, service.ErrCameraNotFound) {
+			errors.NotFound(c, "CAMERA_NOT_FOUND", err.Error())
+			return
+		}
+		errors.InternalServerError(c, "DELETE_FAILED", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Camera removed successfully",
+	})
+}
+
+type RecognitionEventRequest struct {
+	Plate      string    `json:"plate" binding:"required"`
+	Confidence *float64  `json:"confidence" binding:"required"`
+	CameraID   *int64    `json:"camera_id,omitempty"`
+	GateID     string    `json:"gate_id,omitempty"`
+	Timestamp  time.Time `json:"timestamp" binding:"required"`
+}
+
+// Ingest decides on a plate read by the authenticated gate camera. Any
+// answer but "open" must keep the barrier closed.
+func (h *CameraHandler) Ingest(c *gin.Context) {
+	started := time.Now()
+
+	var req RecognitionEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.BadRequest(c, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	camera, ok := c.MustGet("camera").(*domain.GateCamera)
+	if !ok {
+		errors.Unauthorized(c, "INVALID_CAMERA_KEY", "Invalid camera API key")
+		return
+	}
+
+	decision, err := h.cameraService.Decide(c.Request.Context(), camera, domain.PlateRecognition{
+		CarPlate:     req.Plate,
+		Confidence:   *req.Confidence,
+		CameraID:     req.CameraID,
+		GateID:       req.GateID,
+		RecognizedAt: req.Timestamp,
+	})
+	if err != nil {
+		switch {
+		case stderrors.Is(err, service.ErrCameraMismatch):
+			errors.Forbidden(c, "CAMERA_MISMATCH", err.Error())
+		case stderrors.Is(err, service.ErrInvalidConfidence),
+			stderrors.Is(err, service.ErrRecognitionTimestamp):
+			errors.BadRequest(c, "INVALID_REQUEST", err.Error())
+		default:
+			errors.InternalServerError(c, "DECISION_FAILED", err.Error())
+		}
+		return
+	}
+
+	response := gin.H{
+		"decision":   "deny",
+		"car_plate":  decision.CarPlate,
+		"latency_ms": time.Since(started).Milliseconds(),
+	}
+	if decision.Open {
+		response["decision"] = "open"
+	}
+	if decision.Reason != "" {
+		response["reason"] = decision.Reason
+	}
+	if decision.Apartment != "" {
+		response["apartment"] = decision.Apartment
+	}
+	if decision.Watchlist != nil {
+		response["alert"] = decision.Watchlist
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strconv"
+
+	"yardpass/internal/errors"
+	"yardpass/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EntryRequestHandler struct {
+	entryRequestService *service.EntryRequestService
+}
+
+func NewEntryRequestHandler(entryRequestService *service.EntryRequestService) *EntryRequestHandler {
+	return &EntryRequestHandler{
+		entryRequestService: entryRequestService,
+	}
+}
+
+type CreateEntryRequestRequest struct {
+	BuildingID *int64 `json:"building_id,omitempty"`
+	Apartment  string `json:"apartment" binding:"required"`
+	CarPlate   string `json:"car_plate" binding:"required"`
+}
+
+// Create asks the residents of an apartment to let a visitor in. The guard
+// polls GetByID for their answer.
+func (h *EntryRequestHandler) Create(c *gin.Context) {
+	var req CreateEntryRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.BadRequest(c, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	own, ok := ownBuilding(c)
+	if !ok {
+		return
+	}
+
+	buildingID := req.BuildingID
+	if own != nil {
+		if buildingID != nil && *buildingID != *own {
+			errors.Forbidden(c, "FORBIDDEN_BUILDING", "Cannot call residents of another building")
+			return
+		}
+		buildingID = own
+	}
+	if buildingID == nil {
+		errors.BadRequest(c, "MISSING_BUILDING_ID", "building_id is required")
+		return
+	}
+
+	var guardUserID int64
+	if userID, exists := c.Get("user_id"); exists {
+		guardUserID, _ = userID.(int64)
+	}
+
+	request, err := h.entryRequestService.CreateRequest(c.Request.Context(), guardUserID, *buildingID, req.Apartment, req.CarPlate)
+	if err != nil {
+		switch {
+		case stderrors.Is(err, service.ErrApartmentNotFound):
+			errors.NotFound(c, "APARTMENT_NOT_FOUND", err.Error())
+		case stderrors.Is(err, service.ErrNoEntryApprovers):
+			errors.BadRequest(c, "NO_RESIDENTS", err.Error())
+		case stderrors.Is(err, service.ErrInvalidCarPlate):
+			errors.BadRequest(c, "INVALID_CAR_PLATE", err.Error())
+		default:
+			errors.BadRequest(c, "CREATE_FAILED", err.Error())
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, request)
+}
+
+func (h *EntryRequestHandler) GetByID(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		errors.BadRequest(c, "INVALID_ID", "Invalid entry request ID format")
+		return
+	}
+
+	own, ok := ownBuilding(c)
+	if !ok {
+		return
+	}
+
+	request, err := h.entryRequestService.GetRequest(c.Request.Context(), id)
+	if err != nil {
+		if stderrors.Is(err, service.ErrEntryRequestNotFound) {
+			errors.NotFound(c, "ENTRY_REQUEST_NOT_FOUND", "Entry request not found")
+			return
+		}
+		errors.InternalServerError(c, "FETCH_FAILED", err.Error())
+		return
+	}
+
+	if own != nil && request.BuildingID != *own {
+		errors.NotFound(c, "ENTRY_REQUEST_NOT_FOUND", "Entry request not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, request)
+}
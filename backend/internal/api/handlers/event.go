@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"yardpass/internal/domain"
+	"yardpass/internal/errors"
+	"yardpass/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EventHandler struct {
+	eventService *service.EventService
+}
+
+func NewEventHandler(eventService *service.EventService) *EventHandler {
+	return &EventHandler{
+		eventService: eventService,
+	}
+}
+
+// Create issues an event from a multipart form with the event fields and
+// the guest list in the file field. Rows with problems reject the whole
+// list and are returned in errors.
+func (h *EventHandler) Create(c *gin.Context) {
+	own, ok := ownBuilding(c)
+	if !ok {
+		return
+	}
+
+	buildingID := own
+	if value := c.PostForm("building_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			errors.BadRequest(c, "INVALID_BUILDING_ID", "Invalid building ID format")
+			return
+		}
+		if own != nil && id != *own {
+			errors.Forbidden(c, "FORBIDDEN_BUILDING", "Cannot create events in another building")
+			return
+		}
+		buildingID = &id
+	}
+	if buildingID == nil {
+		errors.BadRequest(c, "MISSING_BUILDING_ID", "building_id is required")
+		return
+	}
+
+	apartmentID, err := strconv.ParseInt(c.PostForm("apartment_id"), 10, 64)
+	if err != nil {
+		errors.BadRequest(c, "INVALID_APARTMENT_ID", "apartment_id is required")
+		return
+	}
+
+	validFrom, err := time.Parse(time.RFC3339, c.PostForm("valid_from"))
+	if err != nil {
+		errors.BadRequest(c, "INVALID_VALID_FROM", "valid_from must be an RFC 3339 time")
+		return
+	}
+	validTo, err := time.Parse(time.RFC3339, c.PostForm("valid_to"))
+	if err != nil {
+		errors.BadRequest(c, "INVALID_VALID_TO", "valid_to must be an RFC 3339 time")
+		return
+	}
+
+	capacity, err := strconv.Atoi(c.PostForm("capacity"))
+	if err != nil {
+		errors.BadRequest(c, "INVALID_CAPACITY", "capacity is required")
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		errors.BadRequest(c, "MISSING_FILE", "file form field is required")
+		return
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		errors.BadRequest(c, "FILE_OPEN_ERROR", err.Error())
+		return
+	}
+	defer src.Close()
+
+	guests, rowErrors, err := h.eventService.ParseGuestList(file.Filename, src)
+	if err != nil {
+		errors.BadRequest(c, "INVALID_GUEST_LIST", err.Error())
+		return
+	}
+	if len(rowErrors) > 0 {
+		guestListErrors(c, rowErrors)
+		return
+	}
+
+	req := domain.CreateEventRequest{
+		BuildingID:  *buildingID,
+		ApartmentID: apartmentID,
+		Name:        c.PostForm("name"),
+		ValidFrom:   validFrom,
+		ValidTo:     validTo,
+		Capacity:    capacity,
+		Guests:      guests,
+	}
+	if userID, exists := c.Get("user_id"); exists {
+		if id, ok := userID.(int64); ok {
+			req.CreatedBy = &id
+		}
+	}
+
+	event, err := h.eventService.CreateEvent(c.Request.Context(), req)
+	if err != nil {
+		var listErr *service.GuestListError
+		if stderrors.As(err, &listErr) {
+			guestListErrors(c, listErr.Rows)
+			return
+		}
+		if stderrors.Is(err, service.ErrApartmentNotFound) {
+			errors.NotFound(c, "APARTMENT_NOT_FOUND", err.Error())
+			return
+		}
+		errors.BadRequest(c, "CREATE_FAILED", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, event)
+}
+
+// guestListErrors rejects a guest list with the errors of its rows.
+func guestListErrors(c *gin.Context, rowErrors []domain.BulkCreateError) {
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error": errors.ErrorDetail{
+			Code:    "INVALID_GUEST_LIST",
+			Message: fmt.Sprintf("guest list has %d invalid rows", len(rowErrors)),
+		},
+		"errors": rowErrors,
+	})
+}
+
+func (h *EventHandler) List(c *gin.Context) {
+	own, ok := ownBuilding(c)
+	if !ok {
+		return
+	}
+
+	buildingID := own
+	if own == nil {
+		if buildingIDStr := c.Query("building_id"); buildingIDStr != "" {
+			if id, err := strconv.ParseInt(buildingIDStr, 10, 64); err == nil {
+				buildingID = &id
+			}
+		}
+	}
+
+	events, err := h.eventService.ListEvents(c.Request.Context(), buildingID)
+	if err != nil {
+		errors.InternalServerError(c, "FETCH_FAILED", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events": events,
+		"count":  len(events),
+	})
+}
+
+func (h *EventHandler) GetByID(c *gin.Context) {
+	id, own, ok := eventParams(c)
+	if !ok {
+		return
+	}
+
+	event, err := h.eventService.GetEvent(c.Request.Context(), id, own)
+	if err != nil {
+		eventError(c, err, "FETCH_FAILED")
+		return
+	}
+
+	c.JSON(http.StatusOK, event)
+}
+
+// GetPDF returns the printable passes of every guest of the event.
+func (h *EventHandler) GetPDF(c *gin.Context) {
+	id, own, ok := eventParams(c)
+	if !ok {
+		return
+	}
+
+	document, err := h.eventService.EventPDF(c.Request.Context(), id, own)
+	if err != nil {
+		eventError(c, err, "PRINT_FAILED")
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=event_%d.pdf", id))
+	c.Data(http.StatusOK, "application/pdf", document)
+}
+
+// GetQRArchive returns a ZIP archive with the QR code of every guest.
+func (h *EventHandler) GetQRArchive(c *gin.Context) {
+	id, own, ok := eventParams(c)
+	if !ok {
+		return
+	}
+
+	archive, err := h.eventService.EventQRArchive(c.Request.Context(), id, own)
+	if err != nil {
+		eventError(c, err, "QR_GENERATION_FAILED")
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=event_%d_qr.zip", id))
+	c.Data(http.StatusOK, "application/zip", archive)
+}
+
+func (h *EventHandler) Revoke(c *gin.Context) {
+	id, own, ok := eventParams(c)
+	if !ok {
+		return
+	}
+
+	revoked, err := h.eventService.RevokeEvent(c.Request.Context(), id, own)
+	if err != nil {
+		eventError(c, err, "REVOKE_FAILED")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Event revoked successfully",
+		"revoked_passes": revoked,
+	})
+}
+
+func (h *EventHandler) GetAttendance(c *gin.Context) {
+	id, own, ok := eventParams(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.eventService.Attendance(c.Request.Context(), id, own)
+	if err != nil {
+		eventError(c, err, "FETCH_FAILED")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func eventParams(c *gin.Context) (int64, *int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		errors.BadRequest(c, "INVALID_ID", "Invalid event ID format")
+		return 0, nil, false
+	}
+
+	own, ok := ownBuilding(c)
+	if !ok {
+		return 0, nil, false
+	}
+
+	return id, own, true
+}
+
+func eventError(c *gin.Context, err error, code string) {
+	switch {
+	case stderrors.Is(err, service.ErrEventNotFound):
+		errors.NotFound(c, "EVENT_NOT_FOUND", err.Error())
+	case stderrors.Is(err, service.ErrEventRevoked):
+		errors.BadRequest(c, "EVENT_REVOKED", err.Error())
+	case stderrors.Is(err, service.ErrNoPassesToPrint):
+		errors.BadRequest(c, "NO_PASSES", err.Error())
+	default:
+		errors.InternalServerError(c, code, err.Error())
+	}
+}
@@ -11,12 +11,14 @@ import (
 )
 
 type ParkingHandler struct {
-	passService *service.PassService
+	passService    *service.PassService
+	vehicleService *service.ResidentVehicleService
 }
 
-func NewParkingHandler(passService *service.PassService) *ParkingHandler {
+func NewParkingHandler(passService *service.PassService, vehicleService *service.ResidentVehicleService) *ParkingHandler {
 	return &ParkingHandler{
-		passService: passService,
+		passService:    passService,
+		vehicleService: vehicleService,
 	}
 }
 
@@ -50,14 +52,23 @@ func (h *ParkingHandler) GetOccupancy(c *gin.Context) {
 		return
 	}
 
-	occupied := len(activePasses)
+	// Residents' own cars hold their places too.
+	residentVehicles, err := h.vehicleService.CountVehicles(c.Request.Context(), *bID)
+	if err != nil {
+		errors.InternalServerError(c, "FETCH_FAILED", err.Error())
+		return
+	}
+
+	occupied := len(activePasses) + residentVehicles
 	total := 100
 
 	c.JSON(http.StatusOK, gin.H{
-		"occupied": occupied,
-		"total":    total,
-		"free":     total - occupied,
-		"percent":  float64(occupied) / float64(total) * 100,
+		"occupied":          occupied,
+		"guest_passes":      len(activePasses),
+		"resident_vehicles": residentVehicles,
+		"total":             total,
+		"free":              total - occupied,
+		"percent":           float64(occupied) / float64(total) * 100,
 	})
 }
 
@@ -1,12 +1,14 @@
 package handlers
 
 import (
+	stderrors "errors"
 	"net/http"
 	"strconv"
 	"time"
 
 	"yardpass/internal/domain"
 	"yardpass/internal/errors"
+	"yardpass/internal/qr"
 	"yardpass/internal/service"
 
 	"github.com/gin-gonic/gin"
@@ -15,11 +17,13 @@ import (
 
 type PassHandler struct {
 	passService *service.PassService
+	qrGen       *qr.Generator
 }
 
-func NewPassHandler(passService *service.PassService) *PassHandler {
+func NewPassHandler(passService *service.PassService, qrGen *qr.Generator) *PassHandler {
 	return &PassHandler{
 		passService: passService,
+		qrGen:       qrGen,
 	}
 }
 
@@ -29,10 +33,26 @@ type CreatePassRequest struct {
 	GuestName   *string   `json:"guest_name,omitempty"`
 	ValidFrom   time.Time `json:"valid_from"`
 	ValidTo     time.Time `json:"valid_to" binding:"required"`
+	Dynamic     bool      `json:"dynamic,omitempty"`
+	Category    string    `json:"category,omitempty"`
+}
+
+type CreateContractorPassRequest struct {
+	ApartmentID int64     `json:"apartment_id" binding:"required"`
+	Category    string    `json:"category,omitempty"`
+	CompanyName string    `json:"company_name" binding:"required"`
+	CarPlate    *string   `json:"car_plate,omitempty"`
+	GuestName   *string   `json:"guest_name,omitempty"`
+	ValidFrom   time.Time `json:"valid_from"`
+	ValidTo     time.Time `json:"valid_to" binding:"required"`
+	Weekdays    []int     `json:"weekdays,omitempty"`
+	DailyFrom   string    `json:"daily_from,omitempty"`
+	DailyTo     string    `json:"daily_to,omitempty"`
 }
 
 type ValidatePassRequest struct {
 	QRUUID   string `json:"qr_uuid,omitempty"`
+	QRData   string `json:"qr_data,omitempty"`
 	CarPlate string `json:"car_plate,omitempty"`
 }
 
@@ -57,10 +77,77 @@ func (h *PassHandler) Create(c *gin.Context) {
 		GuestName:   req.GuestName,
 		ValidFrom:   req.ValidFrom,
 		ValidTo:     req.ValidTo,
+		Dynamic:     req.Dynamic,
+		Category:    req.Category,
 	}
 
 	pass, err := h.passService.CreatePass(c.Request.Context(), createReq)
 	if err != nil {
+		if stderrors.Is(err, service.ErrPlateBanned) {
+			errors.Forbidden(c, "CAR_PLATE_BANNED", err.Error())
+			return
+		}
+		if stderrors.Is(err, service.ErrInvalidCarPlate) {
+			errors.BadRequest(c, "INVALID_CAR_PLATE", err.Error())
+			return
+		}
+		errors.BadRequest(c, "CREATE_PASS_FAILED", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, pass)
+}
+
+// CreateContractor issues a pass for a crew or a service company to an
+// apartment of the admin's building.
+func (h *PassHandler) CreateContractor(c *gin.Context) {
+	var req CreateContractorPassRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.BadRequest(c, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	own, ok := ownBuilding(c)
+	if !ok {
+		return
+	}
+
+	if req.ValidFrom.IsZero() {
+		req.ValidFrom = time.Now().UTC()
+	}
+
+	createReq := domain.CreateContractorPassRequest{
+		ApartmentID: req.ApartmentID,
+		Category:    req.Category,
+		CompanyName: req.CompanyName,
+		CarPlate:    req.CarPlate,
+		GuestName:   req.GuestName,
+		ValidFrom:   req.ValidFrom.UTC(),
+		ValidTo:     req.ValidTo.UTC(),
+		Weekdays:    req.Weekdays,
+		DailyFrom:   req.DailyFrom,
+		DailyTo:     req.DailyTo,
+	}
+	if userID, exists := c.Get("user_id"); exists {
+		if id, ok := userID.(int64); ok {
+			createReq.IssuedBy = &id
+		}
+	}
+
+	pass, err := h.passService.CreateContractorPass(c.Request.Context(), createReq, own)
+	if err != nil {
+		if stderrors.Is(err, service.ErrApartmentNotFound) {
+			errors.NotFound(c, "APARTMENT_NOT_FOUND", err.Error())
+			return
+		}
+		if stderrors.Is(err, service.ErrPlateBanned) {
+			errors.Forbidden(c, "CAR_PLATE_BANNED", err.Error())
+			return
+		}
+		if stderrors.Is(err, service.ErrInvalidCarPlate) {
+			errors.BadRequest(c, "INVALID_CAR_PLATE", err.Error())
+			return
+		}
 		errors.BadRequest(c, "CREATE_PASS_FAILED", err.Error())
 		return
 	}
@@ -68,6 +155,34 @@ func (h *PassHandler) Create(c *gin.Context) {
 	c.JSON(http.StatusCreated, pass)
 }
 
+// ListContractors returns the contractor passes that have not ended yet.
+func (h *PassHandler) ListContractors(c *gin.Context) {
+	own, ok := ownBuilding(c)
+	if !ok {
+		return
+	}
+
+	buildingID := own
+	if own == nil {
+		if buildingIDStr := c.Query("building_id"); buildingIDStr != "" {
+			if id, err := strconv.ParseInt(buildingIDStr, 10, 64); err == nil {
+				buildingID = &id
+			}
+		}
+	}
+
+	passes, err := h.passService.ListContractorPasses(c.Request.Context(), buildingID)
+	if err != nil {
+		errors.InternalServerError(c, "FETCH_FAILED", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"passes": passes,
+		"count":  len(passes),
+	})
+}
+
 func (h *PassHandler) GetByID(c *gin.Context) {
 	_ = c.Param("id")
 	errors.ErrorResponseJSON(c, http.StatusNotImplemented, "NOT_IMPLEMENTED", "Get pass by ID not yet implemented")
@@ -124,15 +239,21 @@ func (h *PassHandler) Validate(c *gin.Context) {
 
 	if req.CarPlate != "" {
 		result, err = h.passService.ValidatePassByCarPlate(c.Request.Context(), req.CarPlate, guardUserID, bID)
-	} else if req.QRUUID != "" {
-		passID, parseErr := uuid.Parse(req.QRUUID)
+	} else if req.QRData != "" || req.QRUUID != "" {
+		// qr_data is the scanned text as is; qr_uuid is kept for clients that
+		// send the pass ID only.
+		raw := req.QRData
+		if raw == "" {
+			raw = req.QRUUID
+		}
+		code, parseErr := h.qrGen.Parse(c.Request.Context(), raw)
 		if parseErr != nil {
 			errors.BadRequest(c, "INVALID_QR_UUID", "Invalid QR code format")
 			return
 		}
-		result, err = h.passService.ValidatePass(c.Request.Context(), passID, guardUserID)
+		result, err = h.passService.ValidateQR(c.Request.Context(), code, guardUserID)
 	} else {
-		errors.BadRequest(c, "MISSING_PARAMETER", "Either qr_uuid or car_plate must be provided")
+		errors.BadRequest(c, "MISSING_PARAMETER", "Either qr_data, qr_uuid or car_plate must be provided")
 		return
 	}
 
@@ -142,18 +263,64 @@ func (h *PassHandler) Validate(c *gin.Context) {
 	}
 
 	if result.Valid {
-		c.JSON(http.StatusOK, gin.H{
+		response := gin.H{
 			"valid":     true,
 			"car_plate": result.CarPlate,
 			"apartment": result.Apartment,
 			"valid_to":  result.ValidTo,
-		})
+		}
+		// A resident's own car is admitted without a pass.
+		if result.Vehicle != nil {
+			response["reason"] = result.Reason
+			response["vehicle"] = result.Vehicle
+		}
+		if result.Watchlist != nil {
+			response["alert"] = result.Watchlist
+		}
+		c.JSON(http.StatusOK, response)
 	} else {
-		c.JSON(http.StatusOK, gin.H{
+		response := gin.H{
 			"valid":  false,
 			"reason": result.Reason,
-		})
+		}
+		if result.Watchlist != nil {
+			response["alert"] = result.Watchlist
+		}
+		if len(result.Suggestions) > 0 {
+			response["suggestions"] = result.Suggestions
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// EnableDynamic switches the pass to rotating QR codes.
+func (h *PassHandler) EnableDynamic(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.BadRequest(c, "INVALID_UUID", "Invalid pass ID format")
+		return
+	}
+
+	scope, ok := passScope(c)
+	if !ok {
+		return
 	}
+
+	pass, err := h.passService.EnableDynamicQR(c.Request.Context(), id, scope)
+	if err != nil {
+		if stderrors.Is(err, service.ErrPassNotFound) {
+			errors.NotFound(c, "PASS_NOT_FOUND", err.Error())
+			return
+		}
+		if stderrors.Is(err, service.ErrPassNotActive) {
+			errors.BadRequest(c, "PASS_NOT_ACTIVE", err.Error())
+			return
+		}
+		errors.BadRequest(c, "DYNAMIC_QR_FAILED", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, pass)
 }
 
 func (h *PassHandler) GetActive(c *gin.Context) {
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"yardpass/internal/domain"
+	"yardpass/internal/errors"
+	"yardpass/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PassExceptionHandler struct {
+	exceptionService *service.PassExceptionService
+}
+
+func NewPassExceptionHandler(exceptionService *service.PassExceptionService) *PassExceptionHandler {
+	return &PassExceptionHandler{
+		exceptionService: exceptionService,
+	}
+}
+
+type DecidePassExceptionRequest struct {
+	Comment *string `json:"comment,omitempty"`
+}
+
+// List returns the exception queue, by default only the pending requests;
+// status=all returns every request.
+func (h *PassExceptionHandler) List(c *gin.Context) {
+	own, ok := ownBuilding(c)
+	if !ok {
+		return
+	}
+
+	buildingID := own
+	if own == nil {
+		if buildingIDStr := c.Query("building_id"); buildingIDStr != "" {
+			if id, err := strconv.ParseInt(buildingIDStr, 10, 64); err == nil {
+				buildingID = &id
+			}
+		}
+	}
+
+	var status *string
+	switch value := c.DefaultQuery("status", domain.PassExceptionStatusPending); value {
+	case "all":
+	case domain.PassExceptionStatusPending, domain.PassExceptionStatusApproved, domain.PassExceptionStatusRejected:
+		status = &value
+	default:
+		errors.BadRequest(c, "INVALID_STATUS", "status must be pending, approved, rejected or all")
+		return
+	}
+
+	exceptions, err := h.exceptionService.List(c.Request.Context(), buildingID, status)
+	if err != nil {
+		errors.InternalServerError(c, "FETCH_FAILED", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"exceptions": exceptions,
+		"count":      len(exceptions),
+	})
+}
+
+func (h *PassExceptionHandler) GetByID(c *gin.Context) {
+	id, own, ok := passExceptionParams(c)
+	if !ok {
+		return
+	}
+
+	exception, err := h.exceptionService.Get(c.Request.Context(), id, own)
+	if err != nil {
+		passExceptionError(c, err, "FETCH_FAILED")
+		return
+	}
+
+	c.JSON(http.StatusOK, exception)
+}
+
+// Approve issues the requested pass without the rule it breaks.
+func (h *PassExceptionHandler) Approve(c *gin.Context) {
+	id, own, ok := passExceptionParams(c)
+	if !ok {
+		return
+	}
+
+	req, ok := decidePassExceptionRequest(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	adminID, _ := userID.(int64)
+
+	exception, pass, err := h.exceptionService.Approve(c.Request.Context(), id, adminID, own, req.Comment)
+	if err != nil {
+		passExceptionError(c, err, "APPROVE_FAILED")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"exception": exception,
+		"pass":      pass,
+	})
+}
+
+func (h *PassExceptionHandler) Reject(c *gin.Context) {
+	id, own, ok := passExceptionParams(c)
+	if !ok {
+		return
+	}
+
+	req, ok := decidePassExceptionRequest(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	adminID, _ := userID.(int64)
+
+	exception, err := h.exceptionService.Reject(c.Request.Context(), id, adminID, own, req.Comment)
+	if err != nil {
+		passExceptionError(c, err, "REJECT_FAILED")
+		return
+	}
+
+	c.JSON(http.StatusOK, exception)
+}
+
+// decidePassExceptionRequest binds the optional body of a decision.
+func decidePassExceptionRequest(c *gin.Context) (DecidePassExceptionRequest, bool) {
+	var req DecidePassExceptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !stderrors.Is(err, io.EOF) {
+		errors.BadRequest(c, "INVALID_REQUEST", err.Error())
+		return req, false
+	}
+	return req, true
+}
+
+func passExceptionParams(c *gin.Context) (int64, *int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		errors.BadRequest(c, "INVALID_ID", "Invalid exception ID format")
+		return 0, nil, false
+	}
+
+	own, ok := ownBuilding(c)
+	if !ok {
+		return 0, nil, false
+	}
+
+	return id, own, true
+}
+
+// passExceptionError maps service errors. A pass the remaining rules still
+// reject cannot be approved and is reported as a bad request.
+func passExceptionError(c *gin.Context, err error, code string) {
+	switch {
+	case stderrors.Is(err, service.ErrPassExceptionNotFound):
+		errors.NotFound(c, "PASS_EXCEPTION_NOT_FOUND", err.Error())
+	case stderrors.Is(err, service.ErrPassExceptionDecided):
+		errors.BadRequest(c, "PASS_EXCEPTION_DECIDED", err.Error())
+	case stderrors.Is(err, service.ErrPassExceptionExpired):
+		errors.BadRequest(c, "PASS_EXCEPTION_EXPIRED", err.Error())
+	case code == "APPROVE_FAILED":
+		errors.BadRequest(c, code, err.Error())
+	default:
+		errors.InternalServerError(c, code, err.Error())
+	}
+}
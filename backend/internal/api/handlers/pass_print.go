@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"yardpass/internal/errors"
+	"yardpass/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type PassPrintHandler struct {
+	printService *service.PassPrintService
+}
+
+func NewPassPrintHandler(printService *service.PassPrintService) *PassPrintHandler {
+	return &PassPrintHandler{
+		printService: printService,
+	}
+}
+
+type PrintPassesRequest struct {
+	PassIDs []string `json:"pass_ids" binding:"required"`
+}
+
+// GetPDF returns a single pass as a printable PDF.
+func (h *PassPrintHandler) GetPDF(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.BadRequest(c, "INVALID_UUID", "Invalid pass ID format")
+		return
+	}
+
+	h.print(c, []uuid.UUID{id}, "pass_"+id.String()+".pdf")
+}
+
+// PrintBatch returns a PDF with a page for every requested pass.
+func (h *PassPrintHandler) PrintBatch(c *gin.Context) {
+	var req PrintPassesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.BadRequest(c, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	ids := make([]uuid.UUID, 0, len(req.PassIDs))
+	for _, raw := range req.PassIDs {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			errors.BadRequest(c, "INVALID_UUID", "Invalid pass ID format: "+raw)
+			return
+		}
+		ids = append(ids, id)
+	}
+
+	h.print(c, ids, fmt.Sprintf("passes_%s.pdf", time.Now().Format("20060102_150405")))
+}
+
+func (h *PassPrintHandler) print(c *gin.Context, ids []uuid.UUID, fileName string) {
+	own, ok := ownBuilding(c)
+	if !ok {
+		return
+	}
+
+	document, err := h.printService.PrintPasses(c.Request.Context(), ids, own)
+	if err != nil {
+		switch {
+		case stderrors.Is(err, service.ErrPrintPassNotFound):
+			errors.NotFound(c, "PASS_NOT_FOUND", err.Error())
+		case stderrors.Is(err, service.ErrNoPassesToPrint):
+			errors.BadRequest(c, "NO_PASSES", err.Error())
+		case stderrors.Is(err, service.ErrTooManyPassesToPrint):
+			errors.BadRequest(c, "TOO_MANY_PASSES", err.Error())
+		default:
+			errors.InternalServerError(c, "PRINT_FAILED", err.Error())
+		}
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename="+fileName)
+	c.Data(http.StatusOK, "application/pdf", document)
+}
@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/base64"
+	stderrors "errors"
+	"html/template"
+	"net/http"
+	"strconv"
+	"time"
+
+	"yardpass/internal/domain"
+	"yardpass/internal/errors"
+	"yardpass/internal/qr"
+	"yardpass/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type PassShareHandler struct {
+	shareService *service.PassShareService
+	qrGen        *qr.Generator
+	location     *time.Location
+	logger       *zap.Logger
+}
+
+func NewPassShareHandler(shareService *service.PassShareService, qrGen *qr.Generator, location *time.Location, logger *zap.Logger) *PassShareHandler {
+	return &PassShareHandler{
+		shareService: shareService,
+		qrGen:        qrGen,
+		location:     location,
+		logger:       logger,
+	}
+}
+
+// passScope limits pass management to the caller. Staff manage the passes
+// of their building. Service callers act for the resident named in the
+// resident_id query parameter.
+func passScope(c *gin.Context) (service.PassScope, bool) {
+	if _, isUser := c.Get("role"); isUser {
+		own, ok := ownBuilding(c)
+		return service.PassScope{BuildingID: own}, ok
+	}
+
+	residentID, err := strconv.ParseInt(c.Query("resident_id"), 10, 64)
+	if err != nil {
+		errors.BadRequest(c, "INVALID_RESIDENT_ID", "resident_id is required")
+		return service.PassScope{}, false
+	}
+	return service.PassScope{ResidentID: &residentID}, true
+}
+
+// Share returns the public link of the pass, creating it on first use.
+func (h *PassShareHandler) Share(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.BadRequest(c, "INVALID_UUID", "Invalid pass ID format")
+		return
+	}
+
+	scope, ok := passScope(c)
+	if !ok {
+		return
+	}
+
+	url, err := h.shareService.Share(c.Request.Context(), id, scope)
+	if err != nil {
+		if stderrors.Is(err, service.ErrPassNotFound) {
+			errors.NotFound(c, "PASS_NOT_FOUND", err.Error())
+			return
+		}
+		if stderrors.Is(err, service.ErrPassSharingDisabled) {
+			errors.BadRequest(c, "SHARING_DISABLED", err.Error())
+			return
+		}
+		errors.BadRequest(c, "SHARE_FAILED", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"pass_id": id.String(),
+		"url":     url,
+	})
+}
+
+// Unshare revokes the public link of the pass.
+func (h *PassShareHandler) Unshare(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.BadRequest(c, "INVALID_UUID", "Invalid pass ID format")
+		return
+	}
+
+	scope, ok := passScope(c)
+	if !ok {
+		return
+	}
+
+	if err := h.shareService.Unshare(c.Request.Context(), id, scope); err != nil {
+		if stderrors.Is(err, service.ErrPassNotFound) {
+			errors.NotFound(c, "PASS_NOT_FOUND", err.Error())
+			return
+		}
+		errors.InternalServerError(c, "UNSHARE_FAILED", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Pass link revoked successfully",
+		"pass_id": id.String(),
+	})
+}
+
+type sharedPassPage struct {
+	Found     bool
+	State     string
+	Building  string
+	Address   string
+	Apartment string
+	CarPlate  string
+	GuestName string
+	ValidFrom string
+	ValidTo   string
+	QR        template.URL
+	// Refresh is the number of seconds until a rotating code changes, zero
+	// for static passes.
+	Refresh int
+}
+
+// Show renders the public page of a shared pass. The QR code is only shown
+// while the pass can still be used.
+func (h *PassShareHandler) Show(c *gin.Context) {
+	shared, err := h.shareService.GetSharedPass(c.Request.Context(), c.Param("token"))
+	if stderrors.Is(err, service.ErrSharedPassNotFound) {
+		h.render(c, http.StatusNotFound, sharedPassPage{})
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to get shared pass", zap.Error(err))
+		c.String(http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	page := sharedPassPage{
+		Found:     true,
+		State:     shared.State,
+		Building:  shared.Building.Name,
+		Address:   shared.Building.Address,
+		Apartment: shared.Apartment.Number,
+		ValidFrom: shared.Pass.ValidFrom.In(h.location).Format("15:04 02.01.2006"),
+		ValidTo:   shared.Pass.ValidTo.In(h.location).Format("15:04 02.01.2006"),
+	}
+	if shared.Pass.CarPlate != nil {
+		page.CarPlate = *shared.Pass.CarPlate
+	}
+	if shared.Pass.GuestName != nil {
+		page.GuestName = *shared.Pass.GuestName
+	}
+
+	if shared.State == domain.SharedPassStateActive || shared.State == domain.SharedPassStateNotYetValid {
+		var png []byte
+		if shared.Pass.Dynamic {
+			now := time.Now()
+			png, err = h.qrGen.GenerateDynamicQR(c.Request.Context(), shared.Pass.ID, shared.Pass.QRSecret, now)
+			page.Refresh = int(qr.StepEnd(qr.TimeStep(now)).Sub(now).Seconds()) + 1
+		} else {
+			png, err = h.qrGen.GenerateQR(c.Request.Context(), shared.Pass.ID)
+		}
+		if err != nil {
+			h.logger.Error("failed to generate QR", zap.Error(err), zap.String("pass_id", shared.Pass.ID.String()))
+			c.String(http.StatusInternalServerError, "Internal server error")
+			return
+		}
+		page.QR = template.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(png))
+	}
+
+	h.render(c, http.StatusOK, page)
+}
+
+func (h *PassShareHandler) render(c *gin.Context, status int, page sharedPassPage) {
+	var buf bytes.Buffer
+	if err := sharedPassTemplate.Execute(&buf, page); err != nil {
+		h.logger.Error("failed to render shared pass", zap.Error(err))
+		c.String(http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	// The page must not be kept by shared caches: the pass may be revoked.
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Robots-Tag", "noindex")
+	c.Data(status, "text/html; charset=utf-8", buf.Bytes())
+}
+
+var sharedPassTemplate = template.Must(template.New("pass").Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+{{if .Refresh}}<meta http-equiv="refresh" content="{{.Refresh}}">{{end}}
+<title>Пропуск{{if .Found}} — {{.Building}}{{end}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Roboto, sans-serif; margin: 0; padding: 24px; background: #f5f5f5; color: #222; }
+.card { max-width: 360px; margin: 0 auto; padding: 24px; background: #fff; border-radius: 12px; text-align: center; }
+.qr { width: 256px; height: 256px; }
+.plate { font-size: 28px; font-weight: bold; letter-spacing: 2px; }
+.state { padding: 12px; border-radius: 8px; font-weight: bold; }
+.state.bad { background: #fdecea; color: #b71c1c; }
+.state.wait { background: #fff8e1; color: #8d6e00; }
+.muted { color: #666; }
+</style>
+</head>
+<body>
+<div class="card">
+{{if not .Found}}
+<p class="state bad">Пропуск не найден или ссылка отозвана</p>
+{{else}}
+<h2>{{.Building}}</h2>
+{{if .Address}}<p class="muted">{{.Address}}</p>{{end}}
+{{if eq .State "revoked"}}<p class="state bad">Пропуск отозван</p>
+{{else if eq .State "expired"}}<p class="state bad">Срок действия пропуска истёк</p>
+{{else if eq .State "used"}}<p class="state bad">Пропуск уже использован</p>
+{{else if eq .State "not_yet_valid"}}<p class="state wait">Пропуск начнёт действовать в {{.ValidFrom}}</p>
+{{end}}
+{{if .QR}}<img class="qr" src="{{.QR}}" alt="QR код пропуска">{{end}}
+{{if .Refresh}}<p class="muted">Код меняется каждые 30 секунд, страница обновляется сама</p>{{end}}
+{{if .CarPlate}}<p class="plate">{{.CarPlate}}</p>{{else}}<p>Пеший гость</p>{{end}}
+{{if .GuestName}}<p>{{.GuestName}}</p>{{end}}
+<p>Квартира {{.Apartment}}</p>
+<p class="muted">Действует с {{.ValidFrom}} до {{.ValidTo}}</p>
+{{end}}
+</div>
+</body>
+</html>
+`))
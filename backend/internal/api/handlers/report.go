@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"yardpass/internal/domain"
@@ -16,12 +17,14 @@ import (
 type ReportHandler struct {
 	scanEventRepo domain.ScanEventRepository
 	passRepo      domain.PassRepository
+	apartmentRepo domain.ApartmentRepository
 }
 
-func NewReportHandler(scanEventRepo domain.ScanEventRepository, passRepo domain.PassRepository) *ReportHandler {
+func NewReportHandler(scanEventRepo domain.ScanEventRepository, passRepo domain.PassRepository, apartmentRepo domain.ApartmentRepository) *ReportHandler {
 	return &ReportHandler{
 		scanEventRepo: scanEventRepo,
 		passRepo:      passRepo,
+		apartmentRepo: apartmentRepo,
 	}
 }
 
@@ -77,14 +80,15 @@ func (h *ReportHandler) GetStatistics(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"total_scans":   stats.TotalScans,
-		"valid_scans":   stats.ValidScans,
-		"invalid_scans": stats.InvalidScans,
-		"unique_passes": stats.UniquePasses,
-		"unique_guards": stats.UniqueGuards,
-		"valid_percent": calculatePercent(stats.ValidScans, stats.TotalScans),
-		"period_from":   from,
-		"period_to":     to,
+		"total_scans":      stats.TotalScans,
+		"valid_scans":      stats.ValidScans,
+		"invalid_scans":    stats.InvalidScans,
+		"unique_passes":    stats.UniquePasses,
+		"unique_guards":    stats.UniqueGuards,
+		"contractor_scans": stats.ContractorScans,
+		"valid_percent":    calculatePercent(stats.ValidScans, stats.TotalScans),
+		"period_from":      from,
+		"period_to":        to,
 	})
 }
 
@@ -166,7 +170,7 @@ func (h *ReportHandler) ExportToExcel(c *gin.Context) {
 
 	file.SetActiveSheet(index)
 
-	headers := []string{"ID", "Дата/Время", "Результат", "Номер авто", "Квартира", "Охранник", "Причина"}
+	headers := []string{"ID", "Дата/Время", "Результат", "Номер авто", "Квартира", "Охранник", "Причина", "Категория"}
 	for i, header := range headers {
 		cell := fmt.Sprintf("%c1", 'A'+i)
 		file.SetCellValue(sheetName, cell, header)
@@ -183,6 +187,14 @@ func (h *ReportHandler) ExportToExcel(c *gin.Context) {
 		if event.Reason != nil {
 			file.SetCellValue(sheetName, fmt.Sprintf("G%d", row), *event.Reason)
 		}
+		if event.PassCategory != nil {
+			file.SetCellValue(sheetName, fmt.Sprintf("H%d", row), categoryNames[*event.PassCategory])
+		}
+	}
+
+	if err := h.writeContractorSheet(c, file, bID); err != nil {
+		errors.InternalServerError(c, "EXCEL_ERROR", err.Error())
+		return
 	}
 
 	file.DeleteSheet("Sheet1")
@@ -196,6 +208,83 @@ func (h *ReportHandler) ExportToExcel(c *gin.Context) {
 	}
 }
 
+// writeContractorSheet lists the contractor passes that have not ended on a
+// sheet of their own, since they are issued by admins rather than residents.
+func (h *ReportHandler) writeContractorSheet(c *gin.Context, file *excelize.File, buildingID *int64) error {
+	passes, err := h.passRepo.ListContractorPasses(c.Request.Context(), buildingID)
+	if err != nil {
+		return err
+	}
+
+	sheetName := "Подрядчики"
+	if _, err := file.NewSheet(sheetName); err != nil {
+		return err
+	}
+
+	headers := []string{"ID", "Категория", "Компания", "Номер авто", "Сотрудник", "Квартира", "Действует с", "Действует до", "Дни недели", "Часы", "Статус"}
+	for i, header := range headers {
+		cell := fmt.Sprintf("%c1", 'A'+i)
+		file.SetCellValue(sheetName, cell, header)
+	}
+
+	apartments := map[int64]string{}
+	for i, pass := range passes {
+		number, ok := apartments[pass.ApartmentID]
+		if !ok {
+			if apartment, err := h.apartmentRepo.GetByID(c.Request.Context(), pass.ApartmentID); err == nil && apartment != nil {
+				number = apartment.Number
+			}
+			apartments[pass.ApartmentID] = number
+		}
+
+		row := i + 2
+		file.SetCellValue(sheetName, fmt.Sprintf("A%d", row), pass.ID.String())
+		file.SetCellValue(sheetName, fmt.Sprintf("B%d", row), categoryNames[pass.Category])
+		if pass.CompanyName != nil {
+			file.SetCellValue(sheetName, fmt.Sprintf("C%d", row), *pass.CompanyName)
+		}
+		if pass.CarPlate != nil {
+			file.SetCellValue(sheetName, fmt.Sprintf("D%d", row), *pass.CarPlate)
+		}
+		if pass.GuestName != nil {
+			file.SetCellValue(sheetName, fmt.Sprintf("E%d", row), *pass.GuestName)
+		}
+		file.SetCellValue(sheetName, fmt.Sprintf("F%d", row), number)
+		file.SetCellValue(sheetName, fmt.Sprintf("G%d", row), pass.ValidFrom.Format("2006-01-02 15:04"))
+		file.SetCellValue(sheetName, fmt.Sprintf("H%d", row), pass.ValidTo.Format("2006-01-02 15:04"))
+		file.SetCellValue(sheetName, fmt.Sprintf("I%d", row), formatWeekdays(pass.Weekdays))
+		if pass.DailyFrom != nil && pass.DailyTo != nil {
+			file.SetCellValue(sheetName, fmt.Sprintf("J%d", row), *pass.DailyFrom+"-"+*pass.DailyTo)
+		}
+		file.SetCellValue(sheetName, fmt.Sprintf("K%d", row), pass.Status)
+	}
+
+	return nil
+}
+
+var categoryNames = map[string]string{
+	domain.PassCategoryGuest:      "Гость",
+	domain.PassCategoryTaxi:       "Такси",
+	domain.PassCategoryDelivery:   "Доставка",
+	domain.PassCategoryContractor: "Подрядчик",
+	domain.PassCategoryService:    "Сервис",
+}
+
+func formatWeekdays(weekdays []int) string {
+	if len(weekdays) == 0 {
+		return "Ежедневно"
+	}
+
+	names := []string{"", "Пн", "Вт", "Ср", "Чт", "Пт", "Сб", "Вс"}
+	days := make([]string, 0, len(weekdays))
+	for _, day := range weekdays {
+		if day >= 1 && day <= 7 {
+			days = append(days, names[day])
+		}
+	}
+	return strings.Join(days, ", ")
+}
+
 func calculatePercent(part, total int) float64 {
 	if total == 0 {
 		return 0
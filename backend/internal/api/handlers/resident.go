@@ -137,6 +137,10 @@ func (h *ResidentHandler) ListResidents(c *gin.Context) {
 		filters.Status = &status
 	}
 
+	if role := c.Query("role"); role != "" {
+		filters.Role = &role
+	}
+
 	filters.Limit = 100
 	if limitStr := c.Query("limit"); limitStr != "" {
 		if id, err := strconv.ParseInt(limitStr, 10, 64); err == nil {
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strconv"
+
+	"yardpass/internal/domain"
+	"yardpass/internal/errors"
+	"yardpass/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ResidentVehicleHandler struct {
+	vehicleService *service.ResidentVehicleService
+}
+
+func NewResidentVehicleHandler(vehicleService *service.ResidentVehicleService) *ResidentVehicleHandler {
+	return &ResidentVehicleHandler{
+		vehicleService: vehicleService,
+	}
+}
+
+type CreateResidentVehicleRequest struct {
+	ApartmentID int64   `json:"apartment_id" binding:"required"`
+	CarPlate    string  `json:"car_plate" binding:"required"`
+	Label       *string `json:"label,omitempty"`
+}
+
+// Create registers a resident's car. Admins are not held to the cap
+// residents have in the bot.
+func (h *ResidentVehicleHandler) Create(c *gin.Context) {
+	var req CreateResidentVehicleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.BadRequest(c, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	own, ok := ownBuilding(c)
+	if !ok {
+		return
+	}
+
+	createReq := domain.CreateResidentVehicleRequest{
+		ApartmentID: req.ApartmentID,
+		CarPlate:    req.CarPlate,
+		Label:       req.Label,
+	}
+	if userID, exists := c.Get("user_id"); exists {
+		if id, ok := userID.(int64); ok {
+			createReq.CreatedBy = &id
+		}
+	}
+
+	vehicle, err := h.vehicleService.AddVehicle(c.Request.Context(), createReq, own)
+	if err != nil {
+		switch {
+		case stderrors.Is(err, service.ErrApartmentNotFound):
+			errors.NotFound(c, "APARTMENT_NOT_FOUND", err.Error())
+		case stderrors.Is(err, service.ErrVehicleOtherBuilding):
+			errors.Forbidden(c, "FORBIDDEN_BUILDING", "Cannot register cars in another building")
+		case stderrors.Is(err, service.ErrVehicleExists):
+			errors.BadRequest(c, "VEHICLE_EXISTS", err.Error())
+		case stderrors.Is(err, service.ErrInvalidCarPlate):
+			errors.BadRequest(c, "INVALID_CAR_PLATE", err.Error())
+		case stderrors.Is(err, service.ErrVehicleLabelTooLong):
+			errors.BadRequest(c, "INVALID_REQUEST", err.Error())
+		default:
+			errors.InternalServerError(c, "CREATE_FAILED", err.Error())
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, vehicle)
+}
+
+// List returns the cars of the admin's building, optionally of one
+// apartment.
+func (h *ResidentVehicleHandler) List(c *gin.Context) {
+	own, ok := ownBuilding(c)
+	if !ok {
+		return
+	}
+
+	buildingID := own
+	if own == nil {
+		if buildingIDStr := c.Query("building_id"); buildingIDStr != "" {
+			if id, err := strconv.ParseInt(buildingIDStr, 10, 64); err == nil {
+				buildingID = &id
+			}
+		}
+	}
+
+	var apartmentID *int64
+	if apartmentIDStr := c.Query("apartment_id"); apartmentIDStr != "" {
+		id, err := strconv.ParseInt(apartmentIDStr, 10, 64)
+		if err != nil {
+			errors.BadRequest(c, "INVALID_APARTMENT_ID", "Invalid apartment ID format")
+			return
+		}
+		apartmentID = &id
+	}
+
+	vehicles, err := h.vehicleService.ListVehicles(c.Request.Context(), buildingID, apartmentID)
+	if err != nil {
+		errors.InternalServerError(c, "FETCH_FAILED", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"vehicles": vehicles,
+		"count":    len(vehicles),
+	})
+}
+
+func (h *ResidentVehicleHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		errors.BadRequest(c, "INVALID_ID", "Invalid vehicle ID format")
+		return
+	}
+
+	own, ok := ownBuilding(c)
+	if !ok {
+		return
+	}
+
+	if err := h.vehicleService.RemoveVehicle(c.Request.Context(), id, own); err != nil {
+		if stderrors.Is(err, service.ErrVehicleNotFound) {
+			errors.NotFound(c, "VEHICLE_NOT_FOUND", err.Error())
+			return
+		}
+		errors.InternalServerError(c, "DELETE_FAILED", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Vehicle removed successfully",
+	})
+}
@@ -3,6 +3,9 @@ package handlers
 import (
 	"fmt"
 	"net/http"
+	"slices"
+	"strings"
+	"time"
 
 	"yardpass/internal/domain"
 	"yardpass/internal/errors"
@@ -25,6 +28,9 @@ type UpdateRuleRequest struct {
 	QuietHoursEnd              *string `json:"quiet_hours_end,omitempty"`
 	DailyPassLimitPerApartment *int    `json:"daily_pass_limit_per_apartment,omitempty"`
 	MaxPassDurationHours       *int    `json:"max_pass_duration_hours,omitempty"`
+	MaxContractorPassDays      *int    `json:"max_contractor_pass_days,omitempty"`
+	// Categories replaces the category limits of the building when set.
+	Categories *[]domain.CategoryRule `json:"categories,omitempty"`
 }
 
 func (h *RuleHandler) Get(c *gin.Context) {
@@ -75,6 +81,13 @@ func (h *RuleHandler) Update(c *gin.Context) {
 		return
 	}
 
+	if req.Categories != nil {
+		if err := validateCategoryRules(*req.Categories); err != nil {
+			errors.BadRequest(c, "INVALID_CATEGORY_RULE", err.Error())
+			return
+		}
+	}
+
 	rule, err := h.ruleRepo.GetByBuildingID(c.Request.Context(), buildingID)
 	if err != nil {
 		errors.InternalServerError(c, "FETCH_FAILED", err.Error())
@@ -86,6 +99,7 @@ func (h *RuleHandler) Update(c *gin.Context) {
 			BuildingID:                 buildingID,
 			DailyPassLimitPerApartment: 5,
 			MaxPassDurationHours:       24,
+			MaxContractorPassDays:      30,
 		}
 	}
 
@@ -101,6 +115,12 @@ func (h *RuleHandler) Update(c *gin.Context) {
 	if req.MaxPassDurationHours != nil {
 		rule.MaxPassDurationHours = *req.MaxPassDurationHours
 	}
+	if req.MaxContractorPassDays != nil {
+		rule.MaxContractorPassDays = *req.MaxContractorPassDays
+	}
+	if req.Categories != nil {
+		rule.Categories = *req.Categories
+	}
 
 	if rule.ID == 0 {
 		err = h.ruleRepo.Create(c.Request.Context(), rule)
@@ -115,3 +135,34 @@ func (h *RuleHandler) Update(c *gin.Context) {
 
 	c.JSON(http.StatusOK, rule)
 }
+
+func validateCategoryRules(categories []domain.CategoryRule) error {
+	seen := map[string]bool{}
+	for _, cr := range categories {
+		if !slices.Contains(domain.PassCategories, cr.Category) {
+			return fmt.Errorf("unknown category %q, expected one of %s", cr.Category, strings.Join(domain.PassCategories, ", "))
+		}
+		if seen[cr.Category] {
+			return fmt.Errorf("category %s is listed twice", cr.Category)
+		}
+		seen[cr.Category] = true
+
+		if cr.MaxDurationHours != nil && *cr.MaxDurationHours <= 0 {
+			return fmt.Errorf("%s: max_duration_hours must be positive", cr.Category)
+		}
+		if cr.DailyLimit != nil && *cr.DailyLimit < 0 {
+			return fmt.Errorf("%s: daily_limit cannot be negative", cr.Category)
+		}
+		if (cr.AllowedFrom == nil) != (cr.AllowedTo == nil) {
+			return fmt.Errorf("%s: allowed_from and allowed_to must be set together", cr.Category)
+		}
+		if cr.AllowedFrom != nil {
+			from, errFrom := time.Parse("15:04", *cr.AllowedFrom)
+			to, errTo := time.Parse("15:04", *cr.AllowedTo)
+			if errFrom != nil || errTo != nil || from.Equal(to) {
+				return fmt.Errorf("%s: allowed_from and allowed_to must be different HH:MM times", cr.Category)
+			}
+		}
+	}
+	return nil
+}
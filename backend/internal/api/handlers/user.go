@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	stderrors "errors"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"yardpass/internal/domain"
 	"yardpass/internal/errors"
@@ -80,3 +82,59 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 		"users": users,
 	})
 }
+
+func (h *UserHandler) CreateTelegramLinkCode(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	code, ttl, err := h.userService.IssueTelegramLinkCode(c.Request.Context(), userID.(int64))
+	if err != nil {
+		errors.BadRequest(c, "LINK_CODE_FAILED", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"code":       code,
+		"expires_in": int64(ttl.Seconds()),
+	})
+}
+
+// UnlinkUserTelegram drops the Telegram link of a staff user of the admin's
+// building, so that the Telegram account can be linked to another user.
+func (h *UserHandler) UnlinkUserTelegram(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		errors.BadRequest(c, "INVALID_ID", "Invalid user ID format")
+		return
+	}
+
+	own, ok := ownBuilding(c)
+	if !ok {
+		return
+	}
+
+	if err := h.userService.UnlinkStaffTelegram(c.Request.Context(), id, own); err != nil {
+		if stderrors.Is(err, service.ErrStaffUserNotFound) {
+			errors.NotFound(c, "USER_NOT_FOUND", err.Error())
+			return
+		}
+		errors.BadRequest(c, "UNLINK_FAILED", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Telegram account unlinked",
+	})
+}
+
+func (h *UserHandler) UnlinkTelegram(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	if err := h.userService.UnlinkTelegram(c.Request.Context(), userID.(int64)); err != nil {
+		errors.BadRequest(c, "UNLINK_FAILED", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Telegram account unlinked",
+	})
+}
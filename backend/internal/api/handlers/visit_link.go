@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strconv"
+
+	"yardpass/internal/errors"
+	"yardpass/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type VisitLinkHandler struct {
+	visitLinkService *service.VisitLinkService
+}
+
+func NewVisitLinkHandler(visitLinkService *service.VisitLinkService) *VisitLinkHandler {
+	return &VisitLinkHandler{
+		visitLinkService: visitLinkService,
+	}
+}
+
+// Get returns the link guests open in the bot to request a pass to the
+// building, creating it on first use.
+func (h *VisitLinkHandler) Get(c *gin.Context) {
+	buildingID, ok := visitLinkBuilding(c)
+	if !ok {
+		return
+	}
+
+	link, err := h.visitLinkService.VisitLink(c.Request.Context(), buildingID)
+	if err != nil {
+		visitLinkError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, link)
+}
+
+// Rotate replaces the building's link, e.g. after it leaked; the old link
+// stops working.
+func (h *VisitLinkHandler) Rotate(c *gin.Context) {
+	buildingID, ok := visitLinkBuilding(c)
+	if !ok {
+		return
+	}
+
+	link, err := h.visitLinkService.RotateVisitLink(c.Request.Context(), buildingID)
+	if err != nil {
+		visitLinkError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, link)
+}
+
+// visitLinkBuilding parses the building ID and keeps admins to their own
+// building.
+func visitLinkBuilding(c *gin.Context) (int64, bool) {
+	buildingID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		errors.BadRequest(c, "INVALID_ID", "Invalid building ID format")
+		return 0, false
+	}
+
+	own, ok := ownBuilding(c)
+	if !ok {
+		return 0, false
+	}
+	if own != nil && *own != buildingID {
+		errors.Forbidden(c, "FORBIDDEN_BUILDING", "Cannot manage visit links of another building")
+		return 0, false
+	}
+
+	return buildingID, true
+}
+
+func visitLinkError(c *gin.Context, err error) {
+	if stderrors.Is(err, service.ErrBuildingNotFound) {
+		errors.NotFound(c, "BUILDING_NOT_FOUND", err.Error())
+		return
+	}
+	errors.InternalServerError(c, "VISIT_LINK_FAILED", err.Error())
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strconv"
+
+	"yardpass/internal/domain"
+	"yardpass/internal/errors"
+	"yardpass/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WatchlistHandler struct {
+	watchlistService *service.WatchlistService
+}
+
+func NewWatchlistHandler(watchlistService *service.WatchlistService) *WatchlistHandler {
+	return &WatchlistHandler{
+		watchlistService: watchlistService,
+	}
+}
+
+type CreateWatchlistEntryRequest struct {
+	// BuildingID is required for superusers; admins list plates in their
+	// own building.
+	BuildingID *int64 `json:"building_id,omitempty"`
+	CarPlate   string `json:"car_plate" binding:"required"`
+	Reason     string `json:"reason" binding:"required"`
+	Severity   string `json:"severity,omitempty"`
+}
+
+// Create lists a plate in the building. Without a severity the plate is
+// banned.
+func (h *WatchlistHandler) Create(c *gin.Context) {
+	var req CreateWatchlistEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.BadRequest(c, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	own, ok := ownBuilding(c)
+	if !ok {
+		return
+	}
+
+	buildingID := req.BuildingID
+	if own != nil {
+		if buildingID != nil && *buildingID != *own {
+			errors.Forbidden(c, "FORBIDDEN_BUILDING", "Cannot list plates in another building")
+			return
+		}
+		buildingID = own
+	}
+	if buildingID == nil {
+		errors.BadRequest(c, "MISSING_BUILDING_ID", "building_id is required")
+		return
+	}
+
+	createReq := domain.CreateWatchlistEntryRequest{
+		BuildingID: *buildingID,
+		CarPlate:   req.CarPlate,
+		Reason:     req.Reason,
+		Severity:   req.Severity,
+	}
+	if userID, exists := c.Get("user_id"); exists {
+		if id, ok := userID.(int64); ok {
+			createReq.CreatedBy = &id
+		}
+	}
+
+	entry, err := h.watchlistService.AddEntry(c.Request.Context(), createReq)
+	if err != nil {
+		switch {
+		case stderrors.Is(err, service.ErrWatchlistEntryExists):
+			errors.BadRequest(c, "WATCHLIST_ENTRY_EXISTS", err.Error())
+		case stderrors.Is(err, service.ErrInvalidCarPlate):
+			errors.BadRequest(c, "INVALID_CAR_PLATE", err.Error())
+		case stderrors.Is(err, service.ErrWatchlistReasonRequired),
+			stderrors.Is(err, service.ErrWatchlistReasonTooLong),
+			stderrors.Is(err, service.ErrInvalidWatchlistSeverity):
+			errors.BadRequest(c, "INVALID_REQUEST", err.Error())
+		default:
+			errors.InternalServerError(c, "CREATE_FAILED", err.Error())
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// List returns the watchlist of the admin's building.
+func (h *WatchlistHandler) List(c *gin.Context) {
+	own, ok := ownBuilding(c)
+	if !ok {
+		return
+	}
+
+	buildingID := own
+	if own == nil {
+		if buildingIDStr := c.Query("building_id"); buildingIDStr != "" {
+			if id, err := strconv.ParseInt(buildingIDStr, 10, 64); err == nil {
+				buildingID = &id
+			}
+		}
+	}
+
+	entries, err := h.watchlistService.ListEntries(c.Request.Context(), buildingID)
+	if err != nil {
+		errors.InternalServerError(c, "FETCH_FAILED", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"entries": entries,
+		"count":   len(entries),
+	})
+}
+
+func (h *WatchlistHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		errors.BadRequest(c, "INVALID_ID", "Invalid watchlist entry ID format")
+		return
+	}
+
+	own, ok := ownBuilding(c)
+	if !ok {
+		return
+	}
+
+	if err := h.watchlistService.RemoveEntry(c.Request.Context(), id, own); err != nil {
+		if stderrors.Is(err, service.ErrWatchlistEntryNotFound) {
+			errors.NotFound(c, "WATCHLIST_ENTRY_NOT_FOUND", err.Error())
+			return
+		}
+		errors.InternalServerError(c, "DELETE_FAILED", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Car plate removed from watchlist",
+	})
+}
@@ -2,10 +2,12 @@ package middleware
 
 import (
 	"context"
+	stderrors "errors"
 	"strings"
 
 	"yardpass/internal/auth"
 	"yardpass/internal/errors"
+	"yardpass/internal/service"
 
 	"github.com/gin-gonic/gin"
 )
@@ -92,3 +94,30 @@ func ServiceAuthMiddleware(serviceToken string) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// CameraAuthMiddleware authenticates a gate camera by its X-Camera-Key
+// header and puts the camera in the context under "camera".
+func CameraAuthMiddleware(cameraService *service.CameraService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		apiKey := c.GetHeader("X-Camera-Key")
+		if apiKey == "" {
+			errors.Unauthorized(c, "MISSING_CAMERA_KEY", "Camera API key is required")
+			c.Abort()
+			return
+		}
+
+		camera, err := cameraService.Authenticate(c.Request.Context(), apiKey)
+		if err != nil {
+			if stderrors.Is(err, service.ErrInvalidCameraKey) {
+				errors.Unauthorized(c, "INVALID_CAMERA_KEY", "Invalid camera API key")
+			} else {
+				errors.InternalServerError(c, "CAMERA_AUTH_FAILED", err.Error())
+			}
+			c.Abort()
+			return
+		}
+
+		c.Set("camera", camera)
+		c.Next()
+	}
+}
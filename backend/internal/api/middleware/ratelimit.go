@@ -58,3 +58,19 @@ func CreatePassRateLimit(redisClient *redis.Client, limit int, window time.Durat
 		c.Next()
 	}
 }
+
+// PublicRateLimit limits unauthenticated endpoints per client IP. Requests
+// pass if Redis is unavailable.
+func PublicRateLimit(redisClient *redis.Client, name string, limit int, window time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := "rate_limit:" + name + ":" + c.ClientIP()
+		allowed, err := redisClient.CheckRateLimit(c.Request.Context(), key, limit, window)
+		if err == nil && !allowed {
+			errors.ErrorResponseJSON(c, http.StatusTooManyRequests, "RATE_LIMIT_EXCEEDED", "Too many requests")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"yardpass/internal/auth"
 	"yardpass/internal/config"
 	"yardpass/internal/redis"
+	"yardpass/internal/service"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/fx"
@@ -35,6 +36,17 @@ func NewRouter(
 	scanEventHandler *handlers.ScanEventHandler,
 	reportHandler *handlers.ReportHandler,
 	parkingHandler *handlers.ParkingHandler,
+	broadcastHandler *handlers.BroadcastHandler,
+	entryRequestHandler *handlers.EntryRequestHandler,
+	passShareHandler *handlers.PassShareHandler,
+	passPrintHandler *handlers.PassPrintHandler,
+	eventHandler *handlers.EventHandler,
+	passExceptionHandler *handlers.PassExceptionHandler,
+	residentVehicleHandler *handlers.ResidentVehicleHandler,
+	watchlistHandler *handlers.WatchlistHandler,
+	cameraHandler *handlers.CameraHandler,
+	visitLinkHandler *handlers.VisitLinkHandler,
+	cameraService *service.CameraService,
 	jwtService *auth.JWTService,
 	redisClient *redis.Client,
 	logger *zap.Logger,
@@ -55,6 +67,12 @@ func NewRouter(
 		c.JSON(200, gin.H{"status": "ok"})
 	})
 
+	public := r.Group("/p")
+	public.Use(middleware.PublicRateLimit(redisClient, "public_pass", cfg.RateLimit.PublicPassPerMinute, time.Minute))
+	{
+		public.GET("/:token", passShareHandler.Show)
+	}
+
 	auth := r.Group("/auth")
 	{
 		auth.POST("/login", authHandler.Login)
@@ -66,11 +84,25 @@ func NewRouter(
 	{
 		api.GET("/me", authHandler.Me)
 
+		telegramLink := api.Group("/me/telegram-link")
+		telegramLink.Use(middleware.RequireRole("guard", "admin", "superuser"))
+		{
+			telegramLink.POST("", userHandler.CreateTelegramLinkCode)
+			telegramLink.DELETE("", userHandler.UnlinkTelegram)
+		}
+
 		passes := api.Group("/passes")
 		{
 			passes.POST("", middleware.CreatePassRateLimit(redisClient, cfg.RateLimit.CreatePassPerHour, time.Hour), passHandler.Create)
+			passes.POST("/contractor", middleware.RequireRole("admin", "superuser"), passHandler.CreateContractor)
+			passes.GET("/contractor", middleware.RequireRole("admin", "superuser"), passHandler.ListContractors)
 			passes.GET("/:id", passHandler.GetByID)
 			passes.POST("/:id/revoke", passHandler.Revoke)
+			passes.POST("/:id/dynamic", middleware.RequireRole("admin", "superuser"), passHandler.EnableDynamic)
+			passes.POST("/:id/share", middleware.RequireRole("admin", "superuser"), passShareHandler.Share)
+			passes.DELETE("/:id/share", middleware.RequireRole("admin", "superuser"), passShareHandler.Unshare)
+			passes.GET("/:id/pdf", passPrintHandler.GetPDF)
+			passes.POST("/pdf", passPrintHandler.PrintBatch)
 			passes.POST("/validate", passHandler.Validate)
 			passes.GET("/active", passHandler.GetActive)
 			passes.GET("/search", passHandler.Search)
@@ -88,6 +120,7 @@ func NewRouter(
 		{
 			users.POST("", userHandler.RegisterUser)
 			users.GET("", userHandler.ListUsers)
+			users.DELETE("/:id/telegram-link", userHandler.UnlinkUserTelegram)
 		}
 
 		residents := api.Group("/residents")
@@ -100,6 +133,37 @@ func NewRouter(
 			residents.DELETE("/:id", residentHandler.DeleteResident)
 		}
 
+		residentVehicles := api.Group("/resident-vehicles")
+		residentVehicles.Use(middleware.RequireRole("admin", "superuser"))
+		{
+			residentVehicles.POST("", residentVehicleHandler.Create)
+			residentVehicles.GET("", residentVehicleHandler.List)
+			residentVehicles.DELETE("/:id", residentVehicleHandler.Delete)
+		}
+
+		watchlist := api.Group("/watchlist")
+		watchlist.Use(middleware.RequireRole("admin", "superuser"))
+		{
+			watchlist.POST("", watchlistHandler.Create)
+			watchlist.GET("", watchlistHandler.List)
+			watchlist.DELETE("/:id", watchlistHandler.Delete)
+		}
+
+		cameras := api.Group("/cameras")
+		cameras.Use(middleware.RequireRole("admin", "superuser"))
+		{
+			cameras.POST("", cameraHandler.Create)
+			cameras.GET("", cameraHandler.List)
+			cameras.DELETE("/:id", cameraHandler.Delete)
+		}
+
+		buildings := api.Group("/buildings")
+		buildings.Use(middleware.RequireRole("admin", "superuser"))
+		{
+			buildings.GET("/:id/visit-link", visitLinkHandler.Get)
+			buildings.POST("/:id/visit-link", visitLinkHandler.Rotate)
+		}
+
 		scanEvents := api.Group("/scan-events")
 		scanEvents.Use(middleware.RequireRole("guard", "admin", "superuser"))
 		{
@@ -119,6 +183,50 @@ func NewRouter(
 			parking.GET("/occupancy", parkingHandler.GetOccupancy)
 			parking.GET("/vehicles", parkingHandler.GetVehicles)
 		}
+
+		broadcasts := api.Group("/broadcasts")
+		broadcasts.Use(middleware.RequireRole("admin", "superuser"))
+		{
+			broadcasts.POST("", broadcastHandler.Create)
+			broadcasts.GET("", broadcastHandler.List)
+			broadcasts.GET("/:id", broadcastHandler.GetByID)
+			broadcasts.GET("/:id/recipients", broadcastHandler.ListRecipients)
+		}
+
+		entryRequests := api.Group("/entry-requests")
+		entryRequests.Use(middleware.RequireRole("guard", "admin", "superuser"))
+		{
+			entryRequests.POST("", entryRequestHandler.Create)
+			entryRequests.GET("/:id", entryRequestHandler.GetByID)
+		}
+
+		events := api.Group("/events")
+		events.Use(middleware.RequireRole("admin", "superuser"))
+		{
+			events.POST("", eventHandler.Create)
+			events.GET("", eventHandler.List)
+			events.GET("/:id", eventHandler.GetByID)
+			events.GET("/:id/pdf", eventHandler.GetPDF)
+			events.GET("/:id/qr", eventHandler.GetQRArchive)
+			events.POST("/:id/revoke", eventHandler.Revoke)
+			events.GET("/:id/attendance", eventHandler.GetAttendance)
+		}
+
+		passExceptions := api.Group("/pass-exceptions")
+		passExceptions.Use(middleware.RequireRole("admin", "superuser"))
+		{
+			passExceptions.GET("", passExceptionHandler.List)
+			passExceptions.GET("/:id", passExceptionHandler.GetByID)
+			passExceptions.POST("/:id/approve", passExceptionHandler.Approve)
+			passExceptions.POST("/:id/reject", passExceptionHandler.Reject)
+		}
+	}
+
+	// Gate cameras authenticate with their own API key, not a user token.
+	anpr := r.Group("/api/v1/anpr")
+	anpr.Use(middleware.CameraAuthMiddleware(cameraService))
+	{
+		anpr.POST("/events", cameraHandler.Ingest)
 	}
 
 	service := r.Group("/service/v1")
@@ -126,6 +234,9 @@ func NewRouter(
 	{
 		service.POST("/passes", middleware.CreatePassRateLimit(redisClient, cfg.RateLimit.CreatePassPerHour, time.Hour), passHandler.Create)
 		service.POST("/passes/:id/revoke", passHandler.Revoke)
+		service.POST("/passes/:id/dynamic", passHandler.EnableDynamic)
+		service.POST("/passes/:id/share", passShareHandler.Share)
+		service.DELETE("/passes/:id/share", passShareHandler.Unshare)
 		service.GET("/passes/active", passHandler.GetActive)
 	}
 
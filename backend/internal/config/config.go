@@ -7,6 +7,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	// The runtime images ship without a zoneinfo database.
+	_ "time/tzdata"
 
 	"github.com/goccy/go-yaml"
 	"github.com/joho/godotenv"
@@ -25,7 +27,13 @@ type Config struct {
 	Telegram  TelegramConfig  `yaml:"telegram"`
 	Service   ServiceConfig   `yaml:"service"`
 	RateLimit RateLimitConfig `yaml:"rate_limit"`
+	PDF       PDFConfig       `yaml:"pdf"`
+	ANPR      ANPRConfig      `yaml:"anpr"`
 	Log       LogConfig       `yaml:"log"`
+
+	// Timezone is where residents and guards live: the bot shows and reads
+	// times in it, and pass schedules are counted in it.
+	Timezone string `yaml:"timezone" env:"TIMEZONE" default:"Europe/Moscow"`
 }
 
 type ServerConfig struct {
@@ -33,6 +41,7 @@ type ServerConfig struct {
 	Port         string        `yaml:"port"          env:"SERVER_PORT"          default:"8080"`
 	StartTimeout time.Duration `yaml:"start_timeout" env:"SERVER_START_TIMEOUT" default:"15s"`
 	StopTimeout  time.Duration `yaml:"stop_timeout"  env:"SERVER_STOP_TIMEOUT"  default:"15s"`
+	PublicURL    string        `yaml:"public_url"    env:"PUBLIC_URL"           default:""`
 }
 
 type PGConfig struct {
@@ -54,10 +63,16 @@ type JWTConfig struct {
 }
 
 type TelegramConfig struct {
-	BotToken   string `yaml:"bot_token"   env:"TELEGRAM_BOT_TOKEN"   default:""`
-	WebhookURL string `yaml:"webhook_url" env:"TELEGRAM_WEBHOOK_URL" default:""`
-	ServerHost string `yaml:"server_host" env:"TELEGRAM_SERVER_HOST" default:"0.0.0.0"`
-	ServerPort string `yaml:"server_port" env:"TELEGRAM_SERVER_PORT" default:"8081"`
+	BotToken      string `yaml:"bot_token"      env:"TELEGRAM_BOT_TOKEN"      default:""`
+	APIBaseURL    string `yaml:"api_base_url"   env:"TELEGRAM_API_BASE_URL"   default:"https://api.telegram.org"`
+	WebhookURL    string `yaml:"webhook_url"    env:"TELEGRAM_WEBHOOK_URL"    default:""`
+	WebhookSecret string `yaml:"webhook_secret" env:"TELEGRAM_WEBHOOK_SECRET" default:""`
+	BotUsername   string `yaml:"bot_username"   env:"TELEGRAM_BOT_USERNAME"   default:""`
+	ServerHost    string `yaml:"server_host"    env:"TELEGRAM_SERVER_HOST"    default:"0.0.0.0"`
+	ServerPort    string `yaml:"server_port"    env:"TELEGRAM_SERVER_PORT"    default:"8081"`
+	Workers       int    `yaml:"workers"        env:"TELEGRAM_WORKERS"        default:"8"`
+	QueueSize     int    `yaml:"queue_size"     env:"TELEGRAM_QUEUE_SIZE"     default:"100"`
+	BroadcastRate int    `yaml:"broadcast_rate" env:"TELEGRAM_BROADCAST_RATE" default:"25"`
 }
 
 type ServiceConfig struct {
@@ -65,9 +80,22 @@ type ServiceConfig struct {
 }
 
 type RateLimitConfig struct {
-	RequestsPerMinute int `yaml:"requests_per_minute"  env:"RATE_LIMIT_REQUESTS_PER_MINUTE" default:"60"`
-	CreatePassPerHour int `yaml:"create_pass_per_hour" env:"RATE_LIMIT_CREATE_PASS_PER_HOUR" default:"10"`
-	ScanPerMinute     int `yaml:"scan_per_minute"      env:"RATE_LIMIT_SCAN_PER_MINUTE"     default:"100"`
+	RequestsPerMinute   int `yaml:"requests_per_minute"    env:"RATE_LIMIT_REQUESTS_PER_MINUTE"    default:"60"`
+	CreatePassPerHour   int `yaml:"create_pass_per_hour"   env:"RATE_LIMIT_CREATE_PASS_PER_HOUR"   default:"10"`
+	ScanPerMinute       int `yaml:"scan_per_minute"        env:"RATE_LIMIT_SCAN_PER_MINUTE"        default:"100"`
+	PublicPassPerMinute int `yaml:"public_pass_per_minute" env:"RATE_LIMIT_PUBLIC_PASS_PER_MINUTE" default:"30"`
+}
+
+type PDFConfig struct {
+	LogoPath string `yaml:"logo_path" env:"PDF_LOGO_PATH" default:""`
+}
+
+// ANPRConfig tunes the gate cameras. Timeout is the budget of one decision;
+// a camera event older than MaxEventAge no longer opens the gate.
+type ANPRConfig struct {
+	MinConfidence float64       `yaml:"min_confidence" env:"ANPR_MIN_CONFIDENCE" default:"0.8"`
+	Timeout       time.Duration `yaml:"timeout"        env:"ANPR_TIMEOUT"        default:"300ms"`
+	MaxEventAge   time.Duration `yaml:"max_event_age"  env:"ANPR_MAX_EVENT_AGE"  default:"10s"`
 }
 
 type LogConfig struct {
@@ -78,7 +106,7 @@ type LogConfig struct {
 	Transport      string         `yaml:"transport"        default:""`
 	FilePath       string         `yaml:"file_path"        default:""`
 	ElasticConfig  *ElasticConfig `yaml:"elastic_config"   default:""`
-	MaskHeaders    []string       `yaml:"mask_headers"     env:"LOG_MASK_HEADERS"     default:"Authorization,X-Service-Token,Cookie"`
+	MaskHeaders    []string       `yaml:"mask_headers"     env:"LOG_MASK_HEADERS"     default:"Authorization,X-Service-Token,X-Camera-Key,Cookie"`
 	MaskBodyFields []string       `yaml:"mask_body_fields" env:"LOG_MASK_BODY_FIELDS" default:"password,token,secret,api_key,apiKey,refresh_token,access_token"`
 }
 
@@ -113,6 +141,16 @@ func Load(configPath string) (*Config, error) {
 	return &cfg, nil
 }
 
+// NewLocation loads the configured timezone once for everything that shows
+// or counts local time.
+func NewLocation(cfg *Config) (*time.Location, error) {
+	location, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("load timezone %q: %w", cfg.Timezone, err)
+	}
+	return location, nil
+}
+
 func processConfig(cfg any) error {
 	return processValue(reflect.ValueOf(cfg))
 }
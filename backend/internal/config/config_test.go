@@ -39,14 +39,27 @@ func TestLoad_Defaults(t *testing.T) {
 
 	// Telegram defaults
 	assertEqual(t, "Telegram.BotToken", "", cfg.Telegram.BotToken)
+	assertEqual(t, "Telegram.APIBaseURL", "https://api.telegram.org", cfg.Telegram.APIBaseURL)
 	assertEqual(t, "Telegram.WebhookURL", "", cfg.Telegram.WebhookURL)
+	assertEqual(t, "Telegram.WebhookSecret", "", cfg.Telegram.WebhookSecret)
+	assertEqual(t, "Telegram.BotUsername", "", cfg.Telegram.BotUsername)
 	assertEqual(t, "Telegram.ServerHost", "0.0.0.0", cfg.Telegram.ServerHost)
 	assertEqual(t, "Telegram.ServerPort", "8081", cfg.Telegram.ServerPort)
+	assertEqual(t, "Telegram.Workers", 8, cfg.Telegram.Workers)
+	assertEqual(t, "Telegram.QueueSize", 100, cfg.Telegram.QueueSize)
+	assertEqual(t, "Telegram.BroadcastRate", 25, cfg.Telegram.BroadcastRate)
 
 	// RateLimit defaults
 	assertEqual(t, "RateLimit.RequestsPerMinute", 60, cfg.RateLimit.RequestsPerMinute)
 	assertEqual(t, "RateLimit.CreatePassPerHour", 10, cfg.RateLimit.CreatePassPerHour)
 	assertEqual(t, "RateLimit.ScanPerMinute", 100, cfg.RateLimit.ScanPerMinute)
+
+	// ANPR defaults
+	assertEqual(t, "ANPR.MinConfidence", 0.8, cfg.ANPR.MinConfidence)
+	assertEqual(t, "ANPR.Timeout", 300*time.Millisecond, cfg.ANPR.Timeout)
+	assertEqual(t, "ANPR.MaxEventAge", 10*time.Second, cfg.ANPR.MaxEventAge)
+
+	assertEqual(t, "Timezone", "Europe/Moscow", cfg.Timezone)
 }
 
 func TestLoad_FromYAML(t *testing.T) {
@@ -413,9 +426,11 @@ func clearEnvVars(t *testing.T) {
 		"DATABASE_URL", "PG_MAX_CONNS", "PG_MIN_CONNS", "PG_MAX_CONN_LIFETIME", "PG_MAX_CONN_IDLE_TIME",
 		"REDIS_URL",
 		"JWT_SECRET", "JWT_ACCESS_TTL", "JWT_REFRESH_TTL",
-		"TELEGRAM_BOT_TOKEN", "TELEGRAM_WEBHOOK_URL", "TELEGRAM_SERVER_HOST", "TELEGRAM_SERVER_PORT",
+		"TELEGRAM_BOT_TOKEN", "TELEGRAM_API_BASE_URL", "TELEGRAM_WEBHOOK_URL", "TELEGRAM_WEBHOOK_SECRET", "TELEGRAM_BOT_USERNAME", "TELEGRAM_SERVER_HOST", "TELEGRAM_SERVER_PORT",
+		"TELEGRAM_WORKERS", "TELEGRAM_QUEUE_SIZE", "TELEGRAM_BROADCAST_RATE",
 		"SERVICE_TOKEN",
 		"RATE_LIMIT_REQUESTS_PER_MINUTE", "RATE_LIMIT_CREATE_PASS_PER_HOUR", "RATE_LIMIT_SCAN_PER_MINUTE",
+		"ANPR_MIN_CONFIDENCE", "ANPR_TIMEOUT", "ANPR_MAX_EVENT_AGE",
 		"LOG_LEVEL", "LOG_FORMAT",
 	}
 	for _, v := range envVars {
@@ -10,20 +10,31 @@ import (
 type BuildingRepository interface {
 	GetByID(ctx context.Context, id int64) (*Building, error)
 	List(ctx context.Context) ([]*Building, error)
+	GetByVisitToken(ctx context.Context, token string) (*Building, error)
+	// SetVisitToken stores token unless the building already has one and
+	// returns the token in effect.
+	SetVisitToken(ctx context.Context, id int64, token string) (string, error)
+	// ReplaceVisitToken overwrites the token so that older links stop working.
+	ReplaceVisitToken(ctx context.Context, id int64, token string) error
 }
 
 type ApartmentRepository interface {
 	GetByID(ctx context.Context, id int64) (*Apartment, error)
 	GetByBuildingID(ctx context.Context, buildingID int64) ([]*Apartment, error)
-	GetByResidentTelegramID(ctx context.Context, telegramID int64) (*Apartment, error)
+	GetByResidentTelegramID(ctx context.Context, telegramID int64) ([]*Apartment, error)
 }
 
 type ResidentRepository interface {
 	GetByID(ctx context.Context, id int64) (*Resident, error)
-	GetByTelegramID(ctx context.Context, telegramID int64) (*Resident, error)
+	ListByTelegramID(ctx context.Context, telegramID int64) ([]*Resident, error)
+	GetByTelegramIDAndApartmentID(ctx context.Context, telegramID, apartmentID int64) (*Resident, error)
 	Create(ctx context.Context, resident *Resident) error
 	Update(ctx context.Context, resident *Resident) error
 	Delete(ctx context.Context, id int64) error
+	// Deactivate marks the resident inactive and revokes their active passes
+	// in one transaction, and returns how many passes it revoked.
+	Deactivate(ctx context.Context, id int64) (int, error)
+	SetLanguageByTelegramID(ctx context.Context, telegramID int64, language *string) error
 	BulkCreate(ctx context.Context, residents []*Resident) error
 	List(ctx context.Context, filters ResidentFilters) ([]*Resident, error)
 }
@@ -32,6 +43,7 @@ type ResidentFilters struct {
 	ApartmentID *int64
 	BuildingID  *int64
 	Status      *string
+	Role        *string
 	Limit       int
 	Offset      int
 }
@@ -44,11 +56,151 @@ type PassRepository interface {
 	GetActiveByBuildingID(ctx context.Context, buildingID int64) ([]*Pass, error)
 	GetActiveByCarPlate(ctx context.Context, normalizedCarPlate string, buildingID *int64) (*Pass, error)
 	SearchByCarPlate(ctx context.Context, carPlate string, buildingID *int64, limit int) ([]*Pass, error)
+	// ListActiveByCarPlateLength returns the active car passes whose plate
+	// has minLength to maxLength characters, the candidates for a fuzzy
+	// plate match. All buildings when buildingID is nil.
+	ListActiveByCarPlateLength(ctx context.Context, buildingID *int64, minLength, maxLength int) ([]*Pass, error)
 	CountActiveTodayByApartmentID(ctx context.Context, apartmentID int64) (int, error)
 	CountActiveTodayByResidentID(ctx context.Context, residentID int64) (int, error)
+	CountActiveTodayByCategory(ctx context.Context, apartmentID int64, category string) (int, error)
 	Create(ctx context.Context, pass *Pass) error
 	Update(ctx context.Context, pass *Pass) error
 	Revoke(ctx context.Context, id uuid.UUID) error
+	GetByShareToken(ctx context.Context, token string) (*Pass, error)
+	SetShareToken(ctx context.Context, id uuid.UUID, token string) (string, error)
+	ClearShareToken(ctx context.Context, id uuid.UUID) error
+	SetQRSecret(ctx context.Context, id uuid.UUID, secret []byte) ([]byte, error)
+	ListContractorPasses(ctx context.Context, buildingID *int64) ([]*Pass, error)
+}
+
+type SavedGuestRepository interface {
+	GetByID(ctx context.Context, id int64) (*SavedGuest, error)
+	ListByResidentID(ctx context.Context, residentID int64, limit int) ([]*SavedGuest, error)
+	Touch(ctx context.Context, residentID int64, carPlate string, guestName *string) error
+	SetStarred(ctx context.Context, id int64, starred bool) error
+	Delete(ctx context.Context, id int64) error
+	PruneRecent(ctx context.Context, residentID int64, keep int) error
+}
+
+type PassRequestRepository interface {
+	Create(ctx context.Context, request *PassRequest) error
+	GetByID(ctx context.Context, id int64) (*PassRequest, error)
+	// CountRecentByGuest counts the requests of a guest within the last window.
+	CountRecentByGuest(ctx context.Context, guestTelegramID int64, window time.Duration) (int, error)
+	// Resolve moves a pending request to status and reports whether it was
+	// still pending.
+	Resolve(ctx context.Context, id int64, status string, decidedBy *int64, passID *uuid.UUID) (bool, error)
+}
+
+type EventRepository interface {
+	// Create stores the event with its passes in one transaction.
+	Create(ctx context.Context, event *Event, passes []*Pass) error
+	GetByID(ctx context.Context, id int64) (*Event, error)
+	List(ctx context.Context, buildingID *int64) ([]*Event, error)
+	GetPasses(ctx context.Context, eventID int64) ([]*Pass, error)
+	// Revoke marks the event and its active passes revoked and returns how
+	// many passes it revoked.
+	Revoke(ctx context.Context, id int64) (int, error)
+	Attendance(ctx context.Context, eventID int64) ([]*EventGuestAttendance, error)
+}
+
+type EntryRequestRepository interface {
+	Create(ctx context.Context, request *EntryRequest) error
+	GetByID(ctx context.Context, id int64) (*EntryRequest, error)
+	// ClaimUnnotified marks up to limit pending requests as notified and
+	// returns them.
+	ClaimUnnotified(ctx context.Context, limit int) ([]*EntryRequest, error)
+	// Resolve moves a pending request to status and reports whether it was
+	// still pending.
+	Resolve(ctx context.Context, id int64, status string, decidedBy *int64, passID *uuid.UUID) (bool, error)
+	// ExpireOverdue marks pending requests that expired before now and
+	// returns them.
+	ExpireOverdue(ctx context.Context, now time.Time) ([]*EntryRequest, error)
+}
+
+type PassExceptionRepository interface {
+	Create(ctx context.Context, exception *PassException) error
+	GetByID(ctx context.Context, id int64) (*PassException, error)
+	// List returns the exceptions of a building (all buildings when nil),
+	// optionally with the given status, oldest first.
+	List(ctx context.Context, buildingID *int64, status *string) ([]*PassException, error)
+	CountPendingByResident(ctx context.Context, residentID int64) (int, error)
+	// Resolve moves a pending exception to status and reports whether it
+	// was still pending.
+	Resolve(ctx context.Context, id int64, status string, decidedBy int64, comment *string, passID *uuid.UUID) (bool, error)
+	// ClaimUndelivered marks up to limit decided exceptions as delivered to
+	// the resident and returns them.
+	ClaimUndelivered(ctx context.Context, limit int) ([]*PassException, error)
+}
+
+type ResidentVehicleRepository interface {
+	Create(ctx context.Context, vehicle *ResidentVehicle) error
+	GetByID(ctx context.Context, id int64) (*ResidentVehicle, error)
+	// GetByCarPlate returns the car registered with the normalized plate in
+	// the building, any building when nil.
+	GetByCarPlate(ctx context.Context, normalizedCarPlate string, buildingID *int64) (*ResidentVehicle, error)
+	// List returns the cars of a building (all buildings when nil),
+	// optionally of one apartment.
+	List(ctx context.Context, buildingID *int64, apartmentID *int64) ([]*ResidentVehicle, error)
+	CountByApartmentID(ctx context.Context, apartmentID int64) (int, error)
+	CountByBuildingID(ctx context.Context, buildingID int64) (int, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type PlateWatchlistRepository interface {
+	Create(ctx context.Context, entry *WatchlistEntry) error
+	GetByID(ctx context.Context, id int64) (*WatchlistEntry, error)
+	// GetByCarPlate returns the entry of the normalized plate in the
+	// building. With a nil building a ban anywhere wins over a watch.
+	GetByCarPlate(ctx context.Context, normalizedCarPlate string, buildingID *int64) (*WatchlistEntry, error)
+	// List returns the entries of a building, all buildings when nil.
+	List(ctx context.Context, buildingID *int64) ([]*WatchlistEntry, error)
+	Delete(ctx context.Context, id int64) error
+	CreateAlert(ctx context.Context, alert *PlateAlert) error
+	// ClaimUnnotifiedAlerts marks up to limit alerts as sent to the admins
+	// and returns them.
+	ClaimUnnotifiedAlerts(ctx context.Context, limit int) ([]*PlateAlert, error)
+}
+
+type GateCameraRepository interface {
+	Create(ctx context.Context, camera *GateCamera) error
+	GetByID(ctx context.Context, id int64) (*GateCamera, error)
+	// TouchByAPIKeyHash sets the last seen time of the camera with the key
+	// and returns it, nil when there is none.
+	TouchByAPIKeyHash(ctx context.Context, apiKeyHash string) (*GateCamera, error)
+	// List returns the cameras of a building, all buildings when nil.
+	List(ctx context.Context, buildingID *int64) ([]*GateCamera, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type BroadcastRepository interface {
+	// Create stores the broadcast together with its recipients and sets
+	// TotalRecipients. Nothing is stored when no resident matches.
+	Create(ctx context.Context, broadcast *Broadcast) error
+	GetByID(ctx context.Context, id int64) (*Broadcast, error)
+	List(ctx context.Context, filters BroadcastFilters) ([]*Broadcast, error)
+	ListRecipients(ctx context.Context, broadcastID int64, filters RecipientFilters) ([]*BroadcastRecipient, error)
+	// ClaimPending marks up to limit pending recipients as sending and
+	// returns them, oldest broadcasts first.
+	ClaimPending(ctx context.Context, limit int) ([]*BroadcastDelivery, error)
+	// ReleaseSending returns recipients claimed more than lease ago and
+	// still in sending, e.g. by a crashed bot, to pending.
+	ReleaseSending(ctx context.Context, lease time.Duration) error
+	MarkSent(ctx context.Context, recipientID int64) error
+	MarkFailed(ctx context.Context, recipientID int64, reason, errorText string) error
+	Requeue(ctx context.Context, recipientID int64, errorText string) error
+}
+
+type BroadcastFilters struct {
+	BuildingID *int64
+	Limit      int
+	Offset     int
+}
+
+type RecipientFilters struct {
+	Status *string
+	Limit  int
+	Offset int
 }
 
 type ScanEventRepository interface {
@@ -60,23 +212,25 @@ type ScanEventRepository interface {
 }
 
 type Statistics struct {
-	TotalScans   int
-	ValidScans   int
-	InvalidScans int
-	UniquePasses int
-	UniqueGuards int
+	TotalScans      int
+	ValidScans      int
+	InvalidScans    int
+	UniquePasses    int
+	UniqueGuards    int
+	ContractorScans int
 }
 
 type ScanEventWithDetails struct {
 	ID              int64
-	PassID          uuid.UUID
-	GuardUserID     int64
+	PassID          *uuid.UUID
+	GuardUserID     *int64
 	GuardUsername   string
 	ScannedAt       time.Time
 	Result          string
 	Reason          *string
 	Meta            *string
 	CarPlate        string
+	PassCategory    *string
 	ApartmentNumber string
 	BuildingID      int64
 }
@@ -100,6 +254,7 @@ type RuleRepository interface {
 type UserRepository interface {
 	GetByID(ctx context.Context, id int64) (*User, error)
 	GetByUsername(ctx context.Context, username string) (*User, error)
+	GetByTelegramID(ctx context.Context, telegramID int64) (*User, error)
 	Create(ctx context.Context, user *User) error
 	Update(ctx context.Context, user *User) error
 	List(ctx context.Context, filters UserFilters) ([]*User, error)
@@ -113,6 +268,9 @@ type UserFilters struct {
 	Offset     int
 }
 
+// CreatePassRequest is a pass issued by a resident. Category is guest, taxi
+// or delivery; empty means guest. ExemptRule skips one of the PassRule
+// checks for a pass an admin approved as an exception.
 type CreatePassRequest struct {
 	ApartmentID int64
 	ResidentID  *int64
@@ -120,6 +278,28 @@ type CreatePassRequest struct {
 	GuestName   *string
 	ValidFrom   time.Time
 	ValidTo     time.Time
+	SingleUse   bool
+	Dynamic     bool
+	Category    string
+	ExemptRule  string
+}
+
+// CreateContractorPassRequest is an admin-issued pass of a crew or a
+// service company. Category is contractor or service; empty means
+// contractor. Weekdays are ISO (1 = Monday); empty means every day.
+// DailyFrom and DailyTo are "HH:MM" and may be left empty together.
+type CreateContractorPassRequest struct {
+	ApartmentID int64
+	Category    string
+	CompanyName string
+	CarPlate    *string
+	GuestName   *string
+	ValidFrom   time.Time
+	ValidTo     time.Time
+	Weekdays    []int
+	DailyFrom   string
+	DailyTo     string
+	IssuedBy    *int64
 }
 
 type AuthTokens struct {
@@ -12,6 +12,15 @@ type Building struct {
 	Address   string    `json:"address"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	// VisitToken is the secret of the guest visit link, nil until an admin
+	// asks for the link.
+	VisitToken *string `json:"-"`
+}
+
+// VisitLink is the link a building shows guests to request a pass in the bot.
+type VisitLink struct {
+	StartParameter string `json:"start_parameter"`
+	URL            string `json:"url,omitempty"`
 }
 
 type Apartment struct {
@@ -24,15 +33,19 @@ type Apartment struct {
 }
 
 type Resident struct {
-	ID          int64     `json:"id"`
-	ApartmentID int64     `json:"apartment_id"`
-	TelegramID  int64     `json:"telegram_id"`
-	ChatID      int64     `json:"chat_id"`
-	Name        *string   `json:"name,omitempty"`
-	Phone       *string   `json:"phone,omitempty"`
-	Status      string    `json:"status"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID             int64     `json:"id"`
+	ApartmentID    int64     `json:"apartment_id"`
+	TelegramID     int64     `json:"telegram_id"`
+	ChatID         int64     `json:"chat_id"`
+	Name           *string   `json:"name,omitempty"`
+	Phone          *string   `json:"phone,omitempty"`
+	Status         string    `json:"status"`
+	Role           string    `json:"role"`
+	CanIssuePasses bool      `json:"can_issue_passes"`
+	InvitedBy      *int64    `json:"invited_by,omitempty"`
+	Language       *string   `json:"language,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
 }
 
 type Pass struct {
@@ -44,29 +57,426 @@ type Pass struct {
 	ValidFrom   time.Time `json:"valid_from"`
 	ValidTo     time.Time `json:"valid_to"`
 	Status      string    `json:"status"`
+	SingleUse   bool      `json:"single_use"`
+	Dynamic     bool      `json:"dynamic"`
+	QRSecret    []byte    `json:"-"`
+	Category    string    `json:"category"`
+	CompanyName *string   `json:"company_name,omitempty"`
+	Weekdays    []int     `json:"weekdays,omitempty"`
+	DailyFrom   *string   `json:"daily_from,omitempty"`
+	DailyTo     *string   `json:"daily_to,omitempty"`
+	IssuedBy    *int64    `json:"issued_by,omitempty"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
-type ScanEvent struct {
+// Guest, taxi and delivery passes are issued by residents, contractor and
+// service passes by admins.
+const (
+	PassCategoryGuest      = "guest"
+	PassCategoryTaxi       = "taxi"
+	PassCategoryDelivery   = "delivery"
+	PassCategoryContractor = "contractor"
+	PassCategoryService    = "service"
+)
+
+var PassCategories = []string{
+	PassCategoryGuest,
+	PassCategoryTaxi,
+	PassCategoryDelivery,
+	PassCategoryContractor,
+	PassCategoryService,
+}
+
+// PassDetails is a pass with the apartment and building it admits to.
+type PassDetails struct {
+	Pass      *Pass
+	Apartment *Apartment
+	Building  *Building
+}
+
+// SharedPass is what the public page of a shared pass shows.
+type SharedPass struct {
+	PassDetails
+	State string
+}
+
+const (
+	SharedPassStateActive      = "active"
+	SharedPassStateNotYetValid = "not_yet_valid"
+	SharedPassStateExpired     = "expired"
+	SharedPassStateRevoked     = "revoked"
+	SharedPassStateUsed        = "used"
+)
+
+type SavedGuest struct {
+	ID         int64     `json:"id"`
+	ResidentID int64     `json:"resident_id"`
+	CarPlate   string    `json:"car_plate"`
+	GuestName  *string   `json:"guest_name,omitempty"`
+	Starred    bool      `json:"starred"`
+	UseCount   int       `json:"use_count"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// PassRequest is a pass a guest asked for. A resident of the apartment
+// approves it, which creates the pass starting at ValidFrom or, without
+// one, at that moment.
+type PassRequest struct {
+	ID              int64      `json:"id"`
+	ApartmentID     int64      `json:"apartment_id"`
+	GuestTelegramID int64      `json:"guest_telegram_id"`
+	GuestChatID     int64      `json:"guest_chat_id"`
+	GuestName       *string    `json:"guest_name,omitempty"`
+	GuestLanguage   *string    `json:"guest_language,omitempty"`
+	CarPlate        *string    `json:"car_plate,omitempty"`
+	ValidFrom       *time.Time `json:"valid_from,omitempty"`
+	DurationMinutes int        `json:"duration_minutes"`
+	Status          string     `json:"status"`
+	DecidedBy       *int64     `json:"decided_by,omitempty"`
+	DecidedAt       *time.Time `json:"decided_at,omitempty"`
+	PassID          *uuid.UUID `json:"pass_id,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+const (
+	PassRequestStatusPending  = "pending"
+	PassRequestStatusApproved = "approved"
+	PassRequestStatusDeclined = "declined"
+	PassRequestStatusExpired  = "expired"
+)
+
+// EntryRequest is a guard asking the residents of an apartment whether to
+// let in a visitor without a pass. Allowing it issues a short single-use
+// pass.
+type EntryRequest struct {
+	ID          int64      `json:"id"`
+	ApartmentID int64      `json:"apartment_id"`
+	BuildingID  int64      `json:"building_id"`
+	GuardUserID int64      `json:"guard_user_id"`
+	CarPlate    string     `json:"car_plate"`
+	Status      string     `json:"status"`
+	NotifiedAt  *time.Time `json:"notified_at,omitempty"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	DecidedBy   *int64     `json:"decided_by,omitempty"`
+	DecidedAt   *time.Time `json:"decided_at,omitempty"`
+	PassID      *uuid.UUID `json:"pass_id,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+const (
+	EntryRequestStatusPending = "pending"
+	EntryRequestStatusAllowed = "allowed"
+	EntryRequestStatusDenied  = "denied"
+	EntryRequestStatusExpired = "expired"
+)
+
+// PassException is a resident asking the building admins for a pass the
+// rules reject. Approval issues the pass without the broken Rule on behalf
+// of the resident; DecidedBy is the admin.
+type PassException struct {
+	ID          int64      `json:"id"`
+	ApartmentID int64      `json:"apartment_id"`
+	BuildingID  int64      `json:"building_id"`
+	ResidentID  int64      `json:"resident_id"`
+	CarPlate    *string    `json:"car_plate,omitempty"`
+	GuestName   *string    `json:"guest_name,omitempty"`
+	Category    string     `json:"category"`
+	ValidFrom   time.Time  `json:"valid_from"`
+	ValidTo     time.Time  `json:"valid_to"`
+	Rule        string     `json:"rule"`
+	Reason      string     `json:"reason"`
+	Status      string     `json:"status"`
+	DecidedBy   *int64     `json:"decided_by,omitempty"`
+	DecidedAt   *time.Time `json:"decided_at,omitempty"`
+	Comment     *string    `json:"comment,omitempty"`
+	PassID      *uuid.UUID `json:"pass_id,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+
+	// PassValidFrom and PassValidTo are the window of the pass an approval
+	// of the pending exception issues now. Expired is set instead once the
+	// requested window has passed.
+	PassValidFrom *time.Time `json:"pass_valid_from,omitempty"`
+	PassValidTo   *time.Time `json:"pass_valid_to,omitempty"`
+	Expired       bool       `json:"expired,omitempty"`
+}
+
+const (
+	PassExceptionStatusPending  = "pending"
+	PassExceptionStatusApproved = "approved"
+	PassExceptionStatusRejected = "rejected"
+)
+
+// Pass rules a resident can ask an exception to.
+const (
+	PassRuleMaxDuration = "max_duration"
+	PassRuleDailyLimit  = "daily_limit"
+)
+
+// ResidentVehicle is a resident's own car. Guards admit it by car plate
+// without a pass. ResidentID is set when a resident registered it in the
+// bot, CreatedBy when an admin did.
+type ResidentVehicle struct {
+	ID          int64     `json:"id"`
+	ApartmentID int64     `json:"apartment_id"`
+	BuildingID  int64     `json:"building_id"`
+	CarPlate    string    `json:"car_plate"`
+	Label       *string   `json:"label,omitempty"`
+	ResidentID  *int64    `json:"resident_id,omitempty"`
+	CreatedBy   *int64    `json:"created_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// WatchlistEntry is a plate management watches for at the gate. A banned
+// plate gets no passes and is not let in; a watched one is let in as usual.
+// Every sighting of either alerts the building admins.
+type WatchlistEntry struct {
+	ID         int64     `json:"id"`
+	BuildingID int64     `json:"building_id"`
+	CarPlate   string    `json:"car_plate"`
+	Reason     string    `json:"reason"`
+	Severity   string    `json:"severity"`
+	CreatedBy  *int64    `json:"created_by,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+const (
+	WatchlistSeverityWatch = "watch"
+	WatchlistSeverityBan   = "ban"
+)
+
+// PlateAlert is a sighting of a listed plate at the gate, waiting to be sent
+// to the building admins. Admitted tells whether the guard was told to let
+// the car in. A gate camera sighting has the camera instead of a guard.
+type PlateAlert struct {
+	ID            int64      `json:"id"`
+	WatchlistID   int64      `json:"watchlist_id"`
+	BuildingID    int64      `json:"building_id"`
+	CarPlate      string     `json:"car_plate"`
+	Reason        string     `json:"reason"`
+	Severity      string     `json:"severity"`
+	GuardUserID   *int64     `json:"guard_user_id,omitempty"`
+	GuardUsername *string    `json:"guard_username,omitempty"`
+	CameraID      *int64     `json:"camera_id,omitempty"`
+	CameraName    *string    `json:"camera_name,omitempty"`
+	PassID        *uuid.UUID `json:"pass_id,omitempty"`
+	Admitted      bool       `json:"admitted"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// GateCamera is a license plate recognition camera at a gate. It posts the
+// plates it reads with its API key, of which only the hash is kept.
+// MinConfidence overrides the server threshold when set.
+type GateCamera struct {
+	ID            int64      `json:"id"`
+	BuildingID    int64      `json:"building_id"`
+	GateID        string     `json:"gate_id"`
+	Name          string     `json:"name"`
+	APIKeyHash    string     `json:"-"`
+	MinConfidence *float64   `json:"min_confidence,omitempty"`
+	LastSeenAt    *time.Time `json:"last_seen_at,omitempty"`
+	CreatedBy     *int64     `json:"created_by,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// PlateRecognition is a plate read by a gate camera. CameraID and GateID
+// are what the camera reports, Confidence is from 0 to 1.
+type PlateRecognition struct {
+	CarPlate     string
+	Confidence   float64
+	CameraID     *int64
+	GateID       string
+	RecognizedAt time.Time
+}
+
+// GateDecision tells a gate camera whether to open the barrier.
+type GateDecision struct {
+	Open      bool
+	Reason    string
+	CarPlate  string
+	Apartment string
+	Watchlist *WatchlistEntry
+}
+
+// Event is a party or building event whose passes are issued from an
+// uploaded guest list, one pass per guest.
+type Event struct {
 	ID          int64     `json:"id"`
-	PassID      uuid.UUID `json:"pass_id"`
-	GuardUserID int64     `json:"guard_user_id"`
-	ScannedAt   time.Time `json:"scanned_at"`
-	Result      string    `json:"result"`
-	Reason      *string   `json:"reason,omitempty"`
-	Meta        *string   `json:"meta,omitempty"`
+	BuildingID  int64     `json:"building_id"`
+	ApartmentID int64     `json:"apartment_id"`
+	Name        string    `json:"name"`
+	ValidFrom   time.Time `json:"valid_from"`
+	ValidTo     time.Time `json:"valid_to"`
+	Capacity    int       `json:"capacity"`
+	Status      string    `json:"status"`
+	CreatedBy   *int64    `json:"created_by,omitempty"`
+	ResidentID  *int64    `json:"resident_id,omitempty"`
+	PassCount   int       `json:"pass_count"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+const (
+	EventStatusActive  = "active"
+	EventStatusRevoked = "revoked"
+)
+
+// EventGuest is a row of an event guest list. Guests without a car plate
+// come on foot.
+type EventGuest struct {
+	CarPlate  *string `json:"car_plate,omitempty"`
+	GuestName *string `json:"guest_name,omitempty"`
+	// Row is the row of the guest in the uploaded list.
+	Row int `json:"-"`
+}
+
+// EventGuestAttendance is a guest of an event with their valid entries.
+type EventGuestAttendance struct {
+	PassID       uuid.UUID  `json:"pass_id"`
+	CarPlate     *string    `json:"car_plate,omitempty"`
+	GuestName    *string    `json:"guest_name,omitempty"`
+	PassStatus   string     `json:"pass_status"`
+	Entries      int        `json:"entries"`
+	FirstEntryAt *time.Time `json:"first_entry_at,omitempty"`
+}
+
+// EventAttendance is the attendance report of an event.
+type EventAttendance struct {
+	Event    *Event                  `json:"event"`
+	Attended int                     `json:"attended"`
+	Guests   []*EventGuestAttendance `json:"guests"`
+}
+
+// Broadcast is an announcement to the residents of a building. The counters
+// and status are derived from its recipients.
+type Broadcast struct {
+	ID              int64     `json:"id"`
+	BuildingID      int64     `json:"building_id"`
+	CreatedBy       *int64    `json:"created_by,omitempty"`
+	Text            string    `json:"text"`
+	Floors          []int     `json:"floors,omitempty"`
+	ApartmentIDs    []int64   `json:"apartment_ids,omitempty"`
+	Status          string    `json:"status"`
+	TotalRecipients int       `json:"total_recipients"`
+	Pending         int       `json:"pending"`
+	Sent            int       `json:"sent"`
+	Failed          int       `json:"failed"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+const (
+	BroadcastStatusQueued    = "queued"
+	BroadcastStatusSending   = "sending"
+	BroadcastStatusCompleted = "completed"
+)
+
+type BroadcastRecipient struct {
+	ID            int64      `json:"id"`
+	BroadcastID   int64      `json:"broadcast_id"`
+	ResidentID    *int64     `json:"resident_id,omitempty"`
+	ChatID        int64      `json:"chat_id"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	FailureReason *string    `json:"failure_reason,omitempty"`
+	Error         *string    `json:"error,omitempty"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+const (
+	RecipientStatusPending = "pending"
+	RecipientStatusSending = "sending"
+	RecipientStatusSent    = "sent"
+	RecipientStatusFailed  = "failed"
+)
+
+// Reasons a broadcast could not be delivered to a recipient.
+const (
+	DeliveryFailureBlocked      = "blocked"
+	DeliveryFailureChatNotFound = "chat_not_found"
+	DeliveryFailureDeactivated  = "user_deactivated"
+	DeliveryFailureRejected     = "rejected"
+	DeliveryFailureError        = "error"
+)
+
+// BroadcastDelivery is a recipient claimed by the bot for sending.
+type BroadcastDelivery struct {
+	RecipientID int64
+	BroadcastID int64
+	ChatID      int64
+	Text        string
+	Language    *string
+	Attempts    int
+}
+
+// ScanEvent is a check at the gate. Entries decided without a pass have no
+// PassID and are recorded against the apartment instead.
+type ScanEvent struct {
+	ID          int64      `json:"id"`
+	PassID      *uuid.UUID `json:"pass_id,omitempty"`
+	ApartmentID *int64     `json:"apartment_id,omitempty"`
+	GuardUserID *int64     `json:"guard_user_id,omitempty"`
+	CameraID    *int64     `json:"camera_id,omitempty"`
+	ScannedAt   time.Time  `json:"scanned_at"`
+	Result      string     `json:"result"`
+	Reason      *string    `json:"reason,omitempty"`
+	Meta        *string    `json:"meta,omitempty"`
 }
 
 type Rule struct {
-	ID                         int64     `json:"id"`
-	BuildingID                 int64     `json:"building_id"`
-	QuietHoursStart            *string   `json:"quiet_hours_start,omitempty"`
-	QuietHoursEnd              *string   `json:"quiet_hours_end,omitempty"`
-	DailyPassLimitPerApartment int       `json:"daily_pass_limit_per_apartment"`
-	MaxPassDurationHours       int       `json:"max_pass_duration_hours"`
-	CreatedAt                  time.Time `json:"created_at"`
-	UpdatedAt                  time.Time `json:"updated_at"`
+	ID                         int64          `json:"id"`
+	BuildingID                 int64          `json:"building_id"`
+	QuietHoursStart            *string        `json:"quiet_hours_start,omitempty"`
+	QuietHoursEnd              *string        `json:"quiet_hours_end,omitempty"`
+	DailyPassLimitPerApartment int            `json:"daily_pass_limit_per_apartment"`
+	MaxPassDurationHours       int            `json:"max_pass_duration_hours"`
+	MaxContractorPassDays      int            `json:"max_contractor_pass_days"`
+	Categories                 []CategoryRule `json:"categories"`
+	CreatedAt                  time.Time      `json:"created_at"`
+	UpdatedAt                  time.Time      `json:"updated_at"`
+}
+
+// CategoryRule narrows the building rules for one pass category. Nil
+// fields fall back to the building-wide rule. AllowedFrom and AllowedTo are
+// "HH:MM"; a window that ends before it starts runs past midnight.
+type CategoryRule struct {
+	Category         string  `json:"category"`
+	MaxDurationHours *int    `json:"max_duration_hours,omitempty"`
+	DailyLimit       *int    `json:"daily_limit,omitempty"`
+	AllowedFrom      *string `json:"allowed_from,omitempty"`
+	AllowedTo        *string `json:"allowed_to,omitempty"`
+}
+
+// CategoryRule returns the limits of category, or nil if the building has
+// none.
+func (r *Rule) CategoryRule(category string) *CategoryRule {
+	for i := range r.Categories {
+		if r.Categories[i].Category == category {
+			return &r.Categories[i]
+		}
+	}
+	return nil
+}
+
+// MaxDurationHours is the longest pass of category in hours. Contractor and
+// service passes are limited in days by default.
+func (r *Rule) MaxDurationHours(category string) int {
+	if cr := r.CategoryRule(category); cr != nil && cr.MaxDurationHours != nil {
+		return *cr.MaxDurationHours
+	}
+	if category == PassCategoryContractor || category == PassCategoryService {
+		return r.MaxContractorPassDays * 24
+	}
+	return r.MaxPassDurationHours
 }
 
 type User struct {
@@ -76,18 +486,41 @@ type User struct {
 	PasswordHash string    `json:"-"`
 	Role         string    `json:"role"`
 	BuildingID   *int64    `json:"building_id,omitempty"`
+	TelegramID   *int64    `json:"telegram_id,omitempty"`
 	Status       string    `json:"status"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
 
+// PassValidationResult is the outcome of a scan. A registered resident car
+// is valid with reason RESIDENT_VEHICLE and Vehicle instead of Pass.
+// Watchlist is set when the plate is on the building's watchlist; a banned
+// plate is invalid with reason BLACKLISTED whatever its pass. A plate that
+// matched no pass comes with Suggestions, the active passes with a similar
+// plate, best first; they are never admitted automatically.
 type PassValidationResult struct {
-	Valid     bool       `json:"valid"`
-	Reason    string     `json:"reason,omitempty"`
-	Pass      *Pass      `json:"pass,omitempty"`
-	CarPlate  string     `json:"car_plate,omitempty"`
-	Apartment string     `json:"apartment,omitempty"`
-	ValidTo   *time.Time `json:"valid_to,omitempty"`
+	Valid     bool             `json:"valid"`
+	Reason    string           `json:"reason,omitempty"`
+	Pass      *Pass            `json:"pass,omitempty"`
+	Vehicle   *ResidentVehicle `json:"vehicle,omitempty"`
+	Watchlist *WatchlistEntry  `json:"watchlist,omitempty"`
+	CarPlate  string           `json:"car_plate,omitempty"`
+	Apartment string           `json:"apartment,omitempty"`
+	ValidTo   *time.Time       `json:"valid_to,omitempty"`
+
+	Suggestions []*PlateSuggestion `json:"suggestions,omitempty"`
+}
+
+// PlateSuggestion is an active pass whose plate looks like the one a guard
+// entered. Distance counts typed edits; swapping lookalike characters such
+// as 0 and O counts as half an edit.
+type PlateSuggestion struct {
+	PassID    uuid.UUID `json:"pass_id"`
+	CarPlate  string    `json:"car_plate"`
+	Apartment string    `json:"apartment,omitempty"`
+	GuestName *string   `json:"guest_name,omitempty"`
+	ValidTo   time.Time `json:"valid_to"`
+	Distance  float64   `json:"distance"`
 }
 
 // RegisterUserRequest is the request payload for user registration.
@@ -108,6 +541,60 @@ type CreateResidentRequest struct {
 	Phone       *string `json:"phone,omitempty"`
 }
 
+// CreateBroadcastRequest is the request payload for broadcast creation.
+// Floors and ApartmentIDs narrow down the recipients; empty means all
+// residents of the building.
+type CreateBroadcastRequest struct {
+	BuildingID   int64
+	CreatedBy    *int64
+	Text         string
+	Floors       []int
+	ApartmentIDs []int64
+}
+
+// CreateEventRequest is the request for an event with its guest list.
+// Exactly one of CreatedBy and ResidentID is set.
+type CreateEventRequest struct {
+	BuildingID  int64
+	ApartmentID int64
+	Name        string
+	ValidFrom   time.Time
+	ValidTo     time.Time
+	Capacity    int
+	CreatedBy   *int64
+	ResidentID  *int64
+	Guests      []EventGuest
+}
+
+// CreateResidentVehicleRequest is the request for a resident's car.
+// Exactly one of CreatedBy and ResidentID is set; only residents are held to
+// the per-apartment cap.
+type CreateResidentVehicleRequest struct {
+	ApartmentID int64
+	CarPlate    string
+	Label       *string
+	CreatedBy   *int64
+	ResidentID  *int64
+}
+
+// CreateWatchlistEntryRequest is the request for listing a plate.
+type CreateWatchlistEntryRequest struct {
+	BuildingID int64
+	CarPlate   string
+	Reason     string
+	Severity   string
+	CreatedBy  *int64
+}
+
+// CreateGateCameraRequest is the request for registering a gate camera.
+type CreateGateCameraRequest struct {
+	BuildingID    int64
+	GateID        string
+	Name          string
+	MinConfidence *float64
+	CreatedBy     *int64
+}
+
 // BulkCreateError represents an error during bulk creation.
 type BulkCreateError struct {
 	Row   int    `json:"row"`
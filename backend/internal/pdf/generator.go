@@ -0,0 +1,186 @@
+package pdf
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"yardpass/internal/config"
+	"yardpass/internal/domain"
+	"yardpass/internal/qr"
+
+	"github.com/go-pdf/fpdf"
+	"go.uber.org/zap"
+)
+
+// The PDF standard fonts have no Cyrillic glyphs, so DejaVu Sans is
+// embedded into every document.
+var (
+	//go:embed fonts/DejaVuSansCondensed.ttf
+	regularFont []byte
+	//go:embed fonts/DejaVuSansCondensed-Bold.ttf
+	boldFont []byte
+)
+
+const (
+	fontFamily = "DejaVu"
+	dateLayout = "15:04 02.01.2006"
+
+	pageMargin = 20.0 // mm
+	qrSize     = 90.0 // mm
+	logoHeight = 20.0 // mm
+)
+
+// Generator renders printable passes, one pass per A4 page.
+type Generator struct {
+	qrGen    *qr.Generator
+	logo     []byte
+	logoType string
+	location *time.Location
+	logger   *zap.Logger
+}
+
+func NewGenerator(cfg *config.Config, qrGen *qr.Generator, location *time.Location, logger *zap.Logger) (*Generator, error) {
+	g := &Generator{
+		qrGen:    qrGen,
+		location: location,
+		logger:   logger,
+	}
+
+	if cfg.PDF.LogoPath != "" {
+		logo, err := os.ReadFile(cfg.PDF.LogoPath)
+		if err != nil {
+			return nil, fmt.Errorf("read PDF logo: %w", err)
+		}
+		switch http.DetectContentType(logo) {
+		case "image/png":
+			g.logoType = "PNG"
+		case "image/jpeg":
+			g.logoType = "JPG"
+		default:
+			return nil, errors.New("PDF logo must be a PNG or JPEG image")
+		}
+		g.logo = logo
+	}
+
+	return g, nil
+}
+
+// Render returns a PDF with a page for every pass.
+func (g *Generator) Render(ctx context.Context, passes []*domain.PassDetails) ([]byte, error) {
+	if len(passes) == 0 {
+		return nil, errors.New("no passes to render")
+	}
+
+	doc := fpdf.New("P", "mm", "A4", "")
+	doc.SetCreator("YardPass", true)
+	doc.SetTitle("Пропуск", true)
+	doc.SetMargins(pageMargin, pageMargin, pageMargin)
+	doc.SetAutoPageBreak(false, pageMargin)
+	doc.AddUTF8FontFromBytes(fontFamily, "", regularFont)
+	doc.AddUTF8FontFromBytes(fontFamily, "B", boldFont)
+
+	if g.logo != nil {
+		doc.RegisterImageOptionsReader("logo", fpdf.ImageOptions{ImageType: g.logoType}, bytes.NewReader(g.logo))
+	}
+
+	for _, details := range passes {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := g.renderPage(ctx, doc, details); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to render PDF: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (g *Generator) renderPage(ctx context.Context, doc *fpdf.Fpdf, details *domain.PassDetails) error {
+	pass := details.Pass
+
+	// A rotating code would be outdated by the time the page is printed.
+	qrName := "qr-" + pass.ID.String()
+	if !pass.Dynamic {
+		png, err := g.qrGen.GenerateQR(ctx, pass.ID)
+		if err != nil {
+			return err
+		}
+		doc.RegisterImageOptionsReader(qrName, fpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(png))
+	}
+
+	doc.AddPage()
+	pageWidth, _ := doc.GetPageSize()
+	contentWidth := pageWidth - 2*pageMargin
+
+	if g.logo != nil {
+		doc.ImageOptions("logo", pageMargin, pageMargin, 0, logoHeight, false, fpdf.ImageOptions{}, 0, "")
+		doc.SetY(pageMargin + logoHeight + 5)
+	}
+
+	doc.SetFont(fontFamily, "B", 20)
+	doc.MultiCell(contentWidth, 9, details.Building.Name, "", "C", false)
+	if details.Building.Address != "" {
+		doc.SetFont(fontFamily, "", 12)
+		doc.SetTextColor(90, 90, 90)
+		doc.MultiCell(contentWidth, 6, details.Building.Address, "", "C", false)
+		doc.SetTextColor(0, 0, 0)
+	}
+
+	doc.Ln(6)
+	doc.SetFont(fontFamily, "B", 16)
+	doc.CellFormat(contentWidth, 8, "ПРОПУСК", "", 1, "C", false, 0, "")
+
+	doc.Ln(4)
+	if pass.Dynamic {
+		doc.SetFont(fontFamily, "", 14)
+		doc.SetXY((pageWidth-qrSize)/2, doc.GetY())
+		doc.MultiCell(qrSize, 8, "Динамический QR код меняется каждые 30 секунд. Покажите его охране из Telegram или по ссылке на пропуск.", "1", "C", false)
+		doc.Ln(6)
+	} else {
+		doc.ImageOptions(qrName, (pageWidth-qrSize)/2, doc.GetY(), qrSize, qrSize, false, fpdf.ImageOptions{}, 0, "")
+		doc.SetY(doc.GetY() + qrSize + 6)
+	}
+
+	if pass.CarPlate != nil {
+		doc.SetFont(fontFamily, "B", 32)
+		doc.CellFormat(contentWidth, 14, *pass.CarPlate, "", 1, "C", false, 0, "")
+	} else {
+		doc.SetFont(fontFamily, "B", 24)
+		doc.CellFormat(contentWidth, 12, "Пеший гость", "", 1, "C", false, 0, "")
+	}
+
+	doc.SetFont(fontFamily, "", 14)
+	if pass.GuestName != nil && *pass.GuestName != "" {
+		doc.MultiCell(contentWidth, 8, *pass.GuestName, "", "C", false)
+	}
+	doc.CellFormat(contentWidth, 8, "Квартира "+details.Apartment.Number, "", 1, "C", false, 0, "")
+
+	doc.Ln(4)
+	doc.SetFont(fontFamily, "B", 14)
+	validity := fmt.Sprintf("Действует с %s по %s",
+		pass.ValidFrom.In(g.location).Format(dateLayout),
+		pass.ValidTo.In(g.location).Format(dateLayout),
+	)
+	doc.CellFormat(contentWidth, 8, validity, "", 1, "C", false, 0, "")
+
+	doc.SetFont(fontFamily, "", 9)
+	doc.SetTextColor(120, 120, 120)
+	doc.CellFormat(contentWidth, 6, "ID: "+pass.ID.String(), "", 1, "C", false, 0, "")
+	doc.SetTextColor(0, 0, 0)
+
+	if doc.Err() {
+		return fmt.Errorf("failed to render pass %s: %w", pass.ID, doc.Error())
+	}
+	return nil
+}
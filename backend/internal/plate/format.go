@@ -0,0 +1,172 @@
+package plate
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Plate is a normalized car plate split into the parts of its format.
+type Plate struct {
+	Normalized string `json:"normalized"`
+	Country    string `json:"country"`
+	Format     string `json:"format"`
+	Series     string `json:"series,omitempty"`
+	Number     string `json:"number"`
+	Region     string `json:"region,omitempty"`
+}
+
+// Format recognizes the plates of one country format. Match is given a
+// normalized plate: upper-case Latin letters and digits only.
+type Format struct {
+	Country string
+	Name    string
+	// Example is shown to users whose plate matches no format.
+	Example string
+	Match   func(normalized string) (Plate, bool)
+}
+
+// ErrUnknownFormat is returned for plates no format recognizes.
+var ErrUnknownFormat = errors.New("unrecognized car plate format")
+
+// Pattern matches a regular expression with the named groups series,
+// number and region. A name may be used by several groups, whose matches
+// are joined in order: Russian series letters surround the number.
+func Pattern(expr string) func(normalized string) (Plate, bool) {
+	re := regexp.MustCompile(expr)
+	return func(normalized string) (Plate, bool) {
+		match := re.FindStringSubmatch(normalized)
+		if match == nil {
+			return Plate{}, false
+		}
+
+		var p Plate
+		for i, name := range re.SubexpNames() {
+			switch name {
+			case "series":
+				p.Series += match[i]
+			case "number":
+				p.Number += match[i]
+			case "region":
+				p.Region += match[i]
+			}
+		}
+		return p, true
+	}
+}
+
+// Letters of Russian plates: the Cyrillic letters that have Latin
+// lookalikes, in their normalized Latin form.
+const (
+	ruLetter = `[ABEKMHOPCTYX]`
+	ruNumber = `(?P<number>00[1-9]|0[1-9]\d|[1-9]\d{2})`
+	ruRegion = `(?P<region>0[1-9]|[1-9]\d|[1-9]\d{2})`
+)
+
+var (
+	RussianStandard = Format{
+		Country: "RU",
+		Name:    "standard",
+		Example: "А123ВС77",
+		Match:   Pattern(`^(?P<series>` + ruLetter + `)` + ruNumber + `(?P<series>` + ruLetter + `{2})` + ruRegion + `$`),
+	}
+	RussianTransit = Format{
+		Country: "RU",
+		Name:    "transit",
+		Example: "АВ123С77",
+		Match:   Pattern(`^(?P<series>` + ruLetter + `{2})` + ruNumber + `(?P<series>` + ruLetter + `)` + ruRegion + `$`),
+	}
+	RussianTaxi = Format{
+		Country: "RU",
+		Name:    "taxi",
+		Example: "АВ12377",
+		Match:   Pattern(`^(?P<series>` + ruLetter + `{2})` + ruNumber + ruRegion + `$`),
+	}
+	// Belarus plates carry the region digit after the series: 1234 AB-7.
+	Belarus = Format{
+		Country: "BY",
+		Name:    "standard",
+		Example: "1234 AB-7",
+		Match:   Pattern(`^(?P<number>\d{4})(?P<series>[ABEIKMHOPCTX]{2})(?P<region>[1-7])$`),
+	}
+	// Kazakhstan plates since 2012; legal entities have two series letters.
+	Kazakhstan = Format{
+		Country: "KZ",
+		Name:    "standard",
+		Example: "123 ABC 02",
+		Match:   Pattern(`^(?P<number>\d{3})(?P<series>[A-Z]{2,3})(?P<region>0[1-9]|1\d|20)$`),
+	}
+	// EU plates differ per country, so any mix of letters and digits of
+	// EU plate length in at most three runs is accepted, e.g. B AB 1234,
+	// AB-123-CD or 1234 BCD. A Russian plate without its region is not.
+	GenericEU = Format{
+		Country: "EU",
+		Name:    "generic",
+		Example: "AB-123-CD",
+		Match:   matchGenericEU,
+	}
+)
+
+var ruWithoutRegion = regexp.MustCompile(`^` + ruLetter + `\d{3}` + ruLetter + `{2}$`)
+
+func matchGenericEU(normalized string) (Plate, bool) {
+	if len(normalized) < 4 || len(normalized) > 8 || ruWithoutRegion.MatchString(normalized) {
+		return Plate{}, false
+	}
+
+	runs, letters, digits := 0, 0, 0
+	for i := 0; i < len(normalized); i++ {
+		digit := normalized[i] >= '0' && normalized[i] <= '9'
+		if digit {
+			digits++
+		} else {
+			letters++
+		}
+		if i == 0 || digit != (normalized[i-1] >= '0' && normalized[i-1] <= '9') {
+			runs++
+		}
+	}
+	if letters == 0 || digits == 0 || runs > 3 {
+		return Plate{}, false
+	}
+
+	return Plate{Number: normalized}, true
+}
+
+// Parser recognizes plates of its formats, tried in order.
+type Parser struct {
+	formats []Format
+}
+
+func NewParser(formats ...Format) *Parser {
+	return &Parser{formats: formats}
+}
+
+// Default knows the plates of the cars that come to the buildings: Russian
+// ones first, then Belarus, Kazakhstan and EU plates.
+var Default = NewParser(RussianStandard, RussianTransit, RussianTaxi, Belarus, Kazakhstan, GenericEU)
+
+// Parse splits a normalized plate into its parts. A plate no format
+// recognizes returns an error that lists the expected formats.
+func (p *Parser) Parse(normalized string) (*Plate, error) {
+	for _, format := range p.formats {
+		if parsed, ok := format.Match(normalized); ok {
+			parsed.Normalized = normalized
+			parsed.Country = format.Country
+			parsed.Format = format.Name
+			return &parsed, nil
+		}
+	}
+
+	examples := make([]string, 0, len(p.formats))
+	for _, format := range p.formats {
+		examples = append(examples, fmt.Sprintf("%s (%s %s)", format.Example, format.Country, format.Name))
+	}
+	return nil, fmt.Errorf("%w, expected a plate like %s", ErrUnknownFormat, strings.Join(examples, ", "))
+}
+
+// Parse splits a normalized plate with the Default parser.
+func Parse(normalized string) (*Plate, error) {
+	return Default.Parse(normalized)
+}
@@ -0,0 +1,65 @@
+package plate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		normalized string
+		want       Plate
+	}{
+		{"A123BC77", Plate{Country: "RU", Format: "standard", Series: "ABC", Number: "123", Region: "77"}},
+		{"A123BC777", Plate{Country: "RU", Format: "standard", Series: "ABC", Number: "123", Region: "777"}},
+		{"AB123C77", Plate{Country: "RU", Format: "transit", Series: "ABC", Number: "123", Region: "77"}},
+		{"AB12377", Plate{Country: "RU", Format: "taxi", Series: "AB", Number: "123", Region: "77"}},
+		{"1234AB7", Plate{Country: "BY", Format: "standard", Series: "AB", Number: "1234", Region: "7"}},
+		{"123ABC02", Plate{Country: "KZ", Format: "standard", Series: "ABC", Number: "123", Region: "02"}},
+		{"123AB15", Plate{Country: "KZ", Format: "standard", Series: "AB", Number: "123", Region: "15"}},
+		{"BAB1234", Plate{Country: "EU", Format: "generic", Number: "BAB1234"}},
+		{"AB123CD", Plate{Country: "EU", Format: "generic", Number: "AB123CD"}},
+		{"1234BCD", Plate{Country: "EU", Format: "generic", Number: "1234BCD"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.normalized, func(t *testing.T) {
+			got, err := Parse(tt.normalized)
+			require.NoError(t, err)
+			tt.want.Normalized = tt.normalized
+			assert.Equal(t, tt.want, *got)
+		})
+	}
+}
+
+func TestParse_Malformed(t *testing.T) {
+	for _, normalized := range []string{
+		"A123BC",     // Russian plate without its region
+		"A123BC7",    // region cut short
+		"A000BC77",   // no plate is numbered 000
+		"A123BC7777", // too long for any format
+		"ABCDEF",     // no digits
+		"123456",     // no letters
+		"W1",         // too short
+	} {
+		t.Run(normalized, func(t *testing.T) {
+			_, err := Parse(normalized)
+			assert.ErrorIs(t, err, ErrUnknownFormat)
+			assert.Contains(t, err.Error(), "1234 AB-7 (BY standard)")
+		})
+	}
+}
+
+func TestParser_Formats(t *testing.T) {
+	// A parser only knows the formats it was given.
+	parser := NewParser(Belarus)
+
+	_, err := parser.Parse("A123BC77")
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+
+	got, err := parser.Parse("1234AB7")
+	require.NoError(t, err)
+	assert.Equal(t, "BY", got.Country)
+}
@@ -0,0 +1,68 @@
+package qr
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DynamicPeriod is how long a rotating code of a dynamic pass is shown.
+const DynamicPeriod = 30 * time.Second
+
+const (
+	secretBytes = 20
+	macBytes    = 10
+)
+
+var macEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// Code is the content of a scanned QR code. Step and MAC are only set for
+// the rotating code of a dynamic pass.
+type Code struct {
+	PassID uuid.UUID
+	Step   int64
+	MAC    string
+}
+
+func (c Code) Dynamic() bool {
+	return c.MAC != ""
+}
+
+// NewSecret returns a random key for the rotating codes of a pass.
+func NewSecret() ([]byte, error) {
+	secret := make([]byte, secretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate QR secret: %w", err)
+	}
+	return secret, nil
+}
+
+// TimeStep returns the number of DynamicPeriod intervals since the Unix
+// epoch, as in TOTP.
+func TimeStep(t time.Time) int64 {
+	return t.Unix() / int64(DynamicPeriod/time.Second)
+}
+
+// StepEnd returns when the code of step stops being shown.
+func StepEnd(step int64) time.Time {
+	return time.Unix((step+1)*int64(DynamicPeriod/time.Second), 0)
+}
+
+// MAC authenticates the pass ID and time step of a rotating code.
+func MAC(secret []byte, passID uuid.UUID, step int64) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(passID[:])
+	binary.Write(mac, binary.BigEndian, step)
+	return macEncoding.EncodeToString(mac.Sum(nil)[:macBytes])
+}
+
+// VerifyMAC reports whether code carries a valid MAC for its time step.
+func VerifyMAC(secret []byte, code Code) bool {
+	return hmac.Equal([]byte(MAC(secret, code.PassID, code.Step)), []byte(code.MAC))
+}
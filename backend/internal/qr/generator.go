@@ -2,7 +2,12 @@ package qr
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/skip2/go-qrcode"
@@ -17,6 +22,19 @@ func NewGenerator() *Generator {
 func (g *Generator) GenerateQR(ctx context.Context, passID uuid.UUID) ([]byte, error) {
 	qrData := fmt.Sprintf("yardpass://pass/%s", passID.String())
 
+	return encode(qrData)
+}
+
+// GenerateDynamicQR returns the rotating code of a dynamic pass for the time
+// step of now.
+func (g *Generator) GenerateDynamicQR(ctx context.Context, passID uuid.UUID, secret []byte, now time.Time) ([]byte, error) {
+	step := TimeStep(now)
+	qrData := fmt.Sprintf("yardpass://pass/%s?t=%d&m=%s", passID.String(), step, MAC(secret, passID, step))
+
+	return encode(qrData)
+}
+
+func encode(qrData string) ([]byte, error) {
 	png, err := qrcode.Encode(qrData, qrcode.Medium, 256)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate QR code: %w", err)
@@ -26,18 +44,42 @@ func (g *Generator) GenerateQR(ctx context.Context, passID uuid.UUID) ([]byte, e
 }
 
 func (g *Generator) ParseQR(ctx context.Context, qrData string) (uuid.UUID, error) {
-	var uuidStr string
-	if len(qrData) > 18 && qrData[:18] == "yardpass://pass/" {
-		uuidStr = qrData[18:]
-	} else {
-		uuidStr = qrData
+	code, err := g.Parse(ctx, qrData)
+	if err != nil {
+		return uuid.Nil, err
 	}
 
+	return code.PassID, nil
+}
+
+// Parse accepts a static code, a rotating code or a bare pass ID.
+func (g *Generator) Parse(ctx context.Context, qrData string) (Code, error) {
+	data := strings.TrimPrefix(strings.TrimSpace(qrData), "yardpass://pass/")
+	uuidStr, rawQuery, dynamic := strings.Cut(data, "?")
+
 	passID, err := uuid.Parse(uuidStr)
 	if err != nil {
-		return uuid.Nil, fmt.Errorf("invalid QR code format: %w", err)
+		return Code{}, fmt.Errorf("invalid QR code format: %w", err)
+	}
+
+	code := Code{PassID: passID}
+	if !dynamic {
+		return code, nil
+	}
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return Code{}, fmt.Errorf("invalid QR code format: %w", err)
+	}
+	code.Step, err = strconv.ParseInt(query.Get("t"), 10, 64)
+	if err != nil {
+		return Code{}, fmt.Errorf("invalid QR code time step: %w", err)
+	}
+	code.MAC = query.Get("m")
+	if code.MAC == "" {
+		return Code{}, errors.New("invalid QR code format: missing MAC")
 	}
 
-	return passID, nil
+	return code, nil
 }
 
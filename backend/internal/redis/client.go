@@ -2,6 +2,7 @@ package redis
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"yardpass/internal/config"
@@ -74,3 +75,18 @@ func (c *Client) Exists(ctx context.Context, key string) (bool, error) {
 	count, err := c.rdb.Exists(ctx, key).Result()
 	return count > 0, err
 }
+
+// SetNX sets the key only if it does not exist yet and reports whether it
+// was set.
+func (c *Client) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	return c.rdb.SetNX(ctx, key, value, expiration).Result()
+}
+
+func (c *Client) GetDel(ctx context.Context, key string) (string, error) {
+	return c.rdb.GetDel(ctx, key).Result()
+}
+
+// IsNil reports whether err means the requested key does not exist.
+func IsNil(err error) bool {
+	return errors.Is(err, redis.Nil)
+}
@@ -76,30 +76,36 @@ func (r *ApartmentRepo) GetByBuildingID(ctx context.Context, buildingID int64) (
 	return apartments, rows.Err()
 }
 
-func (r *ApartmentRepo) GetByResidentTelegramID(ctx context.Context, telegramID int64) (*domain.Apartment, error) {
+func (r *ApartmentRepo) GetByResidentTelegramID(ctx context.Context, telegramID int64) ([]*domain.Apartment, error) {
 	query := `
 		SELECT a.id, a.building_id, a.number, a.floor, a.created_at, a.updated_at
 		FROM apartments a
 		INNER JOIN residents r ON a.id = r.apartment_id
-		WHERE r.telegram_id = $1
+		WHERE r.telegram_id = $1 AND r.status = 'active'
+		ORDER BY a.building_id, a.number
 	`
 
-	var apartment domain.Apartment
-	err := r.pool.QueryRow(ctx, query, telegramID).Scan(
-		&apartment.ID,
-		&apartment.BuildingID,
-		&apartment.Number,
-		&apartment.Floor,
-		&apartment.CreatedAt,
-		&apartment.UpdatedAt,
-	)
-
-	if err == pgx.ErrNoRows {
-		return nil, nil
-	}
+	rows, err := r.pool.Query(ctx, query, telegramID)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	return &apartment, nil
+	var apartments []*domain.Apartment
+	for rows.Next() {
+		var apartment domain.Apartment
+		if err := rows.Scan(
+			&apartment.ID,
+			&apartment.BuildingID,
+			&apartment.Number,
+			&apartment.Floor,
+			&apartment.CreatedAt,
+			&apartment.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		apartments = append(apartments, &apartment)
+	}
+
+	return apartments, rows.Err()
 }
@@ -0,0 +1,329 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"yardpass/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type BroadcastRepo struct {
+	*PostgresRepo
+}
+
+func NewBroadcastRepo(repo *PostgresRepo) *BroadcastRepo {
+	return &BroadcastRepo{repo}
+}
+
+// Create resolves the recipients from the active residents of the building
+// in the same transaction, one per chat. When nobody matches the filters the
+// transaction is rolled back and TotalRecipients stays 0.
+func (r *BroadcastRepo) Create(ctx context.Context, broadcast *domain.Broadcast) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO broadcasts (building_id, created_by, text, floors, apartment_ids)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`,
+		broadcast.BuildingID,
+		broadcast.CreatedBy,
+		broadcast.Text,
+		broadcast.Floors,
+		broadcast.ApartmentIDs,
+	).Scan(&broadcast.ID, &broadcast.CreatedAt, &broadcast.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO broadcast_recipients (broadcast_id, resident_id, chat_id)
+		SELECT DISTINCT ON (r.chat_id) $1, r.id, r.chat_id
+		FROM residents r
+		JOIN apartments a ON a.id = r.apartment_id
+		WHERE a.building_id = $2
+		  AND r.status = 'active'
+		  AND ($3::int[] IS NULL OR a.floor = ANY($3))
+		  AND ($4::bigint[] IS NULL OR a.id = ANY($4))
+		ORDER BY r.chat_id, r.id
+	`,
+		broadcast.ID,
+		broadcast.BuildingID,
+		broadcast.Floors,
+		broadcast.ApartmentIDs,
+	)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		broadcast.ID = 0
+		broadcast.TotalRecipients = 0
+		return nil
+	}
+
+	broadcast.TotalRecipients = int(tag.RowsAffected())
+	broadcast.Pending = broadcast.TotalRecipients
+	broadcast.Status = domain.BroadcastStatusQueued
+
+	return tx.Commit(ctx)
+}
+
+const broadcastSelect = `
+	SELECT b.id, b.building_id, b.created_by, b.text, b.floors, b.apartment_ids, b.created_at, b.updated_at,
+		COUNT(r.id),
+		COUNT(r.id) FILTER (WHERE r.status IN ('pending', 'sending')),
+		COUNT(r.id) FILTER (WHERE r.status = 'sent'),
+		COUNT(r.id) FILTER (WHERE r.status = 'failed')
+	FROM broadcasts b
+	LEFT JOIN broadcast_recipients r ON r.broadcast_id = b.id
+`
+
+func (r *BroadcastRepo) GetByID(ctx context.Context, id int64) (*domain.Broadcast, error) {
+	query := broadcastSelect + `
+		WHERE b.id = $1
+		GROUP BY b.id
+	`
+
+	broadcast, err := scanBroadcast(r.pool.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return broadcast, nil
+}
+
+func (r *BroadcastRepo) List(ctx context.Context, filters domain.BroadcastFilters) ([]*domain.Broadcast, error) {
+	query := broadcastSelect + ` WHERE 1=1`
+	args := []interface{}{}
+	argPos := 1
+
+	if filters.BuildingID != nil {
+		query += fmt.Sprintf(` AND b.building_id = $%d`, argPos)
+		args = append(args, *filters.BuildingID)
+		argPos++
+	}
+
+	query += ` GROUP BY b.id ORDER BY b.created_at DESC, b.id DESC`
+
+	if filters.Limit > 0 {
+		query += fmt.Sprintf(` LIMIT $%d`, argPos)
+		args = append(args, filters.Limit)
+		argPos++
+	}
+
+	if filters.Offset > 0 {
+		query += fmt.Sprintf(` OFFSET $%d`, argPos)
+		args = append(args, filters.Offset)
+	}
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var broadcasts []*domain.Broadcast
+	for rows.Next() {
+		broadcast, err := scanBroadcast(rows)
+		if err != nil {
+			return nil, err
+		}
+		broadcasts = append(broadcasts, broadcast)
+	}
+
+	return broadcasts, rows.Err()
+}
+
+func scanBroadcast(row pgx.Row) (*domain.Broadcast, error) {
+	var broadcast domain.Broadcast
+	err := row.Scan(
+		&broadcast.ID,
+		&broadcast.BuildingID,
+		&broadcast.CreatedBy,
+		&broadcast.Text,
+		&broadcast.Floors,
+		&broadcast.ApartmentIDs,
+		&broadcast.CreatedAt,
+		&broadcast.UpdatedAt,
+		&broadcast.TotalRecipients,
+		&broadcast.Pending,
+		&broadcast.Sent,
+		&broadcast.Failed,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case broadcast.Pending == 0:
+		broadcast.Status = domain.BroadcastStatusCompleted
+	case broadcast.Sent+broadcast.Failed > 0:
+		broadcast.Status = domain.BroadcastStatusSending
+	default:
+		broadcast.Status = domain.BroadcastStatusQueued
+	}
+
+	return &broadcast, nil
+}
+
+func (r *BroadcastRepo) ListRecipients(ctx context.Context, broadcastID int64, filters domain.RecipientFilters) ([]*domain.BroadcastRecipient, error) {
+	query := `
+		SELECT id, broadcast_id, resident_id, chat_id, status, attempts, failure_reason, error, sent_at, created_at, updated_at
+		FROM broadcast_recipients
+		WHERE broadcast_id = $1
+	`
+	args := []interface{}{broadcastID}
+	argPos := 2
+
+	if filters.Status != nil {
+		query += fmt.Sprintf(` AND status = $%d`, argPos)
+		args = append(args, *filters.Status)
+		argPos++
+	}
+
+	query += ` ORDER BY id`
+
+	if filters.Limit > 0 {
+		query += fmt.Sprintf(` LIMIT $%d`, argPos)
+		args = append(args, filters.Limit)
+		argPos++
+	}
+
+	if filters.Offset > 0 {
+		query += fmt.Sprintf(` OFFSET $%d`, argPos)
+		args = append(args, filters.Offset)
+	}
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recipients []*domain.BroadcastRecipient
+	for rows.Next() {
+		var recipient domain.BroadcastRecipient
+		err := rows.Scan(
+			&recipient.ID,
+			&recipient.BroadcastID,
+			&recipient.ResidentID,
+			&recipient.ChatID,
+			&recipient.Status,
+			&recipient.Attempts,
+			&recipient.FailureReason,
+			&recipient.Error,
+			&recipient.SentAt,
+			&recipient.CreatedAt,
+			&recipient.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, &recipient)
+	}
+
+	return recipients, rows.Err()
+}
+
+func (r *BroadcastRepo) ClaimPending(ctx context.Context, limit int) ([]*domain.BroadcastDelivery, error) {
+	query := `
+		WITH claimed AS (
+			UPDATE broadcast_recipients
+			SET status = 'sending', attempts = attempts + 1, claimed_at = NOW()
+			WHERE id IN (
+				SELECT id FROM broadcast_recipients
+				WHERE status = 'pending'
+				ORDER BY id
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING id, broadcast_id, resident_id, chat_id, attempts
+		)
+		SELECT c.id, c.broadcast_id, c.chat_id, b.text, res.language, c.attempts
+		FROM claimed c
+		JOIN broadcasts b ON b.id = c.broadcast_id
+		LEFT JOIN residents res ON res.id = c.resident_id
+		ORDER BY c.id
+	`
+
+	rows, err := r.pool.Query(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*domain.BroadcastDelivery
+	for rows.Next() {
+		var delivery domain.BroadcastDelivery
+		err := rows.Scan(
+			&delivery.RecipientID,
+			&delivery.BroadcastID,
+			&delivery.ChatID,
+			&delivery.Text,
+			&delivery.Language,
+			&delivery.Attempts,
+		)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, &delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+func (r *BroadcastRepo) ReleaseSending(ctx context.Context, lease time.Duration) error {
+	query := `
+		UPDATE broadcast_recipients
+		SET status = 'pending', claimed_at = NULL
+		WHERE status = 'sending'
+		  AND (claimed_at IS NULL OR claimed_at < NOW() - make_interval(secs => $1))
+	`
+
+	_, err := r.pool.Exec(ctx, query, lease.Seconds())
+	return err
+}
+
+func (r *BroadcastRepo) MarkSent(ctx context.Context, recipientID int64) error {
+	query := `
+		UPDATE broadcast_recipients
+		SET status = 'sent', sent_at = NOW(), failure_reason = NULL, error = NULL
+		WHERE id = $1
+	`
+
+	_, err := r.pool.Exec(ctx, query, recipientID)
+	return err
+}
+
+func (r *BroadcastRepo) MarkFailed(ctx context.Context, recipientID int64, reason, errorText string) error {
+	query := `
+		UPDATE broadcast_recipients
+		SET status = 'failed', failure_reason = $2, error = $3
+		WHERE id = $1
+	`
+
+	_, err := r.pool.Exec(ctx, query, recipientID, reason, errorText)
+	return err
+}
+
+func (r *BroadcastRepo) Requeue(ctx context.Context, recipientID int64, errorText string) error {
+	query := `
+		UPDATE broadcast_recipients
+		SET status = 'pending', error = $2
+		WHERE id = $1
+	`
+
+	_, err := r.pool.Exec(ctx, query, recipientID, errorText)
+	return err
+}
@@ -18,7 +18,7 @@ func NewBuildingRepo(repo *PostgresRepo) *BuildingRepo {
 
 func (r *BuildingRepo) GetByID(ctx context.Context, id int64) (*domain.Building, error) {
 	query := `
-		SELECT id, name, address, created_at, updated_at
+		SELECT id, name, address, created_at, updated_at, visit_token
 		FROM buildings
 		WHERE id = $1
 	`
@@ -30,6 +30,7 @@ func (r *BuildingRepo) GetByID(ctx context.Context, id int64) (*domain.Building,
 		&building.Address,
 		&building.CreatedAt,
 		&building.UpdatedAt,
+		&building.VisitToken,
 	)
 
 	if err == pgx.ErrNoRows {
@@ -44,7 +45,7 @@ func (r *BuildingRepo) GetByID(ctx context.Context, id int64) (*domain.Building,
 
 func (r *BuildingRepo) List(ctx context.Context) ([]*domain.Building, error) {
 	query := `
-		SELECT id, name, address, created_at, updated_at
+		SELECT id, name, address, created_at, updated_at, visit_token
 		FROM buildings
 		ORDER BY name
 	`
@@ -64,6 +65,7 @@ func (r *BuildingRepo) List(ctx context.Context) ([]*domain.Building, error) {
 			&building.Address,
 			&building.CreatedAt,
 			&building.UpdatedAt,
+			&building.VisitToken,
 		); err != nil {
 			return nil, err
 		}
@@ -72,3 +74,52 @@ func (r *BuildingRepo) List(ctx context.Context) ([]*domain.Building, error) {
 
 	return buildings, rows.Err()
 }
+
+func (r *BuildingRepo) GetByVisitToken(ctx context.Context, token string) (*domain.Building, error) {
+	query := `
+		SELECT id, name, address, created_at, updated_at, visit_token
+		FROM buildings
+		WHERE visit_token = $1
+	`
+
+	var building domain.Building
+	err := r.pool.QueryRow(ctx, query, token).Scan(
+		&building.ID,
+		&building.Name,
+		&building.Address,
+		&building.CreatedAt,
+		&building.UpdatedAt,
+		&building.VisitToken,
+	)
+
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &building, nil
+}
+
+// SetVisitToken stores token unless the building already has one and returns
+// the token in effect, so that links printed earlier keep working.
+func (r *BuildingRepo) SetVisitToken(ctx context.Context, id int64, token string) (string, error) {
+	query := `
+		UPDATE buildings
+		SET visit_token = COALESCE(visit_token, $2)
+		WHERE id = $1
+		RETURNING visit_token
+	`
+
+	var current string
+	err := r.pool.QueryRow(ctx, query, id, token).Scan(&current)
+	return current, err
+}
+
+func (r *BuildingRepo) ReplaceVisitToken(ctx context.Context, id int64, token string) error {
+	query := `UPDATE buildings SET visit_token = $2 WHERE id = $1`
+
+	_, err := r.pool.Exec(ctx, query, id, token)
+	return err
+}
@@ -0,0 +1,154 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"yardpass/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type EntryRequestRepo struct {
+	*PostgresRepo
+}
+
+func NewEntryRequestRepo(repo *PostgresRepo) *EntryRequestRepo {
+	return &EntryRequestRepo{repo}
+}
+
+func (r *EntryRequestRepo) Create(ctx context.Context, request *domain.EntryRequest) error {
+	query := `
+		INSERT INTO entry_requests (apartment_id, guard_user_id, car_plate, status, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`
+
+	return r.pool.QueryRow(ctx, query,
+		request.ApartmentID,
+		request.GuardUserID,
+		request.CarPlate,
+		request.Status,
+		request.ExpiresAt,
+	).Scan(&request.ID, &request.CreatedAt, &request.UpdatedAt)
+}
+
+func (r *EntryRequestRepo) GetByID(ctx context.Context, id int64) (*domain.EntryRequest, error) {
+	query := `
+		SELECT e.id, e.apartment_id, a.building_id, e.guard_user_id, e.car_plate, e.status, e.notified_at,
+			e.expires_at, e.decided_by, e.decided_at, e.pass_id, e.created_at, e.updated_at
+		FROM entry_requests e
+		INNER JOIN apartments a ON e.apartment_id = a.id
+		WHERE e.id = $1
+	`
+
+	request, err := scanEntryRequest(r.pool.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+func (r *EntryRequestRepo) ClaimUnnotified(ctx context.Context, limit int) ([]*domain.EntryRequest, error) {
+	query := `
+		WITH claimed AS (
+			UPDATE entry_requests
+			SET notified_at = NOW()
+			WHERE id IN (
+				SELECT id
+				FROM entry_requests
+				WHERE notified_at IS NULL AND status = 'pending'
+				ORDER BY created_at
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING *
+		)
+		SELECT e.id, e.apartment_id, a.building_id, e.guard_user_id, e.car_plate, e.status, e.notified_at,
+			e.expires_at, e.decided_by, e.decided_at, e.pass_id, e.created_at, e.updated_at
+		FROM claimed e
+		INNER JOIN apartments a ON e.apartment_id = a.id
+		ORDER BY e.created_at
+	`
+
+	return r.list(ctx, query, limit)
+}
+
+func (r *EntryRequestRepo) Resolve(ctx context.Context, id int64, status string, decidedBy *int64, passID *uuid.UUID) (bool, error) {
+	query := `
+		UPDATE entry_requests
+		SET status = $2, decided_by = $3, pass_id = $4, decided_at = NOW()
+		WHERE id = $1 AND status = 'pending'
+	`
+
+	tag, err := r.pool.Exec(ctx, query, id, status, decidedBy, passID)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *EntryRequestRepo) ExpireOverdue(ctx context.Context, now time.Time) ([]*domain.EntryRequest, error) {
+	query := `
+		WITH expired AS (
+			UPDATE entry_requests
+			SET status = 'expired', decided_at = NOW()
+			WHERE status = 'pending' AND expires_at < $1
+			RETURNING *
+		)
+		SELECT e.id, e.apartment_id, a.building_id, e.guard_user_id, e.car_plate, e.status, e.notified_at,
+			e.expires_at, e.decided_by, e.decided_at, e.pass_id, e.created_at, e.updated_at
+		FROM expired e
+		INNER JOIN apartments a ON e.apartment_id = a.id
+	`
+
+	return r.list(ctx, query, now)
+}
+
+func (r *EntryRequestRepo) list(ctx context.Context, query string, args ...interface{}) ([]*domain.EntryRequest, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var requests []*domain.EntryRequest
+	for rows.Next() {
+		request, err := scanEntryRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+
+	return requests, rows.Err()
+}
+
+func scanEntryRequest(row pgx.Row) (*domain.EntryRequest, error) {
+	var request domain.EntryRequest
+	err := row.Scan(
+		&request.ID,
+		&request.ApartmentID,
+		&request.BuildingID,
+		&request.GuardUserID,
+		&request.CarPlate,
+		&request.Status,
+		&request.NotifiedAt,
+		&request.ExpiresAt,
+		&request.DecidedBy,
+		&request.DecidedAt,
+		&request.PassID,
+		&request.CreatedAt,
+		&request.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
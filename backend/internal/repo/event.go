@@ -0,0 +1,260 @@
+package repo
+
+import (
+	"context"
+
+	"yardpass/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type EventRepo struct {
+	*PostgresRepo
+}
+
+func NewEventRepo(repo *PostgresRepo) *EventRepo {
+	return &EventRepo{repo}
+}
+
+// Create stores the event and its passes in one transaction, so a guest
+// list is either issued completely or not at all.
+func (r *EventRepo) Create(ctx context.Context, event *domain.Event, passes []*domain.Pass) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO events (building_id, apartment_id, name, valid_from, valid_to, capacity, status, created_by, resident_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at, updated_at
+	`,
+		event.BuildingID,
+		event.ApartmentID,
+		event.Name,
+		event.ValidFrom,
+		event.ValidTo,
+		event.Capacity,
+		event.Status,
+		event.CreatedBy,
+		event.ResidentID,
+	).Scan(&event.ID, &event.CreatedAt, &event.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	batch := &pgx.Batch{}
+	for _, pass := range passes {
+		batch.Queue(`
+			INSERT INTO passes (id, apartment_id, resident_id, car_plate, guest_name, valid_from, valid_to, status, event_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			RETURNING created_at, updated_at
+		`,
+			pass.ID,
+			pass.ApartmentID,
+			pass.ResidentID,
+			pass.CarPlate,
+			pass.GuestName,
+			pass.ValidFrom,
+			pass.ValidTo,
+			pass.Status,
+			event.ID,
+		)
+	}
+
+	results := tx.SendBatch(ctx, batch)
+	for _, pass := range passes {
+		if err := results.QueryRow().Scan(&pass.CreatedAt, &pass.UpdatedAt); err != nil {
+			results.Close()
+			return err
+		}
+	}
+	if err := results.Close(); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	event.PassCount = len(passes)
+	return nil
+}
+
+func (r *EventRepo) GetByID(ctx context.Context, id int64) (*domain.Event, error) {
+	query := `
+		SELECT e.id, e.building_id, e.apartment_id, e.name, e.valid_from, e.valid_to, e.capacity, e.status,
+			e.created_by, e.resident_id, (SELECT COUNT(*) FROM passes p WHERE p.event_id = e.id), e.created_at, e.updated_at
+		FROM events e
+		WHERE e.id = $1
+	`
+
+	event, err := scanEvent(r.pool.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+func (r *EventRepo) List(ctx context.Context, buildingID *int64) ([]*domain.Event, error) {
+	query := `
+		SELECT e.id, e.building_id, e.apartment_id, e.name, e.valid_from, e.valid_to, e.capacity, e.status,
+			e.created_by, e.resident_id, (SELECT COUNT(*) FROM passes p WHERE p.event_id = e.id), e.created_at, e.updated_at
+		FROM events e
+		WHERE $1::bigint IS NULL OR e.building_id = $1
+		ORDER BY e.valid_from DESC
+		LIMIT 200
+	`
+
+	rows, err := r.pool.Query(ctx, query, buildingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*domain.Event
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+func (r *EventRepo) GetPasses(ctx context.Context, eventID int64) ([]*domain.Pass, error) {
+	query := `
+		SELECT id, apartment_id, resident_id, car_plate, guest_name, valid_from, valid_to, status, single_use, dynamic, qr_secret,
+			category, company_name, weekdays, daily_from, daily_to, issued_by, created_at, updated_at
+		FROM passes
+		WHERE event_id = $1
+		ORDER BY guest_name NULLS LAST, car_plate NULLS LAST, id
+	`
+
+	rows, err := r.pool.Query(ctx, query, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var passes []*domain.Pass
+	for rows.Next() {
+		var pass domain.Pass
+		if err := rows.Scan(
+			&pass.ID,
+			&pass.ApartmentID,
+			&pass.ResidentID,
+			&pass.CarPlate,
+			&pass.GuestName,
+			&pass.ValidFrom,
+			&pass.ValidTo,
+			&pass.Status,
+			&pass.SingleUse,
+			&pass.Dynamic,
+			&pass.QRSecret,
+			&pass.Category,
+			&pass.CompanyName,
+			&pass.Weekdays,
+			&pass.DailyFrom,
+			&pass.DailyTo,
+			&pass.IssuedBy,
+			&pass.CreatedAt,
+			&pass.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		passes = append(passes, &pass)
+	}
+
+	return passes, rows.Err()
+}
+
+func (r *EventRepo) Revoke(ctx context.Context, id int64) (int, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `UPDATE events SET status = 'revoked' WHERE id = $1`, id); err != nil {
+		return 0, err
+	}
+
+	tag, err := tx.Exec(ctx, `UPDATE passes SET status = 'revoked' WHERE event_id = $1 AND status = 'active'`, id)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+func (r *EventRepo) Attendance(ctx context.Context, eventID int64) ([]*domain.EventGuestAttendance, error) {
+	query := `
+		SELECT p.id, p.car_plate, p.guest_name, p.status,
+			COUNT(se.id) FILTER (WHERE se.result = 'valid'),
+			MIN(se.scanned_at) FILTER (WHERE se.result = 'valid')
+		FROM passes p
+		LEFT JOIN scan_events se ON se.pass_id = p.id
+		WHERE p.event_id = $1
+		GROUP BY p.id
+		ORDER BY p.guest_name NULLS LAST, p.car_plate NULLS LAST, p.id
+	`
+
+	rows, err := r.pool.Query(ctx, query, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var guests []*domain.EventGuestAttendance
+	for rows.Next() {
+		var guest domain.EventGuestAttendance
+		if err := rows.Scan(
+			&guest.PassID,
+			&guest.CarPlate,
+			&guest.GuestName,
+			&guest.PassStatus,
+			&guest.Entries,
+			&guest.FirstEntryAt,
+		); err != nil {
+			return nil, err
+		}
+		guests = append(guests, &guest)
+	}
+
+	return guests, rows.Err()
+}
+
+func scanEvent(row pgx.Row) (*domain.Event, error) {
+	var event domain.Event
+	err := row.Scan(
+		&event.ID,
+		&event.BuildingID,
+		&event.ApartmentID,
+		&event.Name,
+		&event.ValidFrom,
+		&event.ValidTo,
+		&event.Capacity,
+		&event.Status,
+		&event.CreatedBy,
+		&event.ResidentID,
+		&event.PassCount,
+		&event.CreatedAt,
+		&event.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
@@ -0,0 +1,123 @@
+package repo
+
+import (
+	"context"
+
+	"yardpass/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type GateCameraRepo struct {
+	*PostgresRepo
+}
+
+func NewGateCameraRepo(repo *PostgresRepo) *GateCameraRepo {
+	return &GateCameraRepo{repo}
+}
+
+func (r *GateCameraRepo) Create(ctx context.Context, camera *domain.GateCamera) error {
+	query := `
+		INSERT INTO gate_cameras (building_id, gate_id, name, api_key_hash, min_confidence, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+
+	return r.pool.QueryRow(ctx, query,
+		camera.BuildingID,
+		camera.GateID,
+		camera.Name,
+		camera.APIKeyHash,
+		camera.MinConfidence,
+		camera.CreatedBy,
+	).Scan(&camera.ID, &camera.CreatedAt)
+}
+
+func (r *GateCameraRepo) GetByID(ctx context.Context, id int64) (*domain.GateCamera, error) {
+	query := `
+		SELECT id, building_id, gate_id, name, api_key_hash, min_confidence, last_seen_at, created_by, created_at
+		FROM gate_cameras
+		WHERE id = $1
+	`
+
+	camera, err := scanGateCamera(r.pool.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return camera, nil
+}
+
+func (r *GateCameraRepo) TouchByAPIKeyHash(ctx context.Context, apiKeyHash string) (*domain.GateCamera, error) {
+	query := `
+		UPDATE gate_cameras
+		SET last_seen_at = NOW()
+		WHERE api_key_hash = $1
+		RETURNING id, building_id, gate_id, name, api_key_hash, min_confidence, last_seen_at, created_by, created_at
+	`
+
+	camera, err := scanGateCamera(r.pool.QueryRow(ctx, query, apiKeyHash))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return camera, nil
+}
+
+func (r *GateCameraRepo) List(ctx context.Context, buildingID *int64) ([]*domain.GateCamera, error) {
+	query := `
+		SELECT id, building_id, gate_id, name, api_key_hash, min_confidence, last_seen_at, created_by, created_at
+		FROM gate_cameras
+		WHERE ($1::bigint IS NULL OR building_id = $1)
+		ORDER BY building_id, gate_id, name
+	`
+
+	rows, err := r.pool.Query(ctx, query, buildingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cameras []*domain.GateCamera
+	for rows.Next() {
+		camera, err := scanGateCamera(rows)
+		if err != nil {
+			return nil, err
+		}
+		cameras = append(cameras, camera)
+	}
+
+	return cameras, rows.Err()
+}
+
+func (r *GateCameraRepo) Delete(ctx context.Context, id int64) error {
+	query := `DELETE FROM gate_cameras WHERE id = $1`
+	_, err := r.pool.Exec(ctx, query, id)
+	return err
+}
+
+func scanGateCamera(row pgx.Row) (*domain.GateCamera, error) {
+	var camera domain.GateCamera
+	err := row.Scan(
+		&camera.ID,
+		&camera.BuildingID,
+		&camera.GateID,
+		&camera.Name,
+		&camera.APIKeyHash,
+		&camera.MinConfidence,
+		&camera.LastSeenAt,
+		&camera.CreatedBy,
+		&camera.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &camera, nil
+}
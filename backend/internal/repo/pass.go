@@ -21,7 +21,8 @@ func NewPassRepo(repo *PostgresRepo) *PassRepo {
 
 func (r *PassRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Pass, error) {
 	query := `
-		SELECT id, apartment_id, resident_id, car_plate, guest_name, valid_from, valid_to, status, created_at, updated_at
+		SELECT id, apartment_id, resident_id, car_plate, guest_name, valid_from, valid_to, status, single_use, dynamic, qr_secret,
+			category, company_name, weekdays, daily_from, daily_to, issued_by, created_at, updated_at
 		FROM passes
 		WHERE id = $1
 	`
@@ -36,6 +37,15 @@ func (r *PassRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Pass, err
 		&pass.ValidFrom,
 		&pass.ValidTo,
 		&pass.Status,
+		&pass.SingleUse,
+		&pass.Dynamic,
+		&pass.QRSecret,
+		&pass.Category,
+		&pass.CompanyName,
+		&pass.Weekdays,
+		&pass.DailyFrom,
+		&pass.DailyTo,
+		&pass.IssuedBy,
 		&pass.CreatedAt,
 		&pass.UpdatedAt,
 	)
@@ -52,7 +62,8 @@ func (r *PassRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Pass, err
 
 func (r *PassRepo) GetByApartmentID(ctx context.Context, apartmentID int64, status string) ([]*domain.Pass, error) {
 	query := `
-		SELECT id, apartment_id, resident_id, car_plate, guest_name, valid_from, valid_to, status, created_at, updated_at
+		SELECT id, apartment_id, resident_id, car_plate, guest_name, valid_from, valid_to, status, single_use, dynamic, qr_secret,
+			category, company_name, weekdays, daily_from, daily_to, issued_by, created_at, updated_at
 		FROM passes
 		WHERE apartment_id = $1 AND status = $2
 		ORDER BY created_at DESC
@@ -76,6 +87,15 @@ func (r *PassRepo) GetByApartmentID(ctx context.Context, apartmentID int64, stat
 			&pass.ValidFrom,
 			&pass.ValidTo,
 			&pass.Status,
+			&pass.SingleUse,
+			&pass.Dynamic,
+			&pass.QRSecret,
+			&pass.Category,
+			&pass.CompanyName,
+			&pass.Weekdays,
+			&pass.DailyFrom,
+			&pass.DailyTo,
+			&pass.IssuedBy,
 			&pass.CreatedAt,
 			&pass.UpdatedAt,
 		); err != nil {
@@ -87,16 +107,18 @@ func (r *PassRepo) GetByApartmentID(ctx context.Context, apartmentID int64, stat
 	return passes, rows.Err()
 }
 
+// GetActiveByApartmentID returns passes that are valid now or scheduled to
+// start later.
 func (r *PassRepo) GetActiveByApartmentID(ctx context.Context, apartmentID int64) ([]*domain.Pass, error) {
 	now := time.Now()
 	query := `
-		SELECT id, apartment_id, resident_id, car_plate, guest_name, valid_from, valid_to, status, created_at, updated_at
+		SELECT id, apartment_id, resident_id, car_plate, guest_name, valid_from, valid_to, status, single_use, dynamic, qr_secret,
+			category, company_name, weekdays, daily_from, daily_to, issued_by, created_at, updated_at
 		FROM passes
 		WHERE apartment_id = $1
 			AND status = 'active'
-			AND valid_from <= $2
 			AND valid_to >= $2
-		ORDER BY created_at DESC
+		ORDER BY valid_from, created_at DESC
 	`
 
 	rows, err := r.pool.Query(ctx, query, apartmentID, now)
@@ -117,6 +139,15 @@ func (r *PassRepo) GetActiveByApartmentID(ctx context.Context, apartmentID int64
 			&pass.ValidFrom,
 			&pass.ValidTo,
 			&pass.Status,
+			&pass.SingleUse,
+			&pass.Dynamic,
+			&pass.QRSecret,
+			&pass.Category,
+			&pass.CompanyName,
+			&pass.Weekdays,
+			&pass.DailyFrom,
+			&pass.DailyTo,
+			&pass.IssuedBy,
 			&pass.CreatedAt,
 			&pass.UpdatedAt,
 		); err != nil {
@@ -128,16 +159,18 @@ func (r *PassRepo) GetActiveByApartmentID(ctx context.Context, apartmentID int64
 	return passes, rows.Err()
 }
 
+// GetActiveByResidentID returns passes that are valid now or scheduled to
+// start later.
 func (r *PassRepo) GetActiveByResidentID(ctx context.Context, residentID int64) ([]*domain.Pass, error) {
 	now := time.Now()
 	query := `
-		SELECT id, apartment_id, resident_id, car_plate, guest_name, valid_from, valid_to, status, created_at, updated_at
+		SELECT id, apartment_id, resident_id, car_plate, guest_name, valid_from, valid_to, status, single_use, dynamic, qr_secret,
+			category, company_name, weekdays, daily_from, daily_to, issued_by, created_at, updated_at
 		FROM passes
 		WHERE resident_id = $1
 			AND status = 'active'
-			AND valid_from <= $2
 			AND valid_to >= $2
-		ORDER BY created_at DESC
+		ORDER BY valid_from, created_at DESC
 	`
 
 	rows, err := r.pool.Query(ctx, query, residentID, now)
@@ -158,6 +191,15 @@ func (r *PassRepo) GetActiveByResidentID(ctx context.Context, residentID int64)
 			&pass.ValidFrom,
 			&pass.ValidTo,
 			&pass.Status,
+			&pass.SingleUse,
+			&pass.Dynamic,
+			&pass.QRSecret,
+			&pass.Category,
+			&pass.CompanyName,
+			&pass.Weekdays,
+			&pass.DailyFrom,
+			&pass.DailyTo,
+			&pass.IssuedBy,
 			&pass.CreatedAt,
 			&pass.UpdatedAt,
 		); err != nil {
@@ -169,6 +211,8 @@ func (r *PassRepo) GetActiveByResidentID(ctx context.Context, residentID int64)
 	return passes, rows.Err()
 }
 
+// CountActiveTodayByApartmentID counts the passes the apartment issued today;
+// admin-issued contractor and service passes do not take from its limit.
 func (r *PassRepo) CountActiveTodayByApartmentID(ctx context.Context, apartmentID int64) (int, error) {
 	today := time.Now().Truncate(24 * time.Hour)
 	query := `
@@ -176,6 +220,7 @@ func (r *PassRepo) CountActiveTodayByApartmentID(ctx context.Context, apartmentI
 		FROM passes
 		WHERE apartment_id = $1
 			AND status = 'active'
+			AND category NOT IN ('contractor', 'service')
 			AND created_at >= $2
 	`
 
@@ -191,6 +236,7 @@ func (r *PassRepo) CountActiveTodayByResidentID(ctx context.Context, residentID
 		FROM passes
 		WHERE resident_id = $1
 			AND status = 'active'
+			AND category NOT IN ('contractor', 'service')
 			AND created_at >= $2
 	`
 
@@ -199,10 +245,27 @@ func (r *PassRepo) CountActiveTodayByResidentID(ctx context.Context, residentID
 	return count, err
 }
 
+func (r *PassRepo) CountActiveTodayByCategory(ctx context.Context, apartmentID int64, category string) (int, error) {
+	today := time.Now().Truncate(24 * time.Hour)
+	query := `
+		SELECT COUNT(*)
+		FROM passes
+		WHERE apartment_id = $1
+			AND category = $2
+			AND status = 'active'
+			AND created_at >= $3
+	`
+
+	var count int
+	err := r.pool.QueryRow(ctx, query, apartmentID, category, today).Scan(&count)
+	return count, err
+}
+
 func (r *PassRepo) Create(ctx context.Context, pass *domain.Pass) error {
 	query := `
-		INSERT INTO passes (id, apartment_id, resident_id, car_plate, guest_name, valid_from, valid_to, status)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO passes (id, apartment_id, resident_id, car_plate, guest_name, valid_from, valid_to, status, single_use, dynamic, qr_secret,
+			category, company_name, weekdays, daily_from, daily_to, issued_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
 		RETURNING created_at, updated_at
 	`
 
@@ -215,6 +278,15 @@ func (r *PassRepo) Create(ctx context.Context, pass *domain.Pass) error {
 		pass.ValidFrom,
 		pass.ValidTo,
 		pass.Status,
+		pass.SingleUse,
+		pass.Dynamic,
+		pass.QRSecret,
+		pass.Category,
+		pass.CompanyName,
+		pass.Weekdays,
+		pass.DailyFrom,
+		pass.DailyTo,
+		pass.IssuedBy,
 	).Scan(&pass.CreatedAt, &pass.UpdatedAt)
 
 	return err
@@ -250,9 +322,90 @@ func (r *PassRepo) Revoke(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
+// GetByShareToken returns the pass of a public link, or nil if the link is
+// unknown or has been revoked.
+func (r *PassRepo) GetByShareToken(ctx context.Context, token string) (*domain.Pass, error) {
+	query := `
+		SELECT id, apartment_id, resident_id, car_plate, guest_name, valid_from, valid_to, status, single_use, dynamic, qr_secret,
+			category, company_name, weekdays, daily_from, daily_to, issued_by, created_at, updated_at
+		FROM passes
+		WHERE share_token = $1
+	`
+
+	var pass domain.Pass
+	err := r.pool.QueryRow(ctx, query, token).Scan(
+		&pass.ID,
+		&pass.ApartmentID,
+		&pass.ResidentID,
+		&pass.CarPlate,
+		&pass.GuestName,
+		&pass.ValidFrom,
+		&pass.ValidTo,
+		&pass.Status,
+		&pass.SingleUse,
+		&pass.Dynamic,
+		&pass.QRSecret,
+		&pass.Category,
+		&pass.CompanyName,
+		&pass.Weekdays,
+		&pass.DailyFrom,
+		&pass.DailyTo,
+		&pass.IssuedBy,
+		&pass.CreatedAt,
+		&pass.UpdatedAt,
+	)
+
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &pass, nil
+}
+
+// SetShareToken stores token unless the pass is already shared and returns
+// the token in effect, so that links sent earlier keep working.
+func (r *PassRepo) SetShareToken(ctx context.Context, id uuid.UUID, token string) (string, error) {
+	query := `
+		UPDATE passes
+		SET share_token = COALESCE(share_token, $2)
+		WHERE id = $1
+		RETURNING share_token
+	`
+
+	var current string
+	err := r.pool.QueryRow(ctx, query, id, token).Scan(&current)
+	return current, err
+}
+
+func (r *PassRepo) ClearShareToken(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE passes SET share_token = NULL WHERE id = $1`
+
+	_, err := r.pool.Exec(ctx, query, id)
+	return err
+}
+
+// SetQRSecret makes the pass dynamic with secret unless it already is and
+// returns the secret in effect.
+func (r *PassRepo) SetQRSecret(ctx context.Context, id uuid.UUID, secret []byte) ([]byte, error) {
+	query := `
+		UPDATE passes
+		SET dynamic = TRUE, qr_secret = COALESCE(qr_secret, $2)
+		WHERE id = $1
+		RETURNING qr_secret
+	`
+
+	var current []byte
+	err := r.pool.QueryRow(ctx, query, id, secret).Scan(&current)
+	return current, err
+}
+
 func (r *PassRepo) SearchByCarPlate(ctx context.Context, carPlate string, buildingID *int64, limit int) ([]*domain.Pass, error) {
 	query := `
-		SELECT p.id, p.apartment_id, p.resident_id, p.car_plate, p.guest_name, p.valid_from, p.valid_to, p.status, p.created_at, p.updated_at
+		SELECT p.id, p.apartment_id, p.resident_id, p.car_plate, p.guest_name, p.valid_from, p.valid_to, p.status, p.single_use, p.dynamic, p.qr_secret,
+			p.category, p.company_name, p.weekdays, p.daily_from, p.daily_to, p.issued_by, p.created_at, p.updated_at
 		FROM passes p
 		INNER JOIN apartments a ON p.apartment_id = a.id
 		WHERE UPPER(REPLACE(p.car_plate, ' ', '')) LIKE UPPER(REPLACE($1, ' ', ''))
@@ -287,6 +440,15 @@ func (r *PassRepo) SearchByCarPlate(ctx context.Context, carPlate string, buildi
 			&pass.ValidFrom,
 			&pass.ValidTo,
 			&pass.Status,
+			&pass.SingleUse,
+			&pass.Dynamic,
+			&pass.QRSecret,
+			&pass.Category,
+			&pass.CompanyName,
+			&pass.Weekdays,
+			&pass.DailyFrom,
+			&pass.DailyTo,
+			&pass.IssuedBy,
 			&pass.CreatedAt,
 			&pass.UpdatedAt,
 		); err != nil {
@@ -301,7 +463,8 @@ func (r *PassRepo) SearchByCarPlate(ctx context.Context, carPlate string, buildi
 func (r *PassRepo) GetActiveByCarPlate(ctx context.Context, normalizedCarPlate string, buildingID *int64) (*domain.Pass, error) {
 	now := time.Now()
 	query := `
-		SELECT p.id, p.apartment_id, p.resident_id, p.car_plate, p.guest_name, p.valid_from, p.valid_to, p.status, p.created_at, p.updated_at
+		SELECT p.id, p.apartment_id, p.resident_id, p.car_plate, p.guest_name, p.valid_from, p.valid_to, p.status, p.single_use, p.dynamic, p.qr_secret,
+			p.category, p.company_name, p.weekdays, p.daily_from, p.daily_to, p.issued_by, p.created_at, p.updated_at
 		FROM passes p
 		INNER JOIN apartments a ON p.apartment_id = a.id
 		WHERE p.car_plate = $1
@@ -330,6 +493,15 @@ func (r *PassRepo) GetActiveByCarPlate(ctx context.Context, normalizedCarPlate s
 		&pass.ValidFrom,
 		&pass.ValidTo,
 		&pass.Status,
+		&pass.SingleUse,
+		&pass.Dynamic,
+		&pass.QRSecret,
+		&pass.Category,
+		&pass.CompanyName,
+		&pass.Weekdays,
+		&pass.DailyFrom,
+		&pass.DailyTo,
+		&pass.IssuedBy,
 		&pass.CreatedAt,
 		&pass.UpdatedAt,
 	)
@@ -347,7 +519,8 @@ func (r *PassRepo) GetActiveByCarPlate(ctx context.Context, normalizedCarPlate s
 func (r *PassRepo) GetActiveByBuildingID(ctx context.Context, buildingID int64) ([]*domain.Pass, error) {
 	now := time.Now()
 	query := `
-		SELECT p.id, p.apartment_id, p.resident_id, p.car_plate, p.guest_name, p.valid_from, p.valid_to, p.status, p.created_at, p.updated_at
+		SELECT p.id, p.apartment_id, p.resident_id, p.car_plate, p.guest_name, p.valid_from, p.valid_to, p.status, p.single_use, p.dynamic, p.qr_secret,
+			p.category, p.company_name, p.weekdays, p.daily_from, p.daily_to, p.issued_by, p.created_at, p.updated_at
 		FROM passes p
 		INNER JOIN apartments a ON p.apartment_id = a.id
 		WHERE a.building_id = $1
@@ -375,6 +548,122 @@ func (r *PassRepo) GetActiveByBuildingID(ctx context.Context, buildingID int64)
 			&pass.ValidFrom,
 			&pass.ValidTo,
 			&pass.Status,
+			&pass.SingleUse,
+			&pass.Dynamic,
+			&pass.QRSecret,
+			&pass.Category,
+			&pass.CompanyName,
+			&pass.Weekdays,
+			&pass.DailyFrom,
+			&pass.DailyTo,
+			&pass.IssuedBy,
+			&pass.CreatedAt,
+			&pass.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		passes = append(passes, &pass)
+	}
+
+	return passes, rows.Err()
+}
+
+// ListActiveByCarPlateLength returns the active car passes whose plate has
+// minLength to maxLength characters, of every building if buildingID is nil.
+func (r *PassRepo) ListActiveByCarPlateLength(ctx context.Context, buildingID *int64, minLength, maxLength int) ([]*domain.Pass, error) {
+	query := `
+		SELECT p.id, p.apartment_id, p.resident_id, p.car_plate, p.guest_name, p.valid_from, p.valid_to, p.status, p.single_use, p.dynamic, p.qr_secret,
+			p.category, p.company_name, p.weekdays, p.daily_from, p.daily_to, p.issued_by, p.created_at, p.updated_at
+		FROM passes p
+		INNER JOIN apartments a ON p.apartment_id = a.id
+		WHERE p.car_plate IS NOT NULL
+			AND char_length(p.car_plate) BETWEEN $1 AND $2
+			AND p.status = 'active'
+			AND p.valid_from <= $3
+			AND p.valid_to >= $3
+			AND ($4::bigint IS NULL OR a.building_id = $4)
+		ORDER BY p.created_at DESC
+	`
+
+	rows, err := r.pool.Query(ctx, query, minLength, maxLength, time.Now(), buildingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var passes []*domain.Pass
+	for rows.Next() {
+		var pass domain.Pass
+		if err := rows.Scan(
+			&pass.ID,
+			&pass.ApartmentID,
+			&pass.ResidentID,
+			&pass.CarPlate,
+			&pass.GuestName,
+			&pass.ValidFrom,
+			&pass.ValidTo,
+			&pass.Status,
+			&pass.SingleUse,
+			&pass.Dynamic,
+			&pass.QRSecret,
+			&pass.Category,
+			&pass.CompanyName,
+			&pass.Weekdays,
+			&pass.DailyFrom,
+			&pass.DailyTo,
+			&pass.IssuedBy,
+			&pass.CreatedAt,
+			&pass.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		passes = append(passes, &pass)
+	}
+
+	return passes, rows.Err()
+}
+
+// ListContractorPasses returns contractor and service passes that have not
+// ended yet, of every building if buildingID is nil.
+func (r *PassRepo) ListContractorPasses(ctx context.Context, buildingID *int64) ([]*domain.Pass, error) {
+	query := `
+		SELECT p.id, p.apartment_id, p.resident_id, p.car_plate, p.guest_name, p.valid_from, p.valid_to, p.status, p.single_use, p.dynamic, p.qr_secret,
+			p.category, p.company_name, p.weekdays, p.daily_from, p.daily_to, p.issued_by, p.created_at, p.updated_at
+		FROM passes p
+		INNER JOIN apartments a ON p.apartment_id = a.id
+		WHERE p.category IN ('contractor', 'service')
+			AND p.valid_to >= $1
+			AND ($2::bigint IS NULL OR a.building_id = $2)
+		ORDER BY p.valid_from, p.created_at DESC
+	`
+
+	rows, err := r.pool.Query(ctx, query, time.Now(), buildingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var passes []*domain.Pass
+	for rows.Next() {
+		var pass domain.Pass
+		if err := rows.Scan(
+			&pass.ID,
+			&pass.ApartmentID,
+			&pass.ResidentID,
+			&pass.CarPlate,
+			&pass.GuestName,
+			&pass.ValidFrom,
+			&pass.ValidTo,
+			&pass.Status,
+			&pass.SingleUse,
+			&pass.Dynamic,
+			&pass.QRSecret,
+			&pass.Category,
+			&pass.CompanyName,
+			&pass.Weekdays,
+			&pass.DailyFrom,
+			&pass.DailyTo,
+			&pass.IssuedBy,
 			&pass.CreatedAt,
 			&pass.UpdatedAt,
 		); err != nil {
@@ -0,0 +1,176 @@
+package repo
+
+import (
+	"context"
+
+	"yardpass/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type PassExceptionRepo struct {
+	*PostgresRepo
+}
+
+func NewPassExceptionRepo(repo *PostgresRepo) *PassExceptionRepo {
+	return &PassExceptionRepo{repo}
+}
+
+func (r *PassExceptionRepo) Create(ctx context.Context, exception *domain.PassException) error {
+	query := `
+		INSERT INTO pass_exceptions (apartment_id, resident_id, car_plate, guest_name, category, valid_from, valid_to, rule, reason, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, created_at, updated_at
+	`
+
+	return r.pool.QueryRow(ctx, query,
+		exception.ApartmentID,
+		exception.ResidentID,
+		exception.CarPlate,
+		exception.GuestName,
+		exception.Category,
+		exception.ValidFrom,
+		exception.ValidTo,
+		exception.Rule,
+		exception.Reason,
+		exception.Status,
+	).Scan(&exception.ID, &exception.CreatedAt, &exception.UpdatedAt)
+}
+
+func (r *PassExceptionRepo) GetByID(ctx context.Context, id int64) (*domain.PassException, error) {
+	query := `
+		SELECT e.id, e.apartment_id, a.building_id, e.resident_id, e.car_plate, e.guest_name, e.category,
+			e.valid_from, e.valid_to, e.rule, e.reason, e.status, e.decided_by, e.decided_at, e.comment, e.pass_id,
+			e.created_at, e.updated_at
+		FROM pass_exceptions e
+		INNER JOIN apartments a ON e.apartment_id = a.id
+		WHERE e.id = $1
+	`
+
+	exception, err := scanPassException(r.pool.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return exception, nil
+}
+
+func (r *PassExceptionRepo) List(ctx context.Context, buildingID *int64, status *string) ([]*domain.PassException, error) {
+	query := `
+		SELECT e.id, e.apartment_id, a.building_id, e.resident_id, e.car_plate, e.guest_name, e.category,
+			e.valid_from, e.valid_to, e.rule, e.reason, e.status, e.decided_by, e.decided_at, e.comment, e.pass_id,
+			e.created_at, e.updated_at
+		FROM pass_exceptions e
+		INNER JOIN apartments a ON e.apartment_id = a.id
+		WHERE ($1::bigint IS NULL OR a.building_id = $1)
+			AND ($2::varchar IS NULL OR e.status = $2)
+		ORDER BY e.created_at
+	`
+
+	return r.list(ctx, query, buildingID, status)
+}
+
+func (r *PassExceptionRepo) CountPendingByResident(ctx context.Context, residentID int64) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM pass_exceptions
+		WHERE resident_id = $1 AND status = 'pending'
+	`
+
+	var count int
+	err := r.pool.QueryRow(ctx, query, residentID).Scan(&count)
+	return count, err
+}
+
+func (r *PassExceptionRepo) Resolve(ctx context.Context, id int64, status string, decidedBy int64, comment *string, passID *uuid.UUID) (bool, error) {
+	query := `
+		UPDATE pass_exceptions
+		SET status = $2, decided_by = $3, comment = $4, pass_id = $5, decided_at = NOW()
+		WHERE id = $1 AND status = 'pending'
+	`
+
+	tag, err := r.pool.Exec(ctx, query, id, status, decidedBy, comment, passID)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *PassExceptionRepo) ClaimUndelivered(ctx context.Context, limit int) ([]*domain.PassException, error) {
+	query := `
+		WITH claimed AS (
+			UPDATE pass_exceptions
+			SET resident_notified_at = NOW()
+			WHERE id IN (
+				SELECT id
+				FROM pass_exceptions
+				WHERE status <> 'pending' AND resident_notified_at IS NULL
+				ORDER BY decided_at
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING *
+		)
+		SELECT e.id, e.apartment_id, a.building_id, e.resident_id, e.car_plate, e.guest_name, e.category,
+			e.valid_from, e.valid_to, e.rule, e.reason, e.status, e.decided_by, e.decided_at, e.comment, e.pass_id,
+			e.created_at, e.updated_at
+		FROM claimed e
+		INNER JOIN apartments a ON e.apartment_id = a.id
+		ORDER BY e.decided_at
+	`
+
+	return r.list(ctx, query, limit)
+}
+
+func (r *PassExceptionRepo) list(ctx context.Context, query string, args ...interface{}) ([]*domain.PassException, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var exceptions []*domain.PassException
+	for rows.Next() {
+		exception, err := scanPassException(rows)
+		if err != nil {
+			return nil, err
+		}
+		exceptions = append(exceptions, exception)
+	}
+
+	return exceptions, rows.Err()
+}
+
+func scanPassException(row pgx.Row) (*domain.PassException, error) {
+	var exception domain.PassException
+	err := row.Scan(
+		&exception.ID,
+		&exception.ApartmentID,
+		&exception.BuildingID,
+		&exception.ResidentID,
+		&exception.CarPlate,
+		&exception.GuestName,
+		&exception.Category,
+		&exception.ValidFrom,
+		&exception.ValidTo,
+		&exception.Rule,
+		&exception.Reason,
+		&exception.Status,
+		&exception.DecidedBy,
+		&exception.DecidedAt,
+		&exception.Comment,
+		&exception.PassID,
+		&exception.CreatedAt,
+		&exception.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &exception, nil
+}
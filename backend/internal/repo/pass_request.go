@@ -0,0 +1,103 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"yardpass/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type PassRequestRepo struct {
+	*PostgresRepo
+}
+
+func NewPassRequestRepo(repo *PostgresRepo) *PassRequestRepo {
+	return &PassRequestRepo{repo}
+}
+
+func (r *PassRequestRepo) Create(ctx context.Context, request *domain.PassRequest) error {
+	query := `
+		INSERT INTO pass_requests (apartment_id, guest_telegram_id, guest_chat_id, guest_name, guest_language, car_plate, valid_from, duration_minutes, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at, updated_at
+	`
+
+	return r.pool.QueryRow(ctx, query,
+		request.ApartmentID,
+		request.GuestTelegramID,
+		request.GuestChatID,
+		request.GuestName,
+		request.GuestLanguage,
+		request.CarPlate,
+		request.ValidFrom,
+		request.DurationMinutes,
+		request.Status,
+	).Scan(&request.ID, &request.CreatedAt, &request.UpdatedAt)
+}
+
+func (r *PassRequestRepo) GetByID(ctx context.Context, id int64) (*domain.PassRequest, error) {
+	query := `
+		SELECT id, apartment_id, guest_telegram_id, guest_chat_id, guest_name, guest_language, car_plate,
+			valid_from, duration_minutes, status, decided_by, decided_at, pass_id, created_at, updated_at
+		FROM pass_requests
+		WHERE id = $1
+	`
+
+	var request domain.PassRequest
+	err := r.pool.QueryRow(ctx, query, id).Scan(
+		&request.ID,
+		&request.ApartmentID,
+		&request.GuestTelegramID,
+		&request.GuestChatID,
+		&request.GuestName,
+		&request.GuestLanguage,
+		&request.CarPlate,
+		&request.ValidFrom,
+		&request.DurationMinutes,
+		&request.Status,
+		&request.DecidedBy,
+		&request.DecidedAt,
+		&request.PassID,
+		&request.CreatedAt,
+		&request.UpdatedAt,
+	)
+
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &request, nil
+}
+
+func (r *PassRequestRepo) CountRecentByGuest(ctx context.Context, guestTelegramID int64, window time.Duration) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM pass_requests
+		WHERE guest_telegram_id = $1 AND created_at > NOW() - make_interval(secs => $2)
+	`
+
+	var count int
+	err := r.pool.QueryRow(ctx, query, guestTelegramID, window.Seconds()).Scan(&count)
+	return count, err
+}
+
+func (r *PassRequestRepo) Resolve(ctx context.Context, id int64, status string, decidedBy *int64, passID *uuid.UUID) (bool, error) {
+	query := `
+		UPDATE pass_requests
+		SET status = $2, decided_by = $3, pass_id = $4, decided_at = NOW()
+		WHERE id = $1 AND status = 'pending'
+	`
+
+	tag, err := r.pool.Exec(ctx, query, id, status, decidedBy, passID)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
@@ -0,0 +1,196 @@
+package repo
+
+import (
+	"context"
+
+	"yardpass/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type PlateWatchlistRepo struct {
+	*PostgresRepo
+}
+
+func NewPlateWatchlistRepo(repo *PostgresRepo) *PlateWatchlistRepo {
+	return &PlateWatchlistRepo{repo}
+}
+
+func (r *PlateWatchlistRepo) Create(ctx context.Context, entry *domain.WatchlistEntry) error {
+	query := `
+		INSERT INTO plate_watchlist (building_id, car_plate, reason, severity, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`
+
+	return r.pool.QueryRow(ctx, query,
+		entry.BuildingID,
+		entry.CarPlate,
+		entry.Reason,
+		entry.Severity,
+		entry.CreatedBy,
+	).Scan(&entry.ID, &entry.CreatedAt, &entry.UpdatedAt)
+}
+
+func (r *PlateWatchlistRepo) GetByID(ctx context.Context, id int64) (*domain.WatchlistEntry, error) {
+	query := `
+		SELECT id, building_id, car_plate, reason, severity, created_by, created_at, updated_at
+		FROM plate_watchlist
+		WHERE id = $1
+	`
+
+	entry, err := scanWatchlistEntry(r.pool.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+func (r *PlateWatchlistRepo) GetByCarPlate(ctx context.Context, normalizedCarPlate string, buildingID *int64) (*domain.WatchlistEntry, error) {
+	query := `
+		SELECT id, building_id, car_plate, reason, severity, created_by, created_at, updated_at
+		FROM plate_watchlist
+		WHERE car_plate = $1
+			AND ($2::bigint IS NULL OR building_id = $2)
+		ORDER BY severity = 'ban' DESC, created_at
+		LIMIT 1
+	`
+
+	entry, err := scanWatchlistEntry(r.pool.QueryRow(ctx, query, normalizedCarPlate, buildingID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+func (r *PlateWatchlistRepo) List(ctx context.Context, buildingID *int64) ([]*domain.WatchlistEntry, error) {
+	query := `
+		SELECT id, building_id, car_plate, reason, severity, created_by, created_at, updated_at
+		FROM plate_watchlist
+		WHERE ($1::bigint IS NULL OR building_id = $1)
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.pool.Query(ctx, query, buildingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*domain.WatchlistEntry
+	for rows.Next() {
+		entry, err := scanWatchlistEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+func (r *PlateWatchlistRepo) Delete(ctx context.Context, id int64) error {
+	query := `DELETE FROM plate_watchlist WHERE id = $1`
+	_, err := r.pool.Exec(ctx, query, id)
+	return err
+}
+
+func (r *PlateWatchlistRepo) CreateAlert(ctx context.Context, alert *domain.PlateAlert) error {
+	query := `
+		INSERT INTO plate_alerts (watchlist_id, guard_user_id, camera_id, pass_id, admitted)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	return r.pool.QueryRow(ctx, query,
+		alert.WatchlistID,
+		alert.GuardUserID,
+		alert.CameraID,
+		alert.PassID,
+		alert.Admitted,
+	).Scan(&alert.ID, &alert.CreatedAt)
+}
+
+func (r *PlateWatchlistRepo) ClaimUnnotifiedAlerts(ctx context.Context, limit int) ([]*domain.PlateAlert, error) {
+	query := `
+		WITH claimed AS (
+			UPDATE plate_alerts
+			SET notified_at = NOW()
+			WHERE id IN (
+				SELECT id
+				FROM plate_alerts
+				WHERE notified_at IS NULL
+				ORDER BY created_at
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING *
+		)
+		SELECT a.id, a.watchlist_id, w.building_id, w.car_plate, w.reason, w.severity,
+			a.guard_user_id, u.username, a.camera_id, c.name, a.pass_id, a.admitted, a.created_at
+		FROM claimed a
+		INNER JOIN plate_watchlist w ON a.watchlist_id = w.id
+		LEFT JOIN users u ON a.guard_user_id = u.id
+		LEFT JOIN gate_cameras c ON a.camera_id = c.id
+		ORDER BY a.created_at
+	`
+
+	rows, err := r.pool.Query(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var alerts []*domain.PlateAlert
+	for rows.Next() {
+		var alert domain.PlateAlert
+		err := rows.Scan(
+			&alert.ID,
+			&alert.WatchlistID,
+			&alert.BuildingID,
+			&alert.CarPlate,
+			&alert.Reason,
+			&alert.Severity,
+			&alert.GuardUserID,
+			&alert.GuardUsername,
+			&alert.CameraID,
+			&alert.CameraName,
+			&alert.PassID,
+			&alert.Admitted,
+			&alert.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, &alert)
+	}
+
+	return alerts, rows.Err()
+}
+
+func scanWatchlistEntry(row pgx.Row) (*domain.WatchlistEntry, error) {
+	var entry domain.WatchlistEntry
+	err := row.Scan(
+		&entry.ID,
+		&entry.BuildingID,
+		&entry.CarPlate,
+		&entry.Reason,
+		&entry.Severity,
+		&entry.CreatedBy,
+		&entry.CreatedAt,
+		&entry.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
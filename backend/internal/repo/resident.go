@@ -19,7 +19,7 @@ func NewResidentRepo(repo *PostgresRepo) *ResidentRepo {
 
 func (r *ResidentRepo) GetByID(ctx context.Context, id int64) (*domain.Resident, error) {
 	query := `
-		SELECT id, apartment_id, telegram_id, chat_id, name, phone, status, created_at, updated_at
+		SELECT id, apartment_id, telegram_id, chat_id, name, phone, status, role, can_issue_passes, invited_by, language, created_at, updated_at
 		FROM residents
 		WHERE id = $1
 	`
@@ -33,6 +33,10 @@ func (r *ResidentRepo) GetByID(ctx context.Context, id int64) (*domain.Resident,
 		&resident.Name,
 		&resident.Phone,
 		&resident.Status,
+		&resident.Role,
+		&resident.CanIssuePasses,
+		&resident.InvitedBy,
+		&resident.Language,
 		&resident.CreatedAt,
 		&resident.UpdatedAt,
 	)
@@ -47,15 +51,55 @@ func (r *ResidentRepo) GetByID(ctx context.Context, id int64) (*domain.Resident,
 	return &resident, nil
 }
 
-func (r *ResidentRepo) GetByTelegramID(ctx context.Context, telegramID int64) (*domain.Resident, error) {
+func (r *ResidentRepo) ListByTelegramID(ctx context.Context, telegramID int64) ([]*domain.Resident, error) {
 	query := `
-		SELECT id, apartment_id, telegram_id, chat_id, name, phone, status, created_at, updated_at
+		SELECT id, apartment_id, telegram_id, chat_id, name, phone, status, role, can_issue_passes, invited_by, language, created_at, updated_at
 		FROM residents
-		WHERE telegram_id = $1
+		WHERE telegram_id = $1 AND status = 'active'
+		ORDER BY created_at
+	`
+
+	rows, err := r.pool.Query(ctx, query, telegramID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var residents []*domain.Resident
+	for rows.Next() {
+		var resident domain.Resident
+		if err := rows.Scan(
+			&resident.ID,
+			&resident.ApartmentID,
+			&resident.TelegramID,
+			&resident.ChatID,
+			&resident.Name,
+			&resident.Phone,
+			&resident.Status,
+			&resident.Role,
+			&resident.CanIssuePasses,
+			&resident.InvitedBy,
+			&resident.Language,
+			&resident.CreatedAt,
+			&resident.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		residents = append(residents, &resident)
+	}
+
+	return residents, rows.Err()
+}
+
+func (r *ResidentRepo) GetByTelegramIDAndApartmentID(ctx context.Context, telegramID, apartmentID int64) (*domain.Resident, error) {
+	query := `
+		SELECT id, apartment_id, telegram_id, chat_id, name, phone, status, role, can_issue_passes, invited_by, language, created_at, updated_at
+		FROM residents
+		WHERE telegram_id = $1 AND apartment_id = $2
 	`
 
 	var resident domain.Resident
-	err := r.pool.QueryRow(ctx, query, telegramID).Scan(
+	err := r.pool.QueryRow(ctx, query, telegramID, apartmentID).Scan(
 		&resident.ID,
 		&resident.ApartmentID,
 		&resident.TelegramID,
@@ -63,6 +107,10 @@ func (r *ResidentRepo) GetByTelegramID(ctx context.Context, telegramID int64) (*
 		&resident.Name,
 		&resident.Phone,
 		&resident.Status,
+		&resident.Role,
+		&resident.CanIssuePasses,
+		&resident.InvitedBy,
+		&resident.Language,
 		&resident.CreatedAt,
 		&resident.UpdatedAt,
 	)
@@ -79,8 +127,8 @@ func (r *ResidentRepo) GetByTelegramID(ctx context.Context, telegramID int64) (*
 
 func (r *ResidentRepo) Create(ctx context.Context, resident *domain.Resident) error {
 	query := `
-		INSERT INTO residents (apartment_id, telegram_id, chat_id, name, phone, status)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO residents (apartment_id, telegram_id, chat_id, name, phone, status, role, can_issue_passes, invited_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		RETURNING id, created_at, updated_at
 	`
 
@@ -91,6 +139,9 @@ func (r *ResidentRepo) Create(ctx context.Context, resident *domain.Resident) er
 		resident.Name,
 		resident.Phone,
 		resident.Status,
+		resident.Role,
+		resident.CanIssuePasses,
+		resident.InvitedBy,
 	).Scan(&resident.ID, &resident.CreatedAt, &resident.UpdatedAt)
 
 	return err
@@ -99,7 +150,8 @@ func (r *ResidentRepo) Create(ctx context.Context, resident *domain.Resident) er
 func (r *ResidentRepo) Update(ctx context.Context, resident *domain.Resident) error {
 	query := `
 		UPDATE residents
-		SET apartment_id = $2, telegram_id = $3, chat_id = $4, name = $5, phone = $6, status = $7
+		SET apartment_id = $2, telegram_id = $3, chat_id = $4, name = $5, phone = $6, status = $7,
+			role = $8, can_issue_passes = $9, invited_by = $10
 		WHERE id = $1
 		RETURNING updated_at
 	`
@@ -112,9 +164,43 @@ func (r *ResidentRepo) Update(ctx context.Context, resident *domain.Resident) er
 		resident.Name,
 		resident.Phone,
 		resident.Status,
+		resident.Role,
+		resident.CanIssuePasses,
+		resident.InvitedBy,
 	).Scan(&resident.UpdatedAt)
 }
 
+func (r *ResidentRepo) Deactivate(ctx context.Context, id int64) (int, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `UPDATE residents SET status = 'inactive' WHERE id = $1`, id); err != nil {
+		return 0, err
+	}
+
+	tag, err := tx.Exec(ctx, `UPDATE passes SET status = 'revoked' WHERE resident_id = $1 AND status = 'active'`, id)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+// SetLanguageByTelegramID stores the bot language for every resident row of
+// the Telegram user. A nil language means "as in Telegram".
+func (r *ResidentRepo) SetLanguageByTelegramID(ctx context.Context, telegramID int64, language *string) error {
+	query := `UPDATE residents SET language = $2 WHERE telegram_id = $1`
+	_, err := r.pool.Exec(ctx, query, telegramID, language)
+	return err
+}
+
 func (r *ResidentRepo) BulkCreate(ctx context.Context, residents []*domain.Resident) error {
 	if len(residents) == 0 {
 		return nil
@@ -127,10 +213,9 @@ func (r *ResidentRepo) BulkCreate(ctx context.Context, residents []*domain.Resid
 	defer tx.Rollback(ctx)
 
 	query := `
-		INSERT INTO residents (apartment_id, telegram_id, chat_id, name, phone, status)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		ON CONFLICT (telegram_id) DO UPDATE SET
-			apartment_id = EXCLUDED.apartment_id,
+		INSERT INTO residents (apartment_id, telegram_id, chat_id, name, phone, status, role, can_issue_passes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (telegram_id, apartment_id) DO UPDATE SET
 			chat_id = EXCLUDED.chat_id,
 			name = EXCLUDED.name,
 			phone = EXCLUDED.phone,
@@ -146,6 +231,8 @@ func (r *ResidentRepo) BulkCreate(ctx context.Context, residents []*domain.Resid
 			resident.Name,
 			resident.Phone,
 			resident.Status,
+			resident.Role,
+			resident.CanIssuePasses,
 		).Scan(&resident.ID, &resident.CreatedAt, &resident.UpdatedAt)
 		if err != nil {
 			return err
@@ -157,7 +244,7 @@ func (r *ResidentRepo) BulkCreate(ctx context.Context, residents []*domain.Resid
 
 func (r *ResidentRepo) List(ctx context.Context, filters domain.ResidentFilters) ([]*domain.Resident, error) {
 	query := `
-		SELECT id, apartment_id, telegram_id, chat_id, name, phone, status, created_at, updated_at
+		SELECT id, apartment_id, telegram_id, chat_id, name, phone, status, role, can_issue_passes, invited_by, language, created_at, updated_at
 		FROM residents
 		WHERE 1=1
 	`
@@ -182,6 +269,12 @@ func (r *ResidentRepo) List(ctx context.Context, filters domain.ResidentFilters)
 		argPos++
 	}
 
+	if filters.Role != nil {
+		query += fmt.Sprintf(` AND role = $%d`, argPos)
+		args = append(args, *filters.Role)
+		argPos++
+	}
+
 	query += ` ORDER BY created_at DESC`
 
 	if filters.Limit > 0 {
@@ -212,6 +305,10 @@ func (r *ResidentRepo) List(ctx context.Context, filters domain.ResidentFilters)
 			&resident.Name,
 			&resident.Phone,
 			&resident.Status,
+			&resident.Role,
+			&resident.CanIssuePasses,
+			&resident.InvitedBy,
+			&resident.Language,
 			&resident.CreatedAt,
 			&resident.UpdatedAt,
 		); err != nil {
@@ -0,0 +1,153 @@
+package repo
+
+import (
+	"context"
+
+	"yardpass/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type ResidentVehicleRepo struct {
+	*PostgresRepo
+}
+
+func NewResidentVehicleRepo(repo *PostgresRepo) *ResidentVehicleRepo {
+	return &ResidentVehicleRepo{repo}
+}
+
+func (r *ResidentVehicleRepo) Create(ctx context.Context, vehicle *domain.ResidentVehicle) error {
+	query := `
+		INSERT INTO resident_vehicles (apartment_id, car_plate, label, resident_id, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`
+
+	return r.pool.QueryRow(ctx, query,
+		vehicle.ApartmentID,
+		vehicle.CarPlate,
+		vehicle.Label,
+		vehicle.ResidentID,
+		vehicle.CreatedBy,
+	).Scan(&vehicle.ID, &vehicle.CreatedAt, &vehicle.UpdatedAt)
+}
+
+func (r *ResidentVehicleRepo) GetByID(ctx context.Context, id int64) (*domain.ResidentVehicle, error) {
+	query := `
+		SELECT v.id, v.apartment_id, a.building_id, v.car_plate, v.label, v.resident_id, v.created_by, v.created_at, v.updated_at
+		FROM resident_vehicles v
+		INNER JOIN apartments a ON v.apartment_id = a.id
+		WHERE v.id = $1
+	`
+
+	vehicle, err := scanResidentVehicle(r.pool.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return vehicle, nil
+}
+
+func (r *ResidentVehicleRepo) GetByCarPlate(ctx context.Context, normalizedCarPlate string, buildingID *int64) (*domain.ResidentVehicle, error) {
+	query := `
+		SELECT v.id, v.apartment_id, a.building_id, v.car_plate, v.label, v.resident_id, v.created_by, v.created_at, v.updated_at
+		FROM resident_vehicles v
+		INNER JOIN apartments a ON v.apartment_id = a.id
+		WHERE v.car_plate = $1
+			AND ($2::bigint IS NULL OR a.building_id = $2)
+		ORDER BY v.created_at
+		LIMIT 1
+	`
+
+	vehicle, err := scanResidentVehicle(r.pool.QueryRow(ctx, query, normalizedCarPlate, buildingID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return vehicle, nil
+}
+
+func (r *ResidentVehicleRepo) List(ctx context.Context, buildingID *int64, apartmentID *int64) ([]*domain.ResidentVehicle, error) {
+	query := `
+		SELECT v.id, v.apartment_id, a.building_id, v.car_plate, v.label, v.resident_id, v.created_by, v.created_at, v.updated_at
+		FROM resident_vehicles v
+		INNER JOIN apartments a ON v.apartment_id = a.id
+		WHERE ($1::bigint IS NULL OR a.building_id = $1)
+			AND ($2::bigint IS NULL OR v.apartment_id = $2)
+		ORDER BY a.number, v.created_at
+	`
+
+	rows, err := r.pool.Query(ctx, query, buildingID, apartmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var vehicles []*domain.ResidentVehicle
+	for rows.Next() {
+		vehicle, err := scanResidentVehicle(rows)
+		if err != nil {
+			return nil, err
+		}
+		vehicles = append(vehicles, vehicle)
+	}
+
+	return vehicles, rows.Err()
+}
+
+func (r *ResidentVehicleRepo) CountByApartmentID(ctx context.Context, apartmentID int64) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM resident_vehicles
+		WHERE apartment_id = $1
+	`
+
+	var count int
+	err := r.pool.QueryRow(ctx, query, apartmentID).Scan(&count)
+	return count, err
+}
+
+func (r *ResidentVehicleRepo) CountByBuildingID(ctx context.Context, buildingID int64) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM resident_vehicles v
+		INNER JOIN apartments a ON v.apartment_id = a.id
+		WHERE a.building_id = $1
+	`
+
+	var count int
+	err := r.pool.QueryRow(ctx, query, buildingID).Scan(&count)
+	return count, err
+}
+
+func (r *ResidentVehicleRepo) Delete(ctx context.Context, id int64) error {
+	query := `DELETE FROM resident_vehicles WHERE id = $1`
+	_, err := r.pool.Exec(ctx, query, id)
+	return err
+}
+
+func scanResidentVehicle(row pgx.Row) (*domain.ResidentVehicle, error) {
+	var vehicle domain.ResidentVehicle
+	err := row.Scan(
+		&vehicle.ID,
+		&vehicle.ApartmentID,
+		&vehicle.BuildingID,
+		&vehicle.CarPlate,
+		&vehicle.Label,
+		&vehicle.ResidentID,
+		&vehicle.CreatedBy,
+		&vehicle.CreatedAt,
+		&vehicle.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vehicle, nil
+}
@@ -19,7 +19,7 @@ func NewRuleRepo(repo *PostgresRepo) *RuleRepo {
 func (r *RuleRepo) GetByBuildingID(ctx context.Context, buildingID int64) (*domain.Rule, error) {
 	query := `
 		SELECT id, building_id, quiet_hours_start, quiet_hours_end,
-		       daily_pass_limit_per_apartment, max_pass_duration_hours, created_at, updated_at
+		       daily_pass_limit_per_apartment, max_pass_duration_hours, max_contractor_pass_days, created_at, updated_at
 		FROM rules
 		WHERE building_id = $1
 	`
@@ -32,6 +32,7 @@ func (r *RuleRepo) GetByBuildingID(ctx context.Context, buildingID int64) (*doma
 		&rule.QuietHoursEnd,
 		&rule.DailyPassLimitPerApartment,
 		&rule.MaxPassDurationHours,
+		&rule.MaxContractorPassDays,
 		&rule.CreatedAt,
 		&rule.UpdatedAt,
 	)
@@ -43,42 +44,138 @@ func (r *RuleRepo) GetByBuildingID(ctx context.Context, buildingID int64) (*doma
 		return nil, err
 	}
 
+	rule.Categories, err = r.categoryRules(ctx, buildingID)
+	if err != nil {
+		return nil, err
+	}
+
 	return &rule, nil
 }
 
+func (r *RuleRepo) categoryRules(ctx context.Context, buildingID int64) ([]domain.CategoryRule, error) {
+	query := `
+		SELECT category, max_duration_hours, daily_limit, allowed_from, allowed_to
+		FROM category_rules
+		WHERE building_id = $1
+		ORDER BY category
+	`
+
+	rows, err := r.pool.Query(ctx, query, buildingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := []domain.CategoryRule{}
+	for rows.Next() {
+		var cr domain.CategoryRule
+		if err := rows.Scan(
+			&cr.Category,
+			&cr.MaxDurationHours,
+			&cr.DailyLimit,
+			&cr.AllowedFrom,
+			&cr.AllowedTo,
+		); err != nil {
+			return nil, err
+		}
+		categories = append(categories, cr)
+	}
+
+	return categories, rows.Err()
+}
+
+// Create stores the rules with their category limits in one transaction.
 func (r *RuleRepo) Create(ctx context.Context, rule *domain.Rule) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
 	query := `
 		INSERT INTO rules (building_id, quiet_hours_start, quiet_hours_end,
-		                   daily_pass_limit_per_apartment, max_pass_duration_hours)
-		VALUES ($1, $2, $3, $4, $5)
+		                   daily_pass_limit_per_apartment, max_pass_duration_hours, max_contractor_pass_days)
+		VALUES ($1, $2, $3, $4, $5, $6)
 		RETURNING id, created_at, updated_at
 	`
 
-	err := r.pool.QueryRow(ctx, query,
+	err = tx.QueryRow(ctx, query,
 		rule.BuildingID,
 		rule.QuietHoursStart,
 		rule.QuietHoursEnd,
 		rule.DailyPassLimitPerApartment,
 		rule.MaxPassDurationHours,
+		rule.MaxContractorPassDays,
 	).Scan(&rule.ID, &rule.CreatedAt, &rule.UpdatedAt)
+	if err != nil {
+		return err
+	}
 
-	return err
+	if err := replaceCategoryRules(ctx, tx, rule); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
+// Update stores the rules and replaces their category limits with
+// rule.Categories in one transaction.
 func (r *RuleRepo) Update(ctx context.Context, rule *domain.Rule) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
 	query := `
 		UPDATE rules
 		SET quiet_hours_start = $2, quiet_hours_end = $3,
-		    daily_pass_limit_per_apartment = $4, max_pass_duration_hours = $5
+		    daily_pass_limit_per_apartment = $4, max_pass_duration_hours = $5,
+		    max_contractor_pass_days = $6
 		WHERE id = $1
 		RETURNING updated_at
 	`
 
-	return r.pool.QueryRow(ctx, query,
+	err = tx.QueryRow(ctx, query,
 		rule.ID,
 		rule.QuietHoursStart,
 		rule.QuietHoursEnd,
 		rule.DailyPassLimitPerApartment,
 		rule.MaxPassDurationHours,
+		rule.MaxContractorPassDays,
 	).Scan(&rule.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := replaceCategoryRules(ctx, tx, rule); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func replaceCategoryRules(ctx context.Context, tx pgx.Tx, rule *domain.Rule) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM category_rules WHERE building_id = $1`, rule.BuildingID); err != nil {
+		return err
+	}
+
+	for _, cr := range rule.Categories {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO category_rules (building_id, category, max_duration_hours, daily_limit, allowed_from, allowed_to)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`,
+			rule.BuildingID,
+			cr.Category,
+			cr.MaxDurationHours,
+			cr.DailyLimit,
+			cr.AllowedFrom,
+			cr.AllowedTo,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,135 @@
+package repo
+
+import (
+	"context"
+
+	"yardpass/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type SavedGuestRepo struct {
+	*PostgresRepo
+}
+
+func NewSavedGuestRepo(repo *PostgresRepo) *SavedGuestRepo {
+	return &SavedGuestRepo{repo}
+}
+
+func (r *SavedGuestRepo) GetByID(ctx context.Context, id int64) (*domain.SavedGuest, error) {
+	query := `
+		SELECT id, resident_id, car_plate, guest_name, starred, use_count, last_used_at, created_at, updated_at
+		FROM saved_guests
+		WHERE id = $1
+	`
+
+	var guest domain.SavedGuest
+	err := r.pool.QueryRow(ctx, query, id).Scan(
+		&guest.ID,
+		&guest.ResidentID,
+		&guest.CarPlate,
+		&guest.GuestName,
+		&guest.Starred,
+		&guest.UseCount,
+		&guest.LastUsedAt,
+		&guest.CreatedAt,
+		&guest.UpdatedAt,
+	)
+
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &guest, nil
+}
+
+// ListByResidentID returns starred guests first, then the most recently
+// used ones. A limit of 0 returns all of them.
+func (r *SavedGuestRepo) ListByResidentID(ctx context.Context, residentID int64, limit int) ([]*domain.SavedGuest, error) {
+	query := `
+		SELECT id, resident_id, car_plate, guest_name, starred, use_count, last_used_at, created_at, updated_at
+		FROM saved_guests
+		WHERE resident_id = $1
+		ORDER BY starred DESC, last_used_at DESC, id DESC
+	`
+	args := []interface{}{residentID}
+	if limit > 0 {
+		query += " LIMIT $2"
+		args = append(args, limit)
+	}
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var guests []*domain.SavedGuest
+	for rows.Next() {
+		var guest domain.SavedGuest
+		if err := rows.Scan(
+			&guest.ID,
+			&guest.ResidentID,
+			&guest.CarPlate,
+			&guest.GuestName,
+			&guest.Starred,
+			&guest.UseCount,
+			&guest.LastUsedAt,
+			&guest.CreatedAt,
+			&guest.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		guests = append(guests, &guest)
+	}
+
+	return guests, rows.Err()
+}
+
+// Touch records a pass for the guest, creating the entry on first use. A nil
+// guest name keeps the remembered one.
+func (r *SavedGuestRepo) Touch(ctx context.Context, residentID int64, carPlate string, guestName *string) error {
+	query := `
+		INSERT INTO saved_guests (resident_id, car_plate, guest_name, use_count, last_used_at)
+		VALUES ($1, $2, $3, 1, NOW())
+		ON CONFLICT (resident_id, car_plate) DO UPDATE
+		SET guest_name = COALESCE(EXCLUDED.guest_name, saved_guests.guest_name),
+		    use_count = saved_guests.use_count + 1,
+		    last_used_at = NOW()
+	`
+
+	_, err := r.pool.Exec(ctx, query, residentID, carPlate, guestName)
+	return err
+}
+
+func (r *SavedGuestRepo) SetStarred(ctx context.Context, id int64, starred bool) error {
+	query := `UPDATE saved_guests SET starred = $2 WHERE id = $1`
+	_, err := r.pool.Exec(ctx, query, id, starred)
+	return err
+}
+
+func (r *SavedGuestRepo) Delete(ctx context.Context, id int64) error {
+	query := `DELETE FROM saved_guests WHERE id = $1`
+	_, err := r.pool.Exec(ctx, query, id)
+	return err
+}
+
+// PruneRecent deletes all but the keep most recently used non-starred
+// guests of the resident.
+func (r *SavedGuestRepo) PruneRecent(ctx context.Context, residentID int64, keep int) error {
+	query := `
+		DELETE FROM saved_guests
+		WHERE id IN (
+			SELECT id FROM saved_guests
+			WHERE resident_id = $1 AND NOT starred
+			ORDER BY last_used_at DESC, id DESC
+			OFFSET $2
+		)
+	`
+
+	_, err := r.pool.Exec(ctx, query, residentID, keep)
+	return err
+}
@@ -18,14 +18,16 @@ func NewScanEventRepo(repo *PostgresRepo) *ScanEventRepo {
 
 func (r *ScanEventRepo) Create(ctx context.Context, event *domain.ScanEvent) error {
 	query := `
-		INSERT INTO scan_events (pass_id, guard_user_id, scanned_at, result, reason, meta)
-		VALUES ($1, $2, $3, $4, $5, $6::jsonb)
+		INSERT INTO scan_events (pass_id, apartment_id, guard_user_id, camera_id, scanned_at, result, reason, meta)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8::jsonb)
 		RETURNING id
 	`
 
 	err := r.pool.QueryRow(ctx, query,
 		event.PassID,
+		event.ApartmentID,
 		event.GuardUserID,
+		event.CameraID,
 		event.ScannedAt,
 		event.Result,
 		event.Reason,
@@ -37,7 +39,7 @@ func (r *ScanEventRepo) Create(ctx context.Context, event *domain.ScanEvent) err
 
 func (r *ScanEventRepo) List(ctx context.Context, filters domain.ScanEventFilters) ([]*domain.ScanEvent, error) {
 	query := `
-		SELECT id, pass_id, guard_user_id, scanned_at, result, reason, meta
+		SELECT id, pass_id, apartment_id, guard_user_id, camera_id, scanned_at, result, reason, meta
 		FROM scan_events
 		WHERE 1=1
 	`
@@ -99,7 +101,9 @@ func (r *ScanEventRepo) List(ctx context.Context, filters domain.ScanEventFilter
 		if err := rows.Scan(
 			&event.ID,
 			&event.PassID,
+			&event.ApartmentID,
 			&event.GuardUserID,
+			&event.CameraID,
 			&event.ScannedAt,
 			&event.Result,
 			&event.Reason,
@@ -133,10 +137,12 @@ func (r *ScanEventRepo) GetStatistics(ctx context.Context, from, to *time.Time,
 			COUNT(*) FILTER (WHERE result = 'valid') as valid_scans,
 			COUNT(*) FILTER (WHERE result = 'invalid') as invalid_scans,
 			COUNT(DISTINCT pass_id) as unique_passes,
-			COUNT(DISTINCT guard_user_id) as unique_guards
+			COUNT(DISTINCT guard_user_id) as unique_guards,
+			COUNT(*) FILTER (WHERE p.category IN ('contractor', 'service')) as contractor_scans
 		FROM scan_events se
-		INNER JOIN passes p ON se.pass_id = p.id
-		INNER JOIN apartments a ON p.apartment_id = a.id
+		LEFT JOIN passes p ON se.pass_id = p.id
+		LEFT JOIN apartments a ON a.id = COALESCE(p.apartment_id, se.apartment_id)
+		LEFT JOIN gate_cameras gc ON gc.id = se.camera_id
 		WHERE 1=1
 	`
 	args := []interface{}{}
@@ -154,8 +160,10 @@ func (r *ScanEventRepo) GetStatistics(ctx context.Context, from, to *time.Time,
 		argPos++
 	}
 
+	// Camera scans of unknown plates have no apartment; the camera tells
+	// the building.
 	if buildingID != nil {
-		query += fmt.Sprintf(` AND a.building_id = $%d`, argPos)
+		query += fmt.Sprintf(` AND COALESCE(a.building_id, gc.building_id) = $%d`, argPos)
 		args = append(args, *buildingID)
 		argPos++
 	}
@@ -167,6 +175,7 @@ func (r *ScanEventRepo) GetStatistics(ctx context.Context, from, to *time.Time,
 		&stats.InvalidScans,
 		&stats.UniquePasses,
 		&stats.UniqueGuards,
+		&stats.ContractorScans,
 	)
 
 	return &stats, err
@@ -176,19 +185,21 @@ func (r *ScanEventRepo) GetEventsWithDetails(ctx context.Context, filters domain
 	query := `
 		SELECT
 			se.id, se.pass_id, se.guard_user_id, se.scanned_at, se.result, se.reason, se.meta,
-			p.car_plate, a.number as apartment_number, a.building_id,
-			u.username as guard_username
+			COALESCE(p.car_plate, se.meta->>'car_plate'), p.category, COALESCE(a.number, '') as apartment_number,
+			COALESCE(a.building_id, gc.building_id),
+			COALESCE(u.username, se.meta->'camera'->>'name') as guard_username
 		FROM scan_events se
-		INNER JOIN passes p ON se.pass_id = p.id
-		INNER JOIN apartments a ON p.apartment_id = a.id
+		LEFT JOIN passes p ON se.pass_id = p.id
+		LEFT JOIN apartments a ON a.id = COALESCE(p.apartment_id, se.apartment_id)
+		LEFT JOIN gate_cameras gc ON gc.id = se.camera_id
 		LEFT JOIN users u ON se.guard_user_id = u.id
-		WHERE 1=1
+		WHERE COALESCE(a.building_id, gc.building_id) IS NOT NULL
 	`
 	args := []interface{}{}
 	argPos := 1
 
 	if buildingID != nil {
-		query += fmt.Sprintf(` AND a.building_id = $%d`, argPos)
+		query += fmt.Sprintf(` AND COALESCE(a.building_id, gc.building_id) = $%d`, argPos)
 		args = append(args, *buildingID)
 		argPos++
 	}
@@ -256,6 +267,7 @@ func (r *ScanEventRepo) GetEventsWithDetails(ctx context.Context, filters domain
 			&event.Reason,
 			&event.Meta,
 			&carPlate,
+			&event.PassCategory,
 			&event.ApartmentNumber,
 			&event.BuildingID,
 			&guardUsername,
@@ -19,7 +19,7 @@ func NewUserRepo(repo *PostgresRepo) *UserRepo {
 
 func (r *UserRepo) GetByID(ctx context.Context, id int64) (*domain.User, error) {
 	query := `
-		SELECT id, username, email, password_hash, role, building_id, status, created_at, updated_at
+		SELECT id, username, email, password_hash, role, building_id, telegram_id, status, created_at, updated_at
 		FROM users
 		WHERE id = $1
 	`
@@ -32,6 +32,7 @@ func (r *UserRepo) GetByID(ctx context.Context, id int64) (*domain.User, error)
 		&user.PasswordHash,
 		&user.Role,
 		&user.BuildingID,
+		&user.TelegramID,
 		&user.Status,
 		&user.CreatedAt,
 		&user.UpdatedAt,
@@ -49,7 +50,7 @@ func (r *UserRepo) GetByID(ctx context.Context, id int64) (*domain.User, error)
 
 func (r *UserRepo) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
 	query := `
-		SELECT id, username, email, password_hash, role, building_id, status, created_at, updated_at
+		SELECT id, username, email, password_hash, role, building_id, telegram_id, status, created_at, updated_at
 		FROM users
 		WHERE username = $1
 	`
@@ -62,6 +63,38 @@ func (r *UserRepo) GetByUsername(ctx context.Context, username string) (*domain.
 		&user.PasswordHash,
 		&user.Role,
 		&user.BuildingID,
+		&user.TelegramID,
+		&user.Status,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (r *UserRepo) GetByTelegramID(ctx context.Context, telegramID int64) (*domain.User, error) {
+	query := `
+		SELECT id, username, email, password_hash, role, building_id, telegram_id, status, created_at, updated_at
+		FROM users
+		WHERE telegram_id = $1
+	`
+
+	var user domain.User
+	err := r.pool.QueryRow(ctx, query, telegramID).Scan(
+		&user.ID,
+		&user.Username,
+		&user.Email,
+		&user.PasswordHash,
+		&user.Role,
+		&user.BuildingID,
+		&user.TelegramID,
 		&user.Status,
 		&user.CreatedAt,
 		&user.UpdatedAt,
@@ -99,7 +132,7 @@ func (r *UserRepo) Create(ctx context.Context, user *domain.User) error {
 func (r *UserRepo) Update(ctx context.Context, user *domain.User) error {
 	query := `
 		UPDATE users
-		SET username = $2, email = $3, password_hash = $4, role = $5, building_id = $6, status = $7
+		SET username = $2, email = $3, password_hash = $4, role = $5, building_id = $6, status = $7, telegram_id = $8
 		WHERE id = $1
 		RETURNING updated_at
 	`
@@ -112,12 +145,13 @@ func (r *UserRepo) Update(ctx context.Context, user *domain.User) error {
 		user.Role,
 		user.BuildingID,
 		user.Status,
+		user.TelegramID,
 	).Scan(&user.UpdatedAt)
 }
 
 func (r *UserRepo) List(ctx context.Context, filters domain.UserFilters) ([]*domain.User, error) {
 	query := `
-		SELECT id, username, email, password_hash, role, building_id, status, created_at, updated_at
+		SELECT id, username, email, password_hash, role, building_id, telegram_id, status, created_at, updated_at
 		FROM users
 		WHERE 1=1
 	`
@@ -171,6 +205,7 @@ func (r *UserRepo) List(ctx context.Context, filters domain.UserFilters) ([]*dom
 			&user.PasswordHash,
 			&user.Role,
 			&user.BuildingID,
+			&user.TelegramID,
 			&user.Status,
 			&user.CreatedAt,
 			&user.UpdatedAt,
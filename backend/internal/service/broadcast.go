@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"yardpass/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// maxBroadcastLength is the Telegram limit for the text of a message.
+	maxBroadcastLength = 4096
+	// maxDeliveryAttempts is how many times a recipient is tried before a
+	// transient error is recorded as a failure.
+	maxDeliveryAttempts = 3
+)
+
+var (
+	ErrBroadcastNotFound     = errors.New("broadcast not found")
+	ErrNoBroadcastRecipients = errors.New("no residents match the broadcast filters")
+)
+
+// BroadcastService queues announcements for the residents of a building.
+// The bot process delivers them in the background.
+type BroadcastService struct {
+	broadcastRepo domain.BroadcastRepository
+	buildingRepo  domain.BuildingRepository
+	apartmentRepo domain.ApartmentRepository
+	logger        *zap.Logger
+}
+
+func NewBroadcastService(
+	broadcastRepo domain.BroadcastRepository,
+	buildingRepo domain.BuildingRepository,
+	apartmentRepo domain.ApartmentRepository,
+	logger *zap.Logger,
+) *BroadcastService {
+	return &BroadcastService{
+		broadcastRepo: broadcastRepo,
+		buildingRepo:  buildingRepo,
+		apartmentRepo: apartmentRepo,
+		logger:        logger,
+	}
+}
+
+func (s *BroadcastService) CreateBroadcast(ctx context.Context, req domain.CreateBroadcastRequest) (*domain.Broadcast, error) {
+	text := strings.TrimSpace(req.Text)
+	if text == "" {
+		return nil, errors.New("broadcast text is required")
+	}
+	if utf8.RuneCountInString(text) > maxBroadcastLength {
+		return nil, fmt.Errorf("broadcast text exceeds %d characters", maxBroadcastLength)
+	}
+	if len(req.Floors) > 0 && len(req.ApartmentIDs) > 0 {
+		return nil, errors.New("filter by either floors or apartments, not both")
+	}
+
+	building, err := s.buildingRepo.GetByID(ctx, req.BuildingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get building: %w", err)
+	}
+	if building == nil {
+		return nil, errors.New("building not found")
+	}
+
+	if len(req.ApartmentIDs) > 0 {
+		apartments, err := s.apartmentRepo.GetByBuildingID(ctx, req.BuildingID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get apartments: %w", err)
+		}
+		inBuilding := make(map[int64]bool, len(apartments))
+		for _, apartment := range apartments {
+			inBuilding[apartment.ID] = true
+		}
+		for _, id := range req.ApartmentIDs {
+			if !inBuilding[id] {
+				return nil, fmt.Errorf("apartment %d does not belong to the building", id)
+			}
+		}
+	}
+
+	broadcast := &domain.Broadcast{
+		BuildingID: req.BuildingID,
+		CreatedBy:  req.CreatedBy,
+		Text:       text,
+	}
+	if len(req.Floors) > 0 {
+		broadcast.Floors = req.Floors
+	}
+	if len(req.ApartmentIDs) > 0 {
+		broadcast.ApartmentIDs = req.ApartmentIDs
+	}
+
+	if err := s.broadcastRepo.Create(ctx, broadcast); err != nil {
+		return nil, fmt.Errorf("failed to create broadcast: %w", err)
+	}
+	if broadcast.TotalRecipients == 0 {
+		return nil, ErrNoBroadcastRecipients
+	}
+
+	s.logger.Info("broadcast queued",
+		zap.Int64("broadcast_id", broadcast.ID),
+		zap.Int64("building_id", broadcast.BuildingID),
+		zap.Int("recipients", broadcast.TotalRecipients),
+	)
+
+	return broadcast, nil
+}
+
+func (s *BroadcastService) GetBroadcast(ctx context.Context, id int64) (*domain.Broadcast, error) {
+	broadcast, err := s.broadcastRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get broadcast: %w", err)
+	}
+	if broadcast == nil {
+		return nil, ErrBroadcastNotFound
+	}
+	return broadcast, nil
+}
+
+func (s *BroadcastService) ListBroadcasts(ctx context.Context, filters domain.BroadcastFilters) ([]*domain.Broadcast, error) {
+	broadcasts, err := s.broadcastRepo.List(ctx, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list broadcasts: %w", err)
+	}
+	return broadcasts, nil
+}
+
+func (s *BroadcastService) ListRecipients(ctx context.Context, broadcastID int64, filters domain.RecipientFilters) ([]*domain.BroadcastRecipient, error) {
+	recipients, err := s.broadcastRepo.ListRecipients(ctx, broadcastID, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list broadcast recipients: %w", err)
+	}
+	return recipients, nil
+}
+
+// ClaimDeliveries returns up to limit recipients the caller is now
+// responsible for reporting back on.
+func (s *BroadcastService) ClaimDeliveries(ctx context.Context, limit int) ([]*domain.BroadcastDelivery, error) {
+	deliveries, err := s.broadcastRepo.ClaimPending(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim broadcast deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// ReleaseDeliveries returns deliveries whose lease has expired without a
+// report back, left by a bot that stopped, to the queue. Deliveries another
+// bot is still sending keep their lease.
+func (s *BroadcastService) ReleaseDeliveries(ctx context.Context, lease time.Duration) error {
+	if err := s.broadcastRepo.ReleaseSending(ctx, lease); err != nil {
+		return fmt.Errorf("failed to release broadcast deliveries: %w", err)
+	}
+	return nil
+}
+
+func (s *BroadcastService) MarkDelivered(ctx context.Context, delivery *domain.BroadcastDelivery) error {
+	if err := s.broadcastRepo.MarkSent(ctx, delivery.RecipientID); err != nil {
+		return fmt.Errorf("failed to mark broadcast delivered: %w", err)
+	}
+	return nil
+}
+
+// MarkUndeliverable records a permanent failure such as a blocked bot.
+func (s *BroadcastService) MarkUndeliverable(ctx context.Context, delivery *domain.BroadcastDelivery, reason, errorText string) error {
+	if err := s.broadcastRepo.MarkFailed(ctx, delivery.RecipientID, reason, errorText); err != nil {
+		return fmt.Errorf("failed to mark broadcast failed: %w", err)
+	}
+	return nil
+}
+
+// RetryLater puts the delivery back in the queue after a transient error,
+// or records it as failed once it has used up its attempts.
+func (s *BroadcastService) RetryLater(ctx context.Context, delivery *domain.BroadcastDelivery, errorText string) error {
+	if delivery.Attempts >= maxDeliveryAttempts {
+		return s.MarkUndeliverable(ctx, delivery, domain.DeliveryFailureError, errorText)
+	}
+	if err := s.broadcastRepo.Requeue(ctx, delivery.RecipientID, errorText); err != nil {
+		return fmt.Errorf("failed to requeue broadcast delivery: %w", err)
+	}
+	return nil
+}
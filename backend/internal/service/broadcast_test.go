@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"yardpass/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type MockBroadcastRepo struct {
+	mock.Mock
+}
+
+func (m *MockBroadcastRepo) Create(ctx context.Context, broadcast *domain.Broadcast) error {
+	args := m.Called(ctx, broadcast)
+	return args.Error(0)
+}
+
+func (m *MockBroadcastRepo) GetByID(ctx context.Context, id int64) (*domain.Broadcast, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Broadcast), args.Error(1)
+}
+
+func (m *MockBroadcastRepo) List(ctx context.Context, filters domain.BroadcastFilters) ([]*domain.Broadcast, error) {
+	args := m.Called(ctx, filters)
+	return args.Get(0).([]*domain.Broadcast), args.Error(1)
+}
+
+func (m *MockBroadcastRepo) ListRecipients(ctx context.Context, broadcastID int64, filters domain.RecipientFilters) ([]*domain.BroadcastRecipient, error) {
+	args := m.Called(ctx, broadcastID, filters)
+	return args.Get(0).([]*domain.BroadcastRecipient), args.Error(1)
+}
+
+func (m *MockBroadcastRepo) ClaimPending(ctx context.Context, limit int) ([]*domain.BroadcastDelivery, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]*domain.BroadcastDelivery), args.Error(1)
+}
+
+func (m *MockBroadcastRepo) ReleaseSending(ctx context.Context, lease time.Duration) error {
+	args := m.Called(ctx, lease)
+	return args.Error(0)
+}
+
+func (m *MockBroadcastRepo) MarkSent(ctx context.Context, recipientID int64) error {
+	args := m.Called(ctx, recipientID)
+	return args.Error(0)
+}
+
+func (m *MockBroadcastRepo) MarkFailed(ctx context.Context, recipientID int64, reason, errorText string) error {
+	args := m.Called(ctx, recipientID, reason, errorText)
+	return args.Error(0)
+}
+
+func (m *MockBroadcastRepo) Requeue(ctx context.Context, recipientID int64, errorText string) error {
+	args := m.Called(ctx, recipientID, errorText)
+	return args.Error(0)
+}
+
+type MockBuildingRepo struct {
+	mock.Mock
+}
+
+func (m *MockBuildingRepo) GetByID(ctx context.Context, id int64) (*domain.Building, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Building), args.Error(1)
+}
+
+func (m *MockBuildingRepo) List(ctx context.Context) ([]*domain.Building, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*domain.Building), args.Error(1)
+}
+
+func (m *MockBuildingRepo) GetByVisitToken(ctx context.Context, token string) (*domain.Building, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Building), args.Error(1)
+}
+
+func (m *MockBuildingRepo) SetVisitToken(ctx context.Context, id int64, token string) (string, error) {
+	args := m.Called(ctx, id, token)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockBuildingRepo) ReplaceVisitToken(ctx context.Context, id int64, token string) error {
+	args := m.Called(ctx, id, token)
+	return args.Error(0)
+}
+
+func TestBroadcastService_CreateBroadcast(t *testing.T) {
+	ctx := context.Background()
+	building := &domain.Building{ID: 1, Name: "Test"}
+	apartments := []*domain.Apartment{{ID: 10, BuildingID: 1}, {ID: 11, BuildingID: 1}}
+
+	tests := []struct {
+		name       string
+		req        domain.CreateBroadcastRequest
+		recipients int
+		wantErr    error
+		errText    string
+	}{
+		{
+			name:       "whole building",
+			req:        domain.CreateBroadcastRequest{BuildingID: 1, Text: "  Water is off tomorrow  "},
+			recipients: 3,
+		},
+		{
+			name:       "selected apartments",
+			req:        domain.CreateBroadcastRequest{BuildingID: 1, Text: "Hi", ApartmentIDs: []int64{10, 11}},
+			recipients: 2,
+		},
+		{
+			name:    "empty text",
+			req:     domain.CreateBroadcastRequest{BuildingID: 1, Text: "   "},
+			errText: "text is required",
+		},
+		{
+			name:    "text too long",
+			req:     domain.CreateBroadcastRequest{BuildingID: 1, Text: strings.Repeat("я", maxBroadcastLength+1)},
+			errText: "exceeds",
+		},
+		{
+			name:    "both filters",
+			req:     domain.CreateBroadcastRequest{BuildingID: 1, Text: "Hi", Floors: []int{2}, ApartmentIDs: []int64{10}},
+			errText: "either floors or apartments",
+		},
+		{
+			name:    "apartment of another building",
+			req:     domain.CreateBroadcastRequest{BuildingID: 1, Text: "Hi", ApartmentIDs: []int64{10, 99}},
+			errText: "apartment 99",
+		},
+		{
+			name:       "nobody on the floor",
+			req:        domain.CreateBroadcastRequest{BuildingID: 1, Text: "Hi", Floors: []int{30}},
+			recipients: 0,
+			wantErr:    ErrNoBroadcastRecipients,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broadcastRepo := new(MockBroadcastRepo)
+			buildingRepo := new(MockBuildingRepo)
+			apartmentRepo := new(MockApartmentRepo)
+			service := NewBroadcastService(broadcastRepo, buildingRepo, apartmentRepo, zap.NewNop())
+
+			buildingRepo.On("GetByID", ctx, int64(1)).Return(building, nil)
+			apartmentRepo.On("GetByBuildingID", ctx, int64(1)).Return(apartments, nil)
+			broadcastRepo.On("Create", ctx, mock.AnythingOfType("*domain.Broadcast")).Run(func(args mock.Arguments) {
+				b := args.Get(1).(*domain.Broadcast)
+				if tt.recipients > 0 {
+					b.ID = 7
+				}
+				b.TotalRecipients = tt.recipients
+			}).Return(nil)
+
+			broadcast, err := service.CreateBroadcast(ctx, tt.req)
+
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			case tt.errText != "":
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errText)
+				broadcastRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, int64(7), broadcast.ID)
+				assert.Equal(t, strings.TrimSpace(tt.req.Text), broadcast.Text)
+				assert.Equal(t, tt.recipients, broadcast.TotalRecipients)
+			}
+		})
+	}
+}
+
+func TestBroadcastService_RetryLater(t *testing.T) {
+	ctx := context.Background()
+	broadcastRepo := new(MockBroadcastRepo)
+	service := NewBroadcastService(broadcastRepo, nil, nil, zap.NewNop())
+
+	broadcastRepo.On("Requeue", ctx, int64(1), "timeout").Return(nil)
+	broadcastRepo.On("MarkFailed", ctx, int64(2), domain.DeliveryFailureError, "timeout").Return(nil)
+
+	assert.NoError(t, service.RetryLater(ctx, &domain.BroadcastDelivery{RecipientID: 1, Attempts: 1}, "timeout"))
+	assert.NoError(t, service.RetryLater(ctx, &domain.BroadcastDelivery{RecipientID: 2, Attempts: maxDeliveryAttempts}, "timeout"))
+	broadcastRepo.AssertExpectations(t)
+}
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"yardpass/internal/config"
+	"yardpass/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// cameraKeyBytes is the entropy of a camera API key; 32 bytes make a
+	// 43-character URL-safe key.
+	cameraKeyBytes = 32
+	// maxCameraNameLength and maxGateIDLength follow the columns.
+	maxCameraNameLength = 100
+	maxGateIDLength     = 50
+)
+
+var (
+	ErrCameraNotFound       = errors.New("camera not found")
+	ErrCameraExists         = errors.New("camera name is already used in the building")
+	ErrCameraNameRequired   = fmt.Errorf("name is required and must be at most %d characters", maxCameraNameLength)
+	ErrGateIDRequired       = fmt.Errorf("gate_id is required and must be at most %d characters", maxGateIDLength)
+	ErrInvalidConfidence    = errors.New("confidence must be between 0 and 1")
+	ErrInvalidCameraKey     = errors.New("invalid camera API key")
+	ErrCameraMismatch       = errors.New("camera_id or gate_id does not match the camera of the API key")
+	ErrRecognitionTimestamp = errors.New("timestamp is required")
+)
+
+// Reasons a gate camera is refused before the plate is checked, or because
+// the check did not finish in time.
+const (
+	ReasonLowConfidence = "LOW_CONFIDENCE"
+	ReasonEventStale    = "EVENT_STALE"
+	ReasonTimeout       = "TIMEOUT"
+)
+
+// CameraService registers the license plate recognition cameras at the
+// gates and decides whether to open the barrier for the plates they read.
+// The plate is checked by PassService as for a guard, with the camera in
+// place of the guard.
+type CameraService struct {
+	cameraRepo  domain.GateCameraRepository
+	passService *PassService
+	cfg         config.ANPRConfig
+	logger      *zap.Logger
+}
+
+func NewCameraService(
+	cameraRepo domain.GateCameraRepository,
+	passService *PassService,
+	cfg *config.Config,
+	logger *zap.Logger,
+) *CameraService {
+	return &CameraService{
+		cameraRepo:  cameraRepo,
+		passService: passService,
+		cfg:         cfg.ANPR,
+		logger:      logger,
+	}
+}
+
+// AddCamera registers a camera at a gate of the building and returns it
+// with its API key. Only the hash of the key is stored, so the key cannot
+// be shown again.
+func (s *CameraService) AddCamera(ctx context.Context, req domain.CreateGateCameraRequest) (*domain.GateCamera, string, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxCameraNameLength {
+		return nil, "", ErrCameraNameRequired
+	}
+	gateID := strings.TrimSpace(req.GateID)
+	if gateID == "" || utf8.RuneCountInString(gateID) > maxGateIDLength {
+		return nil, "", ErrGateIDRequired
+	}
+	if req.MinConfidence != nil && (*req.MinConfidence < 0 || *req.MinConfidence > 1) {
+		return nil, "", ErrInvalidConfidence
+	}
+
+	cameras, err := s.cameraRepo.List(ctx, &req.BuildingID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list cameras: %w", err)
+	}
+	for _, camera := range cameras {
+		if strings.EqualFold(camera.Name, name) {
+			return nil, "", ErrCameraExists
+		}
+	}
+
+	buf := make([]byte, cameraKeyBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, "", fmt.Errorf("failed to generate camera key: %w", err)
+	}
+	apiKey := base64.RawURLEncoding.EncodeToString(buf)
+
+	camera := &domain.GateCamera{
+		BuildingID:    req.BuildingID,
+		GateID:        gateID,
+		Name:          name,
+		APIKeyHash:    hashCameraKey(apiKey),
+		MinConfidence: req.MinConfidence,
+		CreatedBy:     req.CreatedBy,
+	}
+	if err := s.cameraRepo.Create(ctx, camera); err != nil {
+		return nil, "", fmt.Errorf("failed to create camera: %w", err)
+	}
+
+	s.logger.Info("gate camera registered",
+		zap.Int64("camera_id", camera.ID),
+		zap.Int64("building_id", camera.BuildingID),
+		zap.String("gate_id", camera.GateID),
+	)
+
+	return camera, apiKey, nil
+}
+
+// ListCameras returns the cameras of a building, all buildings when nil.
+func (s *CameraService) ListCameras(ctx context.Context, buildingID *int64) ([]*domain.GateCamera, error) {
+	cameras, err := s.cameraRepo.List(ctx, buildingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cameras: %w", err)
+	}
+	return cameras, nil
+}
+
+// RemoveCamera deletes a camera of the building, any building when nil. Its
+// key stops working at once.
+func (s *CameraService) RemoveCamera(ctx context.Context, id int64, buildingID *int64) error {
+	camera, err := s.cameraRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get camera: %w", err)
+	}
+	if camera == nil || (buildingID != nil && camera.BuildingID != *buildingID) {
+		return ErrCameraNotFound
+	}
+
+	if err := s.cameraRepo.Delete(ctx, camera.ID); err != nil {
+		return fmt.Errorf("failed to delete camera: %w", err)
+	}
+
+	s.logger.Info("gate camera removed",
+		zap.Int64("camera_id", camera.ID),
+		zap.Int64("building_id", camera.BuildingID),
+	)
+	return nil
+}
+
+// Authenticate returns the camera of the API key and marks it seen.
+func (s *CameraService) Authenticate(ctx context.Context, apiKey string) (*domain.GateCamera, error) {
+	if apiKey == "" {
+		return nil, ErrInvalidCameraKey
+	}
+
+	camera, err := s.cameraRepo.TouchByAPIKeyHash(ctx, hashCameraKey(apiKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get camera: %w", err)
+	}
+	if camera == nil {
+		return nil, ErrInvalidCameraKey
+	}
+	return camera, nil
+}
+
+// Decide checks a plate the camera read and tells it whether to open. A
+// read below the confidence threshold or older than the max event age is
+// refused unchecked, and a check that overruns the time budget refuses
+// too: a guard can still let the car in. Every decision is logged as a scan
+// event with the camera in its meta.
+func (s *CameraService) Decide(ctx context.Context, camera *domain.GateCamera, recognition domain.PlateRecognition) (*domain.GateDecision, error) {
+	if recognition.Confidence < 0 || recognition.Confidence > 1 {
+		return nil, ErrInvalidConfidence
+	}
+	if recognition.RecognizedAt.IsZero() {
+		return nil, ErrRecognitionTimestamp
+	}
+	if (recognition.CameraID != nil && *recognition.CameraID != camera.ID) ||
+		(recognition.GateID != "" && recognition.GateID != camera.GateID) {
+		return nil, ErrCameraMismatch
+	}
+
+	// The scan event of a timeout is recorded with ctx, which has time left.
+	checkCtx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+	src := cameraScan(camera, recognition)
+
+	threshold := s.cfg.MinConfidence
+	if camera.MinConfidence != nil {
+		threshold = *camera.MinConfidence
+	}
+
+	decision := &domain.GateDecision{CarPlate: normalizeCarPlate(recognition.CarPlate)}
+	switch {
+	case time.Since(recognition.RecognizedAt) > s.cfg.MaxEventAge:
+		decision.Reason = ReasonEventStale
+	case recognition.Confidence < threshold:
+		decision.Reason = ReasonLowConfidence
+	default:
+		result, err := s.passService.checkCarPlate(checkCtx, recognition.CarPlate, src, &camera.BuildingID)
+		if errors.Is(err, context.DeadlineExceeded) {
+			s.logger.Warn("gate camera decision timed out",
+				zap.Int64("camera_id", camera.ID),
+				zap.String("car_plate", decision.CarPlate),
+				zap.Duration("timeout", s.cfg.Timeout),
+			)
+			decision.Reason = ReasonTimeout
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		decision.Open = result.Valid
+		decision.Reason = result.Reason
+		decision.Apartment = result.Apartment
+		decision.Watchlist = result.Watchlist
+		if result.CarPlate != "" {
+			decision.CarPlate = result.CarPlate
+		}
+
+		// The pass check logs every scan it finds a pass or car for.
+		if result.Reason != "PASS_NOT_FOUND" && result.Reason != "INVALID_CAR_PLATE" {
+			return decision, nil
+		}
+	}
+
+	s.passService.recordScanEvent(ctx, src, &domain.ScanEvent{
+		Result: "invalid",
+		Reason: &decision.Reason,
+	}, map[string]interface{}{
+		"source":    "camera",
+		"car_plate": decision.CarPlate,
+	})
+	return decision, nil
+}
+
+func hashCameraKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
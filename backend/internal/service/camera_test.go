@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"yardpass/internal/config"
+	"yardpass/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type MockGateCameraRepo struct {
+	mock.Mock
+}
+
+func (m *MockGateCameraRepo) Create(ctx context.Context, camera *domain.GateCamera) error {
+	args := m.Called(ctx, camera)
+	return args.Error(0)
+}
+
+func (m *MockGateCameraRepo) GetByID(ctx context.Context, id int64) (*domain.GateCamera, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.GateCamera), args.Error(1)
+}
+
+func (m *MockGateCameraRepo) TouchByAPIKeyHash(ctx context.Context, apiKeyHash string) (*domain.GateCamera, error) {
+	args := m.Called(ctx, apiKeyHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.GateCamera), args.Error(1)
+}
+
+func (m *MockGateCameraRepo) List(ctx context.Context, buildingID *int64) ([]*domain.GateCamera, error) {
+	args := m.Called(ctx, buildingID)
+	return args.Get(0).([]*domain.GateCamera), args.Error(1)
+}
+
+func (m *MockGateCameraRepo) Delete(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func testANPRConfig() *config.Config {
+	return &config.Config{ANPR: config.ANPRConfig{
+		MinConfidence: 0.8,
+		Timeout:       time.Second,
+		MaxEventAge:   10 * time.Second,
+	}}
+}
+
+// cameraMeta decodes the camera part of a scan event meta.
+func cameraMeta(t *testing.T, event *domain.ScanEvent) map[string]interface{} {
+	require.NotNil(t, event.Meta)
+	var meta map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(*event.Meta), &meta))
+	camera, _ := meta["camera"].(map[string]interface{})
+	return camera
+}
+
+func TestCameraService_AddCamera(t *testing.T) {
+	ctx := context.Background()
+	buildingID := int64(1)
+
+	t.Run("stores the key hash only", func(t *testing.T) {
+		cameraRepo := new(MockGateCameraRepo)
+		service := NewCameraService(cameraRepo, nil, testANPRConfig(), zap.NewNop())
+		cameraRepo.On("List", ctx, &buildingID).Return([]*domain.GateCamera{}, nil)
+		cameraRepo.On("Create", ctx, mock.AnythingOfType("*domain.GateCamera")).Return(nil)
+
+		camera, apiKey, err := service.AddCamera(ctx, domain.CreateGateCameraRequest{
+			BuildingID: buildingID,
+			GateID:     " north ",
+			Name:       "Въезд 1",
+		})
+		require.NoError(t, err)
+		assert.Len(t, apiKey, 43)
+		assert.Equal(t, "north", camera.GateID)
+		assert.Equal(t, hashCameraKey(apiKey), camera.APIKeyHash)
+		assert.NotContains(t, camera.APIKeyHash, apiKey)
+	})
+
+	t.Run("name is unique per building", func(t *testing.T) {
+		cameraRepo := new(MockGateCameraRepo)
+		service := NewCameraService(cameraRepo, nil, testANPRConfig(), zap.NewNop())
+		cameraRepo.On("List", ctx, &buildingID).Return([]*domain.GateCamera{{ID: 2, Name: "въезд 1"}}, nil)
+
+		_, _, err := service.AddCamera(ctx, domain.CreateGateCameraRequest{BuildingID: buildingID, GateID: "north", Name: "Въезд 1"})
+		assert.ErrorIs(t, err, ErrCameraExists)
+		cameraRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects bad input", func(t *testing.T) {
+		service := NewCameraService(new(MockGateCameraRepo), nil, testANPRConfig(), zap.NewNop())
+
+		_, _, err := service.AddCamera(ctx, domain.CreateGateCameraRequest{BuildingID: buildingID, GateID: "north", Name: " "})
+		assert.ErrorIs(t, err, ErrCameraNameRequired)
+
+		_, _, err = service.AddCamera(ctx, domain.CreateGateCameraRequest{BuildingID: buildingID, Name: "Въезд 1"})
+		assert.ErrorIs(t, err, ErrGateIDRequired)
+
+		threshold := 1.5
+		_, _, err = service.AddCamera(ctx, domain.CreateGateCameraRequest{BuildingID: buildingID, GateID: "north", Name: "Въезд 1", MinConfidence: &threshold})
+		assert.ErrorIs(t, err, ErrInvalidConfidence)
+	})
+}
+
+func TestCameraService_Authenticate(t *testing.T) {
+	ctx := context.Background()
+	cameraRepo := new(MockGateCameraRepo)
+	service := NewCameraService(cameraRepo, nil, testANPRConfig(), zap.NewNop())
+
+	camera := &domain.GateCamera{ID: 7, BuildingID: 1, GateID: "north", Name: "Въезд 1"}
+	cameraRepo.On("TouchByAPIKeyHash", ctx, hashCameraKey("good-key")).Return(camera, nil)
+	cameraRepo.On("TouchByAPIKeyHash", ctx, hashCameraKey("bad-key")).Return(nil, nil)
+
+	got, err := service.Authenticate(ctx, "good-key")
+	require.NoError(t, err)
+	assert.Equal(t, camera, got)
+
+	_, err = service.Authenticate(ctx, "bad-key")
+	assert.ErrorIs(t, err, ErrInvalidCameraKey)
+
+	_, err = service.Authenticate(ctx, "")
+	assert.ErrorIs(t, err, ErrInvalidCameraKey)
+}
+
+func TestCameraService_Decide(t *testing.T) {
+	ctx := context.Background()
+	buildingID := int64(1)
+	camera := &domain.GateCamera{ID: 7, BuildingID: buildingID, GateID: "north", Name: "Въезд 1"}
+
+	read := func(carPlate string, confidence float64) domain.PlateRecognition {
+		return domain.PlateRecognition{
+			CarPlate:     carPlate,
+			Confidence:   confidence,
+			GateID:       "north",
+			RecognizedAt: time.Now(),
+		}
+	}
+
+	t.Run("opens for a resident car and logs the camera", func(t *testing.T) {
+		apartmentRepo := new(MockApartmentRepo)
+		scanEventRepo := new(MockScanEventRepo)
+		vehicleRepo := new(MockResidentVehicleRepo)
+		passService := NewPassService(new(MockPassRepo), apartmentRepo, new(MockRuleRepo), scanEventRepo, vehicleRepo, noWatchlist(), nil, time.UTC, zap.NewNop())
+		service := NewCameraService(new(MockGateCameraRepo), passService, testANPRConfig(), zap.NewNop())
+
+		vehicleRepo.On("GetByCarPlate", mock.Anything, "A123BC77", &buildingID).Return(&domain.ResidentVehicle{
+			ID: 5, ApartmentID: 10, BuildingID: buildingID, CarPlate: "A123BC77",
+		}, nil)
+		apartmentRepo.On("GetByID", mock.Anything, int64(10)).Return(&domain.Apartment{ID: 10, BuildingID: buildingID, Number: "42"}, nil)
+		scanEventRepo.On("Create", mock.Anything, mock.MatchedBy(func(e *domain.ScanEvent) bool {
+			meta := cameraMeta(t, e)
+			return e.GuardUserID == nil && e.Result == "valid" &&
+				meta["id"] == 7.0 && meta["gate_id"] == "north" && meta["plate"] == "a123bc77" && meta["confidence"] == 0.93
+		})).Return(nil)
+
+		decision, err := service.Decide(ctx, camera, read("a123bc77", 0.93))
+		require.NoError(t, err)
+		assert.True(t, decision.Open)
+		assert.Equal(t, "RESIDENT_VEHICLE", decision.Reason)
+		assert.Equal(t, "42", decision.Apartment)
+		scanEventRepo.AssertExpectations(t)
+	})
+
+	t.Run("unknown plate is denied and logged", func(t *testing.T) {
+		passRepo := new(MockPassRepo)
+		scanEventRepo := new(MockScanEventRepo)
+		vehicleRepo := new(MockResidentVehicleRepo)
+		passService := NewPassService(passRepo, new(MockApartmentRepo), new(MockRuleRepo), scanEventRepo, vehicleRepo, noWatchlist(), nil, time.UTC, zap.NewNop())
+		service := NewCameraService(new(MockGateCameraRepo), passService, testANPRConfig(), zap.NewNop())
+
+		vehicleRepo.On("GetByCarPlate", mock.Anything, "A123BC77", &buildingID).Return(nil, nil)
+		passRepo.On("GetActiveByCarPlate", mock.Anything, "A123BC77", &buildingID).Return(nil, nil)
+		scanEventRepo.On("Create", mock.Anything, mock.MatchedBy(func(e *domain.ScanEvent) bool {
+			return e.GuardUserID == nil && e.CameraID != nil && *e.CameraID == 7 &&
+				e.Result == "invalid" && *e.Reason == "PASS_NOT_FOUND" && cameraMeta(t, e)["id"] == 7.0
+		})).Return(nil)
+
+		decision, err := service.Decide(ctx, camera, read("A123BC77", 0.9))
+		require.NoError(t, err)
+		assert.False(t, decision.Open)
+		assert.Equal(t, "PASS_NOT_FOUND", decision.Reason)
+		scanEventRepo.AssertExpectations(t)
+
+		// A camera has no one to offer lookalike plates to.
+		passRepo.AssertNotCalled(t, "ListActiveByCarPlateLength", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("a check that overruns the budget is denied and logged", func(t *testing.T) {
+		scanEventRepo := new(MockScanEventRepo)
+		vehicleRepo := new(MockResidentVehicleRepo)
+		passService := NewPassService(new(MockPassRepo), new(MockApartmentRepo), new(MockRuleRepo), scanEventRepo, vehicleRepo, noWatchlist(), nil, time.UTC, zap.NewNop())
+		service := NewCameraService(new(MockGateCameraRepo), passService, testANPRConfig(), zap.NewNop())
+
+		vehicleRepo.On("GetByCarPlate", mock.Anything, "A123BC77", &buildingID).Return(nil, context.DeadlineExceeded)
+		// The event is recorded with the request context, not the expired one.
+		scanEventRepo.On("Create", mock.MatchedBy(func(c context.Context) bool { return c.Err() == nil }), mock.MatchedBy(func(e *domain.ScanEvent) bool {
+			return e.CameraID != nil && *e.CameraID == 7 && e.Result == "invalid" && *e.Reason == ReasonTimeout
+		})).Return(nil)
+
+		decision, err := service.Decide(ctx, camera, read("A123BC77", 0.9))
+		require.NoError(t, err)
+		assert.False(t, decision.Open)
+		assert.Equal(t, ReasonTimeout, decision.Reason)
+		scanEventRepo.AssertExpectations(t)
+	})
+
+	t.Run("low confidence and stale reads are denied unchecked", func(t *testing.T) {
+		passRepo := new(MockPassRepo)
+		scanEventRepo := new(MockScanEventRepo)
+		passService := NewPassService(passRepo, new(MockApartmentRepo), new(MockRuleRepo), scanEventRepo, nil, new(MockPlateWatchlistRepo), nil, time.UTC, zap.NewNop())
+		service := NewCameraService(new(MockGateCameraRepo), passService, testANPRConfig(), zap.NewNop())
+		scanEventRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.ScanEvent")).Return(nil)
+
+		decision, err := service.Decide(ctx, camera, read("A123BC77", 0.5))
+		require.NoError(t, err)
+		assert.False(t, decision.Open)
+		assert.Equal(t, ReasonLowConfidence, decision.Reason)
+
+		stale := read("A123BC77", 0.95)
+		stale.RecognizedAt = time.Now().Add(-time.Minute)
+		decision, err = service.Decide(ctx, camera, stale)
+		require.NoError(t, err)
+		assert.False(t, decision.Open)
+		assert.Equal(t, ReasonEventStale, decision.Reason)
+
+		scanEventRepo.AssertNumberOfCalls(t, "Create", 2)
+		passRepo.AssertNotCalled(t, "GetActiveByCarPlate", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("camera threshold overrides the default", func(t *testing.T) {
+		scanEventRepo := new(MockScanEventRepo)
+		passService := NewPassService(new(MockPassRepo), new(MockApartmentRepo), new(MockRuleRepo), scanEventRepo, nil, new(MockPlateWatchlistRepo), nil, time.UTC, zap.NewNop())
+		service := NewCameraService(new(MockGateCameraRepo), passService, testANPRConfig(), zap.NewNop())
+		scanEventRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.ScanEvent")).Return(nil)
+
+		strict := *camera
+		threshold := 0.97
+		strict.MinConfidence = &threshold
+
+		decision, err := service.Decide(ctx, &strict, read("A123BC77", 0.95))
+		require.NoError(t, err)
+		assert.Equal(t, ReasonLowConfidence, decision.Reason)
+	})
+
+	t.Run("rejects reads of another camera", func(t *testing.T) {
+		service := NewCameraService(new(MockGateCameraRepo), nil, testANPRConfig(), zap.NewNop())
+
+		other := read("A123BC77", 0.95)
+		other.GateID = "south"
+		_, err := service.Decide(ctx, camera, other)
+		assert.ErrorIs(t, err, ErrCameraMismatch)
+
+		otherID := int64(8)
+		other = read("A123BC77", 0.95)
+		other.CameraID = &otherID
+		_, err = service.Decide(ctx, camera, other)
+		assert.ErrorIs(t, err, ErrCameraMismatch)
+
+		_, err = service.Decide(ctx, camera, read("A123BC77", 1.2))
+		assert.ErrorIs(t, err, ErrInvalidConfidence)
+	})
+}
@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"yardpass/internal/domain"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// entryRequestTimeout is how long the guard waits for the residents.
+	entryRequestTimeout = 2 * time.Minute
+	// entryPassDuration is the validity of the pass an allowed entry issues.
+	entryPassDuration = 15 * time.Minute
+)
+
+var (
+	ErrNoEntryApprovers     = errors.New("nobody in the apartment can let visitors in")
+	ErrEntryRequestNotFound = errors.New("entry request not found")
+	ErrEntryRequestDecided  = errors.New("entry request has already been decided")
+	ErrEntryRequestExpired  = errors.New("entry request has expired")
+)
+
+// EntryRequestService handles guards asking residents whether to let in a
+// visitor who has no pass. The bot process delivers the question and the
+// guard polls for the answer. Every outcome is recorded as a scan event.
+type EntryRequestService struct {
+	requestRepo   domain.EntryRequestRepository
+	apartmentRepo domain.ApartmentRepository
+	residentRepo  domain.ResidentRepository
+	scanEventRepo domain.ScanEventRepository
+	passService   *PassService
+	logger        *zap.Logger
+}
+
+func NewEntryRequestService(
+	requestRepo domain.EntryRequestRepository,
+	apartmentRepo domain.ApartmentRepository,
+	residentRepo domain.ResidentRepository,
+	scanEventRepo domain.ScanEventRepository,
+	passService *PassService,
+	logger *zap.Logger,
+) *EntryRequestService {
+	return &EntryRequestService{
+		requestRepo:   requestRepo,
+		apartmentRepo: apartmentRepo,
+		residentRepo:  residentRepo,
+		scanEventRepo: scanEventRepo,
+		passService:   passService,
+		logger:        logger,
+	}
+}
+
+// CreateRequest asks the residents of the apartment with the given number
+// whether to let the car in.
+func (s *EntryRequestService) CreateRequest(ctx context.Context, guardUserID, buildingID int64, apartmentNumber, carPlate string) (*domain.EntryRequest, error) {
+	parsed, err := ParseCarPlate(carPlate)
+	if err != nil {
+		return nil, err
+	}
+	normalized := parsed.Normalized
+
+	apartment, err := findApartment(ctx, s.apartmentRepo, buildingID, apartmentNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	approvers, err := passIssuers(ctx, s.residentRepo, apartment.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(approvers) == 0 {
+		return nil, ErrNoEntryApprovers
+	}
+
+	request := &domain.EntryRequest{
+		ApartmentID: apartment.ID,
+		BuildingID:  apartment.BuildingID,
+		GuardUserID: guardUserID,
+		CarPlate:    normalized,
+		Status:      domain.EntryRequestStatusPending,
+		ExpiresAt:   time.Now().UTC().Add(entryRequestTimeout),
+	}
+	if err := s.requestRepo.Create(ctx, request); err != nil {
+		return nil, fmt.Errorf("failed to create entry request: %w", err)
+	}
+
+	s.logger.Info("entry request created",
+		zap.Int64("request_id", request.ID),
+		zap.Int64("apartment_id", request.ApartmentID),
+		zap.Int64("guard_user_id", guardUserID),
+		zap.String("car_plate", normalized),
+	)
+
+	return request, nil
+}
+
+// GetRequest returns the request, expiring it if the residents did not
+// answer in time.
+func (s *EntryRequestService) GetRequest(ctx context.Context, id int64) (*domain.EntryRequest, error) {
+	request, err := s.requestRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get entry request: %w", err)
+	}
+	if request == nil {
+		return nil, ErrEntryRequestNotFound
+	}
+
+	if request.Status == domain.EntryRequestStatusPending && time.Now().After(request.ExpiresAt) {
+		if err := s.expire(ctx, request); err != nil {
+			return nil, err
+		}
+		return s.requestRepo.GetByID(ctx, id)
+	}
+
+	return request, nil
+}
+
+// ClaimNotifications returns new requests the caller is now responsible for
+// delivering to the residents.
+func (s *EntryRequestService) ClaimNotifications(ctx context.Context, limit int) ([]*domain.EntryRequest, error) {
+	requests, err := s.requestRepo.ClaimUnnotified(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim entry requests: %w", err)
+	}
+	return requests, nil
+}
+
+// Approvers returns the residents who should be asked about the request.
+func (s *EntryRequestService) Approvers(ctx context.Context, request *domain.EntryRequest) ([]*domain.Resident, error) {
+	return passIssuers(ctx, s.residentRepo, request.ApartmentID)
+}
+
+// ExpireOverdue closes the requests nobody answered in time and records
+// them. It returns how many were expired.
+func (s *EntryRequestService) ExpireOverdue(ctx context.Context) (int, error) {
+	requests, err := s.requestRepo.ExpireOverdue(ctx, time.Now().UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire entry requests: %w", err)
+	}
+	for _, request := range requests {
+		s.recordEntry(ctx, request, nil, "invalid", "RESIDENT_NO_ANSWER")
+	}
+	return len(requests), nil
+}
+
+// Allow lets the car in with a short single-use pass issued on behalf of the
+// resident.
+func (s *EntryRequestService) Allow(ctx context.Context, requestID int64, resident *domain.Resident) (*domain.EntryRequest, *domain.Pass, error) {
+	request, err := s.pendingRequest(ctx, requestID, resident)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	validFrom := time.Now().UTC()
+	pass, err := s.passService.CreatePass(ctx, domain.CreatePassRequest{
+		ApartmentID: request.ApartmentID,
+		ResidentID:  &resident.ID,
+		CarPlate:    &request.CarPlate,
+		ValidFrom:   validFrom,
+		ValidTo:     validFrom.Add(entryPassDuration),
+		SingleUse:   true,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resolved, err := s.requestRepo.Resolve(ctx, request.ID, domain.EntryRequestStatusAllowed, &resident.ID, &pass.ID)
+	if err == nil && !resolved {
+		err = ErrEntryRequestDecided
+	}
+	if err != nil {
+		// Someone else decided in the meantime; the pass must not stay.
+		if revokeErr := s.passService.RevokePass(ctx, pass.ID, 0); revokeErr != nil {
+			s.logger.Error("failed to revoke pass of a decided entry request", zap.Error(revokeErr), zap.String("pass_id", pass.ID.String()))
+		}
+		if errors.Is(err, ErrEntryRequestDecided) {
+			return nil, nil, err
+		}
+		return nil, nil, fmt.Errorf("failed to allow entry: %w", err)
+	}
+
+	request.Status = domain.EntryRequestStatusAllowed
+	request.DecidedBy = &resident.ID
+	request.PassID = &pass.ID
+	s.recordEntry(ctx, request, &pass.ID, "valid", "RESIDENT_ALLOWED")
+
+	s.logger.Info("entry allowed",
+		zap.Int64("request_id", request.ID),
+		zap.Int64("resident_id", resident.ID),
+		zap.String("pass_id", pass.ID.String()),
+	)
+
+	return request, pass, nil
+}
+
+func (s *EntryRequestService) Deny(ctx context.Context, requestID int64, resident *domain.Resident) (*domain.EntryRequest, error) {
+	request, err := s.pendingRequest(ctx, requestID, resident)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved, err := s.requestRepo.Resolve(ctx, request.ID, domain.EntryRequestStatusDenied, &resident.ID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deny entry: %w", err)
+	}
+	if !resolved {
+		return nil, ErrEntryRequestDecided
+	}
+
+	request.Status = domain.EntryRequestStatusDenied
+	request.DecidedBy = &resident.ID
+	s.recordEntry(ctx, request, nil, "invalid", "RESIDENT_DENIED")
+
+	return request, nil
+}
+
+// pendingRequest returns the request if the resident may still decide on
+// it. Requests of other apartments are reported as not found.
+func (s *EntryRequestService) pendingRequest(ctx context.Context, requestID int64, resident *domain.Resident) (*domain.EntryRequest, error) {
+	request, err := s.requestRepo.GetByID(ctx, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get entry request: %w", err)
+	}
+	if request == nil || request.ApartmentID != resident.ApartmentID {
+		return nil, ErrEntryRequestNotFound
+	}
+	if !resident.CanIssuePasses || resident.Status != "active" {
+		return nil, ErrCannotIssuePasses
+	}
+	if request.Status != domain.EntryRequestStatusPending {
+		return nil, ErrEntryRequestDecided
+	}
+
+	if time.Now().After(request.ExpiresAt) {
+		if err := s.expire(ctx, request); err != nil {
+			s.logger.Error("failed to expire entry request", zap.Error(err), zap.Int64("request_id", request.ID))
+		}
+		return nil, ErrEntryRequestExpired
+	}
+
+	return request, nil
+}
+
+func (s *EntryRequestService) expire(ctx context.Context, request *domain.EntryRequest) error {
+	resolved, err := s.requestRepo.Resolve(ctx, request.ID, domain.EntryRequestStatusExpired, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to expire entry request: %w", err)
+	}
+	if resolved {
+		s.recordEntry(ctx, request, nil, "invalid", "RESIDENT_NO_ANSWER")
+	}
+	return nil
+}
+
+// recordEntry logs the outcome of the request as a scan event of the guard
+// who asked.
+func (s *EntryRequestService) recordEntry(ctx context.Context, request *domain.EntryRequest, passID *uuid.UUID, result, reason string) {
+	meta, err := json.Marshal(map[string]interface{}{
+		"source":           "resident_call",
+		"entry_request_id": request.ID,
+		"car_plate":        request.CarPlate,
+	})
+	if err != nil {
+		s.logger.Error("failed to encode scan event meta", zap.Error(err))
+		return
+	}
+	metaStr := string(meta)
+
+	event := &domain.ScanEvent{
+		PassID:      passID,
+		ApartmentID: &request.ApartmentID,
+		GuardUserID: &request.GuardUserID,
+		ScannedAt:   time.Now().UTC(),
+		Result:      result,
+		Reason:      &reason,
+		Meta:        &metaStr,
+	}
+	if err := s.scanEventRepo.Create(ctx, event); err != nil {
+		s.logger.Error("failed to log entry request scan event",
+			zap.Error(err),
+			zap.Int64("request_id", request.ID),
+			zap.String("reason", reason),
+		)
+	}
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"yardpass/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type MockEntryRequestRepo struct {
+	mock.Mock
+}
+
+func (m *MockEntryRequestRepo) Create(ctx context.Context, request *domain.EntryRequest) error {
+	args := m.Called(ctx, request)
+	return args.Error(0)
+}
+
+func (m *MockEntryRequestRepo) GetByID(ctx context.Context, id int64) (*domain.EntryRequest, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.EntryRequest), args.Error(1)
+}
+
+func (m *MockEntryRequestRepo) ClaimUnnotified(ctx context.Context, limit int) ([]*domain.EntryRequest, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]*domain.EntryRequest), args.Error(1)
+}
+
+func (m *MockEntryRequestRepo) Resolve(ctx context.Context, id int64, status string, decidedBy *int64, passID *uuid.UUID) (bool, error) {
+	args := m.Called(ctx, id, status, decidedBy, passID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockEntryRequestRepo) ExpireOverdue(ctx context.Context, now time.Time) ([]*domain.EntryRequest, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).([]*domain.EntryRequest), args.Error(1)
+}
+
+func TestEntryRequestService_CreateRequest(t *testing.T) {
+	ctx := context.Background()
+	apartment := &domain.Apartment{ID: 10, BuildingID: 1, Number: "42"}
+
+	tests := []struct {
+		name      string
+		number    string
+		carPlate  string
+		residents []*domain.Resident
+		wantErr   error
+	}{
+		{
+			name:      "asks the apartment",
+			number:    "42",
+			residents: []*domain.Resident{{ID: 1, ApartmentID: 10, Status: "active", CanIssuePasses: true}},
+		},
+		{
+			name:    "unknown apartment",
+			number:  "13",
+			wantErr: ErrApartmentNotFound,
+		},
+		{
+			name:      "nobody can let the visitor in",
+			number:    "42",
+			residents: []*domain.Resident{{ID: 2, ApartmentID: 10, Status: "active"}},
+			wantErr:   ErrNoEntryApprovers,
+		},
+		{
+			name:     "malformed car plate",
+			number:   "42",
+			carPlate: "A123BC",
+			wantErr:  ErrInvalidCarPlate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requestRepo := new(MockEntryRequestRepo)
+			apartmentRepo := new(MockApartmentRepo)
+			residentRepo := new(MockResidentRepo)
+			service := NewEntryRequestService(requestRepo, apartmentRepo, residentRepo, nil, nil, zap.NewNop())
+
+			apartmentRepo.On("GetByBuildingID", ctx, int64(1)).Return([]*domain.Apartment{apartment}, nil)
+			residentRepo.On("List", ctx, mock.AnythingOfType("domain.ResidentFilters")).Return(tt.residents, nil)
+			requestRepo.On("Create", ctx, mock.AnythingOfType("*domain.EntryRequest")).Return(nil)
+
+			carPlate := tt.carPlate
+			if carPlate == "" {
+				carPlate = "а123вс 77"
+			}
+			request, err := service.CreateRequest(ctx, 7, 1, tt.number, carPlate)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				requestRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "A123BC77", request.CarPlate)
+			assert.Equal(t, int64(7), request.GuardUserID)
+			assert.Equal(t, domain.EntryRequestStatusPending, request.Status)
+			assert.WithinDuration(t, time.Now().Add(entryRequestTimeout), request.ExpiresAt, time.Minute)
+		})
+	}
+}
+
+func TestEntryRequestService_Deny(t *testing.T) {
+	ctx := context.Background()
+	resident := &domain.Resident{ID: 1, ApartmentID: 10, Status: "active", CanIssuePasses: true}
+	future := time.Now().Add(time.Minute)
+
+	tests := []struct {
+		name       string
+		request    *domain.EntryRequest
+		wantErr    error
+		wantReason string
+	}{
+		{
+			name:       "pending request",
+			request:    &domain.EntryRequest{ID: 5, ApartmentID: 10, GuardUserID: 7, Status: domain.EntryRequestStatusPending, ExpiresAt: future},
+			wantReason: "RESIDENT_DENIED",
+		},
+		{
+			name:    "request of another apartment",
+			request: &domain.EntryRequest{ID: 5, ApartmentID: 11, Status: domain.EntryRequestStatusPending, ExpiresAt: future},
+			wantErr: ErrEntryRequestNotFound,
+		},
+		{
+			name:    "already decided",
+			request: &domain.EntryRequest{ID: 5, ApartmentID: 10, Status: domain.EntryRequestStatusAllowed, ExpiresAt: future},
+			wantErr: ErrEntryRequestDecided,
+		},
+		{
+			name:       "no answer in time",
+			request:    &domain.EntryRequest{ID: 5, ApartmentID: 10, GuardUserID: 7, Status: domain.EntryRequestStatusPending, ExpiresAt: time.Now().Add(-time.Second)},
+			wantErr:    ErrEntryRequestExpired,
+			wantReason: "RESIDENT_NO_ANSWER",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requestRepo := new(MockEntryRequestRepo)
+			scanEventRepo := new(MockScanEventRepo)
+			service := NewEntryRequestService(requestRepo, nil, nil, scanEventRepo, nil, zap.NewNop())
+
+			requestRepo.On("GetByID", ctx, int64(5)).Return(tt.request, nil)
+			requestRepo.On("Resolve", ctx, int64(5), mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+			scanEventRepo.On("Create", ctx, mock.AnythingOfType("*domain.ScanEvent")).Return(nil)
+
+			_, err := service.Deny(ctx, 5, resident)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			if tt.wantReason == "" {
+				scanEventRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+			event := scanEventRepo.Calls[0].Arguments.Get(1).(*domain.ScanEvent)
+			assert.Equal(t, "invalid", event.Result)
+			assert.Equal(t, tt.wantReason, *event.Reason)
+			assert.Nil(t, event.PassID)
+			assert.Equal(t, int64(10), *event.ApartmentID)
+			assert.Equal(t, int64(7), *event.GuardUserID)
+			assert.Contains(t, *event.Meta, `"source":"resident_call"`)
+		})
+	}
+}
@@ -0,0 +1,426 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"yardpass/internal/domain"
+	"yardpass/internal/qr"
+
+	"github.com/google/uuid"
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap"
+)
+
+// maxEventCapacity bounds the guest list of one event; it matches the page
+// limit of a printed batch so that every event can be printed at once.
+const maxEventCapacity = maxPrintPasses
+
+var (
+	ErrEventNotFound        = errors.New("event not found")
+	ErrEventNameRequired    = errors.New("event name is required")
+	ErrInvalidEventCapacity = fmt.Errorf("capacity must be between 1 and %d", maxEventCapacity)
+	ErrEmptyGuestList       = errors.New("guest list is empty")
+	ErrEventOverCapacity    = errors.New("guest list exceeds the event capacity")
+	ErrEventRevoked         = errors.New("event is revoked")
+	ErrUnsupportedGuestList = errors.New("guest list must be a .csv or .xlsx file")
+)
+
+// GuestListError reports the rows of a guest list that cannot get a pass.
+type GuestListError struct {
+	Rows []domain.BulkCreateError
+}
+
+func (e *GuestListError) Error() string {
+	return fmt.Sprintf("guest list has %d invalid rows", len(e.Rows))
+}
+
+// guestListColumns maps the accepted header names of a guest list to its
+// columns.
+var guestListColumns = map[string]string{
+	"car_plate":  "car_plate",
+	"plate":      "car_plate",
+	"номер":      "car_plate",
+	"guest_name": "guest_name",
+	"name":       "guest_name",
+	"имя":        "guest_name",
+	"гость":      "guest_name",
+}
+
+// EventService issues the passes of an event from its guest list.
+type EventService struct {
+	eventRepo     domain.EventRepository
+	apartmentRepo domain.ApartmentRepository
+	passService   *PassService
+	printService  *PassPrintService
+	qrGen         *qr.Generator
+	logger        *zap.Logger
+}
+
+func NewEventService(
+	eventRepo domain.EventRepository,
+	apartmentRepo domain.ApartmentRepository,
+	passService *PassService,
+	printService *PassPrintService,
+	qrGen *qr.Generator,
+	logger *zap.Logger,
+) *EventService {
+	return &EventService{
+		eventRepo:     eventRepo,
+		apartmentRepo: apartmentRepo,
+		passService:   passService,
+		printService:  printService,
+		qrGen:         qrGen,
+		logger:        logger,
+	}
+}
+
+// ParseGuestList reads a CSV or XLSX guest list, chosen by the file
+// extension. The first row is a header with a car_plate and/or guest_name
+// column. Rows with problems are returned as row errors; the error is set
+// only if the file itself cannot be read.
+func (s *EventService) ParseGuestList(fileName string, reader io.Reader) ([]domain.EventGuest, []domain.BulkCreateError, error) {
+	var records [][]string
+	var err error
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv":
+		records, err = readCSVRecords(reader)
+	case ".xlsx":
+		records, err = readXLSXRecords(reader)
+	default:
+		return nil, nil, ErrUnsupportedGuestList
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(records) < 2 {
+		return nil, nil, errors.New("guest list must have header row and at least one data row")
+	}
+
+	columns := make(map[string]int)
+	for i, h := range records[0] {
+		if column, ok := guestListColumns[strings.ToLower(strings.TrimSpace(h))]; ok {
+			if _, seen := columns[column]; !seen {
+				columns[column] = i
+			}
+		}
+	}
+	if len(columns) == 0 {
+		return nil, nil, errors.New("missing required column: car_plate or guest_name")
+	}
+
+	cell := func(record []string, column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var guests []domain.EventGuest
+	var rowErrors []domain.BulkCreateError
+	plates := make(map[string]int)
+	for i, record := range records[1:] {
+		row := i + 2
+		rawPlate := cell(record, "car_plate")
+		name := cell(record, "guest_name")
+		if rawPlate == "" && name == "" {
+			continue
+		}
+
+		guest := domain.EventGuest{Row: row}
+		if rawPlate != "" {
+			parsed, err := ParseCarPlate(rawPlate)
+			if err != nil {
+				rowErrors = append(rowErrors, domain.BulkCreateError{Row: row, Error: fmt.Sprintf("%s: %v", rawPlate, err)})
+				continue
+			}
+			plate := parsed.Normalized
+			if first, ok := plates[plate]; ok {
+				rowErrors = append(rowErrors, domain.BulkCreateError{Row: row, Error: fmt.Sprintf("car plate %s is already listed in row %d", plate, first)})
+				continue
+			}
+			plates[plate] = row
+			guest.CarPlate = &plate
+		}
+		if name != "" {
+			guest.GuestName = &name
+		}
+		guests = append(guests, guest)
+	}
+
+	return guests, rowErrors, nil
+}
+
+func readCSVRecords(reader io.Reader) ([][]string, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV: %w", err)
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	csvReader := csv.NewReader(bytes.NewReader(data))
+	csvReader.TrimLeadingSpace = true
+	csvReader.FieldsPerRecord = -1
+	// Spreadsheets saved with a Russian locale separate fields with ';'.
+	if header, _, _ := bytes.Cut(data, []byte("\n")); bytes.Count(header, []byte(";")) > bytes.Count(header, []byte(",")) {
+		csvReader.Comma = ';'
+	}
+
+	records, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV: %w", err)
+	}
+	return records, nil
+}
+
+func readXLSXRecords(reader io.Reader) ([][]string, error) {
+	file, err := excelize.OpenReader(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read XLSX: %w", err)
+	}
+	defer file.Close()
+
+	sheets := file.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, errors.New("XLSX file has no sheets")
+	}
+
+	records, err := file.GetRows(sheets[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to read XLSX: %w", err)
+	}
+	return records, nil
+}
+
+// CreateEvent issues a pass for every guest of the list. The passes follow
+// the building rules for the window but not the daily limit, and are stored
+// together with the event in one transaction. Guests whose plate is banned
+// in the building are reported in a *GuestListError.
+func (s *EventService) CreateEvent(ctx context.Context, req domain.CreateEventRequest) (*domain.Event, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, ErrEventNameRequired
+	}
+	if req.Capacity < 1 || req.Capacity > maxEventCapacity {
+		return nil, ErrInvalidEventCapacity
+	}
+	if len(req.Guests) == 0 {
+		return nil, ErrEmptyGuestList
+	}
+	if len(req.Guests) > req.Capacity {
+		return nil, fmt.Errorf("%w: %d guests, capacity %d", ErrEventOverCapacity, len(req.Guests), req.Capacity)
+	}
+
+	apartment, err := s.apartmentRepo.GetByID(ctx, req.ApartmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get apartment: %w", err)
+	}
+	if apartment == nil || apartment.BuildingID != req.BuildingID {
+		return nil, ErrApartmentNotFound
+	}
+
+	if err := s.passService.CheckPassWindow(ctx, req.ApartmentID, domain.PassCategoryGuest, req.ValidFrom, req.ValidTo); err != nil {
+		return nil, err
+	}
+
+	var rowErrors []domain.BulkCreateError
+	for _, guest := range req.Guests {
+		if guest.CarPlate == nil {
+			continue
+		}
+		if err := s.passService.checkNotBanned(ctx, *guest.CarPlate, req.BuildingID); err != nil {
+			if !errors.Is(err, ErrPlateBanned) {
+				return nil, err
+			}
+			rowErrors = append(rowErrors, domain.BulkCreateError{Row: guest.Row, Error: fmt.Sprintf("%s: %v", *guest.CarPlate, err)})
+		}
+	}
+	if len(rowErrors) > 0 {
+		return nil, &GuestListError{Rows: rowErrors}
+	}
+
+	event := &domain.Event{
+		BuildingID:  req.BuildingID,
+		ApartmentID: req.ApartmentID,
+		Name:        name,
+		ValidFrom:   req.ValidFrom,
+		ValidTo:     req.ValidTo,
+		Capacity:    req.Capacity,
+		Status:      domain.EventStatusActive,
+		CreatedBy:   req.CreatedBy,
+		ResidentID:  req.ResidentID,
+	}
+
+	passes := make([]*domain.Pass, 0, len(req.Guests))
+	for _, guest := range req.Guests {
+		passes = append(passes, &domain.Pass{
+			ID:          uuid.New(),
+			ApartmentID: req.ApartmentID,
+			ResidentID:  req.ResidentID,
+			CarPlate:    guest.CarPlate,
+			GuestName:   guest.GuestName,
+			ValidFrom:   req.ValidFrom,
+			ValidTo:     req.ValidTo,
+			Status:      "active",
+			Category:    domain.PassCategoryGuest,
+		})
+	}
+
+	if err := s.eventRepo.Create(ctx, event, passes); err != nil {
+		return nil, fmt.Errorf("failed to create event: %w", err)
+	}
+
+	s.logger.Info("event created",
+		zap.Int64("event_id", event.ID),
+		zap.Int64("apartment_id", event.ApartmentID),
+		zap.Int("passes", len(passes)),
+	)
+
+	return event, nil
+}
+
+// GetEvent returns the event. If buildingID is set, events of other
+// buildings are reported as not found.
+func (s *EventService) GetEvent(ctx context.Context, id int64, buildingID *int64) (*domain.Event, error) {
+	event, err := s.eventRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+	if event == nil || (buildingID != nil && event.BuildingID != *buildingID) {
+		return nil, ErrEventNotFound
+	}
+	return event, nil
+}
+
+func (s *EventService) ListEvents(ctx context.Context, buildingID *int64) ([]*domain.Event, error) {
+	return s.eventRepo.List(ctx, buildingID)
+}
+
+// RevokeEvent revokes the event and all its active passes at once.
+func (s *EventService) RevokeEvent(ctx context.Context, id int64, buildingID *int64) (int, error) {
+	event, err := s.GetEvent(ctx, id, buildingID)
+	if err != nil {
+		return 0, err
+	}
+	if event.Status == domain.EventStatusRevoked {
+		return 0, ErrEventRevoked
+	}
+
+	revoked, err := s.eventRepo.Revoke(ctx, id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke event: %w", err)
+	}
+
+	s.logger.Info("event revoked", zap.Int64("event_id", id), zap.Int("passes", revoked))
+	return revoked, nil
+}
+
+// Attendance reports which guests of the event entered, from the valid
+// scans of their passes.
+func (s *EventService) Attendance(ctx context.Context, id int64, buildingID *int64) (*domain.EventAttendance, error) {
+	event, err := s.GetEvent(ctx, id, buildingID)
+	if err != nil {
+		return nil, err
+	}
+
+	guests, err := s.eventRepo.Attendance(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attendance: %w", err)
+	}
+
+	report := &domain.EventAttendance{Event: event, Guests: guests}
+	for _, guest := range guests {
+		if guest.Entries > 0 {
+			report.Attended++
+		}
+	}
+	return report, nil
+}
+
+// EventPDF returns the printable passes of the event in one document.
+func (s *EventService) EventPDF(ctx context.Context, id int64, buildingID *int64) ([]byte, error) {
+	passes, err := s.eventPasses(ctx, id, buildingID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(passes))
+	for _, pass := range passes {
+		ids = append(ids, pass.ID)
+	}
+	return s.printService.PrintPasses(ctx, ids, buildingID)
+}
+
+// EventQRArchive returns a ZIP archive with a QR code image per pass of the
+// event, named after the guest.
+func (s *EventService) EventQRArchive(ctx context.Context, id int64, buildingID *int64) ([]byte, error) {
+	passes, err := s.eventPasses(ctx, id, buildingID)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for i, pass := range passes {
+		png, err := s.qrGen.GenerateQR(ctx, pass.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate QR code: %w", err)
+		}
+
+		w, err := archive.Create(qrFileName(i+1, pass))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(png); err != nil {
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (s *EventService) eventPasses(ctx context.Context, id int64, buildingID *int64) ([]*domain.Pass, error) {
+	if _, err := s.GetEvent(ctx, id, buildingID); err != nil {
+		return nil, err
+	}
+
+	passes, err := s.eventRepo.GetPasses(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event passes: %w", err)
+	}
+	if len(passes) == 0 {
+		return nil, ErrNoPassesToPrint
+	}
+	return passes, nil
+}
+
+// qrFileName names a QR image after the guest, keeping only characters that
+// are safe in file names.
+func qrFileName(n int, pass *domain.Pass) string {
+	label := pass.ID.String()[:8]
+	if pass.GuestName != nil {
+		label = *pass.GuestName
+	} else if pass.CarPlate != nil {
+		label = *pass.CarPlate
+	}
+
+	safe := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) || r < ' ' {
+			return '_'
+		}
+		return r
+	}, label)
+	return fmt.Sprintf("%03d_%s.png", n, safe)
+}
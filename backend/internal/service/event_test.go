@@ -0,0 +1,265 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"yardpass/internal/domain"
+	"yardpass/internal/qr"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap"
+)
+
+// MockEventRepo mocks only Create; other methods panic if called.
+type MockEventRepo struct {
+	domain.EventRepository
+	mock.Mock
+}
+
+func (m *MockEventRepo) Create(ctx context.Context, event *domain.Event, passes []*domain.Pass) error {
+	args := m.Called(ctx, event, passes)
+	return args.Error(0)
+}
+
+func newTestEventService() (*EventService, *MockEventRepo, *MockApartmentRepo, *MockRuleRepo) {
+	logger := zap.NewNop()
+	eventRepo := new(MockEventRepo)
+	apartmentRepo := new(MockApartmentRepo)
+	ruleRepo := new(MockRuleRepo)
+	passService := NewPassService(new(MockPassRepo), apartmentRepo, ruleRepo, new(MockScanEventRepo), nil, noWatchlist(), nil, time.UTC, logger)
+	return NewEventService(eventRepo, apartmentRepo, passService, nil, qr.NewGenerator(), logger), eventRepo, apartmentRepo, ruleRepo
+}
+
+func TestEventService_ParseGuestList(t *testing.T) {
+	service, _, _, _ := newTestEventService()
+
+	xlsx := excelize.NewFile()
+	xlsx.SetSheetRow("Sheet1", "A1", &[]interface{}{"Гость", "Номер"})
+	xlsx.SetSheetRow("Sheet1", "A2", &[]interface{}{"Иван", "а 123 вс 77"})
+	xlsx.SetSheetRow("Sheet1", "A3", &[]interface{}{"Мария", ""})
+	var xlsxData bytes.Buffer
+	_, err := xlsx.WriteTo(&xlsxData)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		fileName string
+		data     []byte
+		want     []string
+	}{
+		{
+			name:     "csv with commas",
+			fileName: "guests.csv",
+			data:     []byte("car_plate,guest_name\nA123BC77,Иван\n,Мария\n,\n"),
+			want:     []string{"A123BC77 Иван", " Мария"},
+		},
+		{
+			name:     "csv with semicolons and BOM",
+			fileName: "Guests.CSV",
+			data:     []byte("\xef\xbb\xbfимя;номер\nИван;А123ВС77\n"),
+			want:     []string{"A123BC77 Иван"},
+		},
+		{
+			name:     "plates only",
+			fileName: "guests.csv",
+			data:     []byte("plate\nA123BC77\nB456CM99\n"),
+			want:     []string{"A123BC77 ", "B456CM99 "},
+		},
+		{
+			name:     "xlsx",
+			fileName: "guests.xlsx",
+			data:     xlsxData.Bytes(),
+			want:     []string{"A123BC77 Иван", " Мария"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			guests, rowErrors, err := service.ParseGuestList(tt.fileName, bytes.NewReader(tt.data))
+
+			require.NoError(t, err)
+			assert.Empty(t, rowErrors)
+			got := make([]string, 0, len(guests))
+			for _, g := range guests {
+				var plate, name string
+				if g.CarPlate != nil {
+					plate = *g.CarPlate
+				}
+				if g.GuestName != nil {
+					name = *g.GuestName
+				}
+				got = append(got, plate+" "+name)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEventService_ParseGuestList_Errors(t *testing.T) {
+	service, _, _, _ := newTestEventService()
+
+	t.Run("unsupported file", func(t *testing.T) {
+		_, _, err := service.ParseGuestList("guests.txt", strings.NewReader("car_plate\nA123BC77\n"))
+		assert.ErrorIs(t, err, ErrUnsupportedGuestList)
+	})
+
+	t.Run("missing columns", func(t *testing.T) {
+		_, _, err := service.ParseGuestList("guests.csv", strings.NewReader("phone\n123\n"))
+		assert.ErrorContains(t, err, "missing required column")
+	})
+
+	t.Run("header only", func(t *testing.T) {
+		_, _, err := service.ParseGuestList("guests.csv", strings.NewReader("car_plate\n"))
+		assert.ErrorContains(t, err, "at least one data row")
+	})
+
+	t.Run("invalid and duplicate plates", func(t *testing.T) {
+		guests, rowErrors, err := service.ParseGuestList("guests.csv", strings.NewReader("car_plate,guest_name\nA123BC77,Иван\n!!!,Пётр\nа123вс77,Мария\nA123BC,Олег\n"))
+
+		require.NoError(t, err)
+		assert.Len(t, guests, 1)
+		require.Len(t, rowErrors, 3)
+		assert.Equal(t, 3, rowErrors[0].Row)
+		assert.Contains(t, rowErrors[0].Error, "invalid car plate")
+		assert.Equal(t, 4, rowErrors[1].Row)
+		assert.Contains(t, rowErrors[1].Error, "already listed in row 2")
+		assert.Equal(t, 5, rowErrors[2].Row)
+		assert.Contains(t, rowErrors[2].Error, "unrecognized car plate format")
+	})
+}
+
+func TestEventService_CreateEvent(t *testing.T) {
+	ctx := context.Background()
+	plate := "A123BC77"
+	name := "Мария"
+	validFrom := time.Now().Add(time.Hour)
+	residentID := int64(5)
+
+	service, eventRepo, apartmentRepo, ruleRepo := newTestEventService()
+	apartmentRepo.On("GetByID", ctx, int64(10)).Return(&domain.Apartment{ID: 10, BuildingID: 1}, nil)
+	ruleRepo.On("GetByBuildingID", ctx, int64(1)).Return(&domain.Rule{DailyPassLimitPerApartment: 1, MaxPassDurationHours: 6}, nil)
+	eventRepo.On("Create", ctx, mock.AnythingOfType("*domain.Event"), mock.AnythingOfType("[]*domain.Pass")).Return(nil)
+
+	event, err := service.CreateEvent(ctx, domain.CreateEventRequest{
+		BuildingID:  1,
+		ApartmentID: 10,
+		Name:        "  День рождения ",
+		ValidFrom:   validFrom,
+		ValidTo:     validFrom.Add(5 * time.Hour),
+		Capacity:    10,
+		ResidentID:  &residentID,
+		Guests:      []domain.EventGuest{{CarPlate: &plate}, {GuestName: &name}},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "День рождения", event.Name)
+	assert.Equal(t, domain.EventStatusActive, event.Status)
+
+	passes := eventRepo.Calls[0].Arguments.Get(2).([]*domain.Pass)
+	require.Len(t, passes, 2, "the daily limit does not apply to event passes")
+	for _, pass := range passes {
+		assert.Equal(t, int64(10), pass.ApartmentID)
+		assert.Equal(t, &residentID, pass.ResidentID)
+		assert.Equal(t, "active", pass.Status)
+		assert.Equal(t, validFrom, pass.ValidFrom)
+	}
+	assert.Equal(t, &plate, passes[0].CarPlate)
+	assert.Equal(t, &name, passes[1].GuestName)
+}
+
+func TestEventService_CreateEvent_Errors(t *testing.T) {
+	ctx := context.Background()
+	plate := "A123BC77"
+	validFrom := time.Now().Add(time.Hour)
+	guests := []domain.EventGuest{{CarPlate: &plate}, {}}
+
+	tests := []struct {
+		name    string
+		modify  func(req *domain.CreateEventRequest)
+		wantErr error
+		errText string
+	}{
+		{name: "empty name", modify: func(req *domain.CreateEventRequest) { req.Name = " " }, wantErr: ErrEventNameRequired},
+		{name: "no capacity", modify: func(req *domain.CreateEventRequest) { req.Capacity = 0 }, wantErr: ErrInvalidEventCapacity},
+		{name: "capacity above limit", modify: func(req *domain.CreateEventRequest) { req.Capacity = maxEventCapacity + 1 }, wantErr: ErrInvalidEventCapacity},
+		{name: "empty guest list", modify: func(req *domain.CreateEventRequest) { req.Guests = nil }, wantErr: ErrEmptyGuestList},
+		{name: "over capacity", modify: func(req *domain.CreateEventRequest) { req.Capacity = 1 }, wantErr: ErrEventOverCapacity},
+		{name: "apartment of another building", modify: func(req *domain.CreateEventRequest) { req.BuildingID = 2 }, wantErr: ErrApartmentNotFound},
+		{name: "window above the rules", modify: func(req *domain.CreateEventRequest) { req.ValidTo = validFrom.Add(7 * time.Hour) }, errText: "exceeds maximum of 6 hours"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, eventRepo, apartmentRepo, ruleRepo := newTestEventService()
+			apartmentRepo.On("GetByID", ctx, int64(10)).Return(&domain.Apartment{ID: 10, BuildingID: 1}, nil)
+			ruleRepo.On("GetByBuildingID", ctx, int64(1)).Return(&domain.Rule{DailyPassLimitPerApartment: 5, MaxPassDurationHours: 6}, nil)
+
+			req := domain.CreateEventRequest{
+				BuildingID:  1,
+				ApartmentID: 10,
+				Name:        "Праздник",
+				ValidFrom:   validFrom,
+				ValidTo:     validFrom.Add(3 * time.Hour),
+				Capacity:    10,
+				Guests:      guests,
+			}
+			tt.modify(&req)
+
+			event, err := service.CreateEvent(ctx, req)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.ErrorContains(t, err, tt.errText)
+			}
+			assert.Nil(t, event)
+			eventRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestEventService_CreateEvent_BannedGuest(t *testing.T) {
+	ctx := context.Background()
+	buildingID := int64(1)
+	allowed, banned := "A123BC77", "M999OP77"
+	validFrom := time.Now().Add(time.Hour)
+
+	eventRepo := new(MockEventRepo)
+	apartmentRepo := new(MockApartmentRepo)
+	ruleRepo := new(MockRuleRepo)
+	watchlistRepo := new(MockPlateWatchlistRepo)
+	passService := NewPassService(new(MockPassRepo), apartmentRepo, ruleRepo, new(MockScanEventRepo), nil, watchlistRepo, nil, time.UTC, zap.NewNop())
+	service := NewEventService(eventRepo, apartmentRepo, passService, nil, qr.NewGenerator(), zap.NewNop())
+
+	apartmentRepo.On("GetByID", ctx, int64(10)).Return(&domain.Apartment{ID: 10, BuildingID: buildingID}, nil)
+	ruleRepo.On("GetByBuildingID", ctx, buildingID).Return(&domain.Rule{DailyPassLimitPerApartment: 5, MaxPassDurationHours: 6}, nil)
+	watchlistRepo.On("GetByCarPlate", ctx, allowed, &buildingID).Return(nil, nil)
+	watchlistRepo.On("GetByCarPlate", ctx, banned, &buildingID).Return(&domain.WatchlistEntry{
+		ID: 3, BuildingID: buildingID, CarPlate: banned, Reason: "долг", Severity: domain.WatchlistSeverityBan,
+	}, nil)
+
+	event, err := service.CreateEvent(ctx, domain.CreateEventRequest{
+		BuildingID:  buildingID,
+		ApartmentID: 10,
+		Name:        "Праздник",
+		ValidFrom:   validFrom,
+		ValidTo:     validFrom.Add(3 * time.Hour),
+		Capacity:    10,
+		Guests:      []domain.EventGuest{{CarPlate: &allowed, Row: 2}, {CarPlate: &banned, Row: 3}},
+	})
+
+	assert.Nil(t, event)
+	var listErr *GuestListError
+	require.ErrorAs(t, err, &listErr)
+	require.Len(t, listErr.Rows, 1)
+	assert.Equal(t, 3, listErr.Rows[0].Row)
+	assert.Contains(t, listErr.Rows[0].Error, "banned")
+	eventRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+}
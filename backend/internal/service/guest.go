@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"yardpass/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// maxRecentGuests is how many non-starred guests are remembered per
+	// resident.
+	maxRecentGuests = 20
+	// maxStarredGuests limits the starred guests per resident.
+	maxStarredGuests = 10
+)
+
+var ErrSavedGuestNotFound = errors.New("saved guest not found")
+
+// GuestService remembers the guests residents issue passes to, so that they
+// can be picked again in one tap.
+type GuestService struct {
+	guestRepo domain.SavedGuestRepository
+	logger    *zap.Logger
+}
+
+func NewGuestService(guestRepo domain.SavedGuestRepository, logger *zap.Logger) *GuestService {
+	return &GuestService{
+		guestRepo: guestRepo,
+		logger:    logger,
+	}
+}
+
+// RememberGuest records a car pass issued by the resident and forgets the
+// oldest non-starred guests beyond maxRecentGuests.
+func (s *GuestService) RememberGuest(ctx context.Context, residentID int64, carPlate string, guestName *string) error {
+	normalized := normalizeCarPlate(carPlate)
+	if normalized == "" {
+		return errors.New("invalid car plate number")
+	}
+	if guestName != nil {
+		name := strings.TrimSpace(*guestName)
+		if name == "" {
+			guestName = nil
+		} else {
+			guestName = &name
+		}
+	}
+
+	if err := s.guestRepo.Touch(ctx, residentID, normalized, guestName); err != nil {
+		return fmt.Errorf("failed to save guest: %w", err)
+	}
+	if err := s.guestRepo.PruneRecent(ctx, residentID, maxRecentGuests); err != nil {
+		return fmt.Errorf("failed to prune guests: %w", err)
+	}
+	return nil
+}
+
+// ListGuests returns the resident's guests, starred first, then by last use.
+// A limit of 0 returns all of them.
+func (s *GuestService) ListGuests(ctx context.Context, residentID int64, limit int) ([]*domain.SavedGuest, error) {
+	guests, err := s.guestRepo.ListByResidentID(ctx, residentID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list guests: %w", err)
+	}
+	return guests, nil
+}
+
+// GetGuest returns a guest of the resident.
+func (s *GuestService) GetGuest(ctx context.Context, residentID, guestID int64) (*domain.SavedGuest, error) {
+	guest, err := s.guestRepo.GetByID(ctx, guestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get guest: %w", err)
+	}
+	if guest == nil || guest.ResidentID != residentID {
+		return nil, ErrSavedGuestNotFound
+	}
+	return guest, nil
+}
+
+// SetStarred stars or unstars a guest of the resident.
+func (s *GuestService) SetStarred(ctx context.Context, residentID, guestID int64, starred bool) (*domain.SavedGuest, error) {
+	guest, err := s.GetGuest(ctx, residentID, guestID)
+	if err != nil {
+		return nil, err
+	}
+
+	if starred && !guest.Starred {
+		guests, err := s.guestRepo.ListByResidentID(ctx, residentID, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list guests: %w", err)
+		}
+		count := 0
+		for _, g := range guests {
+			if g.Starred {
+				count++
+			}
+		}
+		if count >= maxStarredGuests {
+			return nil, fmt.Errorf("starred guests limit reached (%d)", maxStarredGuests)
+		}
+	}
+
+	if err := s.guestRepo.SetStarred(ctx, guest.ID, starred); err != nil {
+		return nil, fmt.Errorf("failed to update guest: %w", err)
+	}
+	guest.Starred = starred
+	return guest, nil
+}
+
+// DeleteGuest forgets a guest of the resident.
+func (s *GuestService) DeleteGuest(ctx context.Context, residentID, guestID int64) error {
+	guest, err := s.GetGuest(ctx, residentID, guestID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.guestRepo.Delete(ctx, guest.ID); err != nil {
+		return fmt.Errorf("failed to delete guest: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"yardpass/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type MockSavedGuestRepo struct {
+	mock.Mock
+}
+
+func (m *MockSavedGuestRepo) GetByID(ctx context.Context, id int64) (*domain.SavedGuest, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SavedGuest), args.Error(1)
+}
+
+func (m *MockSavedGuestRepo) ListByResidentID(ctx context.Context, residentID int64, limit int) ([]*domain.SavedGuest, error) {
+	args := m.Called(ctx, residentID, limit)
+	return args.Get(0).([]*domain.SavedGuest), args.Error(1)
+}
+
+func (m *MockSavedGuestRepo) Touch(ctx context.Context, residentID int64, carPlate string, guestName *string) error {
+	args := m.Called(ctx, residentID, carPlate, guestName)
+	return args.Error(0)
+}
+
+func (m *MockSavedGuestRepo) SetStarred(ctx context.Context, id int64, starred bool) error {
+	args := m.Called(ctx, id, starred)
+	return args.Error(0)
+}
+
+func (m *MockSavedGuestRepo) Delete(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockSavedGuestRepo) PruneRecent(ctx context.Context, residentID int64, keep int) error {
+	args := m.Called(ctx, residentID, keep)
+	return args.Error(0)
+}
+
+func TestGuestService_RememberGuest(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockSavedGuestRepo)
+	service := NewGuestService(repo, zap.NewNop())
+
+	blank := "  "
+	repo.On("Touch", ctx, int64(1), "A123BC77", (*string)(nil)).Return(nil)
+	repo.On("PruneRecent", ctx, int64(1), maxRecentGuests).Return(nil)
+
+	assert.NoError(t, service.RememberGuest(ctx, 1, " a123 bc77", &blank))
+	assert.Error(t, service.RememberGuest(ctx, 1, "  ", nil))
+	repo.AssertExpectations(t)
+}
+
+func TestGuestService_OwnedByResident(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockSavedGuestRepo)
+	service := NewGuestService(repo, zap.NewNop())
+
+	repo.On("GetByID", ctx, int64(5)).Return(&domain.SavedGuest{ID: 5, ResidentID: 2, CarPlate: "A123BC77"}, nil)
+
+	_, err := service.SetStarred(ctx, 1, 5, true)
+	assert.ErrorIs(t, err, ErrSavedGuestNotFound)
+	assert.ErrorIs(t, service.DeleteGuest(ctx, 1, 5), ErrSavedGuestNotFound)
+	repo.AssertNotCalled(t, "SetStarred", mock.Anything, mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestGuestService_StarredLimit(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockSavedGuestRepo)
+	service := NewGuestService(repo, zap.NewNop())
+
+	var guests []*domain.SavedGuest
+	for i := 0; i < maxStarredGuests; i++ {
+		guests = append(guests, &domain.SavedGuest{ID: int64(i + 10), ResidentID: 1, Starred: true})
+	}
+	repo.On("GetByID", ctx, int64(5)).Return(&domain.SavedGuest{ID: 5, ResidentID: 1}, nil)
+	repo.On("ListByResidentID", ctx, int64(1), 0).Return(guests, nil)
+
+	_, err := service.SetStarred(ctx, 1, 5, true)
+	assert.ErrorContains(t, err, "limit")
+
+	repo.On("SetStarred", ctx, int64(5), false).Return(nil)
+	guest, err := service.SetStarred(ctx, 1, 5, false)
+	assert.NoError(t, err)
+	assert.False(t, guest.Starred)
+}
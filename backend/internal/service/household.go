@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"yardpass/internal/domain"
+	"yardpass/internal/redis"
+
+	"go.uber.org/zap"
+)
+
+const householdInviteTTL = 24 * time.Hour
+
+type householdInvite struct {
+	ApartmentID    int64 `json:"apartment_id"`
+	InvitedBy      int64 `json:"invited_by"`
+	CanIssuePasses bool  `json:"can_issue_passes"`
+}
+
+// CreateHouseholdInvite issues a one-time code with which another Telegram
+// user joins the apartment of the primary resident as a household member.
+func (s *ResidentService) CreateHouseholdInvite(ctx context.Context, primaryID int64, canIssuePasses bool) (string, time.Duration, error) {
+	primary, err := s.getPrimary(ctx, primaryID)
+	if err != nil {
+		return "", 0, err
+	}
+
+	buf := make([]byte, 5)
+	if _, err := rand.Read(buf); err != nil {
+		return "", 0, fmt.Errorf("failed to generate invite code: %w", err)
+	}
+	code := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(buf)
+
+	payload, err := json.Marshal(householdInvite{
+		ApartmentID:    primary.ApartmentID,
+		InvitedBy:      primary.ID,
+		CanIssuePasses: canIssuePasses,
+	})
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to marshal invite: %w", err)
+	}
+
+	if err := s.redis.Set(ctx, householdInviteKey(code), payload, householdInviteTTL); err != nil {
+		return "", 0, fmt.Errorf("failed to store invite: %w", err)
+	}
+
+	return code, householdInviteTTL, nil
+}
+
+// AcceptHouseholdInvite consumes an invite code and adds the Telegram user to
+// the apartment as a household member.
+func (s *ResidentService) AcceptHouseholdInvite(ctx context.Context, code string, telegramID, chatID int64, name *string) (*domain.Resident, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return nil, errors.New("invite code is required")
+	}
+
+	payload, err := s.redis.GetDel(ctx, householdInviteKey(code))
+	if err != nil {
+		if redis.IsNil(err) {
+			return nil, errors.New("invite code is invalid or expired")
+		}
+		return nil, fmt.Errorf("failed to check invite: %w", err)
+	}
+
+	var invite householdInvite
+	if err := json.Unmarshal([]byte(payload), &invite); err != nil {
+		return nil, fmt.Errorf("invalid invite payload: %w", err)
+	}
+
+	existing, err := s.residentRepo.GetByTelegramIDAndApartmentID(ctx, telegramID, invite.ApartmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check telegram_id: %w", err)
+	}
+	if existing != nil && (existing.Status == "active" || existing.Role != "member") {
+		return nil, errors.New("you are already a resident of this apartment")
+	}
+
+	if existing != nil {
+		// A removed member keeps the inactive row; joining again revives it.
+		existing.ChatID = chatID
+		existing.Name = name
+		existing.Status = "active"
+		existing.CanIssuePasses = invite.CanIssuePasses
+		existing.InvitedBy = &invite.InvitedBy
+		if err := s.residentRepo.Update(ctx, existing); err != nil {
+			return nil, fmt.Errorf("failed to restore household member: %w", err)
+		}
+
+		s.logger.Info("household member rejoined",
+			zap.Int64("resident_id", existing.ID),
+			zap.Int64("apartment_id", existing.ApartmentID),
+			zap.Int64("invited_by", invite.InvitedBy),
+		)
+
+		return existing, nil
+	}
+
+	member := &domain.Resident{
+		ApartmentID:    invite.ApartmentID,
+		TelegramID:     telegramID,
+		ChatID:         chatID,
+		Name:           name,
+		Status:         "active",
+		Role:           "member",
+		CanIssuePasses: invite.CanIssuePasses,
+		InvitedBy:      &invite.InvitedBy,
+	}
+
+	if err := s.residentRepo.Create(ctx, member); err != nil {
+		return nil, fmt.Errorf("failed to create household member: %w", err)
+	}
+
+	s.logger.Info("household member joined",
+		zap.Int64("resident_id", member.ID),
+		zap.Int64("apartment_id", member.ApartmentID),
+		zap.Int64("invited_by", invite.InvitedBy),
+	)
+
+	return member, nil
+}
+
+// ListHousehold returns the active residents of the primary resident's
+// apartment.
+func (s *ResidentService) ListHousehold(ctx context.Context, primaryID int64) ([]*domain.Resident, error) {
+	primary, err := s.getPrimary(ctx, primaryID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := "active"
+	return s.residentRepo.List(ctx, domain.ResidentFilters{ApartmentID: &primary.ApartmentID, Status: &status})
+}
+
+func (s *ResidentService) SetMemberCanIssuePasses(ctx context.Context, primaryID, memberID int64, canIssuePasses bool) (*domain.Resident, error) {
+	member, err := s.getMember(ctx, primaryID, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	member.CanIssuePasses = canIssuePasses
+	if err := s.residentRepo.Update(ctx, member); err != nil {
+		return nil, fmt.Errorf("failed to update household member: %w", err)
+	}
+
+	return member, nil
+}
+
+// RemoveHouseholdMember deactivates the member and revokes their active
+// passes. The row is kept so that the member's exception records and pass
+// history survive.
+func (s *ResidentService) RemoveHouseholdMember(ctx context.Context, primaryID, memberID int64) (*domain.Resident, error) {
+	member, err := s.getMember(ctx, primaryID, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	revoked, err := s.residentRepo.Deactivate(ctx, member.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove household member: %w", err)
+	}
+	member.Status = "inactive"
+
+	s.logger.Info("household member removed",
+		zap.Int64("resident_id", member.ID),
+		zap.Int64("apartment_id", member.ApartmentID),
+		zap.Int64("removed_by", primaryID),
+		zap.Int("revoked_passes", revoked),
+	)
+
+	return member, nil
+}
+
+func (s *ResidentService) getPrimary(ctx context.Context, primaryID int64) (*domain.Resident, error) {
+	primary, err := s.residentRepo.GetByID(ctx, primaryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resident: %w", err)
+	}
+	if primary == nil || primary.Status != "active" {
+		return nil, errors.New("resident not found")
+	}
+	if primary.Role != "primary" {
+		return nil, errors.New("only the primary resident can manage the household")
+	}
+	return primary, nil
+}
+
+func (s *ResidentService) getMember(ctx context.Context, primaryID, memberID int64) (*domain.Resident, error) {
+	primary, err := s.getPrimary(ctx, primaryID)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := s.residentRepo.GetByID(ctx, memberID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get household member: %w", err)
+	}
+	if member == nil || member.ApartmentID != primary.ApartmentID || member.Role != "member" || member.Status != "active" {
+		return nil, errors.New("household member not found")
+	}
+	return member, nil
+}
+
+func householdInviteKey(code string) string {
+	return "household_invite:" + code
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"yardpass/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestResidentService_RemoveHouseholdMember(t *testing.T) {
+	ctx := context.Background()
+
+	newService := func(member *domain.Resident) (*ResidentService, *MockResidentRepo) {
+		residentRepo := new(MockResidentRepo)
+		residentRepo.On("GetByID", ctx, int64(1)).Return(&domain.Resident{ID: 1, ApartmentID: 10, Status: "active", Role: "primary"}, nil)
+		residentRepo.On("GetByID", ctx, member.ID).Return(member, nil)
+		return NewResidentService(residentRepo, new(MockApartmentRepo), nil, zap.NewNop()), residentRepo
+	}
+
+	t.Run("deactivates the member and revokes their passes", func(t *testing.T) {
+		service, residentRepo := newService(&domain.Resident{ID: 2, ApartmentID: 10, Status: "active", Role: "member"})
+		residentRepo.On("Deactivate", ctx, int64(2)).Return(3, nil)
+
+		member, err := service.RemoveHouseholdMember(ctx, 1, 2)
+		require.NoError(t, err)
+		assert.Equal(t, "inactive", member.Status)
+		residentRepo.AssertExpectations(t)
+	})
+
+	t.Run("an already removed member is not found", func(t *testing.T) {
+		service, residentRepo := newService(&domain.Resident{ID: 2, ApartmentID: 10, Status: "inactive", Role: "member"})
+
+		_, err := service.RemoveHouseholdMember(ctx, 1, 2)
+		assert.EqualError(t, err, "household member not found")
+		residentRepo.AssertNotCalled(t, "Deactivate", mock.Anything, mock.Anything)
+	})
+
+	t.Run("a member of another apartment is not found", func(t *testing.T) {
+		service, residentRepo := newService(&domain.Resident{ID: 3, ApartmentID: 11, Status: "active", Role: "member"})
+
+		_, err := service.RemoveHouseholdMember(ctx, 1, 3)
+		assert.EqualError(t, err, "household member not found")
+		residentRepo.AssertNotCalled(t, "Deactivate", mock.Anything, mock.Anything)
+	})
+}
@@ -2,23 +2,67 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
 	"yardpass/internal/domain"
+	"yardpass/internal/plate"
+	"yardpass/internal/qr"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
+// passStartGrace tolerates clock skew and slow clients for passes that
+// start "now".
+const passStartGrace = 5 * time.Minute
+
+// dynamicQRSkewSteps is how many time steps a rotating code may be off, to
+// tolerate clock skew between the phone and the server and a slow scan.
+const dynamicQRSkewSteps = 1
+
+var (
+	ErrPassNotActive       = errors.New("pass is not active")
+	ErrCompanyNameRequired = errors.New("company_name is required")
+	ErrInvalidWeekdays     = errors.New("weekdays must be ISO weekday numbers from 1 (Monday) to 7 (Sunday)")
+	ErrInvalidDailyWindow  = errors.New("daily_from and daily_to must both be set as HH:MM and differ")
+	ErrInvalidCategory     = errors.New("invalid pass category")
+	ErrPlateBanned         = errors.New("car plate is banned in the building")
+	ErrInvalidCarPlate     = errors.New("invalid car plate number")
+	ErrCannotIssuePasses   = errors.New("you are not allowed to issue passes")
+	ErrPassNotFound        = errors.New("pass not found")
+
+	// ErrPassTooLong and ErrDailyLimitExceeded are the rules a resident can
+	// ask the admins an exception to.
+	ErrPassTooLong        = errors.New("pass duration exceeds maximum")
+	ErrDailyLimitExceeded = errors.New("daily pass limit exceeded")
+)
+
+// residentCategories are the pass categories residents may issue; the rest
+// are issued by admins with CreateContractorPass.
+var residentCategories = []string{
+	domain.PassCategoryGuest,
+	domain.PassCategoryTaxi,
+	domain.PassCategoryDelivery,
+}
+
 type PassService struct {
 	passRepo      domain.PassRepository
 	apartmentRepo domain.ApartmentRepository
 	ruleRepo      domain.RuleRepository
 	scanEventRepo domain.ScanEventRepository
+	vehicleRepo   domain.ResidentVehicleRepository
+	watchlistRepo domain.PlateWatchlistRepository
+	residentRepo  domain.ResidentRepository
 	logger        *zap.Logger
+
+	// location is where the weekdays and daily windows of contractor
+	// passes are counted.
+	location *time.Location
 }
 
 func NewPassService(
@@ -26,6 +70,10 @@ func NewPassService(
 	apartmentRepo domain.ApartmentRepository,
 	ruleRepo domain.RuleRepository,
 	scanEventRepo domain.ScanEventRepository,
+	vehicleRepo domain.ResidentVehicleRepository,
+	watchlistRepo domain.PlateWatchlistRepository,
+	residentRepo domain.ResidentRepository,
+	location *time.Location,
 	logger *zap.Logger,
 ) *PassService {
 	return &PassService{
@@ -33,18 +81,37 @@ func NewPassService(
 		apartmentRepo: apartmentRepo,
 		ruleRepo:      ruleRepo,
 		scanEventRepo: scanEventRepo,
+		vehicleRepo:   vehicleRepo,
+		watchlistRepo: watchlistRepo,
+		residentRepo:  residentRepo,
 		logger:        logger,
+		location:      location,
 	}
 }
 
 func (s *PassService) CreatePass(ctx context.Context, req domain.CreatePassRequest) (*domain.Pass, error) {
+	category := req.Category
+	if category == "" {
+		category = domain.PassCategoryGuest
+	}
+	if !slices.Contains(residentCategories, category) {
+		return nil, fmt.Errorf("%w: residents can issue %s passes", ErrInvalidCategory, strings.Join(residentCategories, ", "))
+	}
+
 	var carPlate *string
 	if req.CarPlate != nil && *req.CarPlate != "" {
-		normalized := normalizeCarPlate(*req.CarPlate)
-		if normalized == "" {
-			return nil, errors.New("invalid car plate number")
+		parsed, err := ParseCarPlate(*req.CarPlate)
+		if err != nil {
+			return nil, err
 		}
-		carPlate = &normalized
+		carPlate = &parsed.Normalized
+	}
+
+	if req.ResidentID == nil {
+		return nil, errors.New("resident_id is required")
+	}
+	if err := s.checkCanIssuePasses(ctx, *req.ResidentID, req.ApartmentID); err != nil {
+		return nil, err
 	}
 
 	apartment, err := s.apartmentRepo.GetByID(ctx, req.ApartmentID)
@@ -55,71 +122,454 @@ func (s *PassService) CreatePass(ctx context.Context, req domain.CreatePassReque
 		return nil, errors.New("apartment not found")
 	}
 
-	rule, err := s.ruleRepo.GetByBuildingID(ctx, apartment.BuildingID)
+	if carPlate != nil {
+		if err := s.checkNotBanned(ctx, *carPlate, apartment.BuildingID); err != nil {
+			return nil, err
+		}
+	}
+
+	rule, err := s.rulesForBuilding(ctx, apartment.BuildingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkWindow(rule, category, req.ValidFrom, req.ValidTo, req.ExemptRule == domain.PassRuleMaxDuration); err != nil {
+		return nil, err
+	}
+
+	if req.ExemptRule != domain.PassRuleDailyLimit {
+		// The daily limit is shared by everyone in the apartment's household.
+		count, err := s.passRepo.CountActiveTodayByApartmentID(ctx, req.ApartmentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check daily limit: %w", err)
+		}
+		if count >= rule.DailyPassLimitPerApartment {
+			return nil, fmt.Errorf("%w: your apartment has created %d passes today (limit: %d)", ErrDailyLimitExceeded, count, rule.DailyPassLimitPerApartment)
+		}
+		if err := s.checkCategoryLimit(ctx, rule, req.ApartmentID, category); err != nil {
+			return nil, err
+		}
+	}
+
+	pass := &domain.Pass{
+		ID:          uuid.New(),
+		ApartmentID: req.ApartmentID,
+		ResidentID:  req.ResidentID,
+		CarPlate:    carPlate,
+		GuestName:   req.GuestName,
+		ValidFrom:   req.ValidFrom,
+		ValidTo:     req.ValidTo,
+		Status:      "active",
+		SingleUse:   req.SingleUse,
+		Dynamic:     req.Dynamic,
+		Category:    category,
+	}
+	if pass.Dynamic {
+		pass.QRSecret, err = qr.NewSecret()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.passRepo.Create(ctx, pass); err != nil {
+		return nil, fmt.Errorf("failed to create pass: %w", err)
+	}
+
+	logFields := []zap.Field{
+		zap.String("pass_id", pass.ID.String()),
+		zap.Int64("apartment_id", pass.ApartmentID),
+		zap.String("category", category),
+	}
+	if req.ExemptRule != "" {
+		logFields = append(logFields, zap.String("exempt_rule", req.ExemptRule))
+	}
+	if carPlate != nil {
+		logFields = append(logFields, zap.String("car_plate", *carPlate))
+	} else {
+		logFields = append(logFields, zap.String("type", "pedestrian"))
+	}
+	s.logger.Info("pass created", logFields...)
+
+	return pass, nil
+}
+
+// RulesForApartment returns the rules of the apartment's building, or the
+// defaults if the building has none.
+func (s *PassService) RulesForApartment(ctx context.Context, apartmentID int64) (*domain.Rule, error) {
+	apartment, err := s.apartmentRepo.GetByID(ctx, apartmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get apartment: %w", err)
+	}
+	if apartment == nil {
+		return nil, errors.New("apartment not found")
+	}
+
+	return s.rulesForBuilding(ctx, apartment.BuildingID)
+}
+
+func (s *PassService) rulesForBuilding(ctx context.Context, buildingID int64) (*domain.Rule, error) {
+	rule, err := s.ruleRepo.GetByBuildingID(ctx, buildingID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get rules: %w", err)
 	}
 	if rule == nil {
 		rule = &domain.Rule{
+			BuildingID:                 buildingID,
 			DailyPassLimitPerApartment: 5,
 			MaxPassDurationHours:       24,
+			MaxContractorPassDays:      30,
 		}
 	}
 
-	maxDuration := time.Duration(rule.MaxPassDurationHours) * time.Hour
-	if req.ValidTo.Sub(req.ValidFrom) > maxDuration {
-		return nil, fmt.Errorf("pass duration exceeds maximum of %d hours", rule.MaxPassDurationHours)
+	return rule, nil
+}
+
+// CheckPassWindow validates a validity window of a pass of category against
+// the building rules without creating a pass, so that clients can reject it
+// before asking for the remaining details.
+func (s *PassService) CheckPassWindow(ctx context.Context, apartmentID int64, category string, validFrom, validTo time.Time) error {
+	rule, err := s.RulesForApartment(ctx, apartmentID)
+	if err != nil {
+		return err
 	}
+	return s.checkWindow(rule, category, validFrom, validTo, false)
+}
 
-	if req.ResidentID == nil {
-		return nil, errors.New("resident_id is required")
+// checkCanIssuePasses refuses residents of other apartments, inactive ones
+// and household members whose right to issue passes was taken away. It is
+// checked when the pass is created, not when a flow starts, so that a right
+// revoked in the meantime counts.
+func (s *PassService) checkCanIssuePasses(ctx context.Context, residentID, apartmentID int64) error {
+	resident, err := s.residentRepo.GetByID(ctx, residentID)
+	if err != nil {
+		return fmt.Errorf("failed to get resident: %w", err)
+	}
+	if resident == nil || resident.ApartmentID != apartmentID || resident.Status != "active" || !resident.CanIssuePasses {
+		return ErrCannotIssuePasses
 	}
+	return nil
+}
 
-	count, err := s.passRepo.CountActiveTodayByResidentID(ctx, *req.ResidentID)
+// checkNotBanned refuses passes for a plate banned in the building.
+func (s *PassService) checkNotBanned(ctx context.Context, carPlate string, buildingID int64) error {
+	entry, err := s.watchlistRepo.GetByCarPlate(ctx, carPlate, &buildingID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check daily limit: %w", err)
+		return fmt.Errorf("failed to check watchlist: %w", err)
+	}
+	if entry != nil && entry.Severity == domain.WatchlistSeverityBan {
+		return fmt.Errorf("%w: %s", ErrPlateBanned, entry.Reason)
 	}
-	if count >= rule.DailyPassLimitPerApartment {
-		return nil, fmt.Errorf("daily pass limit exceeded: you have created %d passes today (limit: %d)", count, rule.DailyPassLimitPerApartment)
+	return nil
+}
+
+// checkCategoryLimit enforces the daily limit of the category, on top of the
+// apartment's overall limit.
+func (s *PassService) checkCategoryLimit(ctx context.Context, rule *domain.Rule, apartmentID int64, category string) error {
+	cr := rule.CategoryRule(category)
+	if cr == nil || cr.DailyLimit == nil {
+		return nil
+	}
+
+	count, err := s.passRepo.CountActiveTodayByCategory(ctx, apartmentID, category)
+	if err != nil {
+		return fmt.Errorf("failed to check daily limit: %w", err)
+	}
+	if count >= *cr.DailyLimit {
+		return fmt.Errorf("%w for %s passes: your apartment has created %d today (limit: %d)", ErrDailyLimitExceeded, category, count, *cr.DailyLimit)
+	}
+	return nil
+}
+
+// checkWindow validates a validity window against the rules. anyDuration
+// skips the maximum duration of an approved exception.
+func (s *PassService) checkWindow(rule *domain.Rule, category string, validFrom, validTo time.Time, anyDuration bool) error {
+	if !validTo.After(validFrom) {
+		return errors.New("valid_to must be after valid_from")
+	}
+
+	if validFrom.Before(time.Now().Add(-passStartGrace)) {
+		return errors.New("pass cannot start in the past")
+	}
+
+	maxHours := rule.MaxDurationHours(category)
+	if !anyDuration && validTo.Sub(validFrom) > time.Duration(maxHours)*time.Hour {
+		return fmt.Errorf("%w of %d hours", ErrPassTooLong, maxHours)
 	}
 
 	if rule.QuietHoursStart != nil && rule.QuietHoursEnd != nil {
-		if err := s.validateQuietHours(req.ValidFrom, req.ValidTo, *rule.QuietHoursStart, *rule.QuietHoursEnd); err != nil {
+		if err := s.validateQuietHours(validFrom, validTo, *rule.QuietHoursStart, *rule.QuietHoursEnd); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CreateContractorPass issues a contractor or service pass for a renovation
+// crew or a service company. It may last up to the category's limit, by
+// default the building's MaxContractorPassDays, does not count towards the
+// apartment's daily limit and admits only on its weekdays within its daily
+// window. buildingID limits the apartment to an admin's building; nil
+// allows any.
+func (s *PassService) CreateContractorPass(ctx context.Context, req domain.CreateContractorPassRequest, buildingID *int64) (*domain.Pass, error) {
+	category := req.Category
+	if category == "" {
+		category = domain.PassCategoryContractor
+	}
+	if category != domain.PassCategoryContractor && category != domain.PassCategoryService {
+		return nil, fmt.Errorf("%w: admins issue contractor and service passes here", ErrInvalidCategory)
+	}
+
+	companyName := strings.TrimSpace(req.CompanyName)
+	if companyName == "" {
+		return nil, ErrCompanyNameRequired
+	}
+
+	var carPlate *string
+	if req.CarPlate != nil && *req.CarPlate != "" {
+		parsed, err := ParseCarPlate(*req.CarPlate)
+		if err != nil {
+			return nil, err
+		}
+		carPlate = &parsed.Normalized
+	}
+
+	weekdays, err := normalizeWeekdays(req.Weekdays)
+	if err != nil {
+		return nil, err
+	}
+
+	var dailyFrom, dailyTo *string
+	if req.DailyFrom != "" || req.DailyTo != "" {
+		from, errFrom := parseTime(req.DailyFrom)
+		to, errTo := parseTime(req.DailyTo)
+		if errFrom != nil || errTo != nil || from.Equal(to) {
+			return nil, ErrInvalidDailyWindow
+		}
+		fromStr, toStr := from.Format("15:04"), to.Format("15:04")
+		dailyFrom, dailyTo = &fromStr, &toStr
+	}
+
+	apartment, err := s.apartmentRepo.GetByID(ctx, req.ApartmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get apartment: %w", err)
+	}
+	if apartment == nil || (buildingID != nil && apartment.BuildingID != *buildingID) {
+		return nil, ErrApartmentNotFound
+	}
+
+	if carPlate != nil {
+		if err := s.checkNotBanned(ctx, *carPlate, apartment.BuildingID); err != nil {
 			return nil, err
 		}
 	}
 
+	rule, err := s.rulesForBuilding(ctx, apartment.BuildingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !req.ValidTo.After(req.ValidFrom) {
+		return nil, errors.New("valid_to must be after valid_from")
+	}
+	if req.ValidFrom.Before(time.Now().Add(-passStartGrace)) {
+		return nil, errors.New("pass cannot start in the past")
+	}
+	if maxHours := rule.MaxDurationHours(category); req.ValidTo.Sub(req.ValidFrom) > time.Duration(maxHours)*time.Hour {
+		if maxHours%24 == 0 {
+			return nil, fmt.Errorf("%s pass duration exceeds maximum of %d days", category, maxHours/24)
+		}
+		return nil, fmt.Errorf("%s pass duration exceeds maximum of %d hours", category, maxHours)
+	}
+	if err := s.checkCategoryLimit(ctx, rule, req.ApartmentID, category); err != nil {
+		return nil, err
+	}
+
 	pass := &domain.Pass{
 		ID:          uuid.New(),
 		ApartmentID: req.ApartmentID,
-		ResidentID:  req.ResidentID,
 		CarPlate:    carPlate,
 		GuestName:   req.GuestName,
 		ValidFrom:   req.ValidFrom,
 		ValidTo:     req.ValidTo,
 		Status:      "active",
+		Category:    category,
+		CompanyName: &companyName,
+		Weekdays:    weekdays,
+		DailyFrom:   dailyFrom,
+		DailyTo:     dailyTo,
+		IssuedBy:    req.IssuedBy,
 	}
 
 	if err := s.passRepo.Create(ctx, pass); err != nil {
 		return nil, fmt.Errorf("failed to create pass: %w", err)
 	}
 
-	logFields := []zap.Field{
+	s.logger.Info("contractor pass created",
 		zap.String("pass_id", pass.ID.String()),
 		zap.Int64("apartment_id", pass.ApartmentID),
+		zap.String("category", category),
+		zap.String("company", companyName),
+	)
+
+	return pass, nil
+}
+
+// ListContractorPasses returns the contractor and service passes that have
+// not ended, of every building if buildingID is nil.
+func (s *PassService) ListContractorPasses(ctx context.Context, buildingID *int64) ([]*domain.Pass, error) {
+	return s.passRepo.ListContractorPasses(ctx, buildingID)
+}
+
+// normalizeWeekdays sorts the weekdays and drops repeats. All seven days
+// mean no restriction and are stored as none.
+func normalizeWeekdays(weekdays []int) ([]int, error) {
+	var days []int
+	for _, day := range weekdays {
+		if day < 1 || day > 7 {
+			return nil, ErrInvalidWeekdays
+		}
+		if !slices.Contains(days, day) {
+			days = append(days, day)
+		}
 	}
-	if carPlate != nil {
-		logFields = append(logFields, zap.String("car_plate", *carPlate))
-	} else {
-		logFields = append(logFields, zap.String("type", "pedestrian"))
+	if len(days) == 7 {
+		return nil, nil
+	}
+	slices.Sort(days)
+	return days, nil
+}
+
+// withinSchedule reports whether a contractor pass admits at local time
+// now. A daily window that ends before it starts runs past midnight and
+// belongs to the weekday it started on.
+func withinSchedule(pass *domain.Pass, now time.Time) bool {
+	day := now
+	if pass.DailyFrom != nil && pass.DailyTo != nil {
+		var ok bool
+		if day, ok = dailyWindowDay(now, *pass.DailyFrom, *pass.DailyTo); !ok {
+			return false
+		}
+	}
+
+	if len(pass.Weekdays) == 0 {
+		return true
+	}
+	weekday := int(day.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	return slices.Contains(pass.Weekdays, weekday)
+}
+
+// dailyWindowDay reports whether now falls in the daily window from-to
+// ("HH:MM") and returns the day the window opened, which is the day before
+// for the part of a window that runs past midnight.
+func dailyWindowDay(now time.Time, from, to string) (time.Time, bool) {
+	start, errFrom := parseTime(from)
+	end, errTo := parseTime(to)
+	if errFrom != nil || errTo != nil {
+		return now, false
+	}
+
+	nowMin := now.Hour()*60 + now.Minute()
+	fromMin := start.Hour()*60 + start.Minute()
+	toMin := end.Hour()*60 + end.Minute()
+
+	switch {
+	case fromMin < toMin:
+		return now, nowMin >= fromMin && nowMin < toMin
+	case nowMin < toMin:
+		return now.AddDate(0, 0, -1), true
+	default:
+		return now, nowMin >= fromMin
+	}
+}
+
+// PassScope narrows pass management to the passes a caller may manage.
+// BuildingID limits staff to their building. ResidentID limits a resident to
+// own passes, or to the whole apartment for the primary resident. Nil fields
+// are not checked.
+type PassScope struct {
+	BuildingID *int64
+	ResidentID *int64
+}
+
+// checkPassScope reports a pass outside the scope as not found, so that
+// callers cannot tell passes of other buildings from missing ones.
+func checkPassScope(ctx context.Context, apartmentRepo domain.ApartmentRepository, residentRepo domain.ResidentRepository, pass *domain.Pass, scope PassScope) error {
+	if scope.BuildingID != nil {
+		apartment, err := apartmentRepo.GetByID(ctx, pass.ApartmentID)
+		if err != nil {
+			return fmt.Errorf("failed to get apartment: %w", err)
+		}
+		if apartment == nil || apartment.BuildingID != *scope.BuildingID {
+			return ErrPassNotFound
+		}
+	}
+
+	if scope.ResidentID != nil {
+		resident, err := residentRepo.GetByID(ctx, *scope.ResidentID)
+		if err != nil {
+			return fmt.Errorf("failed to get resident: %w", err)
+		}
+		if resident == nil || resident.Status != "active" || resident.ApartmentID != pass.ApartmentID {
+			return ErrPassNotFound
+		}
+		if resident.Role != "primary" && (pass.ResidentID == nil || *pass.ResidentID != resident.ID) {
+			return ErrPassNotFound
+		}
+	}
+
+	return nil
+}
+
+// EnableDynamicQR switches an active pass to rotating codes; the static QR
+// already sent stops working. Passes outside the scope are reported as not
+// found.
+func (s *PassService) EnableDynamicQR(ctx context.Context, passID uuid.UUID, scope PassScope) (*domain.Pass, error) {
+	pass, err := s.passRepo.GetByID(ctx, passID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pass: %w", err)
+	}
+	if pass == nil {
+		return nil, ErrPassNotFound
+	}
+	if err := checkPassScope(ctx, s.apartmentRepo, s.residentRepo, pass, scope); err != nil {
+		return nil, err
+	}
+	if pass.Status != "active" || time.Now().After(pass.ValidTo) {
+		return nil, ErrPassNotActive
+	}
+	if pass.Dynamic {
+		return pass, nil
 	}
-	s.logger.Info("pass created", logFields...)
 
+	secret, err := qr.NewSecret()
+	if err != nil {
+		return nil, err
+	}
+	pass.QRSecret, err = s.passRepo.SetQRSecret(ctx, passID, secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enable dynamic QR: %w", err)
+	}
+	pass.Dynamic = true
+
+	s.logger.Info("dynamic QR enabled", zap.String("pass_id", passID.String()))
 	return pass, nil
 }
 
 func (s *PassService) ValidatePass(ctx context.Context, passID uuid.UUID, guardUserID int64) (*domain.PassValidationResult, error) {
-	pass, err := s.passRepo.GetByID(ctx, passID)
+	return s.ValidateQR(ctx, qr.Code{PassID: passID}, guardUserID)
+}
+
+// ValidateQR validates a scanned code. Dynamic passes are only admitted by
+// a rotating code of the current time step, give or take
+// dynamicQRSkewSteps.
+func (s *PassService) ValidateQR(ctx context.Context, code qr.Code, guardUserID int64) (*domain.PassValidationResult, error) {
+	src := guardScan(guardUserID)
+
+	pass, err := s.passRepo.GetByID(ctx, code.PassID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get pass: %w", err)
 	}
@@ -132,10 +582,62 @@ func (s *PassService) ValidatePass(ctx context.Context, passID uuid.UUID, guardU
 		return result, nil
 	}
 
-	return s.validatePassInternal(ctx, pass, guardUserID)
+	if pass.Dynamic {
+		if reason := checkDynamicCode(pass, code, time.Now()); reason != "" {
+			s.logScanEvent(ctx, pass.ID, src, "invalid", reason)
+			return &domain.PassValidationResult{Valid: false, Reason: reason}, nil
+		}
+	}
+
+	if pass.CarPlate == nil {
+		return s.validatePassInternal(ctx, pass, src)
+	}
+
+	apartment, err := s.apartmentRepo.GetByID(ctx, pass.ApartmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get apartment: %w", err)
+	}
+	if apartment == nil {
+		return s.validatePassInternal(ctx, pass, src)
+	}
+
+	entry, err := s.watchlistRepo.GetByCarPlate(ctx, *pass.CarPlate, &apartment.BuildingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check watchlist: %w", err)
+	}
+	if entry != nil && entry.Severity == domain.WatchlistSeverityBan {
+		return s.refuseBanned(ctx, entry, pass, src), nil
+	}
+
+	result, err := s.validatePassInternal(ctx, pass, src)
+	if err != nil {
+		return nil, err
+	}
+	if entry != nil {
+		s.raisePlateAlert(ctx, entry, result, pass, src)
+	}
+	return result, nil
+}
+
+func checkDynamicCode(pass *domain.Pass, code qr.Code, now time.Time) string {
+	if !code.Dynamic() {
+		return "DYNAMIC_QR_REQUIRED"
+	}
+	if !qr.VerifyMAC(pass.QRSecret, code) {
+		return "QR_CODE_INVALID"
+	}
+	if drift := code.Step - qr.TimeStep(now); drift < -dynamicQRSkewSteps || drift > dynamicQRSkewSteps {
+		return "QR_CODE_EXPIRED"
+	}
+	return ""
 }
 
 func (s *PassService) ValidatePassByCarPlate(ctx context.Context, carPlate string, guardUserID int64, buildingID *int64) (*domain.PassValidationResult, error) {
+	return s.checkCarPlate(ctx, carPlate, guardScan(guardUserID), buildingID)
+}
+
+// checkCarPlate validates a car plate read by a guard or a gate camera.
+func (s *PassService) checkCarPlate(ctx context.Context, carPlate string, src scanSource, buildingID *int64) (*domain.PassValidationResult, error) {
 	normalizedCarPlate := normalizeCarPlate(carPlate)
 	if normalizedCarPlate == "" {
 		result := &domain.PassValidationResult{
@@ -145,9 +647,44 @@ func (s *PassService) ValidatePassByCarPlate(ctx context.Context, carPlate strin
 		return result, nil
 	}
 
+	// A banned car is stopped whatever pass or registration it has.
+	entry, err := s.watchlistRepo.GetByCarPlate(ctx, normalizedCarPlate, buildingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check watchlist: %w", err)
+	}
+	if entry != nil && entry.Severity == domain.WatchlistSeverityBan {
+		pass, err := s.passRepo.GetActiveByCarPlate(ctx, normalizedCarPlate, &entry.BuildingID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pass by car plate: %w", err)
+		}
+		return s.refuseBanned(ctx, entry, pass, src), nil
+	}
+
+	result, pass, err := s.validateCarPlate(ctx, normalizedCarPlate, src, buildingID)
+	if err != nil {
+		return nil, err
+	}
+	if entry != nil {
+		s.raisePlateAlert(ctx, entry, result, pass, src)
+	}
+	return result, nil
+}
+
+// validateCarPlate admits a registered resident car or the active pass of
+// the plate. The pass is returned when there is one.
+func (s *PassService) validateCarPlate(ctx context.Context, normalizedCarPlate string, src scanSource, buildingID *int64) (*domain.PassValidationResult, *domain.Pass, error) {
+	// Residents' own cars come in without a pass.
+	vehicle, err := s.vehicleRepo.GetByCarPlate(ctx, normalizedCarPlate, buildingID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get resident vehicle: %w", err)
+	}
+	if vehicle != nil {
+		return s.admitResidentVehicle(ctx, vehicle, src), nil, nil
+	}
+
 	pass, err := s.passRepo.GetActiveByCarPlate(ctx, normalizedCarPlate, buildingID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get pass by car plate: %w", err)
+		return nil, nil, fmt.Errorf("failed to get pass by car plate: %w", err)
 	}
 
 	result := &domain.PassValidationResult{
@@ -156,20 +693,122 @@ func (s *PassService) ValidatePassByCarPlate(ctx context.Context, carPlate strin
 
 	if pass == nil {
 		result.Reason = "PASS_NOT_FOUND"
-		return result, nil
+		// A mistyped or misread plate is offered to the guard, not admitted.
+		// Gate cameras have no one to offer it to.
+		if src.camera == nil {
+			result.Suggestions, err = s.SuggestPasses(ctx, normalizedCarPlate, buildingID)
+			if err != nil {
+				s.logger.Error("failed to suggest passes", zap.Error(err), zap.String("car_plate", normalizedCarPlate))
+			}
+		}
+		return result, nil, nil
 	}
 
-	return s.validatePassInternal(ctx, pass, guardUserID)
+	result, err = s.validatePassInternal(ctx, pass, src)
+	return result, pass, err
 }
 
-func (s *PassService) validatePassInternal(ctx context.Context, pass *domain.Pass, guardUserID int64) (*domain.PassValidationResult, error) {
+// refuseBanned stops a banned car, even one with a valid pass, and alerts
+// the admins. The scan is logged against the pass if the car has one.
+func (s *PassService) refuseBanned(ctx context.Context, entry *domain.WatchlistEntry, pass *domain.Pass, src scanSource) *domain.PassValidationResult {
+	result := &domain.PassValidationResult{
+		Valid:     false,
+		Reason:    "BLACKLISTED",
+		Watchlist: entry,
+		CarPlate:  entry.CarPlate,
+	}
+
+	event := &domain.ScanEvent{
+		Result: "invalid",
+		Reason: &result.Reason,
+	}
+	if pass != nil {
+		event.PassID = &pass.ID
+		event.ApartmentID = &pass.ApartmentID
+	}
+	s.recordScanEvent(ctx, src, event, map[string]interface{}{
+		"source":       "watchlist",
+		"watchlist_id": entry.ID,
+		"car_plate":    entry.CarPlate,
+		"severity":     entry.Severity,
+	})
+
+	s.raisePlateAlert(ctx, entry, result, pass, src)
+	return result
+}
+
+// raisePlateAlert attaches the watchlist entry to the result so the guard
+// sees it and queues the sighting for the building admins.
+func (s *PassService) raisePlateAlert(ctx context.Context, entry *domain.WatchlistEntry, result *domain.PassValidationResult, pass *domain.Pass, src scanSource) {
+	result.Watchlist = entry
+
+	alert := &domain.PlateAlert{
+		WatchlistID: entry.ID,
+		GuardUserID: src.guard(),
+		CameraID:    src.cameraID(),
+		Admitted:    result.Valid,
+	}
+	if pass != nil {
+		alert.PassID = &pass.ID
+	}
+	if err := s.watchlistRepo.CreateAlert(ctx, alert); err != nil {
+		s.logger.Error("failed to queue plate alert",
+			zap.Error(err),
+			zap.Int64("watchlist_id", entry.ID),
+		)
+	}
+
+	s.logger.Warn("watchlisted car plate at the gate",
+		zap.String("car_plate", entry.CarPlate),
+		zap.String("severity", entry.Severity),
+		zap.Int64("building_id", entry.BuildingID),
+		zap.Int64("guard_user_id", src.guardUserID),
+		zap.Bool("admitted", result.Valid),
+	)
+}
+
+// admitResidentVehicle lets a registered resident car in. The entry is
+// logged against the apartment as there is no pass.
+func (s *PassService) admitResidentVehicle(ctx context.Context, vehicle *domain.ResidentVehicle, src scanSource) *domain.PassValidationResult {
+	result := &domain.PassValidationResult{
+		Valid:    true,
+		Reason:   "RESIDENT_VEHICLE",
+		Vehicle:  vehicle,
+		CarPlate: vehicle.CarPlate,
+	}
+
+	apartment, err := s.apartmentRepo.GetByID(ctx, vehicle.ApartmentID)
+	if err == nil && apartment != nil {
+		result.Apartment = apartment.Number
+	}
+
+	s.recordScanEvent(ctx, src, &domain.ScanEvent{
+		ApartmentID: &vehicle.ApartmentID,
+		Result:      "valid",
+		Reason:      &result.Reason,
+	}, map[string]interface{}{
+		"source":     "resident_vehicle",
+		"vehicle_id": vehicle.ID,
+		"car_plate":  vehicle.CarPlate,
+	})
+
+	return result
+}
+
+func (s *PassService) validatePassInternal(ctx context.Context, pass *domain.Pass, src scanSource) (*domain.PassValidationResult, error) {
 	result := &domain.PassValidationResult{
 		Valid: false,
 	}
 
 	if pass.Status == "revoked" {
 		result.Reason = "PASS_REVOKED"
-		s.logScanEvent(ctx, pass.ID, guardUserID, "invalid", result.Reason)
+		s.logScanEvent(ctx, pass.ID, src, "invalid", result.Reason)
+		return result, nil
+	}
+
+	if pass.Status == "used" {
+		result.Reason = "PASS_USED"
+		s.logScanEvent(ctx, pass.ID, src, "invalid", result.Reason)
 		return result, nil
 	}
 
@@ -179,7 +818,7 @@ func (s *PassService) validatePassInternal(ctx context.Context, pass *domain.Pas
 
 	if now.Before(validFrom) {
 		result.Reason = "PASS_NOT_YET_VALID"
-		s.logScanEvent(ctx, pass.ID, guardUserID, "invalid", result.Reason)
+		s.logScanEvent(ctx, pass.ID, src, "invalid", result.Reason)
 		return result, nil
 	}
 
@@ -187,7 +826,13 @@ func (s *PassService) validatePassInternal(ctx context.Context, pass *domain.Pas
 		result.Reason = "PASS_EXPIRED"
 		pass.Status = "expired"
 		_ = s.passRepo.Update(ctx, pass)
-		s.logScanEvent(ctx, pass.ID, guardUserID, "invalid", result.Reason)
+		s.logScanEvent(ctx, pass.ID, src, "invalid", result.Reason)
+		return result, nil
+	}
+
+	if !withinSchedule(pass, now.In(s.location)) {
+		result.Reason = "OUTSIDE_ALLOWED_HOURS"
+		s.logScanEvent(ctx, pass.ID, src, "invalid", result.Reason)
 		return result, nil
 	}
 
@@ -195,10 +840,17 @@ func (s *PassService) validatePassInternal(ctx context.Context, pass *domain.Pas
 	if err == nil && apartment != nil {
 		rule, err := s.ruleRepo.GetByBuildingID(ctx, apartment.BuildingID)
 		if err == nil && rule != nil {
+			if cr := rule.CategoryRule(pass.Category); cr != nil && cr.AllowedFrom != nil && cr.AllowedTo != nil {
+				if _, ok := dailyWindowDay(now.In(s.location), *cr.AllowedFrom, *cr.AllowedTo); !ok {
+					result.Reason = "OUTSIDE_ALLOWED_HOURS"
+					s.logScanEvent(ctx, pass.ID, src, "invalid", result.Reason)
+					return result, nil
+				}
+			}
 			if rule.QuietHoursStart != nil && rule.QuietHoursEnd != nil {
 				if s.isQuietHours(now, *rule.QuietHoursStart, *rule.QuietHoursEnd) {
 					result.Reason = "QUIET_HOURS"
-					s.logScanEvent(ctx, pass.ID, guardUserID, "invalid", result.Reason)
+					s.logScanEvent(ctx, pass.ID, src, "invalid", result.Reason)
 					return result, nil
 				}
 			}
@@ -216,7 +868,15 @@ func (s *PassService) validatePassInternal(ctx context.Context, pass *domain.Pas
 		result.Apartment = apartment.Number
 	}
 
-	s.logScanEvent(ctx, pass.ID, guardUserID, "valid", "")
+	s.logScanEvent(ctx, pass.ID, src, "valid", "")
+
+	if pass.SingleUse {
+		pass.Status = "used"
+		if err := s.passRepo.Update(ctx, pass); err != nil {
+			s.logger.Error("failed to mark single-use pass used", zap.Error(err), zap.String("pass_id", pass.ID.String()))
+		}
+	}
+
 	return result, nil
 }
 
@@ -261,27 +921,108 @@ func (s *PassService) SearchPassesByCarPlate(ctx context.Context, carPlate strin
 	return s.passRepo.SearchByCarPlate(ctx, carPlate, buildingID, 50)
 }
 
-func (s *PassService) logScanEvent(ctx context.Context, passID uuid.UUID, guardUserID int64, result, reason string) {
+func (s *PassService) logScanEvent(ctx context.Context, passID uuid.UUID, src scanSource, result, reason string) {
+	s.recordScanEvent(ctx, src, &domain.ScanEvent{
+		PassID: &passID,
+		Result: result,
+		Reason: &reason,
+	}, nil)
+}
+
+// recordScanEvent logs the scan now by the guard or camera of src with the
+// meta fields. Failures are logged, not returned: the guard has the result
+// already.
+func (s *PassService) recordScanEvent(ctx context.Context, src scanSource, event *domain.ScanEvent, fields map[string]interface{}) {
+	event.ScannedAt = time.Now().UTC()
+	event.GuardUserID = src.guard()
+	event.CameraID = src.cameraID()
 
-	event := &domain.ScanEvent{
-		PassID:      passID,
-		GuardUserID: guardUserID,
-		ScannedAt:   time.Now().UTC(),
-		Result:      result,
-		Reason:      &reason,
+	meta, err := src.meta(fields)
+	if err != nil {
+		s.logger.Error("failed to encode scan event meta", zap.Error(err))
 	}
+	event.Meta = meta
 
 	if err := s.scanEventRepo.Create(ctx, event); err != nil {
-
-		s.logger.Error("failed to log scan event",
+		fields := []zap.Field{
 			zap.Error(err),
-			zap.String("pass_id", passID.String()),
-			zap.String("result", result),
-			zap.String("reason", reason),
-		)
+			zap.String("result", event.Result),
+		}
+		if event.PassID != nil {
+			fields = append(fields, zap.String("pass_id", event.PassID.String()))
+		}
+		if event.Reason != nil {
+			fields = append(fields, zap.String("reason", *event.Reason))
+		}
+		s.logger.Error("failed to log scan event", fields...)
 	}
 }
 
+// scanSource is who checks a pass at the gate: a guard, or a gate camera
+// with the plate it read. It is passed down explicitly so that the camera
+// only changes the checks that ask for it.
+type scanSource struct {
+	guardUserID int64
+	camera      *domain.GateCamera
+	recognition domain.PlateRecognition
+}
+
+func guardScan(guardUserID int64) scanSource {
+	return scanSource{guardUserID: guardUserID}
+}
+
+func cameraScan(camera *domain.GateCamera, recognition domain.PlateRecognition) scanSource {
+	return scanSource{camera: camera, recognition: recognition}
+}
+
+// guard is the guard of the scan, nil for gate cameras and callers with no
+// user.
+func (src scanSource) guard() *int64 {
+	if src.guardUserID == 0 {
+		return nil
+	}
+	return &src.guardUserID
+}
+
+// cameraID is the gate camera of the scan, nil for guards.
+func (src scanSource) cameraID() *int64 {
+	if src.camera == nil {
+		return nil
+	}
+	return &src.camera.ID
+}
+
+// meta encodes the meta of a scan event: the fields and, for a gate camera
+// scan, the camera and its read under "camera". Nil when there is nothing
+// to record.
+func (src scanSource) meta(fields map[string]interface{}) (*string, error) {
+	if len(fields) == 0 && src.camera == nil {
+		return nil, nil
+	}
+
+	meta := make(map[string]interface{}, len(fields)+1)
+	for key, value := range fields {
+		meta[key] = value
+	}
+	if src.camera != nil {
+		meta["camera"] = map[string]interface{}{
+			"id":            src.camera.ID,
+			"name":          src.camera.Name,
+			"gate_id":       src.camera.GateID,
+			"plate":         src.recognition.CarPlate,
+			"confidence":    src.recognition.Confidence,
+			"recognized_at": src.recognition.RecognizedAt.UTC(),
+		}
+	}
+
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return nil, err
+	}
+	metaStr := string(data)
+	return &metaStr, nil
+}
+
 var russianToEnglish = map[rune]rune{
 	'А': 'A', 'В': 'B', 'С': 'C', 'Е': 'E', 'К': 'K',
 	'М': 'M', 'Н': 'H', 'О': 'O', 'Р': 'P', 'Т': 'T',
@@ -306,6 +1047,21 @@ func normalizeCarPlate(plate string) string {
 	return result.String()
 }
 
+// ParseCarPlate normalizes the plate and splits it by the known plate
+// formats. The error of a plate of no known format lists the expected ones.
+func ParseCarPlate(carPlate string) (*plate.Plate, error) {
+	normalized := normalizeCarPlate(carPlate)
+	if normalized == "" {
+		return nil, ErrInvalidCarPlate
+	}
+
+	parsed, err := plate.Parse(normalized)
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: %w", ErrInvalidCarPlate, normalized, err)
+	}
+	return parsed, nil
+}
+
 func (s *PassService) validateQuietHours(validFrom, validTo time.Time, startTime, endTime string) error {
 	start, err := parseTime(startTime)
 	if err != nil {
@@ -316,20 +1072,22 @@ func (s *PassService) validateQuietHours(validFrom, validTo time.Time, startTime
 		return fmt.Errorf("invalid quiet hours end: %w", err)
 	}
 
-	fromHour := validFrom.Hour()*60 + validFrom.Minute()
-	toHour := validTo.Hour()*60 + validTo.Minute()
+	fromMin := validFrom.Hour()*60 + validFrom.Minute()
+	toMin := fromMin + int(validTo.Sub(validFrom).Minutes())
 	startMin := start.Hour()*60 + start.Minute()
 	endMin := end.Hour()*60 + end.Minute()
 
 	if endMin < startMin {
 		endMin += 24 * 60
-		if toHour < startMin {
-			toHour += 24 * 60
-		}
 	}
 
-	if (fromHour < endMin && toHour > startMin) || (fromHour+24*60 < endMin && toHour+24*60 > startMin) {
-		return errors.New("pass cannot overlap with quiet hours")
+	// Compare the pass with the quiet period of every day it touches,
+	// starting with the one that began the day before.
+	for day := -1; day <= toMin/(24*60); day++ {
+		offset := day * 24 * 60
+		if fromMin < endMin+offset && toMin > startMin+offset {
+			return errors.New("pass cannot overlap with quiet hours")
+		}
 	}
 
 	return nil
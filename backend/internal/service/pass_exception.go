@@ -0,0 +1,354 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"yardpass/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// maxPendingPassExceptions limits how many undecided exceptions a
+	// resident can have at once.
+	maxPendingPassExceptions = 3
+	// maxPassExceptionReasonLength is the longest reason in characters.
+	maxPassExceptionReasonLength = 500
+)
+
+var (
+	ErrPassExceptionReasonRequired = errors.New("reason is required")
+	ErrPassExceptionReasonTooLong  = fmt.Errorf("reason must be at most %d characters", maxPassExceptionReasonLength)
+	ErrTooManyPassExceptions       = errors.New("too many exception requests are waiting for a decision")
+	ErrPassExceptionNotFound       = errors.New("pass exception not found")
+	ErrPassExceptionDecided        = errors.New("pass exception has already been decided")
+	ErrPassExceptionExpired        = errors.New("the requested pass window has already passed")
+)
+
+// PassExceptionService handles residents asking the building admins for a
+// pass the rules reject. The bot submits the requests and tells the
+// residents about the decisions the admins make through the API.
+type PassExceptionService struct {
+	exceptionRepo domain.PassExceptionRepository
+	passRepo      domain.PassRepository
+	apartmentRepo domain.ApartmentRepository
+	userRepo      domain.UserRepository
+	passService   *PassService
+	logger        *zap.Logger
+}
+
+func NewPassExceptionService(
+	exceptionRepo domain.PassExceptionRepository,
+	passRepo domain.PassRepository,
+	apartmentRepo domain.ApartmentRepository,
+	userRepo domain.UserRepository,
+	passService *PassService,
+	logger *zap.Logger,
+) *PassExceptionService {
+	return &PassExceptionService{
+		exceptionRepo: exceptionRepo,
+		passRepo:      passRepo,
+		apartmentRepo: apartmentRepo,
+		userRepo:      userRepo,
+		passService:   passService,
+		logger:        logger,
+	}
+}
+
+// ExemptRule returns the rule an error of CreatePass broke if the admins
+// can make an exception to it, or "".
+func ExemptRule(err error) string {
+	switch {
+	case errors.Is(err, ErrPassTooLong):
+		return domain.PassRuleMaxDuration
+	case errors.Is(err, ErrDailyLimitExceeded):
+		return domain.PassRuleDailyLimit
+	default:
+		return ""
+	}
+}
+
+// Submit stores the resident's request for the pass in req and returns the
+// building admins who should be told about it.
+func (s *PassExceptionService) Submit(ctx context.Context, req domain.CreatePassRequest, rule, reason string) (*domain.PassException, []*domain.User, error) {
+	if rule != domain.PassRuleMaxDuration && rule != domain.PassRuleDailyLimit {
+		return nil, nil, fmt.Errorf("no exception can be made to rule %q", rule)
+	}
+	if req.ResidentID == nil {
+		return nil, nil, errors.New("resident_id is required")
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, nil, ErrPassExceptionReasonRequired
+	}
+	if utf8.RuneCountInString(reason) > maxPassExceptionReasonLength {
+		return nil, nil, ErrPassExceptionReasonTooLong
+	}
+	if !req.ValidTo.After(req.ValidFrom) {
+		return nil, nil, errors.New("valid_to must be after valid_from")
+	}
+	if req.CarPlate != nil {
+		parsed, err := ParseCarPlate(*req.CarPlate)
+		if err != nil {
+			return nil, nil, err
+		}
+		req.CarPlate = &parsed.Normalized
+	}
+
+	pending, err := s.exceptionRepo.CountPendingByResident(ctx, *req.ResidentID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to check pending exceptions: %w", err)
+	}
+	if pending >= maxPendingPassExceptions {
+		return nil, nil, ErrTooManyPassExceptions
+	}
+
+	apartment, err := s.apartmentRepo.GetByID(ctx, req.ApartmentID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get apartment: %w", err)
+	}
+	if apartment == nil {
+		return nil, nil, ErrApartmentNotFound
+	}
+
+	category := req.Category
+	if category == "" {
+		category = domain.PassCategoryGuest
+	}
+
+	exception := &domain.PassException{
+		ApartmentID: req.ApartmentID,
+		BuildingID:  apartment.BuildingID,
+		ResidentID:  *req.ResidentID,
+		CarPlate:    req.CarPlate,
+		GuestName:   req.GuestName,
+		Category:    category,
+		ValidFrom:   req.ValidFrom,
+		ValidTo:     req.ValidTo,
+		Rule:        rule,
+		Reason:      reason,
+		Status:      domain.PassExceptionStatusPending,
+	}
+	if err := s.exceptionRepo.Create(ctx, exception); err != nil {
+		return nil, nil, fmt.Errorf("failed to create pass exception: %w", err)
+	}
+
+	s.logger.Info("pass exception submitted",
+		zap.Int64("exception_id", exception.ID),
+		zap.Int64("apartment_id", exception.ApartmentID),
+		zap.Int64("resident_id", exception.ResidentID),
+		zap.String("rule", rule),
+	)
+
+	admins, err := buildingAdmins(ctx, s.userRepo, apartment.BuildingID)
+	if err != nil {
+		// The request is in the queue anyway; only the heads-up is lost.
+		s.logger.Error("failed to get admins of pass exception", zap.Error(err), zap.Int64("exception_id", exception.ID))
+	}
+
+	return exception, admins, nil
+}
+
+// buildingAdmins returns the active admins of the building who linked
+// Telegram.
+func buildingAdmins(ctx context.Context, userRepo domain.UserRepository, buildingID int64) ([]*domain.User, error) {
+	role, status := "admin", "active"
+	users, err := userRepo.List(ctx, domain.UserFilters{Role: &role, BuildingID: &buildingID, Status: &status})
+	if err != nil {
+		return nil, err
+	}
+
+	var admins []*domain.User
+	for _, user := range users {
+		if user.TelegramID != nil {
+			admins = append(admins, user)
+		}
+	}
+	return admins, nil
+}
+
+// List returns the exceptions of the building, all buildings when nil,
+// optionally only those with status.
+func (s *PassExceptionService) List(ctx context.Context, buildingID *int64, status *string) ([]*domain.PassException, error) {
+	exceptions, err := s.exceptionRepo.List(ctx, buildingID, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pass exceptions: %w", err)
+	}
+
+	now := time.Now().UTC()
+	for _, exception := range exceptions {
+		setApprovalWindow(exception, now)
+	}
+	return exceptions, nil
+}
+
+// Get returns the exception. buildingID limits it to an admin's building;
+// exceptions of other buildings are reported as not found.
+func (s *PassExceptionService) Get(ctx context.Context, id int64, buildingID *int64) (*domain.PassException, error) {
+	exception, err := s.get(ctx, id, buildingID)
+	if err != nil {
+		return nil, err
+	}
+
+	setApprovalWindow(exception, time.Now().UTC())
+	return exception, nil
+}
+
+func (s *PassExceptionService) get(ctx context.Context, id int64, buildingID *int64) (*domain.PassException, error) {
+	exception, err := s.exceptionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pass exception: %w", err)
+	}
+	if exception == nil || (buildingID != nil && exception.BuildingID != *buildingID) {
+		return nil, ErrPassExceptionNotFound
+	}
+	return exception, nil
+}
+
+// approvalWindow returns the window of the pass an approval at now issues:
+// what is left of the requested window. ok is false once it has passed.
+func approvalWindow(exception *domain.PassException, now time.Time) (validFrom, validTo time.Time, ok bool) {
+	if !exception.ValidTo.After(now) {
+		return time.Time{}, time.Time{}, false
+	}
+
+	validFrom = exception.ValidFrom
+	if validFrom.Before(now) {
+		validFrom = now
+	}
+	return validFrom, exception.ValidTo, true
+}
+
+// setApprovalWindow shows the admin the window of the pass a pending
+// exception would get, or that it has expired.
+func setApprovalWindow(exception *domain.PassException, now time.Time) {
+	if exception.Status != domain.PassExceptionStatusPending {
+		return
+	}
+
+	validFrom, validTo, ok := approvalWindow(exception, now)
+	if !ok {
+		exception.Expired = true
+		return
+	}
+	exception.PassValidFrom = &validFrom
+	exception.PassValidTo = &validTo
+}
+
+// Approve issues the requested pass on behalf of the resident without the
+// rule it breaks and records the admin. A window that has already started
+// is cut to its remaining part, as shown in PassValidFrom and PassValidTo;
+// a window that has passed cannot be approved.
+func (s *PassExceptionService) Approve(ctx context.Context, id, adminID int64, buildingID *int64, comment *string) (*domain.PassException, *domain.Pass, error) {
+	exception, err := s.pending(ctx, id, buildingID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	validFrom, validTo, ok := approvalWindow(exception, time.Now().UTC())
+	if !ok {
+		return nil, nil, ErrPassExceptionExpired
+	}
+
+	pass, err := s.passService.CreatePass(ctx, domain.CreatePassRequest{
+		ApartmentID: exception.ApartmentID,
+		ResidentID:  &exception.ResidentID,
+		CarPlate:    exception.CarPlate,
+		GuestName:   exception.GuestName,
+		ValidFrom:   validFrom,
+		ValidTo:     validTo,
+		Category:    exception.Category,
+		ExemptRule:  exception.Rule,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resolved, err := s.exceptionRepo.Resolve(ctx, exception.ID, domain.PassExceptionStatusApproved, adminID, comment, &pass.ID)
+	if err == nil && !resolved {
+		err = ErrPassExceptionDecided
+	}
+	if err != nil {
+		// Another admin decided in the meantime; the pass must not stay.
+		if revokeErr := s.passService.RevokePass(ctx, pass.ID, adminID); revokeErr != nil {
+			s.logger.Error("failed to revoke pass of a decided exception", zap.Error(revokeErr), zap.String("pass_id", pass.ID.String()))
+		}
+		if errors.Is(err, ErrPassExceptionDecided) {
+			return nil, nil, err
+		}
+		return nil, nil, fmt.Errorf("failed to approve pass exception: %w", err)
+	}
+
+	exception.Status = domain.PassExceptionStatusApproved
+	exception.DecidedBy = &adminID
+	exception.Comment = comment
+	exception.PassID = &pass.ID
+
+	s.logger.Info("pass exception approved",
+		zap.Int64("exception_id", exception.ID),
+		zap.Int64("admin_id", adminID),
+		zap.String("pass_id", pass.ID.String()),
+	)
+
+	return exception, pass, nil
+}
+
+func (s *PassExceptionService) Reject(ctx context.Context, id, adminID int64, buildingID *int64, comment *string) (*domain.PassException, error) {
+	exception, err := s.pending(ctx, id, buildingID)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved, err := s.exceptionRepo.Resolve(ctx, exception.ID, domain.PassExceptionStatusRejected, adminID, comment, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reject pass exception: %w", err)
+	}
+	if !resolved {
+		return nil, ErrPassExceptionDecided
+	}
+
+	exception.Status = domain.PassExceptionStatusRejected
+	exception.DecidedBy = &adminID
+	exception.Comment = comment
+
+	s.logger.Info("pass exception rejected",
+		zap.Int64("exception_id", exception.ID),
+		zap.Int64("admin_id", adminID),
+	)
+
+	return exception, nil
+}
+
+func (s *PassExceptionService) pending(ctx context.Context, id int64, buildingID *int64) (*domain.PassException, error) {
+	exception, err := s.get(ctx, id, buildingID)
+	if err != nil {
+		return nil, err
+	}
+	if exception.Status != domain.PassExceptionStatusPending {
+		return nil, ErrPassExceptionDecided
+	}
+	return exception, nil
+}
+
+// ClaimDecisions returns decided exceptions the caller is now responsible
+// for telling the residents about.
+func (s *PassExceptionService) ClaimDecisions(ctx context.Context, limit int) ([]*domain.PassException, error) {
+	exceptions, err := s.exceptionRepo.ClaimUndelivered(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim pass exceptions: %w", err)
+	}
+	return exceptions, nil
+}
+
+// Pass returns the pass an approved exception issued, or nil if it is gone.
+func (s *PassExceptionService) Pass(ctx context.Context, exception *domain.PassException) (*domain.Pass, error) {
+	if exception.PassID == nil {
+		return nil, nil
+	}
+	return s.passRepo.GetByID(ctx, *exception.PassID)
+}
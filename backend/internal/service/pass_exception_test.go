@@ -0,0 +1,338 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"yardpass/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type MockPassExceptionRepo struct {
+	mock.Mock
+}
+
+func (m *MockPassExceptionRepo) Create(ctx context.Context, exception *domain.PassException) error {
+	args := m.Called(ctx, exception)
+	return args.Error(0)
+}
+
+func (m *MockPassExceptionRepo) GetByID(ctx context.Context, id int64) (*domain.PassException, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PassException), args.Error(1)
+}
+
+func (m *MockPassExceptionRepo) List(ctx context.Context, buildingID *int64, status *string) ([]*domain.PassException, error) {
+	args := m.Called(ctx, buildingID, status)
+	return args.Get(0).([]*domain.PassException), args.Error(1)
+}
+
+func (m *MockPassExceptionRepo) CountPendingByResident(ctx context.Context, residentID int64) (int, error) {
+	args := m.Called(ctx, residentID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockPassExceptionRepo) Resolve(ctx context.Context, id int64, status string, decidedBy int64, comment *string, passID *uuid.UUID) (bool, error) {
+	args := m.Called(ctx, id, status, decidedBy, comment, passID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPassExceptionRepo) ClaimUndelivered(ctx context.Context, limit int) ([]*domain.PassException, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]*domain.PassException), args.Error(1)
+}
+
+// MockUserRepo mocks only List; other methods panic if called.
+type MockUserRepo struct {
+	domain.UserRepository
+	mock.Mock
+}
+
+func (m *MockUserRepo) List(ctx context.Context, filters domain.UserFilters) ([]*domain.User, error) {
+	args := m.Called(ctx, filters)
+	return args.Get(0).([]*domain.User), args.Error(1)
+}
+
+func TestExemptRule(t *testing.T) {
+	service, _ := newExceptionPassService(t, 5)
+	residentID := int64(1)
+	now := time.Now()
+
+	_, err := service.CreatePass(context.Background(), domain.CreatePassRequest{
+		ApartmentID: 1,
+		ResidentID:  &residentID,
+		ValidFrom:   now,
+		ValidTo:     now.Add(2 * time.Hour),
+	})
+	assert.Equal(t, domain.PassRuleMaxDuration, ExemptRule(err))
+
+	_, err = service.CreatePass(context.Background(), domain.CreatePassRequest{
+		ApartmentID: 1,
+		ResidentID:  &residentID,
+		ValidFrom:   now,
+		ValidTo:     now.Add(30 * time.Minute),
+	})
+	assert.Equal(t, domain.PassRuleDailyLimit, ExemptRule(err))
+
+	assert.Empty(t, ExemptRule(ErrInvalidCategory))
+}
+
+// newExceptionPassService returns a pass service for apartment 1 of
+// building 1, whose passes last at most an hour and which has already issued
+// passesToday of its 5 passes a day.
+func newExceptionPassService(t *testing.T, passesToday int) (*PassService, *MockPassRepo) {
+	t.Helper()
+
+	passRepo := new(MockPassRepo)
+	apartmentRepo := new(MockApartmentRepo)
+	ruleRepo := new(MockRuleRepo)
+	apartmentRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Apartment{ID: 1, BuildingID: 1}, nil)
+	ruleRepo.On("GetByBuildingID", mock.Anything, int64(1)).Return(&domain.Rule{
+		BuildingID:                 1,
+		DailyPassLimitPerApartment: 5,
+		MaxPassDurationHours:       1,
+	}, nil)
+	passRepo.On("CountActiveTodayByApartmentID", mock.Anything, int64(1)).Return(passesToday, nil)
+	passRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Pass")).Return(nil)
+
+	return NewPassService(passRepo, apartmentRepo, ruleRepo, new(MockScanEventRepo), nil, noWatchlist(), passIssuer(1), time.UTC, zap.NewNop()), passRepo
+}
+
+func TestPassExceptionService_Submit(t *testing.T) {
+	ctx := context.Background()
+	telegramID := int64(3003)
+	linked := &domain.User{ID: 7, Role: "admin", TelegramID: &telegramID}
+	unlinked := &domain.User{ID: 8, Role: "admin"}
+
+	tests := []struct {
+		name    string
+		rule    string
+		reason  string
+		plate   string
+		pending int
+		wantErr error
+	}{
+		{name: "notifies linked admins", rule: domain.PassRuleMaxDuration, reason: "  Moving day  "},
+		{name: "daily limit", rule: domain.PassRuleDailyLimit, reason: "Party"},
+		{name: "empty reason", rule: domain.PassRuleMaxDuration, reason: "   ", wantErr: ErrPassExceptionReasonRequired},
+		{name: "reason too long", rule: domain.PassRuleMaxDuration, reason: strings.Repeat("я", maxPassExceptionReasonLength+1), wantErr: ErrPassExceptionReasonTooLong},
+		{name: "too many pending", rule: domain.PassRuleMaxDuration, reason: "Again", pending: maxPendingPassExceptions, wantErr: ErrTooManyPassExceptions},
+		{name: "malformed car plate", rule: domain.PassRuleMaxDuration, reason: "Party", plate: "A123BC", wantErr: ErrInvalidCarPlate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exceptionRepo := new(MockPassExceptionRepo)
+			apartmentRepo := new(MockApartmentRepo)
+			userRepo := new(MockUserRepo)
+			service := NewPassExceptionService(exceptionRepo, nil, apartmentRepo, userRepo, nil, zap.NewNop())
+
+			exceptionRepo.On("CountPendingByResident", ctx, int64(100)).Return(tt.pending, nil)
+			exceptionRepo.On("Create", ctx, mock.AnythingOfType("*domain.PassException")).Return(nil)
+			apartmentRepo.On("GetByID", ctx, int64(10)).Return(&domain.Apartment{ID: 10, BuildingID: 1}, nil)
+			userRepo.On("List", ctx, mock.AnythingOfType("domain.UserFilters")).Return([]*domain.User{linked, unlinked}, nil)
+
+			residentID := int64(100)
+			plate := tt.plate
+			if plate == "" {
+				plate = "а123вс 77"
+			}
+			now := time.Now()
+			exception, admins, err := service.Submit(ctx, domain.CreatePassRequest{
+				ApartmentID: 10,
+				ResidentID:  &residentID,
+				CarPlate:    &plate,
+				ValidFrom:   now,
+				ValidTo:     now.Add(48 * time.Hour),
+			}, tt.rule, tt.reason)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				exceptionRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []*domain.User{linked}, admins)
+			assert.Equal(t, "A123BC77", *exception.CarPlate)
+			assert.Equal(t, strings.TrimSpace(tt.reason), exception.Reason)
+			assert.Equal(t, tt.rule, exception.Rule)
+			assert.Equal(t, domain.PassCategoryGuest, exception.Category)
+			assert.Equal(t, int64(1), exception.BuildingID)
+			assert.Equal(t, domain.PassExceptionStatusPending, exception.Status)
+		})
+	}
+}
+
+func TestPassExceptionService_Approve(t *testing.T) {
+	ctx := context.Background()
+	buildingID := int64(1)
+	adminID := int64(7)
+
+	pending := func(rule string, from time.Time, length time.Duration) *domain.PassException {
+		plate := "A123BC77"
+		return &domain.PassException{
+			ID:          5,
+			ApartmentID: 1,
+			BuildingID:  1,
+			ResidentID:  100,
+			CarPlate:    &plate,
+			Category:    domain.PassCategoryGuest,
+			ValidFrom:   from,
+			ValidTo:     from.Add(length),
+			Rule:        rule,
+			Status:      domain.PassExceptionStatusPending,
+		}
+	}
+
+	t.Run("longer pass", func(t *testing.T) {
+		passService, _ := newExceptionPassService(t, 0)
+		exceptionRepo := new(MockPassExceptionRepo)
+		service := NewPassExceptionService(exceptionRepo, nil, nil, nil, passService, zap.NewNop())
+
+		exceptionRepo.On("GetByID", ctx, int64(5)).Return(pending(domain.PassRuleMaxDuration, time.Now().Add(time.Hour), 48*time.Hour), nil)
+		exceptionRepo.On("Resolve", ctx, int64(5), domain.PassExceptionStatusApproved, adminID, mock.Anything, mock.Anything).Return(true, nil)
+
+		exception, pass, err := service.Approve(ctx, 5, adminID, &buildingID, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.PassExceptionStatusApproved, exception.Status)
+		assert.Equal(t, adminID, *exception.DecidedBy)
+		assert.Equal(t, pass.ID, *exception.PassID)
+		assert.Equal(t, 48*time.Hour, pass.ValidTo.Sub(pass.ValidFrom))
+		assert.Equal(t, int64(100), *pass.ResidentID)
+	})
+
+	t.Run("a started window keeps its end", func(t *testing.T) {
+		passService, _ := newExceptionPassService(t, 5)
+		exceptionRepo := new(MockPassExceptionRepo)
+		service := NewPassExceptionService(exceptionRepo, nil, nil, nil, passService, zap.NewNop())
+		exception := pending(domain.PassRuleDailyLimit, time.Now().Add(-time.Hour), 90*time.Minute)
+
+		exceptionRepo.On("GetByID", ctx, int64(5)).Return(exception, nil)
+		exceptionRepo.On("Resolve", ctx, int64(5), domain.PassExceptionStatusApproved, adminID, mock.Anything, mock.Anything).Return(true, nil)
+
+		_, pass, err := service.Approve(ctx, 5, adminID, &buildingID, nil)
+
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now(), pass.ValidFrom, time.Minute)
+		assert.Equal(t, exception.ValidTo, pass.ValidTo)
+	})
+
+	t.Run("a passed window is not approved", func(t *testing.T) {
+		passService, passRepo := newExceptionPassService(t, 5)
+		exceptionRepo := new(MockPassExceptionRepo)
+		service := NewPassExceptionService(exceptionRepo, nil, nil, nil, passService, zap.NewNop())
+
+		exceptionRepo.On("GetByID", ctx, int64(5)).Return(pending(domain.PassRuleDailyLimit, time.Now().Add(-3*time.Hour), 30*time.Minute), nil)
+
+		_, _, err := service.Approve(ctx, 5, adminID, &buildingID, nil)
+
+		assert.ErrorIs(t, err, ErrPassExceptionExpired)
+		passRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		exceptionRepo.AssertNotCalled(t, "Resolve", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("only the approved rule is skipped", func(t *testing.T) {
+		passService, passRepo := newExceptionPassService(t, 5)
+		exceptionRepo := new(MockPassExceptionRepo)
+		service := NewPassExceptionService(exceptionRepo, nil, nil, nil, passService, zap.NewNop())
+
+		exceptionRepo.On("GetByID", ctx, int64(5)).Return(pending(domain.PassRuleMaxDuration, time.Now(), 48*time.Hour), nil)
+
+		_, _, err := service.Approve(ctx, 5, adminID, &buildingID, nil)
+
+		assert.ErrorIs(t, err, ErrDailyLimitExceeded)
+		passRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		exceptionRepo.AssertNotCalled(t, "Resolve", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("another building", func(t *testing.T) {
+		exceptionRepo := new(MockPassExceptionRepo)
+		service := NewPassExceptionService(exceptionRepo, nil, nil, nil, nil, zap.NewNop())
+		otherBuilding := int64(2)
+
+		exceptionRepo.On("GetByID", ctx, int64(5)).Return(pending(domain.PassRuleMaxDuration, time.Now(), 48*time.Hour), nil)
+
+		_, _, err := service.Approve(ctx, 5, adminID, &otherBuilding, nil)
+
+		assert.ErrorIs(t, err, ErrPassExceptionNotFound)
+	})
+
+	t.Run("already decided", func(t *testing.T) {
+		exceptionRepo := new(MockPassExceptionRepo)
+		service := NewPassExceptionService(exceptionRepo, nil, nil, nil, nil, zap.NewNop())
+		decided := pending(domain.PassRuleMaxDuration, time.Now(), 48*time.Hour)
+		decided.Status = domain.PassExceptionStatusRejected
+
+		exceptionRepo.On("GetByID", ctx, int64(5)).Return(decided, nil)
+
+		_, _, err := service.Approve(ctx, 5, adminID, nil, nil)
+
+		assert.ErrorIs(t, err, ErrPassExceptionDecided)
+	})
+}
+
+func TestPassExceptionService_Get(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	later := now.Add(time.Hour)
+
+	tests := []struct {
+		name        string
+		from, to    time.Time
+		status      string
+		wantFrom    *time.Time
+		wantExpired bool
+	}{
+		{name: "future window", from: later, to: now.Add(2 * time.Hour), status: domain.PassExceptionStatusPending, wantFrom: &later},
+		{name: "started window", from: now.Add(-time.Hour), to: now.Add(time.Hour), status: domain.PassExceptionStatusPending, wantFrom: &now},
+		{name: "passed window", from: now.Add(-2 * time.Hour), to: now.Add(-time.Hour), status: domain.PassExceptionStatusPending, wantExpired: true},
+		{name: "decided", from: now.Add(-2 * time.Hour), to: now.Add(-time.Hour), status: domain.PassExceptionStatusRejected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exceptionRepo := new(MockPassExceptionRepo)
+			service := NewPassExceptionService(exceptionRepo, nil, nil, nil, nil, zap.NewNop())
+			exceptionRepo.On("GetByID", ctx, int64(5)).Return(&domain.PassException{ID: 5, ValidFrom: tt.from, ValidTo: tt.to, Status: tt.status}, nil)
+
+			exception, err := service.Get(ctx, 5, nil)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantExpired, exception.Expired)
+			if tt.wantFrom == nil {
+				assert.Nil(t, exception.PassValidFrom)
+				return
+			}
+			assert.WithinDuration(t, *tt.wantFrom, *exception.PassValidFrom, time.Minute)
+			assert.Equal(t, tt.to, *exception.PassValidTo)
+		})
+	}
+}
+
+func TestPassExceptionService_Reject(t *testing.T) {
+	ctx := context.Background()
+	exceptionRepo := new(MockPassExceptionRepo)
+	service := NewPassExceptionService(exceptionRepo, nil, nil, nil, nil, zap.NewNop())
+	comment := "Use the contractor pass"
+
+	exceptionRepo.On("GetByID", ctx, int64(5)).Return(&domain.PassException{ID: 5, BuildingID: 1, Status: domain.PassExceptionStatusPending}, nil)
+	exceptionRepo.On("Resolve", ctx, int64(5), domain.PassExceptionStatusRejected, int64(7), &comment, (*uuid.UUID)(nil)).Return(true, nil)
+
+	exception, err := service.Reject(ctx, 5, 7, nil, &comment)
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.PassExceptionStatusRejected, exception.Status)
+	assert.Equal(t, comment, *exception.Comment)
+	exceptionRepo.AssertExpectations(t)
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"yardpass/internal/domain"
+	"yardpass/internal/pdf"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// maxPrintPasses bounds the pages of one PDF.
+const maxPrintPasses = 200
+
+var (
+	ErrNoPassesToPrint      = errors.New("no passes to print")
+	ErrTooManyPassesToPrint = fmt.Errorf("at most %d passes can be printed at once", maxPrintPasses)
+	ErrPrintPassNotFound    = errors.New("pass not found")
+)
+
+// PassPrintService renders passes as printable PDF documents.
+type PassPrintService struct {
+	passRepo      domain.PassRepository
+	apartmentRepo domain.ApartmentRepository
+	buildingRepo  domain.BuildingRepository
+	pdfGen        *pdf.Generator
+	logger        *zap.Logger
+}
+
+func NewPassPrintService(
+	passRepo domain.PassRepository,
+	apartmentRepo domain.ApartmentRepository,
+	buildingRepo domain.BuildingRepository,
+	pdfGen *pdf.Generator,
+	logger *zap.Logger,
+) *PassPrintService {
+	return &PassPrintService{
+		passRepo:      passRepo,
+		apartmentRepo: apartmentRepo,
+		buildingRepo:  buildingRepo,
+		pdfGen:        pdfGen,
+		logger:        logger,
+	}
+}
+
+// PrintPasses returns a PDF with a page for every pass, in the given order.
+// If buildingID is set, passes of other buildings are reported as not found.
+func (s *PassPrintService) PrintPasses(ctx context.Context, passIDs []uuid.UUID, buildingID *int64) ([]byte, error) {
+	if len(passIDs) == 0 {
+		return nil, ErrNoPassesToPrint
+	}
+	if len(passIDs) > maxPrintPasses {
+		return nil, ErrTooManyPassesToPrint
+	}
+
+	loader := newPassDetailsLoader(s.apartmentRepo, s.buildingRepo)
+	seen := make(map[uuid.UUID]bool, len(passIDs))
+	documents := make([]*domain.PassDetails, 0, len(passIDs))
+	for _, id := range passIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		pass, err := s.passRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pass: %w", err)
+		}
+		if pass == nil {
+			return nil, fmt.Errorf("%w: %s", ErrPrintPassNotFound, id)
+		}
+
+		details, err := loader.load(ctx, pass)
+		if err != nil {
+			return nil, err
+		}
+		if details == nil || (buildingID != nil && details.Building.ID != *buildingID) {
+			return nil, fmt.Errorf("%w: %s", ErrPrintPassNotFound, id)
+		}
+		documents = append(documents, details)
+	}
+
+	document, err := s.pdfGen.Render(ctx, documents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render passes: %w", err)
+	}
+
+	return document, nil
+}
+
+// passDetailsLoader adds the apartment and building to passes, fetching
+// each of them once.
+type passDetailsLoader struct {
+	apartmentRepo domain.ApartmentRepository
+	buildingRepo  domain.BuildingRepository
+	apartments    map[int64]*domain.Apartment
+	buildings     map[int64]*domain.Building
+}
+
+func newPassDetailsLoader(apartmentRepo domain.ApartmentRepository, buildingRepo domain.BuildingRepository) *passDetailsLoader {
+	return &passDetailsLoader{
+		apartmentRepo: apartmentRepo,
+		buildingRepo:  buildingRepo,
+		apartments:    make(map[int64]*domain.Apartment),
+		buildings:     make(map[int64]*domain.Building),
+	}
+}
+
+// load returns nil if the apartment or building of the pass no longer
+// exists.
+func (l *passDetailsLoader) load(ctx context.Context, pass *domain.Pass) (*domain.PassDetails, error) {
+	apartment, ok := l.apartments[pass.ApartmentID]
+	if !ok {
+		var err error
+		apartment, err = l.apartmentRepo.GetByID(ctx, pass.ApartmentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get apartment: %w", err)
+		}
+		l.apartments[pass.ApartmentID] = apartment
+	}
+	if apartment == nil {
+		return nil, nil
+	}
+
+	building, ok := l.buildings[apartment.BuildingID]
+	if !ok {
+		var err error
+		building, err = l.buildingRepo.GetByID(ctx, apartment.BuildingID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get building: %w", err)
+		}
+		l.buildings[apartment.BuildingID] = building
+	}
+	if building == nil {
+		return nil, nil
+	}
+
+	return &domain.PassDetails{Pass: pass, Apartment: apartment, Building: building}, nil
+}
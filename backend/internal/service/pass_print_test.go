@@ -0,0 +1,92 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"yardpass/internal/config"
+	"yardpass/internal/domain"
+	"yardpass/internal/pdf"
+	"yardpass/internal/qr"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestPassPrintService(t *testing.T) (*PassPrintService, *MockPassRepo, *MockApartmentRepo, *MockBuildingRepo) {
+	t.Helper()
+
+	pdfGen, err := pdf.NewGenerator(&config.Config{}, qr.NewGenerator(), time.UTC, zap.NewNop())
+	require.NoError(t, err)
+
+	passRepo := new(MockPassRepo)
+	apartmentRepo := new(MockApartmentRepo)
+	buildingRepo := new(MockBuildingRepo)
+	return NewPassPrintService(passRepo, apartmentRepo, buildingRepo, pdfGen, zap.NewNop()), passRepo, apartmentRepo, buildingRepo
+}
+
+func TestPassPrintService_PrintPasses(t *testing.T) {
+	ctx := context.Background()
+	buildingID := int64(1)
+	plate := "A123BC77"
+	guest := "Иван"
+	first := &domain.Pass{ID: uuid.New(), ApartmentID: 10, CarPlate: &plate, GuestName: &guest, Status: "active", ValidFrom: time.Now(), ValidTo: time.Now().Add(time.Hour)}
+	second := &domain.Pass{ID: uuid.New(), ApartmentID: 10, Status: "active", ValidFrom: time.Now(), ValidTo: time.Now().Add(time.Hour)}
+
+	service, passRepo, apartmentRepo, buildingRepo := newTestPassPrintService(t)
+	passRepo.On("GetByID", ctx, first.ID).Return(first, nil)
+	passRepo.On("GetByID", ctx, second.ID).Return(second, nil)
+	apartmentRepo.On("GetByID", ctx, int64(10)).Return(&domain.Apartment{ID: 10, BuildingID: buildingID, Number: "42"}, nil)
+	buildingRepo.On("GetByID", ctx, buildingID).Return(&domain.Building{ID: buildingID, Name: "ЖК Тест", Address: "ул. Тестовая, 1"}, nil)
+
+	document, err := service.PrintPasses(ctx, []uuid.UUID{first.ID, second.ID, first.ID}, &buildingID)
+
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(document, []byte("%PDF")))
+	passRepo.AssertNumberOfCalls(t, "GetByID", 2)
+	apartmentRepo.AssertNumberOfCalls(t, "GetByID", 1)
+	buildingRepo.AssertNumberOfCalls(t, "GetByID", 1)
+}
+
+func TestPassPrintService_PrintPasses_Errors(t *testing.T) {
+	ctx := context.Background()
+	otherBuilding := int64(2)
+	pass := &domain.Pass{ID: uuid.New(), ApartmentID: 10, Status: "active", ValidFrom: time.Now(), ValidTo: time.Now().Add(time.Hour)}
+	missing := uuid.New()
+
+	tooMany := make([]uuid.UUID, maxPrintPasses+1)
+	for i := range tooMany {
+		tooMany[i] = uuid.New()
+	}
+
+	tests := []struct {
+		name       string
+		ids        []uuid.UUID
+		buildingID *int64
+		wantErr    error
+	}{
+		{name: "no passes", ids: nil, wantErr: ErrNoPassesToPrint},
+		{name: "too many passes", ids: tooMany, wantErr: ErrTooManyPassesToPrint},
+		{name: "missing pass", ids: []uuid.UUID{missing}, wantErr: ErrPrintPassNotFound},
+		{name: "pass of another building", ids: []uuid.UUID{pass.ID}, buildingID: &otherBuilding, wantErr: ErrPrintPassNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, passRepo, apartmentRepo, buildingRepo := newTestPassPrintService(t)
+			passRepo.On("GetByID", ctx, pass.ID).Return(pass, nil)
+			passRepo.On("GetByID", ctx, missing).Return(nil, nil)
+			apartmentRepo.On("GetByID", ctx, int64(10)).Return(&domain.Apartment{ID: 10, BuildingID: 1, Number: "42"}, nil)
+			buildingRepo.On("GetByID", ctx, int64(1)).Return(&domain.Building{ID: 1, Name: "ЖК Тест"}, nil)
+
+			document, err := service.PrintPasses(ctx, tt.ids, tt.buildingID)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, document)
+		})
+	}
+}
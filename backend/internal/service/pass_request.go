@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"yardpass/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// passRequestTTL is how long a resident can approve a guest's request.
+	passRequestTTL = 30 * time.Minute
+	// maxPassRequestsPerHour limits how many requests a guest can send.
+	maxPassRequestsPerHour = 5
+)
+
+var (
+	ErrApartmentNotFound      = errors.New("apartment not found")
+	ErrNoPassRequestApprovers = errors.New("nobody in the apartment can issue passes")
+	ErrTooManyPassRequests    = errors.New("too many pass requests, try again later")
+	ErrPassRequestNotFound    = errors.New("pass request not found")
+	ErrPassRequestDecided     = errors.New("pass request has already been decided")
+	ErrPassRequestExpired     = errors.New("pass request has expired")
+	ErrPassRequestStartPassed = errors.New("the requested start time has passed")
+)
+
+// PassRequestService handles passes requested by guests themselves. The
+// residents of the apartment decide, and approval issues the pass on behalf
+// of the approving resident.
+type PassRequestService struct {
+	requestRepo   domain.PassRequestRepository
+	apartmentRepo domain.ApartmentRepository
+	residentRepo  domain.ResidentRepository
+	passService   *PassService
+	logger        *zap.Logger
+}
+
+func NewPassRequestService(
+	requestRepo domain.PassRequestRepository,
+	apartmentRepo domain.ApartmentRepository,
+	residentRepo domain.ResidentRepository,
+	passService *PassService,
+	logger *zap.Logger,
+) *PassRequestService {
+	return &PassRequestService{
+		requestRepo:   requestRepo,
+		apartmentRepo: apartmentRepo,
+		residentRepo:  residentRepo,
+		passService:   passService,
+		logger:        logger,
+	}
+}
+
+// FindApartment looks up an apartment of the building by the number the
+// guest typed.
+func (s *PassRequestService) FindApartment(ctx context.Context, buildingID int64, number string) (*domain.Apartment, error) {
+	return findApartment(ctx, s.apartmentRepo, buildingID, number)
+}
+
+func findApartment(ctx context.Context, apartmentRepo domain.ApartmentRepository, buildingID int64, number string) (*domain.Apartment, error) {
+	number = strings.TrimSpace(number)
+	if number == "" {
+		return nil, ErrApartmentNotFound
+	}
+
+	apartments, err := apartmentRepo.GetByBuildingID(ctx, buildingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get apartments: %w", err)
+	}
+	for _, apartment := range apartments {
+		if strings.EqualFold(apartment.Number, number) {
+			return apartment, nil
+		}
+	}
+	return nil, ErrApartmentNotFound
+}
+
+// SubmitRequest stores the guest's request and returns the residents who
+// should be asked to decide.
+func (s *PassRequestService) SubmitRequest(ctx context.Context, request *domain.PassRequest) ([]*domain.Resident, error) {
+	if request.DurationMinutes <= 0 {
+		return nil, errors.New("duration is required")
+	}
+	if request.ValidFrom != nil && !request.ValidFrom.After(time.Now()) {
+		return nil, ErrPassRequestStartPassed
+	}
+	if request.CarPlate != nil {
+		parsed, err := ParseCarPlate(*request.CarPlate)
+		if err != nil {
+			return nil, err
+		}
+		request.CarPlate = &parsed.Normalized
+	}
+
+	count, err := s.requestRepo.CountRecentByGuest(ctx, request.GuestTelegramID, time.Hour)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check request limit: %w", err)
+	}
+	if count >= maxPassRequestsPerHour {
+		return nil, ErrTooManyPassRequests
+	}
+
+	approvers, err := passIssuers(ctx, s.residentRepo, request.ApartmentID)
+	if err != nil {
+		return nil, err
+	}
+	if len(approvers) == 0 {
+		return nil, ErrNoPassRequestApprovers
+	}
+
+	request.Status = domain.PassRequestStatusPending
+	if err := s.requestRepo.Create(ctx, request); err != nil {
+		return nil, fmt.Errorf("failed to create pass request: %w", err)
+	}
+
+	s.logger.Info("pass request submitted",
+		zap.Int64("request_id", request.ID),
+		zap.Int64("apartment_id", request.ApartmentID),
+		zap.Int64("guest_telegram_id", request.GuestTelegramID),
+	)
+
+	return approvers, nil
+}
+
+// passIssuers returns the active residents of the apartment who may issue
+// passes, i.e. who can decide on requests for it.
+func passIssuers(ctx context.Context, residentRepo domain.ResidentRepository, apartmentID int64) ([]*domain.Resident, error) {
+	active := "active"
+	residents, err := residentRepo.List(ctx, domain.ResidentFilters{ApartmentID: &apartmentID, Status: &active})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get residents: %w", err)
+	}
+
+	var issuers []*domain.Resident
+	for _, r := range residents {
+		if r.CanIssuePasses {
+			issuers = append(issuers, r)
+		}
+	}
+	return issuers, nil
+}
+
+// Approve issues the requested pass on behalf of the resident, valid from
+// the start the guest picked or from now. A start that passed while the
+// request waited is moved to now and keeps its end.
+func (s *PassRequestService) Approve(ctx context.Context, requestID int64, resident *domain.Resident) (*domain.PassRequest, *domain.Pass, error) {
+	request, err := s.pendingRequest(ctx, requestID, resident)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	validFrom := time.Now().UTC()
+	duration := time.Duration(request.DurationMinutes) * time.Minute
+	validTo := validFrom.Add(duration)
+	if request.ValidFrom != nil {
+		validTo = request.ValidFrom.Add(duration)
+		if request.ValidFrom.After(validFrom) {
+			validFrom = *request.ValidFrom
+		}
+	}
+
+	pass, err := s.passService.CreatePass(ctx, domain.CreatePassRequest{
+		ApartmentID: request.ApartmentID,
+		ResidentID:  &resident.ID,
+		CarPlate:    request.CarPlate,
+		GuestName:   request.GuestName,
+		ValidFrom:   validFrom,
+		ValidTo:     validTo,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resolved, err := s.requestRepo.Resolve(ctx, request.ID, domain.PassRequestStatusApproved, &resident.ID, &pass.ID)
+	if err == nil && !resolved {
+		err = ErrPassRequestDecided
+	}
+	if err != nil {
+		// Someone else decided in the meantime; the pass must not stay.
+		if revokeErr := s.passService.RevokePass(ctx, pass.ID, 0); revokeErr != nil {
+			s.logger.Error("failed to revoke pass of a decided request", zap.Error(revokeErr), zap.String("pass_id", pass.ID.String()))
+		}
+		if errors.Is(err, ErrPassRequestDecided) {
+			return nil, nil, err
+		}
+		return nil, nil, fmt.Errorf("failed to approve pass request: %w", err)
+	}
+
+	request.Status = domain.PassRequestStatusApproved
+	request.DecidedBy = &resident.ID
+	request.PassID = &pass.ID
+
+	s.logger.Info("pass request approved",
+		zap.Int64("request_id", request.ID),
+		zap.Int64("resident_id", resident.ID),
+		zap.String("pass_id", pass.ID.String()),
+	)
+
+	return request, pass, nil
+}
+
+func (s *PassRequestService) Decline(ctx context.Context, requestID int64, resident *domain.Resident) (*domain.PassRequest, error) {
+	request, err := s.pendingRequest(ctx, requestID, resident)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved, err := s.requestRepo.Resolve(ctx, request.ID, domain.PassRequestStatusDeclined, &resident.ID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decline pass request: %w", err)
+	}
+	if !resolved {
+		return nil, ErrPassRequestDecided
+	}
+
+	request.Status = domain.PassRequestStatusDeclined
+	request.DecidedBy = &resident.ID
+	return request, nil
+}
+
+// pendingRequest returns the request if the resident may still decide on
+// it. Requests of other apartments are reported as not found.
+func (s *PassRequestService) pendingRequest(ctx context.Context, requestID int64, resident *domain.Resident) (*domain.PassRequest, error) {
+	request, err := s.requestRepo.GetByID(ctx, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pass request: %w", err)
+	}
+	if request == nil || request.ApartmentID != resident.ApartmentID {
+		return nil, ErrPassRequestNotFound
+	}
+	if !resident.CanIssuePasses || resident.Status != "active" {
+		return nil, ErrCannotIssuePasses
+	}
+	if request.Status != domain.PassRequestStatusPending {
+		return nil, ErrPassRequestDecided
+	}
+
+	if time.Since(request.CreatedAt) > passRequestTTL {
+		if _, err := s.requestRepo.Resolve(ctx, request.ID, domain.PassRequestStatusExpired, nil, nil); err != nil {
+			s.logger.Error("failed to expire pass request", zap.Error(err), zap.Int64("request_id", request.ID))
+		}
+		return nil, ErrPassRequestExpired
+	}
+
+	return request, nil
+}
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"yardpass/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type MockPassRequestRepo struct {
+	mock.Mock
+}
+
+func (m *MockPassRequestRepo) Create(ctx context.Context, request *domain.PassRequest) error {
+	args := m.Called(ctx, request)
+	return args.Error(0)
+}
+
+func (m *MockPassRequestRepo) GetByID(ctx context.Context, id int64) (*domain.PassRequest, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PassRequest), args.Error(1)
+}
+
+func (m *MockPassRequestRepo) CountRecentByGuest(ctx context.Context, guestTelegramID int64, window time.Duration) (int, error) {
+	args := m.Called(ctx, guestTelegramID, window)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockPassRequestRepo) Resolve(ctx context.Context, id int64, status string, decidedBy *int64, passID *uuid.UUID) (bool, error) {
+	args := m.Called(ctx, id, status, decidedBy, passID)
+	return args.Bool(0), args.Error(1)
+}
+
+// MockResidentRepo mocks only GetByID, List and Deactivate; other methods
+// panic if called.
+type MockResidentRepo struct {
+	domain.ResidentRepository
+	mock.Mock
+}
+
+func (m *MockResidentRepo) GetByID(ctx context.Context, id int64) (*domain.Resident, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Resident), args.Error(1)
+}
+
+func (m *MockResidentRepo) List(ctx context.Context, filters domain.ResidentFilters) ([]*domain.Resident, error) {
+	args := m.Called(ctx, filters)
+	return args.Get(0).([]*domain.Resident), args.Error(1)
+}
+
+func (m *MockResidentRepo) Deactivate(ctx context.Context, id int64) (int, error) {
+	args := m.Called(ctx, id)
+	return args.Int(0), args.Error(1)
+}
+
+// passIssuer makes every resident an active resident of the apartment who
+// may issue passes.
+func passIssuer(apartmentID int64) *MockResidentRepo {
+	residentRepo := new(MockResidentRepo)
+	residentRepo.On("GetByID", mock.Anything, mock.Anything).Return(&domain.Resident{
+		ApartmentID:    apartmentID,
+		Status:         "active",
+		CanIssuePasses: true,
+	}, nil)
+	return residentRepo
+}
+
+func TestPassRequestService_SubmitRequest(t *testing.T) {
+	ctx := context.Background()
+	canIssue := &domain.Resident{ID: 1, ApartmentID: 10, Status: "active", CanIssuePasses: true}
+	cannotIssue := &domain.Resident{ID: 2, ApartmentID: 10, Status: "active"}
+	past := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name      string
+		recent    int
+		carPlate  string
+		validFrom *time.Time
+		residents []*domain.Resident
+		wantErr   error
+	}{
+		{
+			name:      "asks residents who can issue passes",
+			residents: []*domain.Resident{canIssue, cannotIssue},
+		},
+		{
+			name:    "too many requests",
+			recent:  maxPassRequestsPerHour,
+			wantErr: ErrTooManyPassRequests,
+		},
+		{
+			name:      "nobody can approve",
+			residents: []*domain.Resident{cannotIssue},
+			wantErr:   ErrNoPassRequestApprovers,
+		},
+		{
+			name:      "malformed car plate",
+			carPlate:  "A123BC",
+			residents: []*domain.Resident{canIssue},
+			wantErr:   ErrInvalidCarPlate,
+		},
+		{
+			name:      "start in the past",
+			validFrom: &past,
+			residents: []*domain.Resident{canIssue},
+			wantErr:   ErrPassRequestStartPassed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requestRepo := new(MockPassRequestRepo)
+			residentRepo := new(MockResidentRepo)
+			service := NewPassRequestService(requestRepo, nil, residentRepo, nil, zap.NewNop())
+
+			requestRepo.On("CountRecentByGuest", ctx, int64(2002), time.Hour).Return(tt.recent, nil)
+			residentRepo.On("List", ctx, mock.AnythingOfType("domain.ResidentFilters")).Return(tt.residents, nil)
+			requestRepo.On("Create", ctx, mock.AnythingOfType("*domain.PassRequest")).Return(nil)
+
+			plate := tt.carPlate
+			if plate == "" {
+				plate = "а123вс 77"
+			}
+			approvers, err := service.SubmitRequest(ctx, &domain.PassRequest{
+				ApartmentID:     10,
+				GuestTelegramID: 2002,
+				CarPlate:        &plate,
+				ValidFrom:       tt.validFrom,
+				DurationMinutes: 60,
+			})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				requestRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []*domain.Resident{canIssue}, approvers)
+
+			created := requestRepo.Calls[len(requestRepo.Calls)-1].Arguments.Get(1).(*domain.PassRequest)
+			assert.Equal(t, "A123BC77", *created.CarPlate)
+			assert.Equal(t, domain.PassRequestStatusPending, created.Status)
+		})
+	}
+}
+
+func TestPassRequestService_Approve(t *testing.T) {
+	ctx := context.Background()
+	resident := &domain.Resident{ID: 1, ApartmentID: 1, Status: "active", CanIssuePasses: true}
+	later := time.Now().Add(2 * time.Hour).UTC()
+	passed := time.Now().Add(-10 * time.Minute).UTC()
+
+	tests := []struct {
+		name      string
+		validFrom *time.Time
+		wantFrom  time.Time
+		wantTo    time.Time
+	}{
+		{name: "starts when approved", wantFrom: time.Now(), wantTo: time.Now().Add(time.Hour)},
+		{name: "starts at the picked time", validFrom: &later, wantFrom: later, wantTo: later.Add(time.Hour)},
+		{name: "a passed start keeps its end", validFrom: &passed, wantFrom: time.Now(), wantTo: passed.Add(time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			passService, _ := newExceptionPassService(t, 0)
+			requestRepo := new(MockPassRequestRepo)
+			service := NewPassRequestService(requestRepo, nil, nil, passService, zap.NewNop())
+
+			requestRepo.On("GetByID", ctx, int64(5)).Return(&domain.PassRequest{
+				ID:              5,
+				ApartmentID:     1,
+				ValidFrom:       tt.validFrom,
+				DurationMinutes: 60,
+				Status:          domain.PassRequestStatusPending,
+				CreatedAt:       time.Now(),
+			}, nil)
+			requestRepo.On("Resolve", ctx, int64(5), domain.PassRequestStatusApproved, &resident.ID, mock.Anything).Return(true, nil)
+
+			_, pass, err := service.Approve(ctx, 5, resident)
+
+			require.NoError(t, err)
+			assert.WithinDuration(t, tt.wantFrom, pass.ValidFrom, time.Minute)
+			assert.WithinDuration(t, tt.wantTo, pass.ValidTo, time.Minute)
+		})
+	}
+}
+
+func TestPassRequestService_Decline(t *testing.T) {
+	ctx := context.Background()
+	resident := &domain.Resident{ID: 1, ApartmentID: 10, Status: "active", CanIssuePasses: true}
+
+	tests := []struct {
+		name    string
+		request *domain.PassRequest
+		wantErr error
+	}{
+		{
+			name:    "pending request",
+			request: &domain.PassRequest{ID: 5, ApartmentID: 10, Status: domain.PassRequestStatusPending, CreatedAt: time.Now()},
+		},
+		{
+			name:    "request of another apartment",
+			request: &domain.PassRequest{ID: 5, ApartmentID: 11, Status: domain.PassRequestStatusPending, CreatedAt: time.Now()},
+			wantErr: ErrPassRequestNotFound,
+		},
+		{
+			name:    "already decided",
+			request: &domain.PassRequest{ID: 5, ApartmentID: 10, Status: domain.PassRequestStatusApproved, CreatedAt: time.Now()},
+			wantErr: ErrPassRequestDecided,
+		},
+		{
+			name:    "expired",
+			request: &domain.PassRequest{ID: 5, ApartmentID: 10, Status: domain.PassRequestStatusPending, CreatedAt: time.Now().Add(-passRequestTTL - time.Minute)},
+			wantErr: ErrPassRequestExpired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requestRepo := new(MockPassRequestRepo)
+			service := NewPassRequestService(requestRepo, nil, nil, nil, zap.NewNop())
+
+			requestRepo.On("GetByID", ctx, int64(5)).Return(tt.request, nil)
+			requestRepo.On("Resolve", ctx, int64(5), mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+
+			request, err := service.Decline(ctx, 5, resident)
+
+			switch tt.wantErr {
+			case nil:
+				require.NoError(t, err)
+				assert.Equal(t, domain.PassRequestStatusDeclined, request.Status)
+				requestRepo.AssertCalled(t, "Resolve", ctx, int64(5), domain.PassRequestStatusDeclined, &resident.ID, (*uuid.UUID)(nil))
+			case ErrPassRequestExpired:
+				assert.ErrorIs(t, err, tt.wantErr)
+				requestRepo.AssertCalled(t, "Resolve", ctx, int64(5), domain.PassRequestStatusExpired, (*int64)(nil), (*uuid.UUID)(nil))
+			default:
+				assert.ErrorIs(t, err, tt.wantErr)
+				requestRepo.AssertNotCalled(t, "Resolve", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"yardpass/internal/config"
+	"yardpass/internal/domain"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// shareTokenBytes is the entropy of a share token; 24 bytes make a
+// 32-character URL-safe token.
+const shareTokenBytes = 24
+
+var (
+	ErrPassSharingDisabled = errors.New("pass links are not configured")
+	ErrSharedPassNotFound  = errors.New("shared pass not found")
+	ErrPassNotShareable    = errors.New("only active passes can be shared")
+)
+
+// PassShareService manages public links to passes that residents send to
+// their guests instead of the QR image.
+type PassShareService struct {
+	passRepo      domain.PassRepository
+	apartmentRepo domain.ApartmentRepository
+	buildingRepo  domain.BuildingRepository
+	residentRepo  domain.ResidentRepository
+	publicURL     string
+	logger        *zap.Logger
+}
+
+func NewPassShareService(
+	cfg *config.Config,
+	passRepo domain.PassRepository,
+	apartmentRepo domain.ApartmentRepository,
+	buildingRepo domain.BuildingRepository,
+	residentRepo domain.ResidentRepository,
+	logger *zap.Logger,
+) *PassShareService {
+	return &PassShareService{
+		passRepo:      passRepo,
+		apartmentRepo: apartmentRepo,
+		buildingRepo:  buildingRepo,
+		residentRepo:  residentRepo,
+		publicURL:     strings.TrimRight(cfg.Server.PublicURL, "/"),
+		logger:        logger,
+	}
+}
+
+// Enabled reports whether links can be built, i.e. the public URL of the
+// API server is configured.
+func (s *PassShareService) Enabled() bool {
+	return s.publicURL != ""
+}
+
+// Share returns the public link of the pass, creating it on first use.
+// Passes outside the scope are reported as not found.
+func (s *PassShareService) Share(ctx context.Context, passID uuid.UUID, scope PassScope) (string, error) {
+	if !s.Enabled() {
+		return "", ErrPassSharingDisabled
+	}
+
+	pass, err := s.scopedPass(ctx, passID, scope)
+	if err != nil {
+		return "", err
+	}
+	if pass.Status != "active" || time.Now().After(pass.ValidTo) {
+		return "", ErrPassNotShareable
+	}
+
+	buf := make([]byte, shareTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate share token: %w", err)
+	}
+
+	token, err := s.passRepo.SetShareToken(ctx, passID, base64.RawURLEncoding.EncodeToString(buf))
+	if err != nil {
+		return "", fmt.Errorf("failed to share pass: %w", err)
+	}
+
+	return s.publicURL + "/p/" + token, nil
+}
+
+// Unshare revokes the public link; the pass itself stays valid. Passes
+// outside the scope are reported as not found.
+func (s *PassShareService) Unshare(ctx context.Context, passID uuid.UUID, scope PassScope) error {
+	if _, err := s.scopedPass(ctx, passID, scope); err != nil {
+		return err
+	}
+
+	if err := s.passRepo.ClearShareToken(ctx, passID); err != nil {
+		return fmt.Errorf("failed to revoke pass link: %w", err)
+	}
+
+	s.logger.Info("pass link revoked", zap.String("pass_id", passID.String()))
+	return nil
+}
+
+func (s *PassShareService) scopedPass(ctx context.Context, passID uuid.UUID, scope PassScope) (*domain.Pass, error) {
+	pass, err := s.passRepo.GetByID(ctx, passID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pass: %w", err)
+	}
+	if pass == nil {
+		return nil, ErrPassNotFound
+	}
+	if err := checkPassScope(ctx, s.apartmentRepo, s.residentRepo, pass, scope); err != nil {
+		return nil, err
+	}
+	return pass, nil
+}
+
+// GetSharedPass returns the pass of a public link with the building it
+// admits to.
+func (s *PassShareService) GetSharedPass(ctx context.Context, token string) (*domain.SharedPass, error) {
+	pass, err := s.passRepo.GetByShareToken(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pass: %w", err)
+	}
+	if pass == nil {
+		return nil, ErrSharedPassNotFound
+	}
+
+	details, err := newPassDetailsLoader(s.apartmentRepo, s.buildingRepo).load(ctx, pass)
+	if err != nil {
+		return nil, err
+	}
+	if details == nil {
+		return nil, ErrSharedPassNotFound
+	}
+
+	return &domain.SharedPass{
+		PassDetails: *details,
+		State:       sharedPassState(pass, time.Now()),
+	}, nil
+}
+
+func sharedPassState(pass *domain.Pass, now time.Time) string {
+	switch {
+	case pass.Status == "revoked":
+		return domain.SharedPassStateRevoked
+	case pass.Status == "used":
+		return domain.SharedPassStateUsed
+	case pass.Status != "active" || now.After(pass.ValidTo):
+		return domain.SharedPassStateExpired
+	case now.Before(pass.ValidFrom):
+		return domain.SharedPassStateNotYetValid
+	default:
+		return domain.SharedPassStateActive
+	}
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"yardpass/internal/config"
+	"yardpass/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestPassShareService_Share(t *testing.T) {
+	ctx := context.Background()
+	passID := uuid.New()
+	enabled := &config.Config{Server: config.ServerConfig{PublicURL: "https://yardpass.example/"}}
+
+	tests := []struct {
+		name    string
+		cfg     *config.Config
+		pass    *domain.Pass
+		wantErr error
+	}{
+		{
+			name: "active pass",
+			cfg:  enabled,
+			pass: &domain.Pass{ID: passID, Status: "active", ValidTo: time.Now().Add(time.Hour)},
+		},
+		{
+			name:    "public URL not configured",
+			cfg:     &config.Config{},
+			pass:    &domain.Pass{ID: passID, Status: "active", ValidTo: time.Now().Add(time.Hour)},
+			wantErr: ErrPassSharingDisabled,
+		},
+		{
+			name:    "revoked pass",
+			cfg:     enabled,
+			pass:    &domain.Pass{ID: passID, Status: "revoked", ValidTo: time.Now().Add(time.Hour)},
+			wantErr: ErrPassNotShareable,
+		},
+		{
+			name:    "expired pass",
+			cfg:     enabled,
+			pass:    &domain.Pass{ID: passID, Status: "active", ValidTo: time.Now().Add(-time.Minute)},
+			wantErr: ErrPassNotShareable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			passRepo := new(MockPassRepo)
+			service := NewPassShareService(tt.cfg, passRepo, nil, nil, nil, zap.NewNop())
+
+			passRepo.On("GetByID", ctx, passID).Return(tt.pass, nil)
+			passRepo.On("SetShareToken", ctx, passID, mock.AnythingOfType("string")).Return("tok", nil)
+
+			url, err := service.Share(ctx, passID, PassScope{})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				passRepo.AssertNotCalled(t, "SetShareToken", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "https://yardpass.example/p/tok", url)
+
+			token := passRepo.Calls[len(passRepo.Calls)-1].Arguments.String(2)
+			assert.Len(t, token, 32)
+		})
+	}
+}
+
+func TestPassShareService_Scope(t *testing.T) {
+	ctx := context.Background()
+	passID := uuid.New()
+	enabled := &config.Config{Server: config.ServerConfig{PublicURL: "https://yardpass.example"}}
+	owner, member, primary, stranger := int64(1), int64(2), int64(3), int64(4)
+	building, otherBuilding := int64(10), int64(20)
+
+	tests := []struct {
+		name    string
+		scope   PassScope
+		wantErr error
+	}{
+		{name: "own building", scope: PassScope{BuildingID: &building}},
+		{name: "other building", scope: PassScope{BuildingID: &otherBuilding}, wantErr: ErrPassNotFound},
+		{name: "pass owner", scope: PassScope{ResidentID: &owner}},
+		{name: "primary resident of the apartment", scope: PassScope{ResidentID: &primary}},
+		{name: "other household member", scope: PassScope{ResidentID: &member}, wantErr: ErrPassNotFound},
+		{name: "resident of another apartment", scope: PassScope{ResidentID: &stranger}, wantErr: ErrPassNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			passRepo := new(MockPassRepo)
+			apartmentRepo := new(MockApartmentRepo)
+			residentRepo := new(MockResidentRepo)
+			service := NewPassShareService(enabled, passRepo, apartmentRepo, nil, residentRepo, zap.NewNop())
+
+			passRepo.On("GetByID", ctx, passID).Return(&domain.Pass{ID: passID, ApartmentID: 5, ResidentID: &owner, Status: "active", ValidTo: time.Now().Add(time.Hour)}, nil)
+			passRepo.On("SetShareToken", ctx, passID, mock.AnythingOfType("string")).Return("tok", nil)
+			passRepo.On("ClearShareToken", ctx, passID).Return(nil)
+			apartmentRepo.On("GetByID", ctx, int64(5)).Return(&domain.Apartment{ID: 5, BuildingID: building}, nil)
+			residentRepo.On("GetByID", ctx, owner).Return(&domain.Resident{ID: owner, ApartmentID: 5, Status: "active", Role: "member"}, nil)
+			residentRepo.On("GetByID", ctx, member).Return(&domain.Resident{ID: member, ApartmentID: 5, Status: "active", Role: "member"}, nil)
+			residentRepo.On("GetByID", ctx, primary).Return(&domain.Resident{ID: primary, ApartmentID: 5, Status: "active", Role: "primary"}, nil)
+			residentRepo.On("GetByID", ctx, stranger).Return(&domain.Resident{ID: stranger, ApartmentID: 6, Status: "active", Role: "primary"}, nil)
+
+			_, shareErr := service.Share(ctx, passID, tt.scope)
+			unshareErr := service.Unshare(ctx, passID, tt.scope)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, shareErr, tt.wantErr)
+				assert.ErrorIs(t, unshareErr, tt.wantErr)
+				passRepo.AssertNotCalled(t, "SetShareToken", mock.Anything, mock.Anything, mock.Anything)
+				passRepo.AssertNotCalled(t, "ClearShareToken", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, shareErr)
+			assert.NoError(t, unshareErr)
+		})
+	}
+}
+
+func TestSharedPassState(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name string
+		pass domain.Pass
+		want string
+	}{
+		{"active", domain.Pass{Status: "active", ValidFrom: now.Add(-time.Hour), ValidTo: now.Add(time.Hour)}, domain.SharedPassStateActive},
+		{"not yet valid", domain.Pass{Status: "active", ValidFrom: now.Add(time.Hour), ValidTo: now.Add(2 * time.Hour)}, domain.SharedPassStateNotYetValid},
+		{"expired", domain.Pass{Status: "active", ValidFrom: now.Add(-2 * time.Hour), ValidTo: now.Add(-time.Hour)}, domain.SharedPassStateExpired},
+		{"revoked", domain.Pass{Status: "revoked", ValidFrom: now.Add(-time.Hour), ValidTo: now.Add(time.Hour)}, domain.SharedPassStateRevoked},
+		{"used", domain.Pass{Status: "used", ValidFrom: now.Add(-time.Hour), ValidTo: now.Add(time.Hour)}, domain.SharedPassStateUsed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, sharedPassState(&tt.pass, now))
+		})
+	}
+}
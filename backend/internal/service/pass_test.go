@@ -6,10 +6,12 @@ import (
 	"time"
 
 	"yardpass/internal/domain"
+	"yardpass/internal/qr"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
 
@@ -25,6 +27,11 @@ func (m *MockPassRepo) GetActiveByCarPlate(ctx context.Context, normalizedCarPla
 	return args.Get(0).(*domain.Pass), args.Error(1)
 }
 
+func (m *MockPassRepo) ListActiveByCarPlateLength(ctx context.Context, buildingID *int64, minLength, maxLength int) ([]*domain.Pass, error) {
+	args := m.Called(ctx, buildingID, minLength, maxLength)
+	return args.Get(0).([]*domain.Pass), args.Error(1)
+}
+
 func (m *MockPassRepo) GetActiveByResidentID(ctx context.Context, residentID int64) ([]*domain.Pass, error) {
 	args := m.Called(ctx, residentID)
 	return args.Get(0).([]*domain.Pass), args.Error(1)
@@ -83,6 +90,45 @@ func (m *MockPassRepo) SearchByCarPlate(ctx context.Context, carPlate string, bu
 	return args.Get(0).([]*domain.Pass), args.Error(1)
 }
 
+func (m *MockPassRepo) GetByShareToken(ctx context.Context, token string) (*domain.Pass, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Pass), args.Error(1)
+}
+
+func (m *MockPassRepo) SetShareToken(ctx context.Context, id uuid.UUID, token string) (string, error) {
+	args := m.Called(ctx, id, token)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockPassRepo) ClearShareToken(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockPassRepo) SetQRSecret(ctx context.Context, id uuid.UUID, secret []byte) ([]byte, error) {
+	args := m.Called(ctx, id, secret)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (m *MockPassRepo) CountActiveTodayByCategory(ctx context.Context, apartmentID int64, category string) (int, error) {
+	args := m.Called(ctx, apartmentID, category)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockPassRepo) ListContractorPasses(ctx context.Context, buildingID *int64) ([]*domain.Pass, error) {
+	args := m.Called(ctx, buildingID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Pass), args.Error(1)
+}
+
 type MockApartmentRepo struct {
 	mock.Mock
 }
@@ -100,12 +146,9 @@ func (m *MockApartmentRepo) GetByBuildingID(ctx context.Context, buildingID int6
 	return args.Get(0).([]*domain.Apartment), args.Error(1)
 }
 
-func (m *MockApartmentRepo) GetByResidentTelegramID(ctx context.Context, telegramID int64) (*domain.Apartment, error) {
+func (m *MockApartmentRepo) GetByResidentTelegramID(ctx context.Context, telegramID int64) ([]*domain.Apartment, error) {
 	args := m.Called(ctx, telegramID)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).(*domain.Apartment), args.Error(1)
+	return args.Get(0).([]*domain.Apartment), args.Error(1)
 }
 
 type MockRuleRepo struct {
@@ -168,7 +211,7 @@ func TestPassService_CreatePass(t *testing.T) {
 	ruleRepo := new(MockRuleRepo)
 	scanEventRepo := new(MockScanEventRepo)
 
-	service := NewPassService(passRepo, apartmentRepo, ruleRepo, scanEventRepo, logger)
+	service := NewPassService(passRepo, apartmentRepo, ruleRepo, scanEventRepo, nil, noWatchlist(), passIssuer(1), time.UTC, logger)
 
 	t.Run("successful creation", func(t *testing.T) {
 		apartmentID := int64(1)
@@ -188,10 +231,10 @@ func TestPassService_CreatePass(t *testing.T) {
 		}, nil)
 
 		residentID := int64(1)
-		passRepo.On("CountActiveTodayByResidentID", ctx, residentID).Return(2, nil)
+		passRepo.On("CountActiveTodayByApartmentID", ctx, apartmentID).Return(2, nil)
 		passRepo.On("Create", ctx, mock.AnythingOfType("*domain.Pass")).Return(nil)
 
-		carPlate := "A123BC"
+		carPlate := "A123BC77"
 		req := domain.CreatePassRequest{
 			ApartmentID: apartmentID,
 			ResidentID:  &residentID,
@@ -205,7 +248,7 @@ func TestPassService_CreatePass(t *testing.T) {
 		assert.NoError(t, err)
 		assert.NotNil(t, pass)
 		assert.NotNil(t, pass.CarPlate)
-		assert.Equal(t, "A123BC", *pass.CarPlate)
+		assert.Equal(t, "A123BC77", *pass.CarPlate)
 		assert.Equal(t, "active", pass.Status)
 
 		passRepo.AssertExpectations(t)
@@ -218,7 +261,7 @@ func TestPassService_CreatePass(t *testing.T) {
 		apartmentRepo2 := new(MockApartmentRepo)
 		ruleRepo2 := new(MockRuleRepo)
 		scanEventRepo2 := new(MockScanEventRepo)
-		service2 := NewPassService(passRepo2, apartmentRepo2, ruleRepo2, scanEventRepo2, logger)
+		service2 := NewPassService(passRepo2, apartmentRepo2, ruleRepo2, scanEventRepo2, nil, noWatchlist(), passIssuer(1), time.UTC, logger)
 
 		apartmentID := int64(1)
 		buildingID := int64(1)
@@ -236,9 +279,9 @@ func TestPassService_CreatePass(t *testing.T) {
 		}, nil)
 
 		residentID := int64(1)
-		passRepo2.On("CountActiveTodayByResidentID", ctx, residentID).Return(5, nil)
+		passRepo2.On("CountActiveTodayByApartmentID", ctx, apartmentID).Return(5, nil)
 
-		carPlate := "A123BC"
+		carPlate := "A123BC77"
 		req := domain.CreatePassRequest{
 			ApartmentID: apartmentID,
 			ResidentID:  &residentID,
@@ -259,6 +302,66 @@ func TestPassService_CreatePass(t *testing.T) {
 		apartmentRepo2.AssertExpectations(t)
 		ruleRepo2.AssertExpectations(t)
 	})
+
+	t.Run("resident may not issue passes", func(t *testing.T) {
+		residentID := int64(2)
+		carPlate := "A123BC77"
+		tests := []struct {
+			name     string
+			resident *domain.Resident
+		}{
+			{name: "right revoked", resident: &domain.Resident{ID: residentID, ApartmentID: 1, Status: "active"}},
+			{name: "inactive", resident: &domain.Resident{ID: residentID, ApartmentID: 1, Status: "inactive", CanIssuePasses: true}},
+			{name: "other apartment", resident: &domain.Resident{ID: residentID, ApartmentID: 2, Status: "active", CanIssuePasses: true}},
+			{name: "unknown resident"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				passRepo4 := new(MockPassRepo)
+				residentRepo := new(MockResidentRepo)
+				if tt.resident != nil {
+					residentRepo.On("GetByID", ctx, residentID).Return(tt.resident, nil)
+				} else {
+					residentRepo.On("GetByID", ctx, residentID).Return(nil, nil)
+				}
+				service4 := NewPassService(passRepo4, new(MockApartmentRepo), new(MockRuleRepo), new(MockScanEventRepo), nil, noWatchlist(), residentRepo, time.UTC, logger)
+
+				pass, err := service4.CreatePass(ctx, domain.CreatePassRequest{
+					ApartmentID: 1,
+					ResidentID:  &residentID,
+					CarPlate:    &carPlate,
+					ValidFrom:   time.Now(),
+					ValidTo:     time.Now().Add(time.Hour),
+				})
+
+				assert.Nil(t, pass)
+				assert.ErrorIs(t, err, ErrCannotIssuePasses)
+				passRepo4.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			})
+		}
+	})
+
+	t.Run("malformed car plate", func(t *testing.T) {
+		passRepo3 := new(MockPassRepo)
+		apartmentRepo3 := new(MockApartmentRepo)
+		service3 := NewPassService(passRepo3, apartmentRepo3, new(MockRuleRepo), new(MockScanEventRepo), nil, noWatchlist(), nil, time.UTC, logger)
+
+		carPlate := "а123вс"
+		pass, err := service3.CreatePass(ctx, domain.CreatePassRequest{
+			ApartmentID: 1,
+			CarPlate:    &carPlate,
+			ValidFrom:   time.Now(),
+			ValidTo:     time.Now().Add(time.Hour),
+		})
+
+		assert.Nil(t, pass)
+		assert.ErrorIs(t, err, ErrInvalidCarPlate)
+		// The error tells the resident which plates are expected.
+		assert.Contains(t, err.Error(), "A123BC")
+		assert.Contains(t, err.Error(), "А123ВС77 (RU standard)")
+		apartmentRepo3.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
 }
 
 func TestPassService_ValidatePass(t *testing.T) {
@@ -270,7 +373,7 @@ func TestPassService_ValidatePass(t *testing.T) {
 	ruleRepo := new(MockRuleRepo)
 	scanEventRepo := new(MockScanEventRepo)
 
-	service := NewPassService(passRepo, apartmentRepo, ruleRepo, scanEventRepo, logger)
+	service := NewPassService(passRepo, apartmentRepo, ruleRepo, scanEventRepo, nil, noWatchlist(), nil, time.UTC, logger)
 
 	t.Run("valid pass", func(t *testing.T) {
 		passID := uuid.New()
@@ -278,7 +381,7 @@ func TestPassService_ValidatePass(t *testing.T) {
 		buildingID := int64(1)
 		now := time.Now()
 
-		carPlate := "A123BC"
+		carPlate := "A123BC77"
 		pass := &domain.Pass{
 			ID:          passID,
 			ApartmentID: apartmentID,
@@ -327,4 +430,509 @@ func TestPassService_ValidatePass(t *testing.T) {
 		assert.False(t, result.Valid)
 		assert.Equal(t, "PASS_EXPIRED", result.Reason)
 	})
+
+	t.Run("single-use pass admits once", func(t *testing.T) {
+		passID := uuid.New()
+		now := time.Now()
+
+		pass := &domain.Pass{
+			ID:          passID,
+			ApartmentID: 1,
+			Status:      "active",
+			SingleUse:   true,
+			ValidFrom:   now.Add(-time.Minute),
+			ValidTo:     now.Add(10 * time.Minute),
+		}
+
+		passRepo.On("GetByID", ctx, passID).Return(pass, nil)
+		passRepo.On("Update", ctx, mock.AnythingOfType("*domain.Pass")).Return(nil)
+		apartmentRepo.On("GetByID", ctx, int64(1)).Return(&domain.Apartment{ID: 1, BuildingID: 1}, nil)
+		ruleRepo.On("GetByBuildingID", ctx, int64(1)).Return(&domain.Rule{}, nil)
+		scanEventRepo.On("Create", ctx, mock.AnythingOfType("*domain.ScanEvent")).Return(nil)
+
+		result, err := service.ValidatePass(ctx, passID, 1)
+		require.NoError(t, err)
+		assert.True(t, result.Valid)
+		assert.Equal(t, "used", pass.Status)
+
+		result, err = service.ValidatePass(ctx, passID, 1)
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, "PASS_USED", result.Reason)
+	})
+
+	t.Run("contractor pass on another weekday", func(t *testing.T) {
+		passID := uuid.New()
+		now := time.Now()
+
+		// Admit only on the local weekday after today.
+		tomorrow := int(now.In(service.location).AddDate(0, 0, 1).Weekday())
+		if tomorrow == 0 {
+			tomorrow = 7
+		}
+		company := "Ремонт-Сервис"
+		pass := &domain.Pass{
+			ID:          passID,
+			ApartmentID: 1,
+			Status:      "active",
+			Category:    domain.PassCategoryContractor,
+			CompanyName: &company,
+			Weekdays:    []int{tomorrow},
+			ValidFrom:   now.Add(-24 * time.Hour),
+			ValidTo:     now.Add(14 * 24 * time.Hour),
+		}
+
+		passRepo.On("GetByID", ctx, passID).Return(pass, nil)
+		scanEventRepo.On("Create", ctx, mock.AnythingOfType("*domain.ScanEvent")).Return(nil)
+
+		result, err := service.ValidatePass(ctx, passID, 1)
+
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, "OUTSIDE_ALLOWED_HOURS", result.Reason)
+	})
+}
+
+func TestPassService_ValidateQR_Dynamic(t *testing.T) {
+	ctx := context.Background()
+	passID := uuid.New()
+	secret := []byte("0123456789abcdefghij")
+	step := qr.TimeStep(time.Now())
+
+	tests := []struct {
+		name       string
+		code       qr.Code
+		wantReason string
+	}{
+		{name: "current code", code: qr.Code{PassID: passID, Step: step, MAC: qr.MAC(secret, passID, step)}},
+		{name: "previous code within skew", code: qr.Code{PassID: passID, Step: step - 1, MAC: qr.MAC(secret, passID, step-1)}},
+		{name: "static code", code: qr.Code{PassID: passID}, wantReason: "DYNAMIC_QR_REQUIRED"},
+		{name: "old screenshot", code: qr.Code{PassID: passID, Step: step - 3, MAC: qr.MAC(secret, passID, step-3)}, wantReason: "QR_CODE_EXPIRED"},
+		{name: "forged MAC", code: qr.Code{PassID: passID, Step: step, MAC: qr.MAC([]byte("other"), passID, step)}, wantReason: "QR_CODE_INVALID"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			passRepo := new(MockPassRepo)
+			apartmentRepo := new(MockApartmentRepo)
+			ruleRepo := new(MockRuleRepo)
+			scanEventRepo := new(MockScanEventRepo)
+			service := NewPassService(passRepo, apartmentRepo, ruleRepo, scanEventRepo, nil, noWatchlist(), nil, time.UTC, zap.NewNop())
+
+			passRepo.On("GetByID", ctx, passID).Return(&domain.Pass{
+				ID:          passID,
+				ApartmentID: 1,
+				Status:      "active",
+				Dynamic:     true,
+				QRSecret:    secret,
+				ValidFrom:   time.Now().Add(-time.Hour),
+				ValidTo:     time.Now().Add(time.Hour),
+			}, nil)
+			apartmentRepo.On("GetByID", ctx, int64(1)).Return(&domain.Apartment{ID: 1, BuildingID: 1}, nil)
+			ruleRepo.On("GetByBuildingID", ctx, int64(1)).Return(&domain.Rule{}, nil)
+			scanEventRepo.On("Create", ctx, mock.AnythingOfType("*domain.ScanEvent")).Return(nil)
+
+			result, err := service.ValidateQR(ctx, tt.code, 1)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantReason == "", result.Valid)
+			assert.Equal(t, tt.wantReason, result.Reason)
+		})
+	}
+}
+
+func TestPassService_EnableDynamicQR(t *testing.T) {
+	ctx := context.Background()
+	passID := uuid.New()
+
+	passRepo := new(MockPassRepo)
+	service := NewPassService(passRepo, nil, nil, nil, nil, nil, nil, time.UTC, zap.NewNop())
+
+	passRepo.On("GetByID", ctx, passID).Return(&domain.Pass{ID: passID, Status: "active", ValidTo: time.Now().Add(time.Hour)}, nil)
+	passRepo.On("SetQRSecret", ctx, passID, mock.AnythingOfType("[]uint8")).Return([]byte("stored-secret"), nil)
+
+	pass, err := service.EnableDynamicQR(ctx, passID, PassScope{})
+
+	require.NoError(t, err)
+	assert.True(t, pass.Dynamic)
+	assert.Equal(t, []byte("stored-secret"), pass.QRSecret)
+	assert.Len(t, passRepo.Calls[1].Arguments.Get(2), 20)
+}
+
+func TestPassService_EnableDynamicQR_OutOfScope(t *testing.T) {
+	ctx := context.Background()
+	passID := uuid.New()
+	owner, member := int64(1), int64(2)
+	otherBuilding := int64(20)
+
+	passRepo := new(MockPassRepo)
+	apartmentRepo := new(MockApartmentRepo)
+	residentRepo := new(MockResidentRepo)
+	service := NewPassService(passRepo, apartmentRepo, nil, nil, nil, nil, residentRepo, time.UTC, zap.NewNop())
+
+	passRepo.On("GetByID", ctx, passID).Return(&domain.Pass{ID: passID, ApartmentID: 5, ResidentID: &owner, Status: "active", ValidTo: time.Now().Add(time.Hour)}, nil)
+	apartmentRepo.On("GetByID", ctx, int64(5)).Return(&domain.Apartment{ID: 5, BuildingID: 10}, nil)
+	residentRepo.On("GetByID", ctx, member).Return(&domain.Resident{ID: member, ApartmentID: 5, Status: "active", Role: "member"}, nil)
+
+	for _, scope := range []PassScope{{BuildingID: &otherBuilding}, {ResidentID: &member}} {
+		pass, err := service.EnableDynamicQR(ctx, passID, scope)
+
+		assert.Nil(t, pass)
+		assert.ErrorIs(t, err, ErrPassNotFound)
+	}
+	passRepo.AssertNotCalled(t, "SetQRSecret", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPassService_CheckPassWindow(t *testing.T) {
+	logger := zap.NewNop()
+	ctx := context.Background()
+
+	apartmentRepo := new(MockApartmentRepo)
+	ruleRepo := new(MockRuleRepo)
+
+	service := NewPassService(new(MockPassRepo), apartmentRepo, ruleRepo, new(MockScanEventRepo), nil, noWatchlist(), nil, time.UTC, logger)
+
+	quietStart, quietEnd := "22:00", "07:00"
+	apartmentRepo.On("GetByID", ctx, int64(1)).Return(&domain.Apartment{ID: 1, BuildingID: 1, Number: "101"}, nil)
+	ruleRepo.On("GetByBuildingID", ctx, int64(1)).Return(&domain.Rule{
+		DailyPassLimitPerApartment: 5,
+		MaxPassDurationHours:       24,
+		QuietHoursStart:            &quietStart,
+		QuietHoursEnd:              &quietEnd,
+	}, nil)
+
+	day := time.Now().UTC().AddDate(0, 0, 2).Truncate(24 * time.Hour)
+	at := func(hours float64) time.Time {
+		return day.Add(time.Duration(hours * float64(time.Hour)))
+	}
+
+	tests := []struct {
+		name    string
+		from    time.Time
+		to      time.Time
+		wantErr string
+	}{
+		{name: "evening before quiet hours", from: at(20), to: at(21)},
+		{name: "morning after quiet hours", from: at(7), to: at(12)},
+		{name: "runs into quiet hours", from: at(21), to: at(23), wantErr: "quiet hours"},
+		{name: "ends in the morning quiet hours", from: at(5), to: at(8), wantErr: "quiet hours"},
+		{name: "spans the night", from: at(12), to: at(32), wantErr: "quiet hours"},
+		{name: "too long", from: at(8), to: at(40), wantErr: "exceeds maximum"},
+		{name: "ends before it starts", from: at(12), to: at(10), wantErr: "after valid_from"},
+		{name: "starts in the past", from: time.Now().Add(-time.Hour), to: time.Now().Add(time.Hour), wantErr: "past"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.CheckPassWindow(ctx, 1, domain.PassCategoryGuest, tt.from, tt.to)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestPassService_CreateContractorPass(t *testing.T) {
+	logger := zap.NewNop()
+	ctx := context.Background()
+
+	passRepo := new(MockPassRepo)
+	apartmentRepo := new(MockApartmentRepo)
+	ruleRepo := new(MockRuleRepo)
+
+	service := NewPassService(passRepo, apartmentRepo, ruleRepo, new(MockScanEventRepo), nil, noWatchlist(), nil, time.UTC, logger)
+
+	apartmentRepo.On("GetByID", ctx, int64(1)).Return(&domain.Apartment{ID: 1, BuildingID: 1, Number: "101"}, nil)
+	ruleRepo.On("GetByBuildingID", ctx, int64(1)).Return(&domain.Rule{
+		DailyPassLimitPerApartment: 1,
+		MaxPassDurationHours:       24,
+		MaxContractorPassDays:      30,
+	}, nil)
+	passRepo.On("Create", ctx, mock.AnythingOfType("*domain.Pass")).Return(nil)
+
+	validFrom := time.Now().Add(time.Hour)
+	adminID := int64(7)
+	building := int64(1)
+	plate := "а 123 вс 77"
+
+	newRequest := func() domain.CreateContractorPassRequest {
+		return domain.CreateContractorPassRequest{
+			ApartmentID: 1,
+			CompanyName: " Ремонт-Сервис ",
+			CarPlate:    &plate,
+			ValidFrom:   validFrom,
+			ValidTo:     validFrom.Add(21 * 24 * time.Hour),
+			Weekdays:    []int{5, 1, 2, 3, 4, 1},
+			DailyFrom:   "9:00",
+			DailyTo:     "18:00",
+			IssuedBy:    &adminID,
+		}
+	}
+
+	t.Run("weekdays for three weeks", func(t *testing.T) {
+		pass, err := service.CreateContractorPass(ctx, newRequest(), &building)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.PassCategoryContractor, pass.Category)
+		assert.Equal(t, "Ремонт-Сервис", *pass.CompanyName)
+		assert.Equal(t, "A123BC77", *pass.CarPlate)
+		assert.Equal(t, []int{1, 2, 3, 4, 5}, pass.Weekdays)
+		assert.Equal(t, "09:00", *pass.DailyFrom)
+		assert.Equal(t, "18:00", *pass.DailyTo)
+		assert.Equal(t, &adminID, pass.IssuedBy)
+		assert.Nil(t, pass.ResidentID)
+		passRepo.AssertNotCalled(t, "CountActiveTodayByApartmentID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("service company", func(t *testing.T) {
+		req := newRequest()
+		req.Category = domain.PassCategoryService
+
+		pass, err := service.CreateContractorPass(ctx, req, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.PassCategoryService, pass.Category)
+	})
+
+	t.Run("every day without hours", func(t *testing.T) {
+		req := newRequest()
+		req.Weekdays = []int{7, 6, 5, 4, 3, 2, 1}
+		req.DailyFrom, req.DailyTo = "", ""
+
+		pass, err := service.CreateContractorPass(ctx, req, nil)
+
+		require.NoError(t, err)
+		assert.Nil(t, pass.Weekdays)
+		assert.Nil(t, pass.DailyFrom)
+		assert.Nil(t, pass.DailyTo)
+	})
+
+	otherBuilding := int64(2)
+	tests := []struct {
+		name       string
+		modify     func(req *domain.CreateContractorPassRequest)
+		buildingID *int64
+		wantErr    error
+		errText    string
+	}{
+		{name: "no company", modify: func(req *domain.CreateContractorPassRequest) { req.CompanyName = " " }, wantErr: ErrCompanyNameRequired},
+		{name: "resident category", modify: func(req *domain.CreateContractorPassRequest) { req.Category = domain.PassCategoryTaxi }, wantErr: ErrInvalidCategory},
+		{name: "weekday out of range", modify: func(req *domain.CreateContractorPassRequest) { req.Weekdays = []int{0, 1} }, wantErr: ErrInvalidWeekdays},
+		{name: "only start of the window", modify: func(req *domain.CreateContractorPassRequest) { req.DailyTo = "" }, wantErr: ErrInvalidDailyWindow},
+		{name: "malformed window", modify: func(req *domain.CreateContractorPassRequest) { req.DailyTo = "25:00" }, wantErr: ErrInvalidDailyWindow},
+		{name: "empty window", modify: func(req *domain.CreateContractorPassRequest) { req.DailyTo = "09:00" }, wantErr: ErrInvalidDailyWindow},
+		{name: "apartment of another building", modify: func(req *domain.CreateContractorPassRequest) {}, buildingID: &otherBuilding, wantErr: ErrApartmentNotFound},
+		{name: "longer than the rules", modify: func(req *domain.CreateContractorPassRequest) { req.ValidTo = validFrom.Add(31 * 24 * time.Hour) }, errText: "exceeds maximum of 30 days"},
+		{name: "starts in the past", modify: func(req *domain.CreateContractorPassRequest) { req.ValidFrom = time.Now().Add(-time.Hour) }, errText: "past"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newRequest()
+			tt.modify(&req)
+
+			pass, err := service.CreateContractorPass(ctx, req, tt.buildingID)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.ErrorContains(t, err, tt.errText)
+			}
+			assert.Nil(t, pass)
+		})
+	}
+}
+
+func TestWithinSchedule(t *testing.T) {
+	dayFrom, dayTo := "09:00", "18:00"
+	nightFrom, nightTo := "22:00", "06:00"
+
+	// 2026-04-27 is a Monday.
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 4, 27+day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		weekdays []int
+		from, to *string
+		now      time.Time
+		want     bool
+	}{
+		{name: "no restrictions", now: at(6, 3, 0), want: true},
+		{name: "working hours on a weekday", weekdays: []int{1, 2, 3, 4, 5}, from: &dayFrom, to: &dayTo, now: at(0, 9, 0), want: true},
+		{name: "before the window", weekdays: []int{1, 2, 3, 4, 5}, from: &dayFrom, to: &dayTo, now: at(0, 8, 59)},
+		{name: "end of the window", weekdays: []int{1, 2, 3, 4, 5}, from: &dayFrom, to: &dayTo, now: at(4, 18, 0)},
+		{name: "saturday", weekdays: []int{1, 2, 3, 4, 5}, from: &dayFrom, to: &dayTo, now: at(5, 12, 0)},
+		{name: "sunday is seven", weekdays: []int{7}, now: at(6, 12, 0), want: true},
+		{name: "night shift in the evening", weekdays: []int{5}, from: &nightFrom, to: &nightTo, now: at(4, 23, 0), want: true},
+		{name: "night shift after midnight", weekdays: []int{5}, from: &nightFrom, to: &nightTo, now: at(5, 5, 0), want: true},
+		{name: "night shift of the day before", weekdays: []int{5}, from: &nightFrom, to: &nightTo, now: at(4, 5, 0)},
+		{name: "between night shifts", weekdays: []int{5}, from: &nightFrom, to: &nightTo, now: at(4, 12, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pass := &domain.Pass{Weekdays: tt.weekdays, DailyFrom: tt.from, DailyTo: tt.to}
+			assert.Equal(t, tt.want, withinSchedule(pass, tt.now))
+		})
+	}
+}
+
+func TestPassService_CreatePass_Categories(t *testing.T) {
+	logger := zap.NewNop()
+	ctx := context.Background()
+
+	taxiHours, taxiLimit := 1, 2
+	rule := &domain.Rule{
+		DailyPassLimitPerApartment: 5,
+		MaxPassDurationHours:       24,
+		Categories: []domain.CategoryRule{
+			{Category: domain.PassCategoryTaxi, MaxDurationHours: &taxiHours, DailyLimit: &taxiLimit},
+		},
+	}
+
+	newService := func(taxisToday int) (*PassService, *MockPassRepo) {
+		passRepo := new(MockPassRepo)
+		apartmentRepo := new(MockApartmentRepo)
+		ruleRepo := new(MockRuleRepo)
+		apartmentRepo.On("GetByID", ctx, int64(1)).Return(&domain.Apartment{ID: 1, BuildingID: 1}, nil)
+		ruleRepo.On("GetByBuildingID", ctx, int64(1)).Return(rule, nil)
+		passRepo.On("CountActiveTodayByApartmentID", ctx, int64(1)).Return(1, nil)
+		passRepo.On("CountActiveTodayByCategory", ctx, int64(1), domain.PassCategoryTaxi).Return(taxisToday, nil)
+		passRepo.On("Create", ctx, mock.AnythingOfType("*domain.Pass")).Return(nil)
+		return NewPassService(passRepo, apartmentRepo, ruleRepo, new(MockScanEventRepo), nil, noWatchlist(), passIssuer(1), time.UTC, logger), passRepo
+	}
+
+	residentID := int64(1)
+	now := time.Now()
+	newRequest := func(category string, duration time.Duration) domain.CreatePassRequest {
+		plate := "A123BC77"
+		return domain.CreatePassRequest{
+			ApartmentID: 1,
+			ResidentID:  &residentID,
+			CarPlate:    &plate,
+			ValidFrom:   now,
+			ValidTo:     now.Add(duration),
+			Category:    category,
+		}
+	}
+
+	t.Run("guest by default", func(t *testing.T) {
+		service, passRepo := newService(0)
+
+		pass, err := service.CreatePass(ctx, newRequest("", 3*time.Hour))
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.PassCategoryGuest, pass.Category)
+		passRepo.AssertNotCalled(t, "CountActiveTodayByCategory", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("taxi within its limits", func(t *testing.T) {
+		service, _ := newService(1)
+
+		pass, err := service.CreatePass(ctx, newRequest(domain.PassCategoryTaxi, 30*time.Minute))
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.PassCategoryTaxi, pass.Category)
+	})
+
+	t.Run("delivery uses the building limits", func(t *testing.T) {
+		service, _ := newService(0)
+
+		pass, err := service.CreatePass(ctx, newRequest(domain.PassCategoryDelivery, 3*time.Hour))
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.PassCategoryDelivery, pass.Category)
+	})
+
+	t.Run("taxi longer than its category allows", func(t *testing.T) {
+		service, _ := newService(0)
+
+		pass, err := service.CreatePass(ctx, newRequest(domain.PassCategoryTaxi, 2*time.Hour))
+
+		assert.ErrorContains(t, err, "exceeds maximum of 1 hours")
+		assert.Nil(t, pass)
+	})
+
+	t.Run("taxi daily limit", func(t *testing.T) {
+		service, passRepo := newService(2)
+
+		pass, err := service.CreatePass(ctx, newRequest(domain.PassCategoryTaxi, 30*time.Minute))
+
+		assert.ErrorContains(t, err, "daily pass limit exceeded for taxi passes")
+		assert.Nil(t, pass)
+		passRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("admin categories are refused", func(t *testing.T) {
+		service, _ := newService(0)
+
+		for _, category := range []string{domain.PassCategoryContractor, domain.PassCategoryService, "vip"} {
+			pass, err := service.CreatePass(ctx, newRequest(category, time.Hour))
+
+			assert.ErrorIs(t, err, ErrInvalidCategory, category)
+			assert.Nil(t, pass)
+		}
+	})
+}
+
+func TestPassService_ValidatePass_CategoryHours(t *testing.T) {
+	logger := zap.NewNop()
+	ctx := context.Background()
+
+	passRepo := new(MockPassRepo)
+	apartmentRepo := new(MockApartmentRepo)
+	ruleRepo := new(MockRuleRepo)
+	scanEventRepo := new(MockScanEventRepo)
+
+	service := NewPassService(passRepo, apartmentRepo, ruleRepo, scanEventRepo, nil, noWatchlist(), nil, time.UTC, logger)
+
+	// Deliveries are allowed for the hour that starts now, taxis only during
+	// the hour two hours ago.
+	local := time.Now().In(service.location)
+	hhmm := func(t time.Time) *string {
+		s := t.Format("15:04")
+		return &s
+	}
+	apartmentRepo.On("GetByID", ctx, int64(1)).Return(&domain.Apartment{ID: 1, BuildingID: 1, Number: "101"}, nil)
+	ruleRepo.On("GetByBuildingID", ctx, int64(1)).Return(&domain.Rule{
+		Categories: []domain.CategoryRule{
+			{Category: domain.PassCategoryDelivery, AllowedFrom: hhmm(local.Add(-time.Minute)), AllowedTo: hhmm(local.Add(time.Hour))},
+			{Category: domain.PassCategoryTaxi, AllowedFrom: hhmm(local.Add(-2 * time.Hour)), AllowedTo: hhmm(local.Add(-time.Hour))},
+		},
+	}, nil)
+	scanEventRepo.On("Create", ctx, mock.AnythingOfType("*domain.ScanEvent")).Return(nil)
+
+	tests := []struct {
+		category   string
+		wantValid  bool
+		wantReason string
+	}{
+		{category: domain.PassCategoryDelivery, wantValid: true},
+		{category: domain.PassCategoryTaxi, wantReason: "OUTSIDE_ALLOWED_HOURS"},
+		{category: domain.PassCategoryGuest, wantValid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.category, func(t *testing.T) {
+			passID := uuid.New()
+			passRepo.On("GetByID", ctx, passID).Return(&domain.Pass{
+				ID:          passID,
+				ApartmentID: 1,
+				Status:      "active",
+				Category:    tt.category,
+				ValidFrom:   time.Now().Add(-time.Hour),
+				ValidTo:     time.Now().Add(time.Hour),
+			}, nil)
+
+			result, err := service.ValidatePass(ctx, passID, 1)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantValid, result.Valid)
+			assert.Equal(t, tt.wantReason, result.Reason)
+		})
+	}
 }
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"yardpass/internal/domain"
+)
+
+const (
+	// maxPlateSuggestionEdits is how many typed edits away a plate may be
+	// to be suggested.
+	maxPlateSuggestionEdits = 2
+	// maxPlateSuggestions limits the suggestions of one scan.
+	maxPlateSuggestions = 5
+)
+
+// Edit costs in half edits, so that a lookalike swap can cost half of a
+// typo.
+const (
+	plateEditCost       = 2
+	plateLookalikeCost  = 1
+	maxPlateSuggestCost = maxPlateSuggestionEdits * plateEditCost
+)
+
+// plateLookalikes are the characters of normalized plates that cameras and
+// guards confuse: digits and letters drawn alike on the plate font.
+var plateLookalikes = [][2]byte{
+	{'0', 'O'}, {'0', 'D'}, {'O', 'D'}, {'0', 'Q'}, {'O', 'Q'},
+	{'8', 'B'}, {'3', 'B'}, {'6', 'B'},
+	{'1', 'I'}, {'1', 'T'}, {'7', 'T'},
+	{'5', 'S'}, {'2', 'Z'}, {'6', 'G'}, {'4', 'A'},
+	{'K', 'X'},
+}
+
+var plateLookalikeSet = func() map[[2]byte]bool {
+	set := make(map[[2]byte]bool, 2*len(plateLookalikes))
+	for _, pair := range plateLookalikes {
+		set[pair] = true
+		set[[2]byte{pair[1], pair[0]}] = true
+	}
+	return set
+}()
+
+// plateSubstitutionCost is the cost of reading b where a is written.
+func plateSubstitutionCost(a, b byte) int {
+	switch {
+	case a == b:
+		return 0
+	case plateLookalikeSet[[2]byte{a, b}]:
+		return plateLookalikeCost
+	default:
+		return plateEditCost
+	}
+}
+
+// plateDistance is the edit distance between two normalized plates in half
+// edits: insertions, deletions, substitutions and swaps of neighbouring
+// characters cost one edit, a lookalike substitution half of one.
+func plateDistance(a, b string) int {
+	// d[i][j] is the distance between a[:i] and b[:j].
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i * plateEditCost
+	}
+	for j := range d[0] {
+		d[0][j] = j * plateEditCost
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			d[i][j] = min(
+				d[i-1][j]+plateEditCost,
+				d[i][j-1]+plateEditCost,
+				d[i-1][j-1]+plateSubstitutionCost(a[i-1], b[j-1]),
+			)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+plateEditCost)
+			}
+		}
+	}
+
+	return d[len(a)][len(b)]
+}
+
+// SuggestPasses returns the active passes of the building (any building
+// when nil) whose plate is within maxPlateSuggestionEdits of the normalized
+// plate, closest first. An exact match is not a suggestion.
+func (s *PassService) SuggestPasses(ctx context.Context, normalizedCarPlate string, buildingID *int64) ([]*domain.PlateSuggestion, error) {
+	candidates, err := s.passRepo.ListActiveByCarPlateLength(ctx, buildingID,
+		len(normalizedCarPlate)-maxPlateSuggestionEdits,
+		len(normalizedCarPlate)+maxPlateSuggestionEdits,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list passes for plate suggestions: %w", err)
+	}
+
+	type scored struct {
+		pass *domain.Pass
+		cost int
+	}
+	var matches []scored
+	seen := make(map[string]bool)
+	for _, pass := range candidates {
+		plate := *pass.CarPlate
+		// Candidates are newest first; one suggestion per plate is enough.
+		if plate == normalizedCarPlate || seen[plate] {
+			continue
+		}
+		if cost := plateDistance(normalizedCarPlate, plate); cost <= maxPlateSuggestCost {
+			matches = append(matches, scored{pass: pass, cost: cost})
+			seen[plate] = true
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].cost < matches[j].cost
+	})
+	if len(matches) > maxPlateSuggestions {
+		matches = matches[:maxPlateSuggestions]
+	}
+
+	suggestions := make([]*domain.PlateSuggestion, 0, len(matches))
+	for _, match := range matches {
+		suggestion := &domain.PlateSuggestion{
+			PassID:    match.pass.ID,
+			CarPlate:  *match.pass.CarPlate,
+			GuestName: match.pass.GuestName,
+			ValidTo:   match.pass.ValidTo,
+			Distance:  float64(match.cost) / plateEditCost,
+		}
+		apartment, err := s.apartmentRepo.GetByID(ctx, match.pass.ApartmentID)
+		if err == nil && apartment != nil {
+			suggestion.Apartment = apartment.Number
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+
+	return suggestions, nil
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"yardpass/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestPlateDistance(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want int
+	}{
+		{"equal", "A123BC77", "A123BC77", 0},
+		{"missing region digit", "A123BC77", "A123BC777", 2},
+		{"zero for letter O", "A023BC77", "AO23BC77", 1},
+		{"eight for letter B", "A123BC77", "A1238C77", 1},
+		{"swapped neighbours", "A213BC77", "A123BC77", 2},
+		{"unrelated substitution", "A123BC77", "A123BM77", 2},
+		{"two typos", "A123BC77", "A153BC70", 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, plateDistance(tt.a, tt.b))
+			assert.Equal(t, tt.want, plateDistance(tt.b, tt.a))
+		})
+	}
+}
+
+func TestPassService_ValidatePassByCarPlate_Suggestions(t *testing.T) {
+	ctx := context.Background()
+	buildingID := int64(1)
+	now := time.Now()
+
+	newPass := func(carPlate string, apartmentID int64) *domain.Pass {
+		return &domain.Pass{
+			ID:          uuid.New(),
+			ApartmentID: apartmentID,
+			CarPlate:    &carPlate,
+			Status:      "active",
+			ValidFrom:   now.Add(-time.Hour),
+			ValidTo:     now.Add(time.Hour),
+		}
+	}
+	typo := newPass("A123BC777", 10)
+	lookalike := newPass("A123BC70", 11)
+	far := newPass("M999OP77", 12)
+
+	passRepo := new(MockPassRepo)
+	apartmentRepo := new(MockApartmentRepo)
+	vehicleRepo := new(MockResidentVehicleRepo)
+	service := NewPassService(passRepo, apartmentRepo, new(MockRuleRepo), new(MockScanEventRepo), vehicleRepo, noWatchlist(), nil, time.UTC, zap.NewNop())
+
+	vehicleRepo.On("GetByCarPlate", ctx, "A123BC7O", &buildingID).Return(nil, nil)
+	passRepo.On("GetActiveByCarPlate", ctx, "A123BC7O", &buildingID).Return(nil, nil)
+	passRepo.On("ListActiveByCarPlateLength", ctx, &buildingID, 6, 10).Return([]*domain.Pass{typo, lookalike, far}, nil)
+	apartmentRepo.On("GetByID", ctx, int64(10)).Return(&domain.Apartment{ID: 10, BuildingID: buildingID, Number: "42"}, nil)
+	apartmentRepo.On("GetByID", ctx, int64(11)).Return(&domain.Apartment{ID: 11, BuildingID: buildingID, Number: "7"}, nil)
+
+	result, err := service.ValidatePassByCarPlate(ctx, "A123BC7O", 3, &buildingID)
+	require.NoError(t, err)
+
+	// Suggestions are never admitted on their own.
+	assert.False(t, result.Valid)
+	assert.Equal(t, "PASS_NOT_FOUND", result.Reason)
+	require.Len(t, result.Suggestions, 2)
+
+	assert.Equal(t, lookalike.ID, result.Suggestions[0].PassID)
+	assert.Equal(t, "7", result.Suggestions[0].Apartment)
+	assert.Equal(t, 0.5, result.Suggestions[0].Distance)
+
+	assert.Equal(t, typo.ID, result.Suggestions[1].PassID)
+	assert.Equal(t, "42", result.Suggestions[1].Apartment)
+	assert.Equal(t, 2.0, result.Suggestions[1].Distance)
+}
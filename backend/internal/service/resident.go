@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"yardpass/internal/domain"
+	"yardpass/internal/redis"
 
 	"go.uber.org/zap"
 )
@@ -17,13 +18,15 @@ import (
 type ResidentService struct {
 	residentRepo  domain.ResidentRepository
 	apartmentRepo domain.ApartmentRepository
+	redis         *redis.Client
 	logger        *zap.Logger
 }
 
-func NewResidentService(residentRepo domain.ResidentRepository, apartmentRepo domain.ApartmentRepository, logger *zap.Logger) *ResidentService {
+func NewResidentService(residentRepo domain.ResidentRepository, apartmentRepo domain.ApartmentRepository, redisClient *redis.Client, logger *zap.Logger) *ResidentService {
 	return &ResidentService{
 		residentRepo:  residentRepo,
 		apartmentRepo: apartmentRepo,
+		redis:         redisClient,
 		logger:        logger,
 	}
 }
@@ -37,7 +40,7 @@ func (s *ResidentService) CreateResident(ctx context.Context, req domain.CreateR
 		return nil, errors.New("apartment not found")
 	}
 
-	existing, err := s.residentRepo.GetByTelegramID(ctx, req.TelegramID)
+	existing, err := s.residentRepo.GetByTelegramIDAndApartmentID(ctx, req.TelegramID, req.ApartmentID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check telegram_id: %w", err)
 	}
@@ -48,7 +51,6 @@ func (s *ResidentService) CreateResident(ctx context.Context, req domain.CreateR
 	}
 
 	if existing != nil {
-		existing.ApartmentID = req.ApartmentID
 		existing.ChatID = chatID
 		existing.Name = req.Name
 		existing.Phone = req.Phone
@@ -59,12 +61,14 @@ func (s *ResidentService) CreateResident(ctx context.Context, req domain.CreateR
 	}
 
 	resident := &domain.Resident{
-		ApartmentID: req.ApartmentID,
-		TelegramID:  req.TelegramID,
-		ChatID:      chatID,
-		Name:        req.Name,
-		Phone:       req.Phone,
-		Status:      "active",
+		ApartmentID:    req.ApartmentID,
+		TelegramID:     req.TelegramID,
+		ChatID:         chatID,
+		Name:           req.Name,
+		Phone:          req.Phone,
+		Status:         "active",
+		Role:           "primary",
+		CanIssuePasses: true,
 	}
 
 	if err := s.residentRepo.Create(ctx, resident); err != nil {
@@ -218,6 +222,30 @@ func (s *ResidentService) ListResidents(ctx context.Context, filters domain.Resi
 	return s.residentRepo.List(ctx, filters)
 }
 
+// residentLanguages are the bot languages a resident can choose.
+var residentLanguages = map[string]bool{"ru": true, "en": true}
+
+// SetLanguage stores the bot language of a Telegram user for all of their
+// apartments. A nil language resets it to the Telegram client language.
+func (s *ResidentService) SetLanguage(ctx context.Context, telegramID int64, language *string) error {
+	if language != nil && !residentLanguages[*language] {
+		return fmt.Errorf("unsupported language: %s", *language)
+	}
+
+	residents, err := s.residentRepo.ListByTelegramID(ctx, telegramID)
+	if err != nil {
+		return fmt.Errorf("failed to get residents: %w", err)
+	}
+	if len(residents) == 0 {
+		return errors.New("resident not found")
+	}
+
+	if err := s.residentRepo.SetLanguageByTelegramID(ctx, telegramID, language); err != nil {
+		return fmt.Errorf("failed to set language: %w", err)
+	}
+	return nil
+}
+
 func (s *ResidentService) DeleteResident(ctx context.Context, id int64) error {
 	resident, err := s.residentRepo.GetByID(ctx, id)
 	if err != nil {
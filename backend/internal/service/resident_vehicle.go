@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"yardpass/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// maxResidentVehicles limits the cars residents can register per
+	// apartment in the bot. Admins are not held to it.
+	maxResidentVehicles = 3
+	// maxVehicleLabelLength is the longest label in characters.
+	maxVehicleLabelLength = 100
+)
+
+var (
+	ErrVehicleNotFound      = errors.New("vehicle not found")
+	ErrVehicleExists        = errors.New("car plate is already registered in the building")
+	ErrTooManyVehicles      = fmt.Errorf("an apartment can register at most %d cars", maxResidentVehicles)
+	ErrVehicleLabelTooLong  = fmt.Errorf("label must be at most %d characters", maxVehicleLabelLength)
+	ErrVehicleOtherBuilding = errors.New("apartment is in another building")
+)
+
+// ResidentVehicleService keeps the registry of residents' own cars, which
+// guards admit by car plate without a pass.
+type ResidentVehicleService struct {
+	vehicleRepo   domain.ResidentVehicleRepository
+	apartmentRepo domain.ApartmentRepository
+	logger        *zap.Logger
+}
+
+func NewResidentVehicleService(
+	vehicleRepo domain.ResidentVehicleRepository,
+	apartmentRepo domain.ApartmentRepository,
+	logger *zap.Logger,
+) *ResidentVehicleService {
+	return &ResidentVehicleService{
+		vehicleRepo:   vehicleRepo,
+		apartmentRepo: apartmentRepo,
+		logger:        logger,
+	}
+}
+
+// AddVehicle registers a car of the apartment. buildingID limits it to an
+// admin's building. A plate is registered at most once per building.
+func (s *ResidentVehicleService) AddVehicle(ctx context.Context, req domain.CreateResidentVehicleRequest, buildingID *int64) (*domain.ResidentVehicle, error) {
+	parsed, err := ParseCarPlate(req.CarPlate)
+	if err != nil {
+		return nil, err
+	}
+	carPlate := parsed.Normalized
+
+	label := req.Label
+	if label != nil {
+		trimmed := strings.TrimSpace(*label)
+		if utf8.RuneCountInString(trimmed) > maxVehicleLabelLength {
+			return nil, ErrVehicleLabelTooLong
+		}
+		label = &trimmed
+		if trimmed == "" {
+			label = nil
+		}
+	}
+
+	apartment, err := s.apartmentRepo.GetByID(ctx, req.ApartmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get apartment: %w", err)
+	}
+	if apartment == nil {
+		return nil, ErrApartmentNotFound
+	}
+	if buildingID != nil && apartment.BuildingID != *buildingID {
+		return nil, ErrVehicleOtherBuilding
+	}
+
+	existing, err := s.vehicleRepo.GetByCarPlate(ctx, carPlate, &apartment.BuildingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check car plate: %w", err)
+	}
+	if existing != nil {
+		return nil, ErrVehicleExists
+	}
+
+	if req.ResidentID != nil {
+		count, err := s.vehicleRepo.CountByApartmentID(ctx, apartment.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count vehicles: %w", err)
+		}
+		if count >= maxResidentVehicles {
+			return nil, ErrTooManyVehicles
+		}
+	}
+
+	vehicle := &domain.ResidentVehicle{
+		ApartmentID: apartment.ID,
+		BuildingID:  apartment.BuildingID,
+		CarPlate:    carPlate,
+		Label:       label,
+		ResidentID:  req.ResidentID,
+		CreatedBy:   req.CreatedBy,
+	}
+	if err := s.vehicleRepo.Create(ctx, vehicle); err != nil {
+		return nil, fmt.Errorf("failed to create vehicle: %w", err)
+	}
+
+	s.logger.Info("resident vehicle registered",
+		zap.Int64("vehicle_id", vehicle.ID),
+		zap.Int64("apartment_id", vehicle.ApartmentID),
+		zap.String("car_plate", vehicle.CarPlate),
+	)
+
+	return vehicle, nil
+}
+
+// ListVehicles returns the cars of a building (all buildings when nil),
+// optionally of one apartment.
+func (s *ResidentVehicleService) ListVehicles(ctx context.Context, buildingID *int64, apartmentID *int64) ([]*domain.ResidentVehicle, error) {
+	vehicles, err := s.vehicleRepo.List(ctx, buildingID, apartmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vehicles: %w", err)
+	}
+	return vehicles, nil
+}
+
+// GetVehicle returns the car. buildingID limits it to an admin's building;
+// cars of other buildings are reported as not found.
+func (s *ResidentVehicleService) GetVehicle(ctx context.Context, id int64, buildingID *int64) (*domain.ResidentVehicle, error) {
+	vehicle, err := s.vehicleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vehicle: %w", err)
+	}
+	if vehicle == nil || (buildingID != nil && vehicle.BuildingID != *buildingID) {
+		return nil, ErrVehicleNotFound
+	}
+	return vehicle, nil
+}
+
+// RemoveVehicle deletes a car of the building, any building when nil.
+func (s *ResidentVehicleService) RemoveVehicle(ctx context.Context, id int64, buildingID *int64) error {
+	vehicle, err := s.GetVehicle(ctx, id, buildingID)
+	if err != nil {
+		return err
+	}
+	return s.remove(ctx, vehicle)
+}
+
+// RemoveApartmentVehicle deletes a car of the apartment; residents remove
+// the cars of their own apartment only.
+func (s *ResidentVehicleService) RemoveApartmentVehicle(ctx context.Context, apartmentID, id int64) error {
+	vehicle, err := s.GetVehicle(ctx, id, nil)
+	if err != nil {
+		return err
+	}
+	if vehicle.ApartmentID != apartmentID {
+		return ErrVehicleNotFound
+	}
+	return s.remove(ctx, vehicle)
+}
+
+func (s *ResidentVehicleService) remove(ctx context.Context, vehicle *domain.ResidentVehicle) error {
+	if err := s.vehicleRepo.Delete(ctx, vehicle.ID); err != nil {
+		return fmt.Errorf("failed to delete vehicle: %w", err)
+	}
+
+	s.logger.Info("resident vehicle removed",
+		zap.Int64("vehicle_id", vehicle.ID),
+		zap.Int64("apartment_id", vehicle.ApartmentID),
+	)
+	return nil
+}
+
+// CountVehicles returns how many resident cars the building has.
+func (s *ResidentVehicleService) CountVehicles(ctx context.Context, buildingID int64) (int, error) {
+	count, err := s.vehicleRepo.CountByBuildingID(ctx, buildingID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count vehicles: %w", err)
+	}
+	return count, nil
+}
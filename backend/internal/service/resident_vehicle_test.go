@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"yardpass/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type MockResidentVehicleRepo struct {
+	mock.Mock
+}
+
+func (m *MockResidentVehicleRepo) Create(ctx context.Context, vehicle *domain.ResidentVehicle) error {
+	args := m.Called(ctx, vehicle)
+	return args.Error(0)
+}
+
+func (m *MockResidentVehicleRepo) GetByID(ctx context.Context, id int64) (*domain.ResidentVehicle, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ResidentVehicle), args.Error(1)
+}
+
+func (m *MockResidentVehicleRepo) GetByCarPlate(ctx context.Context, normalizedCarPlate string, buildingID *int64) (*domain.ResidentVehicle, error) {
+	args := m.Called(ctx, normalizedCarPlate, buildingID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ResidentVehicle), args.Error(1)
+}
+
+func (m *MockResidentVehicleRepo) List(ctx context.Context, buildingID *int64, apartmentID *int64) ([]*domain.ResidentVehicle, error) {
+	args := m.Called(ctx, buildingID, apartmentID)
+	return args.Get(0).([]*domain.ResidentVehicle), args.Error(1)
+}
+
+func (m *MockResidentVehicleRepo) CountByApartmentID(ctx context.Context, apartmentID int64) (int, error) {
+	args := m.Called(ctx, apartmentID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockResidentVehicleRepo) CountByBuildingID(ctx context.Context, buildingID int64) (int, error) {
+	args := m.Called(ctx, buildingID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockResidentVehicleRepo) Delete(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func TestResidentVehicleService_AddVehicle(t *testing.T) {
+	ctx := context.Background()
+	buildingID := int64(1)
+	otherBuilding := int64(2)
+	residentID := int64(100)
+	adminID := int64(7)
+
+	newService := func() (*ResidentVehicleService, *MockResidentVehicleRepo) {
+		vehicleRepo := new(MockResidentVehicleRepo)
+		apartmentRepo := new(MockApartmentRepo)
+		apartmentRepo.On("GetByID", ctx, int64(10)).Return(&domain.Apartment{ID: 10, BuildingID: buildingID, Number: "42"}, nil)
+		apartmentRepo.On("GetByID", ctx, int64(11)).Return(nil, nil)
+		return NewResidentVehicleService(vehicleRepo, apartmentRepo, zap.NewNop()), vehicleRepo
+	}
+
+	t.Run("normalizes the plate and trims the label", func(t *testing.T) {
+		service, vehicleRepo := newService()
+		label := "  белая Kia "
+		vehicleRepo.On("GetByCarPlate", ctx, "A123BC77", &buildingID).Return(nil, nil)
+		vehicleRepo.On("CountByApartmentID", ctx, int64(10)).Return(2, nil)
+		vehicleRepo.On("Create", ctx, mock.AnythingOfType("*domain.ResidentVehicle")).Return(nil)
+
+		vehicle, err := service.AddVehicle(ctx, domain.CreateResidentVehicleRequest{
+			ApartmentID: 10,
+			CarPlate:    "а 123 вс 77",
+			Label:       &label,
+			ResidentID:  &residentID,
+		}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "A123BC77", vehicle.CarPlate)
+		assert.Equal(t, "белая Kia", *vehicle.Label)
+		assert.Equal(t, buildingID, vehicle.BuildingID)
+	})
+
+	t.Run("residents are capped per apartment", func(t *testing.T) {
+		service, vehicleRepo := newService()
+		vehicleRepo.On("GetByCarPlate", ctx, "A123BC77", &buildingID).Return(nil, nil)
+		vehicleRepo.On("CountByApartmentID", ctx, int64(10)).Return(maxResidentVehicles, nil)
+
+		_, err := service.AddVehicle(ctx, domain.CreateResidentVehicleRequest{ApartmentID: 10, CarPlate: "A123BC77", ResidentID: &residentID}, nil)
+		assert.ErrorIs(t, err, ErrTooManyVehicles)
+		vehicleRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("admins are not capped", func(t *testing.T) {
+		service, vehicleRepo := newService()
+		vehicleRepo.On("GetByCarPlate", ctx, "A123BC77", &buildingID).Return(nil, nil)
+		vehicleRepo.On("Create", ctx, mock.AnythingOfType("*domain.ResidentVehicle")).Return(nil)
+
+		vehicle, err := service.AddVehicle(ctx, domain.CreateResidentVehicleRequest{ApartmentID: 10, CarPlate: "A123BC77", CreatedBy: &adminID}, &buildingID)
+		require.NoError(t, err)
+		assert.Equal(t, &adminID, vehicle.CreatedBy)
+		vehicleRepo.AssertNotCalled(t, "CountByApartmentID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("plate is registered once per building", func(t *testing.T) {
+		service, vehicleRepo := newService()
+		vehicleRepo.On("GetByCarPlate", ctx, "A123BC77", &buildingID).Return(&domain.ResidentVehicle{ID: 5, ApartmentID: 12}, nil)
+
+		_, err := service.AddVehicle(ctx, domain.CreateResidentVehicleRequest{ApartmentID: 10, CarPlate: "A123BC77", CreatedBy: &adminID}, nil)
+		assert.ErrorIs(t, err, ErrVehicleExists)
+	})
+
+	t.Run("rejects apartments of another building", func(t *testing.T) {
+		service, _ := newService()
+
+		_, err := service.AddVehicle(ctx, domain.CreateResidentVehicleRequest{ApartmentID: 10, CarPlate: "A123BC77", CreatedBy: &adminID}, &otherBuilding)
+		assert.ErrorIs(t, err, ErrVehicleOtherBuilding)
+	})
+
+	t.Run("rejects unknown apartments and bad plates", func(t *testing.T) {
+		service, _ := newService()
+
+		_, err := service.AddVehicle(ctx, domain.CreateResidentVehicleRequest{ApartmentID: 11, CarPlate: "A123BC77", CreatedBy: &adminID}, nil)
+		assert.ErrorIs(t, err, ErrApartmentNotFound)
+
+		_, err = service.AddVehicle(ctx, domain.CreateResidentVehicleRequest{ApartmentID: 10, CarPlate: " - ", CreatedBy: &adminID}, nil)
+		assert.ErrorIs(t, err, ErrInvalidCarPlate)
+
+		_, err = service.AddVehicle(ctx, domain.CreateResidentVehicleRequest{ApartmentID: 10, CarPlate: "A123BC", CreatedBy: &adminID}, nil)
+		assert.ErrorIs(t, err, ErrInvalidCarPlate)
+	})
+}
+
+func TestResidentVehicleService_RemoveApartmentVehicle(t *testing.T) {
+	ctx := context.Background()
+	vehicleRepo := new(MockResidentVehicleRepo)
+	service := NewResidentVehicleService(vehicleRepo, new(MockApartmentRepo), zap.NewNop())
+
+	vehicleRepo.On("GetByID", ctx, int64(5)).Return(&domain.ResidentVehicle{ID: 5, ApartmentID: 10, BuildingID: 1}, nil)
+	vehicleRepo.On("Delete", ctx, int64(5)).Return(nil)
+
+	// Residents cannot remove the cars of other apartments.
+	assert.ErrorIs(t, service.RemoveApartmentVehicle(ctx, 11, 5), ErrVehicleNotFound)
+	vehicleRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+
+	assert.NoError(t, service.RemoveApartmentVehicle(ctx, 10, 5))
+	vehicleRepo.AssertCalled(t, "Delete", ctx, int64(5))
+}
+
+func TestPassService_ValidatePassByCarPlate_ResidentVehicle(t *testing.T) {
+	ctx := context.Background()
+	buildingID := int64(1)
+	label := "белая Kia"
+
+	passRepo := new(MockPassRepo)
+	apartmentRepo := new(MockApartmentRepo)
+	scanEventRepo := new(MockScanEventRepo)
+	vehicleRepo := new(MockResidentVehicleRepo)
+	service := NewPassService(passRepo, apartmentRepo, new(MockRuleRepo), scanEventRepo, vehicleRepo, noWatchlist(), nil, time.UTC, zap.NewNop())
+
+	vehicle := &domain.ResidentVehicle{ID: 5, ApartmentID: 10, BuildingID: buildingID, CarPlate: "A123BC77", Label: &label}
+	vehicleRepo.On("GetByCarPlate", ctx, "A123BC77", &buildingID).Return(vehicle, nil)
+	apartmentRepo.On("GetByID", ctx, int64(10)).Return(&domain.Apartment{ID: 10, BuildingID: buildingID, Number: "42"}, nil)
+	scanEventRepo.On("Create", ctx, mock.MatchedBy(func(e *domain.ScanEvent) bool {
+		return e.PassID == nil && *e.ApartmentID == 10 && e.Result == "valid" && *e.Reason == "RESIDENT_VEHICLE" &&
+			assert.JSONEq(t, `{"source":"resident_vehicle","vehicle_id":5,"car_plate":"A123BC77"}`, *e.Meta)
+	})).Return(nil)
+
+	result, err := service.ValidatePassByCarPlate(ctx, "а123вс77", 3, &buildingID)
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, "RESIDENT_VEHICLE", result.Reason)
+	assert.Equal(t, vehicle, result.Vehicle)
+	assert.Equal(t, "42", result.Apartment)
+	assert.Nil(t, result.ValidTo)
+
+	// The registry is checked first, so no guest pass is looked up.
+	passRepo.AssertNotCalled(t, "GetActiveByCarPlate", mock.Anything, mock.Anything, mock.Anything)
+	scanEventRepo.AssertExpectations(t)
+}
@@ -2,24 +2,54 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base32"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"yardpass/internal/auth"
 	"yardpass/internal/domain"
+	"yardpass/internal/redis"
+
 	"go.uber.org/zap"
 )
 
+const (
+	telegramLinkCodeTTL = 10 * time.Minute
+	// telegramLinkCodeBytes is the entropy of a link code; 5 bytes make an
+	// 8-character base32 code.
+	telegramLinkCodeBytes = 5
+	// telegramLinkMaxFailures wrong codes lock /link for the Telegram
+	// account for telegramLinkLockout, so codes cannot be guessed.
+	telegramLinkMaxFailures = 5
+	telegramLinkLockout     = time.Hour
+)
+
+var (
+	ErrTelegramLinkLocked    = errors.New("too many wrong link codes, try again later")
+	ErrTelegramAlreadyLinked = errors.New("this Telegram account is linked to another staff user, ask an admin to unlink it first")
+	ErrStaffUserNotFound     = errors.New("user not found")
+)
+
+// linkCodeEncoding writes link codes in upper case letters and digits 2-7,
+// which are easy to type and read out.
+var linkCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
 type UserService struct {
 	userRepo     domain.UserRepository
 	buildingRepo domain.BuildingRepository
+	redis        *redis.Client
 	logger       *zap.Logger
 }
 
-func NewUserService(userRepo domain.UserRepository, buildingRepo domain.BuildingRepository, logger *zap.Logger) *UserService {
+func NewUserService(userRepo domain.UserRepository, buildingRepo domain.BuildingRepository, redisClient *redis.Client, logger *zap.Logger) *UserService {
 	return &UserService{
 		userRepo:     userRepo,
 		buildingRepo: buildingRepo,
+		redis:        redisClient,
 		logger:       logger,
 	}
 }
@@ -100,3 +130,173 @@ func (s *UserService) ListUsers(ctx context.Context, filters domain.UserFilters)
 	return s.userRepo.List(ctx, filters)
 }
 
+// IssueTelegramLinkCode creates a short-lived one-time code that a staff user
+// sends to the bot (/link <code>) to bind their Telegram account.
+func (s *UserService) IssueTelegramLinkCode(ctx context.Context, userID int64) (string, time.Duration, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return "", 0, errors.New("user not found")
+	}
+	if user.Status != "active" {
+		return "", 0, errors.New("user account is inactive")
+	}
+
+	buf := make([]byte, telegramLinkCodeBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", 0, fmt.Errorf("failed to generate code: %w", err)
+	}
+	code := linkCodeEncoding.EncodeToString(buf)
+
+	if err := s.redis.Set(ctx, telegramLinkKey(code), userID, telegramLinkCodeTTL); err != nil {
+		return "", 0, fmt.Errorf("failed to store link code: %w", err)
+	}
+
+	return code, telegramLinkCodeTTL, nil
+}
+
+// LinkTelegram consumes a link code and binds telegramID to the staff user
+// that requested it. A Telegram account linked to another staff user is
+// refused until an admin unlinks it. After telegramLinkMaxFailures wrong
+// codes the Telegram account is locked out for telegramLinkLockout.
+func (s *UserService) LinkTelegram(ctx context.Context, code string, telegramID int64) (*domain.User, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return nil, errors.New("link code is required")
+	}
+
+	failures, err := s.redis.Get(ctx, telegramLinkFailuresKey(telegramID))
+	if err != nil && !redis.IsNil(err) {
+		return nil, fmt.Errorf("failed to check link attempts: %w", err)
+	}
+	if n, _ := strconv.Atoi(failures); n >= telegramLinkMaxFailures {
+		return nil, ErrTelegramLinkLocked
+	}
+
+	existing, err := s.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check telegram_id: %w", err)
+	}
+
+	userIDStr, err := s.redis.GetDel(ctx, telegramLinkKey(code))
+	if err != nil {
+		if redis.IsNil(err) {
+			return nil, s.failLinkAttempt(ctx, telegramID)
+		}
+		return nil, fmt.Errorf("failed to check link code: %w", err)
+	}
+
+	userID, err := strconv.ParseInt(userIDStr, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid link code payload: %w", err)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil || user.Status != "active" {
+		return nil, errors.New("user not found or inactive")
+	}
+
+	if existing != nil && existing.ID != user.ID {
+		return nil, ErrTelegramAlreadyLinked
+	}
+
+	user.TelegramID = &telegramID
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to link telegram: %w", err)
+	}
+
+	if err := s.redis.Delete(ctx, telegramLinkFailuresKey(telegramID)); err != nil {
+		s.logger.Warn("failed to reset link attempts", zap.Error(err), zap.Int64("telegram_id", telegramID))
+	}
+
+	s.logger.Info("telegram linked to user",
+		zap.Int64("user_id", user.ID),
+		zap.Int64("telegram_id", telegramID),
+	)
+
+	return user, nil
+}
+
+// failLinkAttempt counts a wrong link code of the Telegram account and
+// returns the error to report.
+func (s *UserService) failLinkAttempt(ctx context.Context, telegramID int64) error {
+	allowed, err := s.redis.CheckRateLimit(ctx, telegramLinkFailuresKey(telegramID), telegramLinkMaxFailures, telegramLinkLockout)
+	if err != nil {
+		return fmt.Errorf("failed to count link attempts: %w", err)
+	}
+
+	s.logger.Warn("wrong telegram link code", zap.Int64("telegram_id", telegramID))
+	if !allowed {
+		return ErrTelegramLinkLocked
+	}
+	return errors.New("link code is invalid or expired")
+}
+
+// UnlinkStaffTelegram drops the Telegram link of a staff user of the
+// building, any building when nil, so that the Telegram account can be
+// linked to another user.
+func (s *UserService) UnlinkStaffTelegram(ctx context.Context, userID int64, buildingID *int64) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil || (buildingID != nil && (user.BuildingID == nil || *user.BuildingID != *buildingID)) {
+		return ErrStaffUserNotFound
+	}
+
+	if err := s.UnlinkTelegram(ctx, user.ID); err != nil {
+		return err
+	}
+
+	s.logger.Info("telegram unlinked from user", zap.Int64("user_id", user.ID))
+	return nil
+}
+
+func (s *UserService) UnlinkTelegram(ctx context.Context, userID int64) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return errors.New("user not found")
+	}
+	if user.TelegramID == nil {
+		return nil
+	}
+
+	user.TelegramID = nil
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return fmt.Errorf("failed to unlink telegram: %w", err)
+	}
+
+	return nil
+}
+
+// GetStaffByTelegramID returns the active guard/admin linked to telegramID,
+// or nil if the Telegram account is not linked to staff.
+func (s *UserService) GetStaffByTelegramID(ctx context.Context, telegramID int64) (*domain.User, error) {
+	user, err := s.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || user.Status != "active" {
+		return nil, nil
+	}
+	if user.Role != "guard" && user.Role != "admin" && user.Role != "superuser" {
+		return nil, nil
+	}
+	return user, nil
+}
+
+func telegramLinkKey(code string) string {
+	return "telegram_link:" + code
+}
+
+func telegramLinkFailuresKey(telegramID int64) string {
+	return "telegram_link_failures:" + strconv.FormatInt(telegramID, 10)
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"yardpass/internal/config"
+	"yardpass/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// VisitStartPrefix starts the /start parameter of a guest visit link.
+const VisitStartPrefix = "visit_"
+
+// visitTokenBytes is the entropy of a visit token; 12 bytes make a
+// 16-character URL-safe token that fits a Telegram start parameter.
+const visitTokenBytes = 12
+
+var ErrBuildingNotFound = errors.New("building not found")
+
+// VisitLinkService hands out the bot links guests open to request a pass.
+// Links carry a random token so that buildings cannot be enumerated.
+type VisitLinkService struct {
+	buildingRepo domain.BuildingRepository
+	botUsername  string
+	logger       *zap.Logger
+}
+
+func NewVisitLinkService(cfg *config.Config, buildingRepo domain.BuildingRepository, logger *zap.Logger) *VisitLinkService {
+	return &VisitLinkService{
+		buildingRepo: buildingRepo,
+		botUsername:  cfg.Telegram.BotUsername,
+		logger:       logger,
+	}
+}
+
+// VisitLink returns the building's link, creating its token on first use.
+func (s *VisitLinkService) VisitLink(ctx context.Context, buildingID int64) (*domain.VisitLink, error) {
+	building, err := s.building(ctx, buildingID)
+	if err != nil {
+		return nil, err
+	}
+	if building.VisitToken != nil {
+		return s.link(*building.VisitToken), nil
+	}
+
+	token, err := newVisitToken()
+	if err != nil {
+		return nil, err
+	}
+	token, err = s.buildingRepo.SetVisitToken(ctx, buildingID, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create visit link: %w", err)
+	}
+
+	return s.link(token), nil
+}
+
+// RotateVisitLink replaces the building's token; links handed out before
+// stop working.
+func (s *VisitLinkService) RotateVisitLink(ctx context.Context, buildingID int64) (*domain.VisitLink, error) {
+	if _, err := s.building(ctx, buildingID); err != nil {
+		return nil, err
+	}
+
+	token, err := newVisitToken()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.buildingRepo.ReplaceVisitToken(ctx, buildingID, token); err != nil {
+		return nil, fmt.Errorf("failed to rotate visit link: %w", err)
+	}
+
+	s.logger.Info("visit link rotated", zap.Int64("building_id", buildingID))
+	return s.link(token), nil
+}
+
+func (s *VisitLinkService) building(ctx context.Context, buildingID int64) (*domain.Building, error) {
+	building, err := s.buildingRepo.GetByID(ctx, buildingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get building: %w", err)
+	}
+	if building == nil {
+		return nil, ErrBuildingNotFound
+	}
+	return building, nil
+}
+
+func (s *VisitLinkService) link(token string) *domain.VisitLink {
+	link := &domain.VisitLink{StartParameter: VisitStartPrefix + token}
+	if s.botUsername != "" {
+		link.URL = fmt.Sprintf("https://t.me/%s?start=%s", s.botUsername, link.StartParameter)
+	}
+	return link
+}
+
+func newVisitToken() (string, error) {
+	buf := make([]byte, visitTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate visit token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"yardpass/internal/config"
+	"yardpass/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newVisitLinkService(buildingRepo *MockBuildingRepo, botUsername string) *VisitLinkService {
+	cfg := &config.Config{Telegram: config.TelegramConfig{BotUsername: botUsername}}
+	return NewVisitLinkService(cfg, buildingRepo, zap.NewNop())
+}
+
+func TestVisitLinkService_VisitLink(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("creates a random token on first use", func(t *testing.T) {
+		buildingRepo := new(MockBuildingRepo)
+		buildingRepo.On("GetByID", ctx, int64(1)).Return(&domain.Building{ID: 1}, nil)
+		var generated string
+		buildingRepo.On("SetVisitToken", ctx, int64(1), mock.AnythingOfType("string")).
+			Run(func(args mock.Arguments) { generated = args.String(2) }).
+			Return("hV3kq9Zt2WcXbN4p", nil)
+
+		link, err := newVisitLinkService(buildingRepo, "yardpass_bot").VisitLink(ctx, 1)
+		require.NoError(t, err)
+		assert.Len(t, generated, 16)
+		// The token in effect wins if another request created one first.
+		assert.Equal(t, "visit_hV3kq9Zt2WcXbN4p", link.StartParameter)
+		assert.Equal(t, "https://t.me/yardpass_bot?start=visit_hV3kq9Zt2WcXbN4p", link.URL)
+	})
+
+	t.Run("keeps an existing token", func(t *testing.T) {
+		token := "hV3kq9Zt2WcXbN4p"
+		buildingRepo := new(MockBuildingRepo)
+		buildingRepo.On("GetByID", ctx, int64(1)).Return(&domain.Building{ID: 1, VisitToken: &token}, nil)
+
+		link, err := newVisitLinkService(buildingRepo, "").VisitLink(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "visit_"+token, link.StartParameter)
+		assert.Empty(t, link.URL)
+		buildingRepo.AssertNotCalled(t, "SetVisitToken", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown building", func(t *testing.T) {
+		buildingRepo := new(MockBuildingRepo)
+		buildingRepo.On("GetByID", ctx, int64(9)).Return(nil, nil)
+
+		_, err := newVisitLinkService(buildingRepo, "").VisitLink(ctx, 9)
+		assert.ErrorIs(t, err, ErrBuildingNotFound)
+	})
+}
+
+func TestVisitLinkService_RotateVisitLink(t *testing.T) {
+	ctx := context.Background()
+	token := "hV3kq9Zt2WcXbN4p"
+	buildingRepo := new(MockBuildingRepo)
+	buildingRepo.On("GetByID", ctx, int64(1)).Return(&domain.Building{ID: 1, VisitToken: &token}, nil)
+	var replaced string
+	buildingRepo.On("ReplaceVisitToken", ctx, int64(1), mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { replaced = args.String(2) }).
+		Return(nil)
+
+	link, err := newVisitLinkService(buildingRepo, "").RotateVisitLink(ctx, 1)
+	require.NoError(t, err)
+	assert.NotEqual(t, token, replaced)
+	assert.Equal(t, "visit_"+replaced, link.StartParameter)
+}
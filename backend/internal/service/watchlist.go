@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"yardpass/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// maxWatchlistReasonLength is the longest reason in characters.
+const maxWatchlistReasonLength = 500
+
+var (
+	ErrWatchlistEntryNotFound   = errors.New("watchlist entry not found")
+	ErrWatchlistEntryExists     = errors.New("car plate is already on the watchlist of the building")
+	ErrWatchlistReasonRequired  = errors.New("reason is required")
+	ErrWatchlistReasonTooLong   = fmt.Errorf("reason must be at most %d characters", maxWatchlistReasonLength)
+	ErrInvalidWatchlistSeverity = errors.New("severity must be watch or ban")
+)
+
+// WatchlistService keeps the per-building list of plates management bans
+// or watches for, and hands the bot the sightings to send to the admins.
+// The gate checks themselves are made by PassService.
+type WatchlistService struct {
+	watchlistRepo domain.PlateWatchlistRepository
+	userRepo      domain.UserRepository
+	logger        *zap.Logger
+}
+
+func NewWatchlistService(
+	watchlistRepo domain.PlateWatchlistRepository,
+	userRepo domain.UserRepository,
+	logger *zap.Logger,
+) *WatchlistService {
+	return &WatchlistService{
+		watchlistRepo: watchlistRepo,
+		userRepo:      userRepo,
+		logger:        logger,
+	}
+}
+
+// AddEntry lists a plate in the building, banned unless the severity says
+// otherwise. A plate is listed at most once per building.
+func (s *WatchlistService) AddEntry(ctx context.Context, req domain.CreateWatchlistEntryRequest) (*domain.WatchlistEntry, error) {
+	parsed, err := ParseCarPlate(req.CarPlate)
+	if err != nil {
+		return nil, err
+	}
+	carPlate := parsed.Normalized
+
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, ErrWatchlistReasonRequired
+	}
+	if utf8.RuneCountInString(reason) > maxWatchlistReasonLength {
+		return nil, ErrWatchlistReasonTooLong
+	}
+
+	severity := req.Severity
+	if severity == "" {
+		severity = domain.WatchlistSeverityBan
+	}
+	if severity != domain.WatchlistSeverityWatch && severity != domain.WatchlistSeverityBan {
+		return nil, ErrInvalidWatchlistSeverity
+	}
+
+	existing, err := s.watchlistRepo.GetByCarPlate(ctx, carPlate, &req.BuildingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check car plate: %w", err)
+	}
+	if existing != nil {
+		return nil, ErrWatchlistEntryExists
+	}
+
+	entry := &domain.WatchlistEntry{
+		BuildingID: req.BuildingID,
+		CarPlate:   carPlate,
+		Reason:     reason,
+		Severity:   severity,
+		CreatedBy:  req.CreatedBy,
+	}
+	if err := s.watchlistRepo.Create(ctx, entry); err != nil {
+		return nil, fmt.Errorf("failed to create watchlist entry: %w", err)
+	}
+
+	s.logger.Info("car plate added to watchlist",
+		zap.Int64("entry_id", entry.ID),
+		zap.Int64("building_id", entry.BuildingID),
+		zap.String("car_plate", entry.CarPlate),
+		zap.String("severity", entry.Severity),
+	)
+
+	return entry, nil
+}
+
+// ListEntries returns the watchlist of a building, all buildings when nil.
+func (s *WatchlistService) ListEntries(ctx context.Context, buildingID *int64) ([]*domain.WatchlistEntry, error) {
+	entries, err := s.watchlistRepo.List(ctx, buildingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list watchlist: %w", err)
+	}
+	return entries, nil
+}
+
+// RemoveEntry takes a plate off the watchlist. buildingID limits it to an
+// admin's building; entries of other buildings are reported as not found.
+func (s *WatchlistService) RemoveEntry(ctx context.Context, id int64, buildingID *int64) error {
+	entry, err := s.watchlistRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get watchlist entry: %w", err)
+	}
+	if entry == nil || (buildingID != nil && entry.BuildingID != *buildingID) {
+		return ErrWatchlistEntryNotFound
+	}
+
+	if err := s.watchlistRepo.Delete(ctx, entry.ID); err != nil {
+		return fmt.Errorf("failed to delete watchlist entry: %w", err)
+	}
+
+	s.logger.Info("car plate removed from watchlist",
+		zap.Int64("entry_id", entry.ID),
+		zap.Int64("building_id", entry.BuildingID),
+		zap.String("car_plate", entry.CarPlate),
+	)
+	return nil
+}
+
+// ClaimAlerts returns sightings of listed plates the caller is now
+// responsible for sending to the building admins.
+func (s *WatchlistService) ClaimAlerts(ctx context.Context, limit int) ([]*domain.PlateAlert, error) {
+	alerts, err := s.watchlistRepo.ClaimUnnotifiedAlerts(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim plate alerts: %w", err)
+	}
+	return alerts, nil
+}
+
+// Admins returns the active admins of the building who linked Telegram.
+func (s *WatchlistService) Admins(ctx context.Context, buildingID int64) ([]*domain.User, error) {
+	return buildingAdmins(ctx, s.userRepo, buildingID)
+}
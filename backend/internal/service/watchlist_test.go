@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"yardpass/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type MockPlateWatchlistRepo struct {
+	mock.Mock
+}
+
+func (m *MockPlateWatchlistRepo) Create(ctx context.Context, entry *domain.WatchlistEntry) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func (m *MockPlateWatchlistRepo) GetByID(ctx context.Context, id int64) (*domain.WatchlistEntry, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WatchlistEntry), args.Error(1)
+}
+
+func (m *MockPlateWatchlistRepo) GetByCarPlate(ctx context.Context, normalizedCarPlate string, buildingID *int64) (*domain.WatchlistEntry, error) {
+	args := m.Called(ctx, normalizedCarPlate, buildingID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WatchlistEntry), args.Error(1)
+}
+
+func (m *MockPlateWatchlistRepo) List(ctx context.Context, buildingID *int64) ([]*domain.WatchlistEntry, error) {
+	args := m.Called(ctx, buildingID)
+	return args.Get(0).([]*domain.WatchlistEntry), args.Error(1)
+}
+
+func (m *MockPlateWatchlistRepo) Delete(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockPlateWatchlistRepo) CreateAlert(ctx context.Context, alert *domain.PlateAlert) error {
+	args := m.Called(ctx, alert)
+	return args.Error(0)
+}
+
+func (m *MockPlateWatchlistRepo) ClaimUnnotifiedAlerts(ctx context.Context, limit int) ([]*domain.PlateAlert, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]*domain.PlateAlert), args.Error(1)
+}
+
+// noWatchlist is a watchlist with no plates on it.
+func noWatchlist() *MockPlateWatchlistRepo {
+	watchlistRepo := new(MockPlateWatchlistRepo)
+	watchlistRepo.On("GetByCarPlate", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	return watchlistRepo
+}
+
+func TestWatchlistService_AddEntry(t *testing.T) {
+	ctx := context.Background()
+	buildingID := int64(1)
+
+	t.Run("normalizes the plate and bans by default", func(t *testing.T) {
+		watchlistRepo := new(MockPlateWatchlistRepo)
+		service := NewWatchlistService(watchlistRepo, new(MockUserRepo), zap.NewNop())
+		watchlistRepo.On("GetByCarPlate", ctx, "A123BC77", &buildingID).Return(nil, nil)
+		watchlistRepo.On("Create", ctx, mock.AnythingOfType("*domain.WatchlistEntry")).Return(nil)
+
+		entry, err := service.AddEntry(ctx, domain.CreateWatchlistEntryRequest{
+			BuildingID: buildingID,
+			CarPlate:   "а 123 вс 77",
+			Reason:     "  долг за парковку ",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "A123BC77", entry.CarPlate)
+		assert.Equal(t, "долг за парковку", entry.Reason)
+		assert.Equal(t, domain.WatchlistSeverityBan, entry.Severity)
+	})
+
+	t.Run("plate is listed once per building", func(t *testing.T) {
+		watchlistRepo := new(MockPlateWatchlistRepo)
+		service := NewWatchlistService(watchlistRepo, new(MockUserRepo), zap.NewNop())
+		watchlistRepo.On("GetByCarPlate", ctx, "A123BC77", &buildingID).Return(&domain.WatchlistEntry{ID: 3}, nil)
+
+		_, err := service.AddEntry(ctx, domain.CreateWatchlistEntryRequest{BuildingID: buildingID, CarPlate: "A123BC77", Reason: "ДТП"})
+		assert.ErrorIs(t, err, ErrWatchlistEntryExists)
+		watchlistRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects bad input", func(t *testing.T) {
+		service := NewWatchlistService(new(MockPlateWatchlistRepo), new(MockUserRepo), zap.NewNop())
+
+		_, err := service.AddEntry(ctx, domain.CreateWatchlistEntryRequest{BuildingID: buildingID, CarPlate: " - ", Reason: "ДТП"})
+		assert.ErrorIs(t, err, ErrInvalidCarPlate)
+
+		_, err = service.AddEntry(ctx, domain.CreateWatchlistEntryRequest{BuildingID: buildingID, CarPlate: "A123BC", Reason: "ДТП"})
+		assert.ErrorIs(t, err, ErrInvalidCarPlate)
+
+		_, err = service.AddEntry(ctx, domain.CreateWatchlistEntryRequest{BuildingID: buildingID, CarPlate: "A123BC77", Reason: "  "})
+		assert.ErrorIs(t, err, ErrWatchlistReasonRequired)
+
+		_, err = service.AddEntry(ctx, domain.CreateWatchlistEntryRequest{BuildingID: buildingID, CarPlate: "A123BC77", Reason: "ДТП", Severity: "maybe"})
+		assert.ErrorIs(t, err, ErrInvalidWatchlistSeverity)
+	})
+}
+
+func TestPassService_CreatePass_BannedPlate(t *testing.T) {
+	ctx := context.Background()
+	buildingID := int64(1)
+	residentID := int64(100)
+	carPlate := "а123вс77"
+
+	passRepo := new(MockPassRepo)
+	apartmentRepo := new(MockApartmentRepo)
+	watchlistRepo := new(MockPlateWatchlistRepo)
+	service := NewPassService(passRepo, apartmentRepo, new(MockRuleRepo), new(MockScanEventRepo), nil, watchlistRepo, passIssuer(10), time.UTC, zap.NewNop())
+
+	apartmentRepo.On("GetByID", ctx, int64(10)).Return(&domain.Apartment{ID: 10, BuildingID: buildingID, Number: "42"}, nil)
+	watchlistRepo.On("GetByCarPlate", ctx, "A123BC77", &buildingID).Return(&domain.WatchlistEntry{
+		ID: 3, BuildingID: buildingID, CarPlate: "A123BC77", Reason: "долг", Severity: domain.WatchlistSeverityBan,
+	}, nil)
+
+	_, err := service.CreatePass(ctx, domain.CreatePassRequest{
+		ApartmentID: 10,
+		ResidentID:  &residentID,
+		CarPlate:    &carPlate,
+		ValidFrom:   time.Now(),
+		ValidTo:     time.Now().Add(time.Hour),
+	})
+	assert.ErrorIs(t, err, ErrPlateBanned)
+	passRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestPassService_ValidatePassByCarPlate_Watchlist(t *testing.T) {
+	ctx := context.Background()
+	buildingID := int64(1)
+	now := time.Now()
+
+	newService := func(severity string) (*PassService, *MockPlateWatchlistRepo, *MockScanEventRepo, *domain.Pass) {
+		passRepo := new(MockPassRepo)
+		apartmentRepo := new(MockApartmentRepo)
+		ruleRepo := new(MockRuleRepo)
+		scanEventRepo := new(MockScanEventRepo)
+		vehicleRepo := new(MockResidentVehicleRepo)
+		watchlistRepo := new(MockPlateWatchlistRepo)
+
+		carPlate := "A123BC77"
+		pass := &domain.Pass{
+			ID:          uuid.New(),
+			ApartmentID: 10,
+			CarPlate:    &carPlate,
+			Status:      "active",
+			ValidFrom:   now.Add(-time.Hour),
+			ValidTo:     now.Add(time.Hour),
+		}
+		watchlistRepo.On("GetByCarPlate", ctx, "A123BC77", &buildingID).Return(&domain.WatchlistEntry{
+			ID: 3, BuildingID: buildingID, CarPlate: "A123BC77", Reason: "долг", Severity: severity,
+		}, nil)
+		vehicleRepo.On("GetByCarPlate", ctx, "A123BC77", &buildingID).Return(nil, nil)
+		passRepo.On("GetActiveByCarPlate", ctx, "A123BC77", &buildingID).Return(pass, nil)
+		apartmentRepo.On("GetByID", ctx, int64(10)).Return(&domain.Apartment{ID: 10, BuildingID: buildingID, Number: "42"}, nil)
+		ruleRepo.On("GetByBuildingID", ctx, buildingID).Return(&domain.Rule{}, nil)
+
+		return NewPassService(passRepo, apartmentRepo, ruleRepo, scanEventRepo, vehicleRepo, watchlistRepo, nil, time.UTC, zap.NewNop()), watchlistRepo, scanEventRepo, pass
+	}
+
+	t.Run("a banned car is stopped despite a valid pass", func(t *testing.T) {
+		service, watchlistRepo, scanEventRepo, pass := newService(domain.WatchlistSeverityBan)
+		scanEventRepo.On("Create", ctx, mock.MatchedBy(func(e *domain.ScanEvent) bool {
+			return *e.PassID == pass.ID && e.Result == "invalid" && *e.Reason == "BLACKLISTED" &&
+				assert.JSONEq(t, `{"source":"watchlist","watchlist_id":3,"car_plate":"A123BC77","severity":"ban"}`, *e.Meta)
+		})).Return(nil)
+		watchlistRepo.On("CreateAlert", ctx, mock.MatchedBy(func(a *domain.PlateAlert) bool {
+			return a.WatchlistID == 3 && *a.GuardUserID == 5 && *a.PassID == pass.ID && !a.Admitted
+		})).Return(nil)
+
+		result, err := service.ValidatePassByCarPlate(ctx, "A 123 BC 77", 5, &buildingID)
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, "BLACKLISTED", result.Reason)
+		require.NotNil(t, result.Watchlist)
+		assert.Equal(t, "долг", result.Watchlist.Reason)
+		scanEventRepo.AssertExpectations(t)
+		watchlistRepo.AssertExpectations(t)
+	})
+
+	t.Run("a watched car is let in with an alert", func(t *testing.T) {
+		service, watchlistRepo, scanEventRepo, pass := newService(domain.WatchlistSeverityWatch)
+		scanEventRepo.On("Create", ctx, mock.AnythingOfType("*domain.ScanEvent")).Return(nil)
+		watchlistRepo.On("CreateAlert", ctx, mock.MatchedBy(func(a *domain.PlateAlert) bool {
+			return a.WatchlistID == 3 && *a.PassID == pass.ID && a.Admitted
+		})).Return(nil)
+
+		result, err := service.ValidatePassByCarPlate(ctx, "A123BC77", 5, &buildingID)
+		require.NoError(t, err)
+		assert.True(t, result.Valid)
+		require.NotNil(t, result.Watchlist)
+		assert.Equal(t, domain.WatchlistSeverityWatch, result.Watchlist.Severity)
+		watchlistRepo.AssertExpectations(t)
+	})
+}
@@ -13,6 +13,7 @@ import (
 	"yardpass/internal/config"
 	"yardpass/internal/domain"
 	"yardpass/internal/observability/logger"
+	"yardpass/internal/pdf"
 	"yardpass/internal/qr"
 	"yardpass/internal/redis"
 	"yardpass/internal/repo"
@@ -44,6 +45,13 @@ func SetupApi(configPath string) (*fx.App, error) {
 			fx.Annotate(repo.NewUserRepo, fx.As(new(domain.UserRepository))),
 			fx.Annotate(repo.NewResidentRepo, fx.As(new(domain.ResidentRepository))),
 			fx.Annotate(repo.NewScanEventRepo, fx.As(new(domain.ScanEventRepository))),
+			fx.Annotate(repo.NewBroadcastRepo, fx.As(new(domain.BroadcastRepository))),
+			fx.Annotate(repo.NewEntryRequestRepo, fx.As(new(domain.EntryRequestRepository))),
+			fx.Annotate(repo.NewEventRepo, fx.As(new(domain.EventRepository))),
+			fx.Annotate(repo.NewPassExceptionRepo, fx.As(new(domain.PassExceptionRepository))),
+			fx.Annotate(repo.NewResidentVehicleRepo, fx.As(new(domain.ResidentVehicleRepository))),
+			fx.Annotate(repo.NewPlateWatchlistRepo, fx.As(new(domain.PlateWatchlistRepository))),
+			fx.Annotate(repo.NewGateCameraRepo, fx.As(new(domain.GateCameraRepository))),
 
 			redis.NewClient,
 
@@ -51,6 +59,18 @@ func SetupApi(configPath string) (*fx.App, error) {
 			service.NewPassService,
 			service.NewUserService,
 			service.NewResidentService,
+			service.NewBroadcastService,
+			service.NewEntryRequestService,
+			service.NewPassShareService,
+			service.NewPassPrintService,
+			service.NewEventService,
+			service.NewPassExceptionService,
+			service.NewResidentVehicleService,
+			service.NewWatchlistService,
+			service.NewCameraService,
+			service.NewVisitLinkService,
+			qr.NewGenerator,
+			pdf.NewGenerator,
 
 			handlers.NewAuthHandler,
 			handlers.NewPassHandler,
@@ -60,10 +80,21 @@ func SetupApi(configPath string) (*fx.App, error) {
 			handlers.NewScanEventHandler,
 			handlers.NewReportHandler,
 			handlers.NewParkingHandler,
+			handlers.NewBroadcastHandler,
+			handlers.NewEntryRequestHandler,
+			handlers.NewPassShareHandler,
+			handlers.NewPassPrintHandler,
+			handlers.NewEventHandler,
+			handlers.NewPassExceptionHandler,
+			handlers.NewResidentVehicleHandler,
+			handlers.NewWatchlistHandler,
+			handlers.NewCameraHandler,
+			handlers.NewVisitLinkHandler,
 
 			api.NewRouter,
 
 			func() *config.Config { return cfg },
+			config.NewLocation,
 			func() config.PGConfig { return cfg.PG },
 			func() config.RedisConfig { return cfg.Redis },
 			func() config.JWTConfig { return cfg.JWT },
@@ -96,18 +127,46 @@ func SetupBot(configPath string) (*fx.App, error) {
 			repo.NewPostgresRepo,
 			fx.Annotate(repo.NewPassRepo, fx.As(new(domain.PassRepository))),
 			fx.Annotate(repo.NewApartmentRepo, fx.As(new(domain.ApartmentRepository))),
+			fx.Annotate(repo.NewBuildingRepo, fx.As(new(domain.BuildingRepository))),
 			fx.Annotate(repo.NewRuleRepo, fx.As(new(domain.RuleRepository))),
+			fx.Annotate(repo.NewUserRepo, fx.As(new(domain.UserRepository))),
 			fx.Annotate(repo.NewResidentRepo, fx.As(new(domain.ResidentRepository))),
 			fx.Annotate(repo.NewScanEventRepo, fx.As(new(domain.ScanEventRepository))),
+			fx.Annotate(repo.NewSavedGuestRepo, fx.As(new(domain.SavedGuestRepository))),
+			fx.Annotate(repo.NewBroadcastRepo, fx.As(new(domain.BroadcastRepository))),
+			fx.Annotate(repo.NewPassRequestRepo, fx.As(new(domain.PassRequestRepository))),
+			fx.Annotate(repo.NewEntryRequestRepo, fx.As(new(domain.EntryRequestRepository))),
+			fx.Annotate(repo.NewEventRepo, fx.As(new(domain.EventRepository))),
+			fx.Annotate(repo.NewPassExceptionRepo, fx.As(new(domain.PassExceptionRepository))),
+			fx.Annotate(repo.NewResidentVehicleRepo, fx.As(new(domain.ResidentVehicleRepository))),
+			fx.Annotate(repo.NewPlateWatchlistRepo, fx.As(new(domain.PlateWatchlistRepository))),
+			fx.Annotate(repo.NewGateCameraRepo, fx.As(new(domain.GateCameraRepository))),
 
 			redis.NewClient,
 
 			service.NewPassService,
+			service.NewUserService,
+			service.NewResidentService,
+			service.NewGuestService,
+			service.NewBroadcastService,
+			service.NewPassRequestService,
+			service.NewEntryRequestService,
+			service.NewPassShareService,
+			service.NewPassPrintService,
+			service.NewEventService,
+			service.NewPassExceptionService,
+			service.NewResidentVehicleService,
+			service.NewWatchlistService,
+			service.NewCameraService,
 			qr.NewGenerator,
+			pdf.NewGenerator,
 
+			fx.Annotate(telegram.NewAPIClient, fx.As(new(telegram.TelegramAPI))),
+			fx.Annotate(telegram.NewRedisStateStore, fx.As(new(telegram.StateStore))),
 			telegram.NewBot,
 
 			func() *config.Config { return cfg },
+			config.NewLocation,
 			func() config.PGConfig { return cfg.PG },
 			func() config.RedisConfig { return cfg.Redis },
 			func() config.LogConfig { return cfg.Log },
@@ -0,0 +1,147 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"yardpass/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+const selectApartmentPrefix = "select_apartment:"
+
+// residentsFor returns the resident rows (one per apartment) of a Telegram
+// user. It reports "not found" to the chat itself and returns nil then.
+func (b *Bot) residentsFor(ctx context.Context, chatID int64, userID int64) []*domain.Resident {
+	residents, err := b.residentRepo.ListByTelegramID(ctx, userID)
+	if err != nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgResidentNotFound))
+		b.logger.Error("failed to list residents", zap.Error(err), zap.Int64("user_id", userID))
+		return nil
+	}
+	if len(residents) == 0 {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgResidentNotFound))
+		return nil
+	}
+	return residents
+}
+
+// withResident runs fn for the resident row the user acts as. With a single
+// apartment fn runs immediately, otherwise an apartment picker is shown and
+// fn is dispatched later from handleSelectApartment by action.
+func (b *Bot) withResident(ctx context.Context, chatID int64, userID int64, action string, fn func(resident *domain.Resident)) {
+	all := b.residentsFor(ctx, chatID, userID)
+	if all == nil {
+		return
+	}
+
+	var residents []*domain.Resident
+	for _, r := range all {
+		if residentCan(r, action) {
+			residents = append(residents, r)
+		}
+	}
+
+	if len(residents) == 0 {
+		switch action {
+		case "create_pass", "create_event", "vehicles":
+			b.sendMessage(ctx, chatID, b.t(ctx, msgNoIssueRight))
+		case "household":
+			b.sendMessage(ctx, chatID, b.t(ctx, msgPrimaryOnly))
+		}
+		return
+	}
+
+	if len(residents) == 1 {
+		fn(residents[0])
+		return
+	}
+
+	var keyboardRows [][]map[string]interface{}
+	for _, resident := range residents {
+		keyboardRows = append(keyboardRows, []map[string]interface{}{
+			{
+				"text":          b.apartmentLabel(ctx, resident.ApartmentID),
+				"callback_data": fmt.Sprintf("%s%s:%d", selectApartmentPrefix, action, resident.ID),
+			},
+		})
+	}
+
+	keyboard := map[string]interface{}{
+		"inline_keyboard": keyboardRows,
+	}
+
+	b.sendMessageWithKeyboard(ctx, chatID, b.t(ctx, msgChooseApartment), keyboard)
+}
+
+func (b *Bot) handleSelectApartment(ctx context.Context, cb CallbackQuery) {
+	chatID := cb.Message.Chat.ID
+	action, residentIDStr, ok := strings.Cut(strings.TrimPrefix(cb.Data, selectApartmentPrefix), ":")
+	residentID, err := strconv.ParseInt(residentIDStr, 10, 64)
+	if !ok || err != nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgInvalidApartmentChoice))
+		return
+	}
+
+	residents := b.residentsFor(ctx, chatID, cb.From.ID)
+	var resident *domain.Resident
+	for _, r := range residents {
+		if r.ID == residentID && residentCan(r, action) {
+			resident = r
+			break
+		}
+	}
+	if resident == nil {
+		if residents != nil {
+			b.sendMessage(ctx, chatID, b.t(ctx, msgApartmentNotFound))
+		}
+		return
+	}
+
+	switch action {
+	case "create_pass":
+		b.startCreatePass(ctx, chatID, cb.From.ID, resident)
+	case "list_active":
+		b.listActivePasses(ctx, chatID, resident)
+	case "revoke_pass":
+		b.showPassesForRevoke(ctx, chatID, resident)
+	case "household":
+		b.showHousehold(ctx, chatID, resident)
+	case "guests":
+		b.showGuests(ctx, chatID, resident)
+	case "create_event":
+		b.startEvent(ctx, chatID, cb.From.ID, resident)
+	case "vehicles":
+		b.showVehicles(ctx, chatID, resident)
+	default:
+		b.sendMessage(ctx, chatID, b.t(ctx, msgUnknownAction))
+	}
+}
+
+func (b *Bot) apartmentLabel(ctx context.Context, apartmentID int64) string {
+	apartment, err := b.apartmentRepo.GetByID(ctx, apartmentID)
+	if err != nil || apartment == nil {
+		return b.t(ctx, msgApartmentFallback, apartmentID)
+	}
+
+	label := b.t(ctx, msgApartmentShort, apartment.Number)
+	building, err := b.buildingRepo.GetByID(ctx, apartment.BuildingID)
+	if err == nil && building != nil {
+		label = fmt.Sprintf("%s, %s", label, building.Name)
+	}
+	return label
+}
+
+// residentCan reports whether a resident row may be used for the bot action.
+func residentCan(r *domain.Resident, action string) bool {
+	switch action {
+	case "create_pass", "create_event", "vehicles":
+		return r.CanIssuePasses
+	case "household":
+		return r.Role == "primary"
+	}
+	return true
+}
@@ -0,0 +1,301 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"yardpass/internal/config"
+
+	"go.uber.org/zap"
+)
+
+// TelegramAPI is the part of the Bot API the bot uses.
+type TelegramAPI interface {
+	// Call invokes a method with a JSON payload and ignores the result.
+	Call(ctx context.Context, method string, payload map[string]interface{}) error
+	SendPhoto(ctx context.Context, chatID int64, photo []byte, caption string) error
+	SendPhotoWithKeyboard(ctx context.Context, chatID int64, photo []byte, caption string, keyboard interface{}) error
+	SendDocument(ctx context.Context, chatID int64, fileName string, document []byte, caption string) error
+	// DownloadFile returns the contents of a file users sent to the bot.
+	DownloadFile(ctx context.Context, fileID string) ([]byte, error)
+	// GetUpdates long-polls for at most timeout.
+	GetUpdates(ctx context.Context, offset int64, timeout time.Duration) ([]Update, error)
+}
+
+const (
+	apiMaxRetries     = 3
+	apiInitialBackoff = time.Second
+	apiMaxBackoff     = 30 * time.Second
+	apiRequestTimeout = 30 * time.Second
+
+	// apiMaxDownloadSize is the largest file the Bot API lets bots download.
+	apiMaxDownloadSize = 20 << 20
+)
+
+// APIError is an unsuccessful Bot API response.
+type APIError struct {
+	Method      string
+	StatusCode  int
+	Description string
+	RetryAfter  time.Duration
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("telegram API error in %s (status %d): %s", e.Method, e.StatusCode, e.Description)
+}
+
+// APIClient talks to the Bot API over HTTP. Requests rejected with 429 are
+// retried after the delay Telegram asks for, server errors with
+// exponential backoff.
+type APIClient struct {
+	baseURL     string
+	fileBaseURL string
+	http        *http.Client
+	logger      *zap.Logger
+
+	// sleep waits between retries; replaced in tests.
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+func NewAPIClient(cfg *config.Config, logger *zap.Logger) *APIClient {
+	return &APIClient{
+		baseURL:     fmt.Sprintf("%s/bot%s", strings.TrimRight(cfg.Telegram.APIBaseURL, "/"), cfg.Telegram.BotToken),
+		fileBaseURL: fmt.Sprintf("%s/file/bot%s", strings.TrimRight(cfg.Telegram.APIBaseURL, "/"), cfg.Telegram.BotToken),
+		http:        &http.Client{},
+		logger:      logger,
+		sleep:       sleepContext,
+	}
+}
+
+func (c *APIClient) Call(ctx context.Context, method string, payload map[string]interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	_, err = c.do(ctx, method, "application/json", body, apiRequestTimeout)
+	return err
+}
+
+func (c *APIClient) SendPhoto(ctx context.Context, chatID int64, photo []byte, caption string) error {
+	return c.SendPhotoWithKeyboard(ctx, chatID, photo, caption, nil)
+}
+
+func (c *APIClient) SendPhotoWithKeyboard(ctx context.Context, chatID int64, photo []byte, caption string, keyboard interface{}) error {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	writer.WriteField("chat_id", strconv.FormatInt(chatID, 10))
+	writer.WriteField("caption", caption)
+	if keyboard != nil {
+		markup, err := json.Marshal(keyboard)
+		if err != nil {
+			return fmt.Errorf("failed to marshal keyboard: %w", err)
+		}
+		writer.WriteField("reply_markup", string(markup))
+	}
+
+	part, err := writer.CreateFormFile("photo", "qr.png")
+	if err != nil {
+		return fmt.Errorf("failed to create form file: %w", err)
+	}
+	if _, err := part.Write(photo); err != nil {
+		return fmt.Errorf("failed to write photo: %w", err)
+	}
+	writer.Close()
+
+	_, err = c.do(ctx, "sendPhoto", writer.FormDataContentType(), body.Bytes(), apiRequestTimeout)
+	return err
+}
+
+func (c *APIClient) SendDocument(ctx context.Context, chatID int64, fileName string, document []byte, caption string) error {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	writer.WriteField("chat_id", strconv.FormatInt(chatID, 10))
+	writer.WriteField("caption", caption)
+
+	part, err := writer.CreateFormFile("document", fileName)
+	if err != nil {
+		return fmt.Errorf("failed to create form file: %w", err)
+	}
+	if _, err := part.Write(document); err != nil {
+		return fmt.Errorf("failed to write document: %w", err)
+	}
+	writer.Close()
+
+	_, err = c.do(ctx, "sendDocument", writer.FormDataContentType(), body.Bytes(), apiRequestTimeout)
+	return err
+}
+
+func (c *APIClient) DownloadFile(ctx context.Context, fileID string) ([]byte, error) {
+	body, err := json.Marshal(map[string]interface{}{"file_id": fileID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	result, err := c.do(ctx, "getFile", "application/json", body, apiRequestTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var file struct {
+		FilePath string `json:"file_path"`
+	}
+	if err := json.Unmarshal(result, &file); err != nil {
+		return nil, fmt.Errorf("failed to decode file: %w", err)
+	}
+	if file.FilePath == "" {
+		return nil, errors.New("file is not available for download")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, apiRequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/%s", c.fileBaseURL, file.FilePath), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &APIError{Method: "getFile", StatusCode: resp.StatusCode, Description: resp.Status}
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, apiMaxDownloadSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file: %w", err)
+	}
+	return data, nil
+}
+
+func (c *APIClient) GetUpdates(ctx context.Context, offset int64, timeout time.Duration) ([]Update, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"offset":          offset,
+		"timeout":         int(timeout.Seconds()),
+		"allowed_updates": []string{"message", "callback_query"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	result, err := c.do(ctx, "getUpdates", "application/json", body, timeout+apiRequestTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var updates []Update
+	if err := json.Unmarshal(result, &updates); err != nil {
+		return nil, fmt.Errorf("failed to decode updates: %w", err)
+	}
+	return updates, nil
+}
+
+func (c *APIClient) do(ctx context.Context, method, contentType string, body []byte, timeout time.Duration) (json.RawMessage, error) {
+	backoff := apiInitialBackoff
+	for attempt := 0; ; attempt++ {
+		result, err := c.doOnce(ctx, method, contentType, body, timeout)
+		if err == nil {
+			return result, nil
+		}
+
+		apiErr, ok := err.(*APIError)
+		retryable := ok && (apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= http.StatusInternalServerError)
+		if !retryable || attempt >= apiMaxRetries {
+			return nil, err
+		}
+
+		wait := backoff
+		if apiErr.RetryAfter > 0 {
+			wait = apiErr.RetryAfter
+		}
+		backoff = min(backoff*2, apiMaxBackoff)
+
+		c.logger.Warn("Telegram API request failed, retrying",
+			zap.String("method", method),
+			zap.Int("status", apiErr.StatusCode),
+			zap.Duration("wait", wait),
+			zap.Int("attempt", attempt+1),
+		)
+		if err := c.sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (c *APIClient) doOnce(ctx context.Context, method, contentType string, body []byte, timeout time.Duration) (json.RawMessage, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/%s", c.baseURL, method), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		OK          bool            `json:"ok"`
+		Result      json.RawMessage `json:"result"`
+		Description string          `json:"description"`
+		Parameters  struct {
+			RetryAfter int `json:"retry_after"`
+		} `json:"parameters"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil && resp.StatusCode == http.StatusOK {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK || !result.OK {
+		return nil, &APIError{
+			Method:      method,
+			StatusCode:  resp.StatusCode,
+			Description: result.Description,
+			RetryAfter:  time.Duration(result.Parameters.RetryAfter) * time.Second,
+		}
+	}
+
+	return result.Result, nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// truncateText shortens s to at most max characters, ending it with "..."
+// when it is cut. It counts runes so that Cyrillic texts are not cut in the
+// middle of a character.
+func truncateText(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return string([]rune(s)[:max-3]) + "..."
+}
@@ -0,0 +1,92 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"yardpass/internal/config"
+	"yardpass/internal/telegram/telegramtest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestAPIClient(t *testing.T) (*APIClient, *telegramtest.Server, *[]time.Duration) {
+	fake := telegramtest.NewServer(t)
+	client := NewAPIClient(&config.Config{Telegram: config.TelegramConfig{
+		BotToken:   telegramtest.Token,
+		APIBaseURL: fake.URL() + "/",
+	}}, zap.NewNop())
+
+	var waits []time.Duration
+	client.sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	return client, fake, &waits
+}
+
+func TestAPIClient_RetriesOn429HonoringRetryAfter(t *testing.T) {
+	client, fake, waits := newTestAPIClient(t)
+	fake.FailNext("sendMessage", http.StatusTooManyRequests, 7)
+
+	err := client.Call(context.Background(), "sendMessage", map[string]interface{}{"chat_id": 1, "text": "hi"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []time.Duration{7 * time.Second}, *waits)
+	assert.Len(t, fake.Requests("sendMessage"), 2)
+	assert.Len(t, fake.Messages(1), 1)
+}
+
+func TestAPIClient_BacksOffOnServerErrors(t *testing.T) {
+	client, fake, waits := newTestAPIClient(t)
+	for i := 0; i < apiMaxRetries+1; i++ {
+		fake.FailNext("sendPhoto", http.StatusBadGateway, 0)
+	}
+
+	err := client.SendPhoto(context.Background(), 1, []byte("png"), "caption")
+
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusBadGateway, apiErr.StatusCode)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}, *waits)
+	assert.Len(t, fake.Requests("sendPhoto"), apiMaxRetries+1)
+}
+
+func TestAPIClient_DoesNotRetryClientErrors(t *testing.T) {
+	client, fake, waits := newTestAPIClient(t)
+	fake.FailNext("answerCallbackQuery", http.StatusBadRequest, 0)
+
+	err := client.Call(context.Background(), "answerCallbackQuery", map[string]interface{}{"callback_query_id": "1"})
+
+	require.Error(t, err)
+	assert.Empty(t, *waits)
+	assert.Len(t, fake.Requests("answerCallbackQuery"), 1)
+}
+
+func TestAPIClient_GetUpdates(t *testing.T) {
+	client, fake, _ := newTestAPIClient(t)
+	fake.SendText(5, "hello")
+	fake.PressButton(5, "list_active")
+
+	updates, err := client.GetUpdates(context.Background(), 0, 0)
+	require.NoError(t, err)
+	require.Len(t, updates, 2)
+	assert.Equal(t, "hello", updates[0].Message.Text)
+	assert.Equal(t, int64(5), updates[0].Message.Chat.ID)
+	assert.Equal(t, "list_active", updates[1].CallbackQuery.Data)
+
+	updates, err = client.GetUpdates(context.Background(), updates[1].UpdateID+1, 0)
+	require.NoError(t, err)
+	assert.Empty(t, updates)
+}
+
+func TestTruncateText(t *testing.T) {
+	assert.Equal(t, "А123ВС77 · Иван", truncateText("А123ВС77 · Иван", 15))
+	assert.Equal(t, "А123ВС77 · И...", truncateText("А123ВС77 · Иванов", 15))
+	assert.Equal(t, "A123BC...", truncateText("A123BC77 guest", 9))
+}
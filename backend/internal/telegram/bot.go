@@ -2,7 +2,6 @@ package telegram
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"sync"
@@ -18,72 +17,110 @@ import (
 	"go.uber.org/zap"
 )
 
+// pollTimeout is how long a getUpdates long poll waits for new updates.
+const pollTimeout = 30 * time.Second
+
 type Bot struct {
-	token      string
-	apiURL     string
-	webhookURL string
-	serverHost string
-	serverPort string
-
-	passService   *service.PassService
-	residentRepo  domain.ResidentRepository
-	apartmentRepo domain.ApartmentRepository
-	qrGen         *qr.Generator
-	redis         *redis.Client
-	logger        *zap.Logger
-	states        map[int64]*UserState
-	location      *time.Location
+	webhookURL    string
+	webhookSecret string
+	serverHost    string
+	serverPort    string
+	botUsername   string
+
+	api                 TelegramAPI
+	passService         *service.PassService
+	userService         *service.UserService
+	residentService     *service.ResidentService
+	guestService        *service.GuestService
+	broadcastService    *service.BroadcastService
+	passRequestService  *service.PassRequestService
+	entryRequestService *service.EntryRequestService
+	passShareService    *service.PassShareService
+	passPrintService    *service.PassPrintService
+	eventService        *service.EventService
+	exceptionService    *service.PassExceptionService
+	vehicleService      *service.ResidentVehicleService
+	watchlistService    *service.WatchlistService
+	residentRepo        domain.ResidentRepository
+	apartmentRepo       domain.ApartmentRepository
+	buildingRepo        domain.BuildingRepository
+	qrGen               *qr.Generator
+	redis               *redis.Client
+	logger              *zap.Logger
+	states              StateStore
+	location            *time.Location
+	dispatcher          *dispatcher
+	server              *http.Server
+
+	// broadcastInterval is the pause between two broadcast messages.
+	broadcastInterval time.Duration
 
 	wg     sync.WaitGroup
 	ctx    context.Context
 	cancel context.CancelFunc
-}
 
-type UserState struct {
-	Step      string
-	Data      map[string]interface{}
-	ExpiresAt time.Time
+	// processCancel aborts updates still being handled when Stop runs out
+	// of time.
+	processCancel context.CancelFunc
 }
 
-const (
-	StateWaitingGuestType  = "waiting_guest_type"
-	StateWaitingCarPlate   = "waiting_car_plate"
-	StateWaitingDuration   = "waiting_duration"
-	StateWaitingCustomTime = "waiting_custom_time"
-	StateWaitingGuestName  = "waiting_guest_name"
-)
-
 func NewBot(
 	lf fx.Lifecycle,
 	cfg *config.Config,
+	api TelegramAPI,
 	passService *service.PassService,
+	userService *service.UserService,
+	residentService *service.ResidentService,
+	guestService *service.GuestService,
+	broadcastService *service.BroadcastService,
+	passRequestService *service.PassRequestService,
+	entryRequestService *service.EntryRequestService,
+	passShareService *service.PassShareService,
+	passPrintService *service.PassPrintService,
+	eventService *service.EventService,
+	exceptionService *service.PassExceptionService,
+	vehicleService *service.ResidentVehicleService,
+	watchlistService *service.WatchlistService,
 	residentRepo domain.ResidentRepository,
 	apartmentRepo domain.ApartmentRepository,
+	buildingRepo domain.BuildingRepository,
 	qrGen *qr.Generator,
+	states StateStore,
 	redisClient *redis.Client,
+	location *time.Location,
 	logger *zap.Logger,
 ) *Bot {
-	location, err := time.LoadLocation("Europe/Moscow")
-	if err != nil {
-		logger.Warn("Failed to load Europe/Moscow timezone, using UTC", zap.Error(err))
-		location = time.UTC
-	}
-
 	bot := &Bot{
-		token:         cfg.Telegram.BotToken,
-		apiURL:        fmt.Sprintf("https://api.telegram.org/bot%s", cfg.Telegram.BotToken),
-		webhookURL:    cfg.Telegram.WebhookURL,
-		serverHost:    cfg.Telegram.ServerHost,
-		serverPort:    cfg.Telegram.ServerPort,
-		passService:   passService,
-		residentRepo:  residentRepo,
-		apartmentRepo: apartmentRepo,
-		qrGen:         qrGen,
-		redis:         redisClient,
-		logger:        logger,
-		states:        make(map[int64]*UserState),
-		location:      location,
+		api:                 api,
+		webhookURL:          cfg.Telegram.WebhookURL,
+		webhookSecret:       webhookSecretFor(cfg.Telegram.WebhookSecret, cfg.Telegram.BotToken),
+		serverHost:          cfg.Telegram.ServerHost,
+		serverPort:          cfg.Telegram.ServerPort,
+		botUsername:         cfg.Telegram.BotUsername,
+		passService:         passService,
+		userService:         userService,
+		residentService:     residentService,
+		guestService:        guestService,
+		broadcastService:    broadcastService,
+		passRequestService:  passRequestService,
+		entryRequestService: entryRequestService,
+		passShareService:    passShareService,
+		passPrintService:    passPrintService,
+		eventService:        eventService,
+		exceptionService:    exceptionService,
+		vehicleService:      vehicleService,
+		watchlistService:    watchlistService,
+		residentRepo:        residentRepo,
+		apartmentRepo:       apartmentRepo,
+		buildingRepo:        buildingRepo,
+		qrGen:               qrGen,
+		redis:               redisClient,
+		logger:              logger,
+		states:              states,
+		location:            location,
+		broadcastInterval:   broadcastInterval(cfg.Telegram.BroadcastRate),
 	}
+	bot.dispatcher = newDispatcher(cfg.Telegram.Workers, cfg.Telegram.QueueSize, bot.ProcessUpdate, logger)
 
 	lf.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
@@ -104,22 +141,56 @@ func (b *Bot) Start(ctx context.Context) error {
 
 	b.logger.Info("Bot commands menu set successfully")
 
-	b.ctx, b.cancel = context.WithCancel(context.Background())
-	b.wg.Add(1)
 	if b.webhookURL != "" {
 		b.logger.Info("Setting up webhook", zap.String("url", b.webhookURL))
-		go b.listenWebhook()
-	} else {
+		if err := b.setWebhook(ctx); err != nil {
+			return fmt.Errorf("failed to set webhook: %w", err)
+		}
+	} else if err := b.deleteWebhook(ctx); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	var processCtx context.Context
+	processCtx, b.processCancel = context.WithCancel(context.Background())
+	b.dispatcher.start(processCtx)
+
+	if err := b.startServer(); err != nil {
+		b.processCancel()
+		return fmt.Errorf("failed to start bot HTTP server: %w", err)
+	}
+
+	b.ctx, b.cancel = context.WithCancel(context.Background())
+	if b.webhookURL == "" {
 		b.logger.Info("Starting polling mode")
+		b.wg.Add(1)
 		go b.startPolling(b.ctx)
 	}
 
+	b.wg.Add(1)
+	go b.runBroadcasts(b.ctx)
+
+	b.wg.Add(1)
+	go b.runEntryRequests(b.ctx)
+
+	b.wg.Add(1)
+	go b.runPassExceptions(b.ctx)
+
+	b.wg.Add(1)
+	go b.runPlateAlerts(b.ctx)
+
 	return nil
 }
 
+// Stop stops receiving updates first (webhook server, polling loop) and then
+// waits for the updates already queued to be processed.
 func (b *Bot) Stop(ctx context.Context) error {
 	b.logger.Info("Shutting down bot...")
 	b.cancel()
+	defer b.processCancel()
+
+	if err := b.server.Shutdown(ctx); err != nil {
+		return fmt.Errorf("shutdown bot HTTP server: %w", err)
+	}
 
 	done := make(chan struct{})
 	go func() {
@@ -129,38 +200,18 @@ func (b *Bot) Stop(ctx context.Context) error {
 
 	select {
 	case <-done:
-		b.logger.Info("Bot exited")
-		return nil
 	case <-ctx.Done():
 		b.logger.Info("Bot shutdown by context")
 		return ctx.Err()
 	}
-}
-
-func (b *Bot) listenWebhook() {
-	defer b.wg.Done()
-	http.HandleFunc("/webhook", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodPost {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
-
-		var update Update
-		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
-			b.logger.Error("Failed to decode update", zap.Error(err))
-			http.Error(w, "Bad request", http.StatusBadRequest)
-			return
-		}
-
-		b.ProcessUpdate(r.Context(), update)
-		w.WriteHeader(http.StatusOK)
-	})
 
-	addr := fmt.Sprintf("%s:%s", b.serverHost, b.serverPort)
-	b.logger.Info("Webhook server listening", zap.String("address", addr))
-	if err := http.ListenAndServe(addr, nil); err != nil {
-		b.logger.Fatal("Failed to start webhook server", zap.Error(err))
+	if err := b.dispatcher.close(ctx); err != nil {
+		b.logger.Info("Bot shutdown by context before queued updates were processed")
+		return err
 	}
+
+	b.logger.Info("Bot exited")
+	return nil
 }
 
 func (b *Bot) startPolling(ctx context.Context) {
@@ -175,7 +226,7 @@ func (b *Bot) startPolling(ctx context.Context) {
 		default:
 		}
 
-		updates, err := b.GetUpdates(ctx, offset)
+		updates, err := b.api.GetUpdates(ctx, offset, pollTimeout)
 		if err != nil {
 			b.logger.Error("Failed to get updates", zap.Error(err))
 			select {
@@ -187,18 +238,17 @@ func (b *Bot) startPolling(ctx context.Context) {
 			continue
 		}
 
+		// getUpdates is a long poll, so the next request is issued right
+		// away. submit blocks while the workers are saturated, which keeps
+		// the bot from fetching more than it can handle.
 		for _, update := range updates {
-			b.ProcessUpdate(ctx, update)
+			if err := b.dispatcher.submit(ctx, update); err != nil {
+				b.logger.Info("Polling mode stopped by context")
+				return
+			}
 			if update.UpdateID >= offset {
 				offset = update.UpdateID + 1
 			}
 		}
-
-		select {
-		case <-ctx.Done():
-			b.logger.Info("Polling mode stopped by context")
-			return
-		case <-time.After(1 * time.Second):
-		}
 	}
 }
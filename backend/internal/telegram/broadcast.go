@@ -0,0 +1,139 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"yardpass/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// broadcastBatchSize is how many recipients are claimed from the queue
+	// at once.
+	broadcastBatchSize = 100
+	// broadcastPollInterval is how often an empty queue is checked again.
+	broadcastPollInterval = 5 * time.Second
+	// broadcastClaimLease is how long claimed deliveries stay with the bot
+	// that claimed them. It is well above the time a batch takes to send,
+	// so only deliveries of a bot that stopped are queued again.
+	broadcastClaimLease = 10 * time.Minute
+)
+
+// broadcastInterval spaces broadcast messages so that they stay below
+// Telegram's limit of about 30 messages per second, leaving room for
+// replies to users.
+func broadcastInterval(rate int) time.Duration {
+	if rate <= 0 {
+		return 0
+	}
+	return time.Second / time.Duration(rate)
+}
+
+// runBroadcasts delivers queued broadcasts until ctx is cancelled.
+func (b *Bot) runBroadcasts(ctx context.Context) {
+	defer b.wg.Done()
+
+	for {
+		if err := b.broadcastService.ReleaseDeliveries(ctx, broadcastClaimLease); err != nil && ctx.Err() == nil {
+			b.logger.Error("Failed to release broadcast deliveries", zap.Error(err))
+		}
+
+		handled, err := b.deliverBroadcasts(ctx)
+		if err != nil && ctx.Err() == nil {
+			b.logger.Error("Failed to deliver broadcasts", zap.Error(err))
+		}
+		if handled > 0 && err == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			b.logger.Info("Broadcast delivery stopped by context")
+			return
+		case <-time.After(broadcastPollInterval):
+		}
+	}
+}
+
+// deliverBroadcasts sends one batch from the queue and returns how many
+// deliveries it handled. Deliveries claimed but not sent before ctx is
+// cancelled are released once their lease expires.
+func (b *Bot) deliverBroadcasts(ctx context.Context) (int, error) {
+	deliveries, err := b.broadcastService.ClaimDeliveries(ctx, broadcastBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, delivery := range deliveries {
+		if i > 0 {
+			if err := sleepContext(ctx, b.broadcastInterval); err != nil {
+				return i, err
+			}
+		}
+		b.deliverBroadcast(ctx, delivery)
+	}
+
+	return len(deliveries), nil
+}
+
+func (b *Bot) deliverBroadcast(ctx context.Context, delivery *domain.BroadcastDelivery) {
+	text := translate(storedLang(delivery.Language), msgBroadcast, delivery.Text)
+	sendErr := b.sendMessage(ctx, delivery.ChatID, text)
+
+	// The outcome is recorded even if the bot is stopping meanwhile.
+	recordCtx := context.WithoutCancel(ctx)
+
+	var err error
+	if sendErr == nil {
+		err = b.broadcastService.MarkDelivered(recordCtx, delivery)
+	} else if reason, permanent := deliveryFailure(sendErr); permanent {
+		err = b.broadcastService.MarkUndeliverable(recordCtx, delivery, reason, sendErr.Error())
+	} else {
+		err = b.broadcastService.RetryLater(recordCtx, delivery, sendErr.Error())
+
+		// The API client already waited out 429s; if Telegram still asks
+		// to slow down, pause the whole queue.
+		var apiErr *APIError
+		if errors.As(sendErr, &apiErr) && apiErr.RetryAfter > 0 {
+			sleepContext(ctx, apiErr.RetryAfter)
+		}
+	}
+
+	if err != nil {
+		b.logger.Error("Failed to record broadcast delivery",
+			zap.Error(err),
+			zap.Int64("broadcast_id", delivery.BroadcastID),
+			zap.Int64("recipient_id", delivery.RecipientID),
+		)
+	}
+}
+
+// deliveryFailure classifies a sendMessage error. Permanent failures are
+// not retried: the user blocked the bot, deleted their account or the chat
+// no longer exists.
+func deliveryFailure(err error) (reason string, permanent bool) {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return domain.DeliveryFailureError, false
+	}
+
+	description := strings.ToLower(apiErr.Description)
+	switch apiErr.StatusCode {
+	case http.StatusForbidden:
+		if strings.Contains(description, "deactivated") {
+			return domain.DeliveryFailureDeactivated, true
+		}
+		return domain.DeliveryFailureBlocked, true
+	case http.StatusBadRequest:
+		if strings.Contains(description, "chat not found") {
+			return domain.DeliveryFailureChatNotFound, true
+		}
+		return domain.DeliveryFailureRejected, true
+	}
+	return domain.DeliveryFailureError, false
+}
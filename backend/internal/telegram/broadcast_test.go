@@ -0,0 +1,119 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"yardpass/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeliverBroadcasts(t *testing.T) {
+	h := newHarness(t)
+	en := "en"
+
+	blocked := h.broadcasts.queue(3003, "Water is off", nil)
+	ru := h.broadcasts.queue(residentTelegramID, "Отключение воды", nil)
+	english := h.broadcasts.queue(4004, "Water is off", &en)
+	h.fake.FailNext("sendMessage", http.StatusForbidden, 0)
+
+	handled, err := h.bot.deliverBroadcasts(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, handled)
+
+	rec := h.broadcasts.recipient(blocked)
+	assert.Equal(t, domain.RecipientStatusFailed, rec.Status)
+	require.NotNil(t, rec.FailureReason)
+	assert.Equal(t, domain.DeliveryFailureBlocked, *rec.FailureReason)
+
+	assert.Equal(t, domain.RecipientStatusSent, h.broadcasts.recipient(ru).Status)
+	assert.Equal(t, "📢 Объявление\n\nОтключение воды", h.last(residentTelegramID).Text)
+
+	assert.Equal(t, domain.RecipientStatusSent, h.broadcasts.recipient(english).Status)
+	assert.Equal(t, "📢 Announcement\n\nWater is off", h.last(4004).Text)
+}
+
+func TestDeliverBroadcasts_RetriesTransientErrors(t *testing.T) {
+	h := newHarness(t)
+
+	id := h.broadcasts.queue(residentTelegramID, "Уборка двора", nil)
+	// The API client retries server errors itself; fail every attempt.
+	for i := 0; i <= apiMaxRetries; i++ {
+		h.fake.FailNext("sendMessage", http.StatusBadGateway, 0)
+	}
+
+	_, err := h.bot.deliverBroadcasts(context.Background())
+	require.NoError(t, err)
+
+	rec := h.broadcasts.recipient(id)
+	assert.Equal(t, domain.RecipientStatusPending, rec.Status)
+	assert.Equal(t, 1, rec.Attempts)
+	assert.Nil(t, rec.FailureReason)
+
+	_, err = h.bot.deliverBroadcasts(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, domain.RecipientStatusSent, h.broadcasts.recipient(id).Status)
+}
+
+func TestBot_DeliversQueuedBroadcasts(t *testing.T) {
+	h := newHarness(t)
+	h.broadcasts.queue(residentTelegramID, "Собрание жильцов в 19:00", nil)
+
+	require.NoError(t, h.bot.Start(context.Background()))
+	messages, ok := h.fake.WaitMessages(residentTelegramID, 1, 5*time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, h.bot.Stop(ctx))
+
+	require.True(t, ok, "broadcast was not delivered")
+	assert.Contains(t, messages[0].Text, "Собрание жильцов")
+}
+
+func TestDeliveryFailure(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		reason    string
+		permanent bool
+	}{
+		{"blocked", &APIError{StatusCode: 403, Description: "Forbidden: bot was blocked by the user"}, domain.DeliveryFailureBlocked, true},
+		{"deactivated", &APIError{StatusCode: 403, Description: "Forbidden: user is deactivated"}, domain.DeliveryFailureDeactivated, true},
+		{"chat not found", &APIError{StatusCode: 400, Description: "Bad Request: chat not found"}, domain.DeliveryFailureChatNotFound, true},
+		{"bad request", &APIError{StatusCode: 400, Description: "Bad Request: message is too long"}, domain.DeliveryFailureRejected, true},
+		{"flood", &APIError{StatusCode: 429, Description: "Too Many Requests: retry after 5"}, domain.DeliveryFailureError, false},
+		{"server error", &APIError{StatusCode: 502, Description: "Bad Gateway"}, domain.DeliveryFailureError, false},
+		{"network", errors.New("failed to send request: connection refused"), domain.DeliveryFailureError, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, permanent := deliveryFailure(tt.err)
+			assert.Equal(t, tt.reason, reason)
+			assert.Equal(t, tt.permanent, permanent)
+		})
+	}
+}
+
+func TestReleaseDeliveries_KeepsLiveClaims(t *testing.T) {
+	h := newHarness(t)
+	ctx := context.Background()
+
+	id := h.broadcasts.queue(residentTelegramID, "Уборка двора", nil)
+	deliveries, err := h.bot.broadcastService.ClaimDeliveries(ctx, broadcastBatchSize)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+
+	// Another bot starting up leaves a batch that is still being sent.
+	require.NoError(t, h.bot.broadcastService.ReleaseDeliveries(ctx, broadcastClaimLease))
+	assert.Equal(t, domain.RecipientStatusSending, h.broadcasts.recipient(id).Status)
+
+	// A claim older than the lease was left by a bot that stopped.
+	require.NoError(t, h.bot.broadcastService.ReleaseDeliveries(ctx, -time.Second))
+	assert.Equal(t, domain.RecipientStatusPending, h.broadcasts.recipient(id).Status)
+}
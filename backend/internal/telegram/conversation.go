@@ -0,0 +1,357 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"yardpass/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+const (
+	callbackBack     = "conv_back"
+	callbackCancel   = "conv_cancel"
+	callbackSchedule = "schedule"
+	callbackConfirm  = "confirm_window"
+	callbackStartNow = "start_now"
+)
+
+func (b *Bot) durationKeyboardRows(ctx context.Context) [][]map[string]interface{} {
+	return [][]map[string]interface{}{
+		{
+			{"text": b.t(ctx, msgDuration1h), "callback_data": "duration_1h"},
+			{"text": b.t(ctx, msgDuration2h), "callback_data": "duration_2h"},
+		},
+		{
+			{"text": b.t(ctx, msgDuration4h), "callback_data": "duration_4h"},
+			{"text": b.t(ctx, msgDurationUntil), "callback_data": "duration_custom"},
+		},
+		{
+			{"text": b.t(ctx, msgSchedule), "callback_data": callbackSchedule},
+		},
+	}
+}
+
+// loadConversation returns the user's conversation or nil. Storage errors
+// are logged and reported to the chat.
+func (b *Bot) loadConversation(ctx context.Context, chatID int64, userID int64) *Conversation {
+	conv, err := b.states.Get(ctx, userID)
+	if err != nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgSessionError))
+		b.logger.Error("failed to load conversation", zap.Error(err), zap.Int64("user_id", userID))
+		return nil
+	}
+	return conv
+}
+
+func (b *Bot) saveConversation(ctx context.Context, chatID int64, userID int64, conv *Conversation) bool {
+	if err := b.states.Set(ctx, userID, conv); err != nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgSessionError))
+		b.logger.Error("failed to save conversation", zap.Error(err), zap.Int64("user_id", userID))
+		return false
+	}
+	return true
+}
+
+func (b *Bot) clearConversation(ctx context.Context, userID int64) {
+	if err := b.states.Delete(ctx, userID); err != nil {
+		b.logger.Error("failed to delete conversation", zap.Error(err), zap.Int64("user_id", userID))
+	}
+}
+
+// advance fires the event, stores the conversation and shows the prompt of
+// the new step. Events that do not fit the current step are ignored with a
+// hint, so stale buttons cannot break the dialog.
+func (b *Bot) advance(ctx context.Context, chatID int64, userID int64, conv *Conversation, event Event) {
+	if err := conv.Fire(event); err != nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgActionUnavailable))
+		b.logger.Debug("rejected conversation event", zap.Error(err), zap.Int64("user_id", userID))
+		return
+	}
+
+	if conv.Done() {
+		b.clearConversation(ctx, userID)
+		if conv.IsVisit() {
+			b.submitVisitRequest(ctx, chatID, userID, conv)
+			return
+		}
+		b.createPassFromConversation(ctx, chatID, userID, conv)
+		return
+	}
+
+	if b.saveConversation(ctx, chatID, userID, conv) {
+		b.promptStep(ctx, chatID, conv)
+	}
+}
+
+// finishStep fires the event that ends a flow whose last step does the work
+// itself, before the work is done. A conversation the event does not fit is
+// stale: the input is dropped with the conversation and false is returned.
+// The step clears the conversation once its work succeeds.
+func (b *Bot) finishStep(ctx context.Context, chatID int64, userID int64, conv *Conversation, event Event) bool {
+	if err := conv.Fire(event); err != nil {
+		b.logger.Debug("dropped stale conversation input", zap.Error(err), zap.Int64("user_id", userID))
+		b.clearConversation(ctx, userID)
+		b.sendMessage(ctx, chatID, b.t(ctx, msgUnknownState))
+		return false
+	}
+	return true
+}
+
+func (b *Bot) promptStep(ctx context.Context, chatID int64, conv *Conversation) {
+	var text string
+	var rows [][]map[string]interface{}
+	now := time.Now().In(b.location)
+
+	switch conv.Step {
+	case StepGuestType:
+		text = b.t(ctx, msgChooseGuestType)
+		rows = [][]map[string]interface{}{
+			{{"text": b.t(ctx, msgGuestByCar), "callback_data": "guest_car"}},
+			{{"text": b.t(ctx, msgGuestOnFoot), "callback_data": "guest_pedestrian"}},
+			{
+				{"text": b.t(ctx, msgGuestTaxi), "callback_data": "guest_taxi"},
+				{"text": b.t(ctx, msgGuestDelivery), "callback_data": "guest_delivery"},
+			},
+		}
+	case StepCarPlate:
+		text = b.t(ctx, msgEnterCarPlate)
+		if rows = b.quickPickRows(ctx, conv); len(rows) > 0 {
+			text = b.t(ctx, msgPickGuestOrEnterPlate)
+		}
+	case StepDuration:
+		text = b.t(ctx, msgChooseDuration)
+		rows = b.durationKeyboardRows(ctx)
+	case StepCustomTime:
+		text = b.t(ctx, msgChooseEndOrType)
+		rows = timeSlotRows(langFrom(ctx), endSlots(now, b.maxPassDuration(ctx, conv)), now, 4)
+	case StepStartDate, StepVisitStartDate:
+		text = b.t(ctx, msgChooseStartDate)
+		if conv.Step == StepVisitStartDate {
+			text = b.t(ctx, msgVisitChooseStart)
+			rows = [][]map[string]interface{}{
+				{{"text": b.t(ctx, msgStartNow), "callback_data": callbackStartNow}},
+			}
+		}
+		month := now
+		if conv.CalendarMonth != "" {
+			if m, err := time.ParseInLocation(monthLayout, conv.CalendarMonth, b.location); err == nil {
+				month = m
+			}
+		}
+		rows = append(rows, calendarRows(langFrom(ctx), month, now)...)
+	case StepStartTime, StepVisitStartTime:
+		date, err := time.ParseInLocation(dateLayout, conv.StartDate, b.location)
+		if err != nil {
+			return
+		}
+		slots := startSlots(date, now)
+		if len(slots) == 0 {
+			text = b.t(ctx, msgNoTimeLeftOnDate)
+		} else {
+			text = b.t(ctx, msgChooseStartTime, date.Format("02.01.2006"))
+			rows = timeSlotRows(langFrom(ctx), slots, date, 6)
+		}
+	case StepEndTime:
+		if conv.ValidFrom == nil {
+			return
+		}
+		from := conv.ValidFrom.In(b.location)
+		text = b.t(ctx, msgChooseEndTime, b.formatLocalTime(from))
+		rows = timeSlotRows(langFrom(ctx), endSlots(from, b.maxPassDuration(ctx, conv)), from, 4)
+	case StepConfirm:
+		if conv.ValidFrom == nil || conv.ValidTo == nil {
+			return
+		}
+		text = b.t(ctx, msgConfirmWindow,
+			b.formatLocalTime(*conv.ValidFrom),
+			b.formatLocalTime(*conv.ValidTo),
+		)
+		rows = [][]map[string]interface{}{
+			{{"text": b.t(ctx, msgConfirm), "callback_data": callbackConfirm}},
+		}
+	case StepGuestName:
+		text = b.t(ctx, msgEnterGuestName)
+		if conv.SavedGuestName != nil {
+			rows = [][]map[string]interface{}{
+				{{"text": fmt.Sprintf("👤 %s", *conv.SavedGuestName), "callback_data": callbackSavedName}},
+			}
+		}
+	case StepVisitApartment:
+		text = b.t(ctx, msgVisitEnterApartment)
+	case StepVisitCarPlate:
+		text = b.t(ctx, msgVisitEnterCarPlate)
+		rows = [][]map[string]interface{}{
+			{{"text": b.t(ctx, msgGuestOnFoot), "callback_data": "guest_pedestrian"}},
+		}
+	case StepVisitDuration:
+		text = b.t(ctx, msgChooseDuration)
+		if conv.ValidFrom != nil {
+			text = b.t(ctx, msgVisitChooseDuration, b.formatLocalTime(*conv.ValidFrom))
+		}
+		rows = [][]map[string]interface{}{
+			{
+				{"text": b.t(ctx, msgDuration1h), "callback_data": "duration_1h"},
+				{"text": b.t(ctx, msgDuration2h), "callback_data": "duration_2h"},
+				{"text": b.t(ctx, msgDuration4h), "callback_data": "duration_4h"},
+			},
+		}
+	case StepEventDetails:
+		text = b.t(ctx, msgEventEnterDetails)
+	case StepEventGuestList:
+		if conv.ValidFrom == nil || conv.ValidTo == nil {
+			return
+		}
+		text = b.t(ctx, msgEventSendGuestList, conv.EventName, b.formatLocalTime(*conv.ValidFrom), b.formatLocalTime(*conv.ValidTo))
+	case StepExceptionReason:
+		text = b.t(ctx, msgExceptionEnterReason)
+	case StepVehiclePlate:
+		text = b.t(ctx, msgVehicleEnterPlate)
+	default:
+		return
+	}
+
+	nav := []map[string]interface{}{}
+	if len(conv.History) > 0 {
+		nav = append(nav, map[string]interface{}{"text": b.t(ctx, msgBack), "callback_data": callbackBack})
+	}
+	nav = append(nav, map[string]interface{}{"text": b.t(ctx, msgCancel), "callback_data": callbackCancel})
+	rows = append(rows, nav)
+
+	b.sendMessageWithKeyboard(ctx, chatID, text, map[string]interface{}{
+		"inline_keyboard": rows,
+	})
+}
+
+// maxPassDuration is the longest pass the building rules allow for the
+// conversation's apartment and category.
+func (b *Bot) maxPassDuration(ctx context.Context, conv *Conversation) time.Duration {
+	rule, err := b.passService.RulesForApartment(ctx, conv.ApartmentID)
+	if err != nil {
+		b.logger.Error("failed to get rules", zap.Error(err), zap.Int64("apartment_id", conv.ApartmentID))
+		return 24 * time.Hour
+	}
+	return time.Duration(rule.MaxDurationHours(passCategory(conv))) * time.Hour
+}
+
+// checkWindow validates the chosen validity window against the building
+// rules and explains the problem to the user.
+func (b *Bot) checkWindow(ctx context.Context, chatID int64, conv *Conversation, validFrom, validTo time.Time) bool {
+	if err := b.passService.CheckPassWindow(ctx, conv.ApartmentID, passCategory(conv), validFrom, validTo); err != nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgWindowRejected, err.Error()))
+		return false
+	}
+	return true
+}
+
+// passCategory is the category of the pass the conversation creates.
+func passCategory(conv *Conversation) string {
+	if conv.Category == "" {
+		return domain.PassCategoryGuest
+	}
+	return conv.Category
+}
+
+func (b *Bot) handleBack(ctx context.Context, chatID int64, userID int64) {
+	conv := b.loadConversation(ctx, chatID, userID)
+	if conv == nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgNoActiveAction))
+		return
+	}
+
+	if !conv.Back() {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgFirstStep))
+		b.promptStep(ctx, chatID, conv)
+		return
+	}
+
+	if b.saveConversation(ctx, chatID, userID, conv) {
+		b.promptStep(ctx, chatID, conv)
+	}
+}
+
+func (b *Bot) handleCancel(ctx context.Context, chatID int64, userID int64) {
+	conv := b.loadConversation(ctx, chatID, userID)
+	if conv == nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgNoActiveAction))
+		return
+	}
+
+	b.clearConversation(ctx, userID)
+	b.sendMessage(ctx, chatID, b.t(ctx, msgPassCreationCancelled))
+}
+
+// handlePickerCallback serves the calendar and time slot buttons of
+// passes and visits. A time slot means the start or the end of the pass
+// depending on the step.
+func (b *Bot) handlePickerCallback(ctx context.Context, cb CallbackQuery) {
+	chatID := cb.Message.Chat.ID
+	userID := cb.From.ID
+
+	conv := b.loadConversation(ctx, chatID, userID)
+	if conv == nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgSessionExpired))
+		return
+	}
+
+	switch {
+	case strings.HasPrefix(cb.Data, calendarMonthPrefix) && (conv.Step == StepStartDate || conv.Step == StepVisitStartDate):
+		month, err := time.ParseInLocation(monthLayout, strings.TrimPrefix(cb.Data, calendarMonthPrefix), b.location)
+		if err != nil {
+			return
+		}
+		conv.CalendarMonth = month.Format(monthLayout)
+		if b.saveConversation(ctx, chatID, userID, conv) {
+			b.promptStep(ctx, chatID, conv)
+		}
+
+	case strings.HasPrefix(cb.Data, calendarDatePrefix):
+		date, err := time.ParseInLocation(dateLayout, strings.TrimPrefix(cb.Data, calendarDatePrefix), b.location)
+		if err != nil {
+			return
+		}
+		now := time.Now().In(b.location)
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, b.location)
+		if date.Before(today) || date.After(today.AddDate(0, 0, maxScheduleDays)) {
+			b.sendMessage(ctx, chatID, b.t(ctx, msgDateOutOfRange, maxScheduleDays))
+			return
+		}
+		conv.StartDate = date.Format(dateLayout)
+		b.advance(ctx, chatID, userID, conv, EventStartDate)
+
+	case strings.HasPrefix(cb.Data, timeSlotPrefix):
+		unix, err := strconv.ParseInt(strings.TrimPrefix(cb.Data, timeSlotPrefix), 10, 64)
+		if err != nil {
+			return
+		}
+		slot := time.Unix(unix, 0).UTC()
+
+		switch conv.Step {
+		case StepCustomTime:
+			b.setCustomTime(ctx, chatID, userID, conv, slot)
+		case StepStartTime, StepVisitStartTime:
+			if !slot.After(time.Now()) {
+				b.sendMessage(ctx, chatID, b.t(ctx, msgTimePassed))
+				return
+			}
+			conv.ValidFrom = &slot
+			conv.ValidTo = nil
+			b.advance(ctx, chatID, userID, conv, EventStartTime)
+		case StepEndTime:
+			if conv.ValidFrom == nil || !b.checkWindow(ctx, chatID, conv, *conv.ValidFrom, slot) {
+				return
+			}
+			conv.ValidTo = &slot
+			b.advance(ctx, chatID, userID, conv, EventEndTime)
+		default:
+			b.sendMessage(ctx, chatID, b.t(ctx, msgActionUnavailable))
+		}
+
+	default:
+		b.sendMessage(ctx, chatID, b.t(ctx, msgActionUnavailable))
+	}
+}
@@ -0,0 +1,128 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+var errDispatcherClosed = errors.New("dispatcher is closed")
+
+// dispatcher processes updates on a fixed pool of workers. Updates of one
+// chat always land on the same worker, so they are handled in the order they
+// were received while different chats proceed in parallel. Each worker has a
+// bounded queue; submit blocks when it is full.
+type dispatcher struct {
+	queues []chan Update
+	handle func(ctx context.Context, update Update)
+	logger *zap.Logger
+
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+func newDispatcher(workers, queueSize int, handle func(ctx context.Context, update Update), logger *zap.Logger) *dispatcher {
+	if workers < 1 {
+		workers = 1
+	}
+	if queueSize < 1 {
+		queueSize = 1
+	}
+
+	d := &dispatcher{
+		queues: make([]chan Update, workers),
+		handle: handle,
+		logger: logger,
+	}
+	for i := range d.queues {
+		d.queues[i] = make(chan Update, queueSize)
+	}
+	return d
+}
+
+// start launches the workers. ctx is passed to the handler and should
+// outlive close so that queued updates can still be answered.
+func (d *dispatcher) start(ctx context.Context) {
+	for _, queue := range d.queues {
+		d.wg.Add(1)
+		go d.work(ctx, queue)
+	}
+}
+
+func (d *dispatcher) work(ctx context.Context, queue <-chan Update) {
+	defer d.wg.Done()
+	for update := range queue {
+		d.process(ctx, update)
+	}
+}
+
+func (d *dispatcher) process(ctx context.Context, update Update) {
+	defer func() {
+		if r := recover(); r != nil {
+			d.logger.Error("panic while processing update",
+				zap.Any("panic", r),
+				zap.Int64("update_id", update.UpdateID),
+			)
+		}
+	}()
+	d.handle(ctx, update)
+}
+
+// submit queues an update, waiting while the chat's worker is busy.
+func (d *dispatcher) submit(ctx context.Context, update Update) error {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.closed {
+		return errDispatcherClosed
+	}
+
+	queue := d.queues[uint64(updateChatID(update))%uint64(len(d.queues))]
+	select {
+	case queue <- update:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// close stops accepting updates and waits until the queued ones are
+// processed or ctx is done.
+func (d *dispatcher) close(ctx context.Context) error {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		for _, queue := range d.queues {
+			close(queue)
+		}
+	}
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func updateChatID(update Update) int64 {
+	switch {
+	case update.Message != nil && update.Message.Chat != nil:
+		return update.Message.Chat.ID
+	case update.CallbackQuery != nil && update.CallbackQuery.Message != nil && update.CallbackQuery.Message.Chat != nil:
+		return update.CallbackQuery.Message.Chat.ID
+	case update.CallbackQuery != nil && update.CallbackQuery.From != nil:
+		return update.CallbackQuery.From.ID
+	}
+	return 0
+}
@@ -0,0 +1,120 @@
+package telegram
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func messageUpdate(updateID, chatID int64) Update {
+	return Update{
+		UpdateID: updateID,
+		Message:  &Message{Chat: &Chat{ID: chatID}, From: &User{ID: chatID}},
+	}
+}
+
+func TestDispatcher_KeepsOrderPerChat(t *testing.T) {
+	var mu sync.Mutex
+	seen := make(map[int64][]int64)
+
+	d := newDispatcher(4, 10, func(ctx context.Context, update Update) {
+		mu.Lock()
+		defer mu.Unlock()
+		chatID := update.Message.Chat.ID
+		seen[chatID] = append(seen[chatID], update.UpdateID)
+	}, zap.NewNop())
+	d.start(context.Background())
+
+	ctx := context.Background()
+	for i := int64(0); i < 50; i++ {
+		for chatID := int64(1); chatID <= 5; chatID++ {
+			assert.NoError(t, d.submit(ctx, messageUpdate(i, chatID)))
+		}
+	}
+	assert.NoError(t, d.close(ctx))
+
+	for chatID := int64(1); chatID <= 5; chatID++ {
+		assert.Len(t, seen[chatID], 50)
+		for i, updateID := range seen[chatID] {
+			assert.Equal(t, int64(i), updateID)
+		}
+	}
+}
+
+func TestDispatcher_ProcessesChatsConcurrently(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan int64, 2)
+
+	d := newDispatcher(2, 1, func(ctx context.Context, update Update) {
+		started <- update.Message.Chat.ID
+		<-release
+	}, zap.NewNop())
+	d.start(context.Background())
+
+	ctx := context.Background()
+	assert.NoError(t, d.submit(ctx, messageUpdate(1, 0)))
+	assert.NoError(t, d.submit(ctx, messageUpdate(2, 1)))
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-started:
+		case <-time.After(time.Second):
+			t.Fatal("updates of different chats were not processed in parallel")
+		}
+	}
+
+	close(release)
+	assert.NoError(t, d.close(ctx))
+}
+
+func TestDispatcher_SubmitBlocksWhenQueueIsFull(t *testing.T) {
+	release := make(chan struct{})
+
+	d := newDispatcher(1, 1, func(ctx context.Context, update Update) {
+		<-release
+	}, zap.NewNop())
+	d.start(context.Background())
+
+	ctx := context.Background()
+	assert.NoError(t, d.submit(ctx, messageUpdate(1, 1)))
+	// The first update may still be waiting in the queue; the next one must
+	// fit once the worker picked it up.
+	assert.Eventually(t, func() bool {
+		return len(d.queues[0]) == 0
+	}, time.Second, time.Millisecond)
+	assert.NoError(t, d.submit(ctx, messageUpdate(2, 1)))
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, d.submit(timeoutCtx, messageUpdate(3, 1)), context.DeadlineExceeded)
+
+	close(release)
+	assert.NoError(t, d.close(ctx))
+	assert.ErrorIs(t, d.submit(ctx, messageUpdate(4, 1)), errDispatcherClosed)
+}
+
+func TestDispatcher_RecoversFromPanics(t *testing.T) {
+	var mu sync.Mutex
+	var processed []int64
+
+	d := newDispatcher(1, 10, func(ctx context.Context, update Update) {
+		if update.UpdateID == 1 {
+			panic("boom")
+		}
+		mu.Lock()
+		processed = append(processed, update.UpdateID)
+		mu.Unlock()
+	}, zap.NewNop())
+	d.start(context.Background())
+
+	ctx := context.Background()
+	assert.NoError(t, d.submit(ctx, messageUpdate(1, 1)))
+	assert.NoError(t, d.submit(ctx, messageUpdate(2, 1)))
+	assert.NoError(t, d.close(ctx))
+
+	assert.Equal(t, []int64{2}, processed)
+}
@@ -0,0 +1,81 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"yardpass/internal/domain"
+	"yardpass/internal/service"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	dynamicQRPrefix = "dynamic_qr_"
+	refreshQRPrefix = "refresh_qr_"
+)
+
+// sendPassQR sends the QR code of a pass. A static pass gets a button to
+// switch it to rotating codes, a dynamic one gets the current code with a
+// refresh button.
+func (b *Bot) sendPassQR(ctx context.Context, chatID int64, pass *domain.Pass, caption string) error {
+	var png []byte
+	var err error
+	var button map[string]interface{}
+	if pass.Dynamic {
+		png, err = b.qrGen.GenerateDynamicQR(ctx, pass.ID, pass.QRSecret, time.Now())
+		button = map[string]interface{}{"text": b.t(ctx, msgRefreshQR), "callback_data": refreshQRPrefix + pass.ID.String()}
+	} else {
+		png, err = b.qrGen.GenerateQR(ctx, pass.ID)
+		button = map[string]interface{}{"text": b.t(ctx, msgMakeQRDynamic), "callback_data": dynamicQRPrefix + pass.ID.String()}
+	}
+	if err != nil {
+		return err
+	}
+
+	keyboard := map[string]interface{}{
+		"inline_keyboard": [][]map[string]interface{}{{button}},
+	}
+	if err := b.api.SendPhotoWithKeyboard(ctx, chatID, png, caption, keyboard); err != nil {
+		b.logger.Error("failed to send photo", zap.Error(err))
+	}
+	return nil
+}
+
+// handleDynamicQRCallback switches a pass to rotating codes or sends its
+// current code.
+func (b *Bot) handleDynamicQRCallback(ctx context.Context, cb CallbackQuery) {
+	chatID := cb.Message.Chat.ID
+
+	enable := strings.HasPrefix(cb.Data, dynamicQRPrefix)
+	passID, err := uuid.Parse(strings.TrimPrefix(strings.TrimPrefix(cb.Data, dynamicQRPrefix), refreshQRPrefix))
+	if err != nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgInvalidPassID))
+		return
+	}
+
+	pass := b.ownPass(ctx, chatID, cb.From.ID, passID)
+	if pass == nil {
+		return
+	}
+
+	if enable {
+		pass, err = b.passService.EnableDynamicQR(ctx, passID, service.PassScope{})
+		if err != nil {
+			if !errors.Is(err, service.ErrPassNotActive) {
+				b.logger.Error("failed to enable dynamic QR", zap.Error(err), zap.String("pass_id", passID.String()))
+			}
+			b.sendMessage(ctx, chatID, b.t(ctx, msgDynamicQRFailed, err.Error()))
+			return
+		}
+	}
+
+	caption := b.t(ctx, msgDynamicQRCaption, b.passInfo(ctx, pass))
+	if err := b.sendPassQR(ctx, chatID, pass, caption); err != nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgDynamicQRFailed, err.Error()))
+		b.logger.Error("failed to generate QR", zap.Error(err), zap.String("pass_id", passID.String()))
+	}
+}
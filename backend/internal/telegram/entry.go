@@ -0,0 +1,140 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"yardpass/internal/domain"
+	"yardpass/internal/service"
+
+	"go.uber.org/zap"
+)
+
+const (
+	entryAllowPrefix = "entry_ok:"
+	entryDenyPrefix  = "entry_no:"
+
+	// entryRequestBatchSize is how many new guard calls are claimed at once.
+	entryRequestBatchSize = 20
+	// entryRequestPollInterval is short because a guard is waiting at the
+	// gate.
+	entryRequestPollInterval = time.Second
+)
+
+// runEntryRequests asks residents about the guard calls created by the API
+// and expires the unanswered ones until ctx is cancelled.
+func (b *Bot) runEntryRequests(ctx context.Context) {
+	defer b.wg.Done()
+
+	for {
+		if _, err := b.notifyEntryRequests(ctx); err != nil && ctx.Err() == nil {
+			b.logger.Error("Failed to notify entry requests", zap.Error(err))
+		}
+		if _, err := b.entryRequestService.ExpireOverdue(ctx); err != nil && ctx.Err() == nil {
+			b.logger.Error("Failed to expire entry requests", zap.Error(err))
+		}
+
+		if err := sleepContext(ctx, entryRequestPollInterval); err != nil {
+			b.logger.Info("Entry request notifications stopped by context")
+			return
+		}
+	}
+}
+
+// notifyEntryRequests sends one batch of new guard calls to the residents
+// and returns how many requests it handled.
+func (b *Bot) notifyEntryRequests(ctx context.Context) (int, error) {
+	requests, err := b.entryRequestService.ClaimNotifications(ctx, entryRequestBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, request := range requests {
+		approvers, err := b.entryRequestService.Approvers(ctx, request)
+		if err != nil {
+			b.logger.Error("Failed to get residents for entry request", zap.Error(err), zap.Int64("request_id", request.ID))
+			continue
+		}
+
+		for _, approver := range approvers {
+			approverCtx := withLang(ctx, residentLang(approver))
+			text := b.t(approverCtx, msgEntryCallPrompt, request.CarPlate, b.apartmentLabel(approverCtx, request.ApartmentID))
+			keyboard := map[string]interface{}{
+				"inline_keyboard": [][]map[string]interface{}{
+					{
+						{"text": b.t(approverCtx, msgEntryCallAllow), "callback_data": fmt.Sprintf("%s%d:%d", entryAllowPrefix, approver.ID, request.ID)},
+						{"text": b.t(approverCtx, msgEntryCallDeny), "callback_data": fmt.Sprintf("%s%d:%d", entryDenyPrefix, approver.ID, request.ID)},
+					},
+				},
+			}
+			if err := b.sendMessageWithKeyboard(approverCtx, approver.ChatID, text, keyboard); err != nil {
+				b.logger.Error("failed to notify resident of entry request", zap.Error(err), zap.Int64("resident_id", approver.ID))
+			}
+		}
+	}
+
+	return len(requests), nil
+}
+
+// handleEntryDecision serves the allow/deny buttons of a guard call. The
+// resident ID in the callback data is checked against the caller's own
+// resident rows.
+func (b *Bot) handleEntryDecision(ctx context.Context, cb CallbackQuery) {
+	chatID := cb.Message.Chat.ID
+
+	prefix := entryAllowPrefix
+	if strings.HasPrefix(cb.Data, entryDenyPrefix) {
+		prefix = entryDenyPrefix
+	}
+
+	residentIDStr, requestIDStr, ok := strings.Cut(strings.TrimPrefix(cb.Data, prefix), ":")
+	residentID, err1 := strconv.ParseInt(residentIDStr, 10, 64)
+	requestID, err2 := strconv.ParseInt(requestIDStr, 10, 64)
+	if !ok || err1 != nil || err2 != nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgInvalidButtonData))
+		return
+	}
+
+	residents := b.residentsFor(ctx, chatID, cb.From.ID)
+	if residents == nil {
+		return
+	}
+
+	var resident *domain.Resident
+	for _, r := range residents {
+		if r.ID == residentID {
+			resident = r
+			break
+		}
+	}
+	if resident == nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgInvalidButtonData))
+		return
+	}
+
+	var request *domain.EntryRequest
+	var err error
+	if prefix == entryAllowPrefix {
+		request, _, err = b.entryRequestService.Allow(ctx, requestID, resident)
+	} else {
+		request, err = b.entryRequestService.Deny(ctx, requestID, resident)
+	}
+
+	switch {
+	case errors.Is(err, service.ErrEntryRequestDecided):
+		b.sendMessage(ctx, chatID, b.t(ctx, msgEntryCallDecided))
+	case errors.Is(err, service.ErrEntryRequestExpired):
+		b.sendMessage(ctx, chatID, b.t(ctx, msgEntryCallExpired))
+	case err != nil:
+		b.sendMessage(ctx, chatID, b.t(ctx, msgEntryCallFailed, err.Error()))
+		b.logger.Warn("failed to decide entry request", zap.Error(err))
+	case prefix == entryAllowPrefix:
+		b.sendMessage(ctx, chatID, b.t(ctx, msgEntryCallAllowed, request.CarPlate))
+	default:
+		b.sendMessage(ctx, chatID, b.t(ctx, msgEntryCallDenied, request.CarPlate))
+	}
+}
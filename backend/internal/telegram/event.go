@@ -0,0 +1,288 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"yardpass/internal/domain"
+	"yardpass/internal/service"
+
+	"go.uber.org/zap"
+)
+
+const (
+	eventRevokePrefix = "event_revoke_"
+	eventReportPrefix = "event_report_"
+
+	// maxGuestListSize bounds the guest list files the bot downloads.
+	maxGuestListSize = 1 << 20
+
+	// maxGuestListErrors is how many row errors are listed in one reply.
+	maxGuestListErrors = 10
+)
+
+// eventWindowPattern matches "25.04 18:00-23:00", optionally with a year.
+var eventWindowPattern = regexp.MustCompile(`^(\d{1,2})\.(\d{1,2})(?:\.(\d{4}))?\s+(\d{1,2}):(\d{2})\s*[-–—]\s*(\d{1,2}):(\d{2})$`)
+
+// parseEventDetails reads "Name; DD.MM HH:MM-HH:MM". A date without a year
+// that has already passed means next year, and an end before the start
+// means the event runs past midnight.
+func parseEventDetails(text string, now time.Time) (name string, validFrom, validTo time.Time, ok bool) {
+	i := strings.LastIndexAny(text, ";\n")
+	if i < 0 {
+		return "", time.Time{}, time.Time{}, false
+	}
+
+	name = strings.TrimSpace(text[:i])
+	m := eventWindowPattern.FindStringSubmatch(strings.TrimSpace(text[i+1:]))
+	if name == "" || m == nil {
+		return "", time.Time{}, time.Time{}, false
+	}
+
+	n := make([]int, len(m))
+	for j := 1; j < len(m); j++ {
+		n[j], _ = strconv.Atoi(m[j])
+	}
+	day, month, year := n[1], n[2], n[3]
+	if m[3] == "" {
+		year = now.Year()
+	}
+	if n[4] > 23 || n[5] > 59 || n[6] > 23 || n[7] > 59 {
+		return "", time.Time{}, time.Time{}, false
+	}
+
+	validFrom = time.Date(year, time.Month(month), day, n[4], n[5], 0, 0, now.Location())
+	if validFrom.Day() != day || int(validFrom.Month()) != month {
+		return "", time.Time{}, time.Time{}, false
+	}
+	if m[3] == "" && validFrom.Before(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())) {
+		validFrom = validFrom.AddDate(1, 0, 0)
+	}
+
+	validTo = time.Date(validFrom.Year(), validFrom.Month(), validFrom.Day(), n[6], n[7], 0, 0, now.Location())
+	if !validTo.After(validFrom) {
+		validTo = validTo.AddDate(0, 0, 1)
+	}
+
+	return name, validFrom, validTo, true
+}
+
+func (b *Bot) startEvent(ctx context.Context, chatID int64, userID int64, resident *domain.Resident) {
+	conv := NewEventConversation(resident.ID, resident.ApartmentID)
+	if b.saveConversation(ctx, chatID, userID, conv) {
+		b.promptStep(ctx, chatID, conv)
+	}
+}
+
+func (b *Bot) handleEventDetails(ctx context.Context, msg Message, conv *Conversation) {
+	name, validFrom, validTo, ok := parseEventDetails(strings.TrimSpace(msg.Text), time.Now().In(b.location))
+	if !ok {
+		b.sendMessage(ctx, msg.Chat.ID, b.t(ctx, msgEventInvalidDetails))
+		return
+	}
+
+	if !b.checkWindow(ctx, msg.Chat.ID, conv, validFrom, validTo) {
+		return
+	}
+
+	validFrom, validTo = validFrom.UTC(), validTo.UTC()
+	conv.EventName = name
+	conv.ValidFrom = &validFrom
+	conv.ValidTo = &validTo
+	b.advance(ctx, msg.Chat.ID, msg.From.ID, conv, EventEventDetails)
+}
+
+// handleEventGuestList issues the event from the uploaded guest list. A
+// list with errors keeps the conversation, so that a fixed file can be
+// sent again.
+func (b *Bot) handleEventGuestList(ctx context.Context, msg Message, conv *Conversation) {
+	chatID := msg.Chat.ID
+
+	if msg.Document == nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgEventSendFile))
+		return
+	}
+	if conv.ValidFrom == nil || conv.ValidTo == nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgUnknownState))
+		b.clearConversation(ctx, msg.From.ID)
+		return
+	}
+	if msg.Document.FileSize > maxGuestListSize {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgEventFileTooLarge))
+		return
+	}
+
+	data, err := b.api.DownloadFile(ctx, msg.Document.FileID)
+	if err != nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgEventFileFailed, err.Error()))
+		b.logger.Error("failed to download guest list", zap.Error(err), zap.Int64("user_id", msg.From.ID))
+		return
+	}
+
+	guests, rowErrors, err := b.eventService.ParseGuestList(msg.Document.FileName, bytes.NewReader(data))
+	if err != nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgEventGuestListInvalid, err.Error()))
+		return
+	}
+	if len(rowErrors) > 0 {
+		b.sendGuestListErrors(ctx, chatID, rowErrors)
+		return
+	}
+
+	apartment, err := b.apartmentRepo.GetByID(ctx, conv.ApartmentID)
+	if err != nil || apartment == nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgApartmentNotFound))
+		return
+	}
+
+	if !b.finishStep(ctx, chatID, msg.From.ID, conv, EventGuestList) {
+		return
+	}
+
+	residentID := conv.ResidentID
+	event, err := b.eventService.CreateEvent(ctx, domain.CreateEventRequest{
+		BuildingID:  apartment.BuildingID,
+		ApartmentID: conv.ApartmentID,
+		Name:        conv.EventName,
+		ValidFrom:   *conv.ValidFrom,
+		ValidTo:     *conv.ValidTo,
+		Capacity:    len(guests),
+		ResidentID:  &residentID,
+		Guests:      guests,
+	})
+	if err != nil {
+		var listErr *service.GuestListError
+		if errors.As(err, &listErr) {
+			b.sendGuestListErrors(ctx, chatID, listErr.Rows)
+			return
+		}
+		b.sendMessage(ctx, chatID, b.t(ctx, msgEventCreateFailed, err.Error()))
+		return
+	}
+	b.clearConversation(ctx, msg.From.ID)
+
+	b.sendMessageWithKeyboard(ctx, chatID,
+		b.t(ctx, msgEventCreated, event.Name, b.formatLocalTime(event.ValidFrom), b.formatLocalTime(event.ValidTo), event.PassCount),
+		b.eventKeyboard(ctx, event),
+	)
+
+	document, err := b.eventService.EventPDF(ctx, event.ID, nil)
+	if err != nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgPrintFailed, err.Error()))
+		b.logger.Error("failed to print event passes", zap.Error(err), zap.Int64("event_id", event.ID))
+		return
+	}
+
+	fileName := fmt.Sprintf("event_%d.pdf", event.ID)
+	if err := b.api.SendDocument(ctx, chatID, fileName, document, b.t(ctx, msgEventPDF, event.Name)); err != nil {
+		b.logger.Error("failed to send document", zap.Error(err), zap.String("file", fileName))
+	}
+}
+
+// sendGuestListErrors lists the rows to fix, the first maxGuestListErrors of
+// them.
+func (b *Bot) sendGuestListErrors(ctx context.Context, chatID int64, rowErrors []domain.BulkCreateError) {
+	var lines []string
+	for i, rowErr := range rowErrors {
+		if i == maxGuestListErrors {
+			lines = append(lines, "…")
+			break
+		}
+		lines = append(lines, b.t(ctx, msgEventGuestListRow, rowErr.Row, rowErr.Error))
+	}
+	b.sendMessage(ctx, chatID, b.t(ctx, msgEventGuestListErrors, strings.Join(lines, "\n")))
+}
+
+func (b *Bot) eventKeyboard(ctx context.Context, event *domain.Event) map[string]interface{} {
+	return map[string]interface{}{
+		"inline_keyboard": [][]map[string]interface{}{
+			{{"text": b.t(ctx, msgEventAttendanceButton), "callback_data": fmt.Sprintf("%s%d", eventReportPrefix, event.ID)}},
+			{{"text": b.t(ctx, msgEventRevokeButton), "callback_data": fmt.Sprintf("%s%d", eventRevokePrefix, event.ID)}},
+		},
+	}
+}
+
+// handleEventCallback serves the attendance and revoke buttons of an event.
+func (b *Bot) handleEventCallback(ctx context.Context, cb CallbackQuery) {
+	chatID := cb.Message.Chat.ID
+
+	if idStr, ok := strings.CutPrefix(cb.Data, eventRevokePrefix); ok {
+		event := b.ownEvent(ctx, chatID, cb.From.ID, idStr)
+		if event == nil {
+			return
+		}
+
+		revoked, err := b.eventService.RevokeEvent(ctx, event.ID, nil)
+		if err != nil {
+			if errors.Is(err, service.ErrEventRevoked) {
+				b.sendMessage(ctx, chatID, b.t(ctx, msgEventAlreadyRevoked))
+				return
+			}
+			b.sendMessage(ctx, chatID, b.t(ctx, msgError, err.Error()))
+			return
+		}
+
+		b.sendMessage(ctx, chatID, b.t(ctx, msgEventRevoked, event.Name, revoked))
+		return
+	}
+
+	event := b.ownEvent(ctx, chatID, cb.From.ID, strings.TrimPrefix(cb.Data, eventReportPrefix))
+	if event == nil {
+		return
+	}
+
+	report, err := b.eventService.Attendance(ctx, event.ID, nil)
+	if err != nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgError, err.Error()))
+		return
+	}
+
+	lines := make([]string, 0, len(report.Guests))
+	for _, guest := range report.Guests {
+		info := b.passInfo(ctx, &domain.Pass{CarPlate: guest.CarPlate, GuestName: guest.GuestName})
+		if guest.FirstEntryAt != nil {
+			lines = append(lines, fmt.Sprintf("✅ %s — %s", info, b.formatLocalTime(*guest.FirstEntryAt)))
+		} else {
+			lines = append(lines, "▫️ "+info)
+		}
+	}
+
+	b.sendMessage(ctx, chatID, b.t(ctx, msgEventAttendance, event.Name, report.Attended, len(report.Guests), strings.Join(lines, "\n")))
+}
+
+// ownEvent returns the event of the button if the user issued it or is the
+// primary resident of its apartment.
+func (b *Bot) ownEvent(ctx context.Context, chatID int64, userID int64, idStr string) *domain.Event {
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgInvalidButtonData))
+		return nil
+	}
+
+	event, err := b.eventService.GetEvent(ctx, id, nil)
+	if err != nil {
+		if errors.Is(err, service.ErrEventNotFound) {
+			b.sendMessage(ctx, chatID, b.t(ctx, msgEventNotFound))
+			return nil
+		}
+		b.sendMessage(ctx, chatID, b.t(ctx, msgError, err.Error()))
+		return nil
+	}
+
+	residents := b.residentsFor(ctx, chatID, userID)
+	for _, r := range residents {
+		if (event.ResidentID != nil && r.ID == *event.ResidentID) || (r.Role == "primary" && r.ApartmentID == event.ApartmentID) {
+			return event
+		}
+	}
+	if residents != nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgEventNotFound))
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"yardpass/internal/domain"
+	"yardpass/internal/service"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// passExceptionBatchSize is how many decisions are delivered at once.
+	passExceptionBatchSize = 20
+	// passExceptionPollInterval is how often the admins' decisions are
+	// checked; nobody waits at the gate for them.
+	passExceptionPollInterval = 10 * time.Second
+)
+
+// exceptionRuleTexts names the rules residents can ask an exception to.
+var exceptionRuleTexts = map[string]msgKey{
+	domain.PassRuleMaxDuration: msgExceptionRuleMaxDuration,
+	domain.PassRuleDailyLimit:  msgExceptionRuleDailyLimit,
+}
+
+// offerPassException keeps the pass the rules rejected with err and asks the
+// resident for a reason to send to the admins. It reports false if no
+// exception can be made to the error.
+func (b *Bot) offerPassException(ctx context.Context, chatID int64, userID int64, conv *Conversation, req domain.CreatePassRequest, err error) bool {
+	rule := service.ExemptRule(err)
+	if rule == "" {
+		return false
+	}
+
+	exception := &Conversation{
+		Step:          StepExceptionReason,
+		ResidentID:    conv.ResidentID,
+		ApartmentID:   req.ApartmentID,
+		IsPedestrian:  conv.IsPedestrian,
+		CarPlate:      conv.CarPlate,
+		ValidFrom:     &req.ValidFrom,
+		ValidTo:       &req.ValidTo,
+		GuestName:     req.GuestName,
+		Category:      conv.Category,
+		ExceptionRule: rule,
+	}
+	if b.saveConversation(ctx, chatID, userID, exception) {
+		b.promptStep(ctx, chatID, exception)
+	}
+	return true
+}
+
+// handleExceptionReason submits the exception with the resident's reason
+// and lets the building admins know.
+func (b *Bot) handleExceptionReason(ctx context.Context, msg Message, conv *Conversation) {
+	chatID := msg.Chat.ID
+
+	var resident *domain.Resident
+	for _, r := range b.residentsFor(ctx, chatID, msg.From.ID) {
+		if r.ID == conv.ResidentID {
+			resident = r
+			break
+		}
+	}
+	if resident == nil || conv.ValidFrom == nil || conv.ValidTo == nil {
+		b.clearConversation(ctx, msg.From.ID)
+		b.sendMessage(ctx, chatID, b.t(ctx, msgApartmentNotFoundRestart))
+		return
+	}
+
+	req := domain.CreatePassRequest{
+		ApartmentID: resident.ApartmentID,
+		ResidentID:  &resident.ID,
+		GuestName:   conv.GuestName,
+		ValidFrom:   *conv.ValidFrom,
+		ValidTo:     *conv.ValidTo,
+		Category:    conv.Category,
+	}
+	if !conv.IsPedestrian {
+		req.CarPlate = &conv.CarPlate
+	}
+
+	if !b.finishStep(ctx, chatID, msg.From.ID, conv, EventExceptionReason) {
+		return
+	}
+
+	exception, admins, err := b.exceptionService.Submit(ctx, req, conv.ExceptionRule, msg.Text)
+	switch {
+	case errors.Is(err, service.ErrPassExceptionReasonRequired), errors.Is(err, service.ErrPassExceptionReasonTooLong):
+		b.sendMessage(ctx, chatID, b.t(ctx, msgExceptionReasonInvalid, err.Error()))
+		return
+	case errors.Is(err, service.ErrTooManyPassExceptions):
+		b.clearConversation(ctx, msg.From.ID)
+		b.sendMessage(ctx, chatID, b.t(ctx, msgExceptionTooMany))
+		return
+	case err != nil:
+		b.clearConversation(ctx, msg.From.ID)
+		b.sendMessage(ctx, chatID, b.t(ctx, msgExceptionFailed, err.Error()))
+		b.logger.Error("failed to submit pass exception", zap.Error(err), zap.Int64("resident_id", resident.ID))
+		return
+	}
+
+	b.clearConversation(ctx, msg.From.ID)
+	b.sendMessage(ctx, chatID, b.t(ctx, msgExceptionSubmitted, exception.ID))
+
+	// Staff accounts have no language of their own.
+	adminCtx := withLang(ctx, defaultLang)
+	for _, admin := range admins {
+		guest := b.t(adminCtx, msgPedestrianGuest)
+		if exception.CarPlate != nil {
+			guest = *exception.CarPlate
+		}
+		text := b.t(adminCtx, msgExceptionAdminNotice,
+			exception.ID,
+			b.apartmentLabel(adminCtx, exception.ApartmentID),
+			guest,
+			b.formatLocalTime(exception.ValidFrom),
+			b.formatLocalTime(exception.ValidTo),
+			b.t(adminCtx, exceptionRuleTexts[exception.Rule]),
+			exception.Reason,
+		)
+		if err := b.sendMessage(adminCtx, *admin.TelegramID, text); err != nil {
+			b.logger.Error("failed to notify admin of pass exception", zap.Error(err), zap.Int64("user_id", admin.ID))
+		}
+	}
+}
+
+// runPassExceptions tells residents about the admins' decisions until ctx
+// is cancelled.
+func (b *Bot) runPassExceptions(ctx context.Context) {
+	defer b.wg.Done()
+
+	for {
+		if _, err := b.notifyPassExceptionDecisions(ctx); err != nil && ctx.Err() == nil {
+			b.logger.Error("Failed to deliver pass exception decisions", zap.Error(err))
+		}
+
+		if err := sleepContext(ctx, passExceptionPollInterval); err != nil {
+			b.logger.Info("Pass exception notifications stopped by context")
+			return
+		}
+	}
+}
+
+// notifyPassExceptionDecisions sends one batch of decisions to the residents
+// and returns how many it handled. An approval comes with the pass's QR code.
+func (b *Bot) notifyPassExceptionDecisions(ctx context.Context) (int, error) {
+	exceptions, err := b.exceptionService.ClaimDecisions(ctx, passExceptionBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, exception := range exceptions {
+		resident, err := b.residentRepo.GetByID(ctx, exception.ResidentID)
+		if err != nil || resident == nil {
+			b.logger.Error("Failed to get resident of pass exception", zap.Error(err), zap.Int64("exception_id", exception.ID))
+			continue
+		}
+
+		residentCtx := withLang(ctx, residentLang(resident))
+		text := b.t(residentCtx, msgExceptionRejected, exception.ID)
+		if exception.Status == domain.PassExceptionStatusApproved {
+			text = b.t(residentCtx, msgExceptionApproved, exception.ID)
+		}
+		if exception.Comment != nil && *exception.Comment != "" {
+			text += b.t(residentCtx, msgExceptionComment, *exception.Comment)
+		}
+		if err := b.sendMessage(residentCtx, resident.ChatID, text); err != nil {
+			b.logger.Error("failed to notify resident of pass exception", zap.Error(err), zap.Int64("resident_id", resident.ID))
+			continue
+		}
+
+		if exception.Status != domain.PassExceptionStatusApproved {
+			continue
+		}
+		pass, err := b.exceptionService.Pass(ctx, exception)
+		if err != nil || pass == nil {
+			b.logger.Error("Failed to get pass of pass exception", zap.Error(err), zap.Int64("exception_id", exception.ID))
+			continue
+		}
+		if err := b.sendPassQR(residentCtx, resident.ChatID, pass, b.passCaption(residentCtx, pass)); err != nil {
+			b.logger.Error("failed to send pass of pass exception", zap.Error(err), zap.String("pass_id", pass.ID.String()))
+		}
+	}
+
+	return len(exceptions), nil
+}
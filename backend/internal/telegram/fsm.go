@@ -0,0 +1,235 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Step is a state of the pass creation conversation.
+type Step string
+
+const (
+	StepGuestType  Step = "waiting_guest_type"
+	StepCarPlate   Step = "waiting_car_plate"
+	StepDuration   Step = "waiting_duration"
+	StepCustomTime Step = "waiting_custom_time"
+	StepStartDate  Step = "waiting_start_date"
+	StepStartTime  Step = "waiting_start_time"
+	StepEndTime    Step = "waiting_end_time"
+	StepConfirm    Step = "waiting_confirm"
+	StepGuestName  Step = "waiting_guest_name"
+	StepDone       Step = "done"
+
+	// Steps of a guest asking a resident for a pass.
+	StepVisitApartment Step = "waiting_visit_apartment"
+	StepVisitCarPlate  Step = "waiting_visit_car_plate"
+	StepVisitStartDate Step = "waiting_visit_start_date"
+	StepVisitStartTime Step = "waiting_visit_start_time"
+	StepVisitDuration  Step = "waiting_visit_duration"
+
+	// Steps of a resident issuing the passes of an event.
+	StepEventDetails   Step = "waiting_event_details"
+	StepEventGuestList Step = "waiting_event_guest_list"
+
+	// StepExceptionReason asks why a pass the rules rejected is needed.
+	StepExceptionReason Step = "waiting_exception_reason"
+
+	// StepVehiclePlate asks for a resident's car to register.
+	StepVehiclePlate Step = "waiting_vehicle_plate"
+)
+
+// Event is an input that moves the conversation from one step to another.
+type Event string
+
+const (
+	EventGuestCar        Event = "guest_car"
+	EventGuestPedestrian Event = "guest_pedestrian"
+	EventCarPlate        Event = "car_plate"
+	EventDurationPreset  Event = "duration_preset"
+	EventDurationCustom  Event = "duration_custom"
+	EventCustomTime      Event = "custom_time"
+	EventSchedule        Event = "schedule"
+	EventStartNow        Event = "start_now"
+	EventStartDate       Event = "start_date"
+	EventStartTime       Event = "start_time"
+	EventEndTime         Event = "end_time"
+	EventConfirm         Event = "confirm"
+	EventGuestName       Event = "guest_name"
+	EventApartment       Event = "apartment"
+	EventEventDetails    Event = "event_details"
+	EventGuestList       Event = "guest_list"
+	EventExceptionReason Event = "exception_reason"
+	EventVehiclePlate    Event = "vehicle_plate"
+)
+
+var ErrInvalidTransition = errors.New("invalid conversation transition")
+
+// transitions lists every allowed move of the conversation. Inputs that have
+// no entry for the current step (e.g. a button from an outdated message) are
+// rejected by Fire.
+var transitions = map[Step]map[Event]Step{
+	StepGuestType: {
+		EventGuestCar:        StepCarPlate,
+		EventGuestPedestrian: StepDuration,
+	},
+	StepCarPlate: {
+		EventCarPlate: StepDuration,
+	},
+	StepDuration: {
+		EventDurationPreset: StepGuestName,
+		EventDurationCustom: StepCustomTime,
+		EventSchedule:       StepStartDate,
+	},
+	StepCustomTime: {
+		EventCustomTime: StepGuestName,
+	},
+	StepStartDate: {
+		EventStartDate: StepStartTime,
+	},
+	StepStartTime: {
+		EventStartTime: StepEndTime,
+	},
+	StepEndTime: {
+		EventEndTime: StepConfirm,
+	},
+	StepConfirm: {
+		EventConfirm: StepGuestName,
+	},
+	StepGuestName: {
+		EventGuestName: StepDone,
+	},
+	StepVisitApartment: {
+		EventApartment: StepVisitCarPlate,
+	},
+	StepVisitCarPlate: {
+		EventCarPlate:        StepVisitStartDate,
+		EventGuestPedestrian: StepVisitStartDate,
+	},
+	StepVisitStartDate: {
+		EventStartNow:  StepVisitDuration,
+		EventStartDate: StepVisitStartTime,
+	},
+	StepVisitStartTime: {
+		EventStartTime: StepVisitDuration,
+	},
+	StepVisitDuration: {
+		EventDurationPreset: StepDone,
+	},
+	StepEventDetails: {
+		EventEventDetails: StepEventGuestList,
+	},
+	StepEventGuestList: {
+		EventGuestList: StepDone,
+	},
+	StepExceptionReason: {
+		EventExceptionReason: StepDone,
+	},
+	StepVehiclePlate: {
+		EventVehiclePlate: StepDone,
+	},
+}
+
+// Conversation is the serializable state of a pass creation dialog.
+type Conversation struct {
+	Step    Step   `json:"step"`
+	History []Step `json:"history,omitempty"`
+	// ResidentID is the resident issuing the pass, zero for a guest's
+	// request.
+	ResidentID   int64         `json:"resident_id"`
+	ApartmentID  int64         `json:"apartment_id"`
+	IsPedestrian bool          `json:"is_pedestrian"`
+	CarPlate     string        `json:"car_plate,omitempty"`
+	Duration     time.Duration `json:"duration,omitempty"`
+	// ValidFrom is set only for scheduled passes and visits, others start
+	// when created.
+	ValidFrom *time.Time `json:"valid_from,omitempty"`
+	ValidTo   *time.Time `json:"valid_to,omitempty"`
+	// StartDate (YYYY-MM-DD) is the day picked in the date picker.
+	StartDate string `json:"start_date,omitempty"`
+	// CalendarMonth (YYYY-MM) is the month the date picker shows.
+	CalendarMonth string  `json:"calendar_month,omitempty"`
+	GuestName     *string `json:"guest_name,omitempty"`
+
+	// SavedGuestName is offered at the guest name step after a quick pick.
+	SavedGuestName *string `json:"saved_guest_name,omitempty"`
+	// BuildingID is set instead of ResidentID when a guest requests a pass.
+	BuildingID int64 `json:"building_id,omitempty"`
+	// EventName is collected with the window before an event's guest list.
+	EventName string `json:"event_name,omitempty"`
+	// Category is the pass category chosen with the guest type; empty is
+	// guest.
+	Category string `json:"category,omitempty"`
+	// ExceptionRule is the rule a rejected pass broke while its reason is
+	// asked.
+	ExceptionRule string `json:"exception_rule,omitempty"`
+}
+
+func NewConversation(residentID, apartmentID int64) *Conversation {
+	return &Conversation{
+		Step:        StepGuestType,
+		ResidentID:  residentID,
+		ApartmentID: apartmentID,
+	}
+}
+
+// NewVisitConversation starts a guest's request for a pass to an apartment
+// of the building.
+func NewVisitConversation(buildingID int64) *Conversation {
+	return &Conversation{
+		Step:       StepVisitApartment,
+		BuildingID: buildingID,
+	}
+}
+
+// NewEventConversation starts a resident's event with a guest list.
+func NewEventConversation(residentID, apartmentID int64) *Conversation {
+	return &Conversation{
+		Step:        StepEventDetails,
+		ResidentID:  residentID,
+		ApartmentID: apartmentID,
+	}
+}
+
+// NewVehicleConversation registers a car of the resident's apartment.
+func NewVehicleConversation(residentID, apartmentID int64) *Conversation {
+	return &Conversation{
+		Step:        StepVehiclePlate,
+		ResidentID:  residentID,
+		ApartmentID: apartmentID,
+	}
+}
+
+// IsVisit reports whether the conversation is a guest's pass request.
+func (c *Conversation) IsVisit() bool {
+	return c.BuildingID != 0
+}
+
+// Fire applies an event to the conversation and remembers the previous step
+// so that Back can return to it.
+func (c *Conversation) Fire(event Event) error {
+	next, ok := transitions[c.Step][event]
+	if !ok {
+		return fmt.Errorf("%w: %s on %s", ErrInvalidTransition, event, c.Step)
+	}
+
+	c.History = append(c.History, c.Step)
+	c.Step = next
+	return nil
+}
+
+// Back returns the conversation to the previous step. It reports false on
+// the first step.
+func (c *Conversation) Back() bool {
+	if len(c.History) == 0 {
+		return false
+	}
+
+	c.Step = c.History[len(c.History)-1]
+	c.History = c.History[:len(c.History)-1]
+	return true
+}
+
+func (c *Conversation) Done() bool {
+	return c.Step == StepDone
+}
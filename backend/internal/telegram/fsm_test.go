@@ -0,0 +1,145 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConversation_CarFlow(t *testing.T) {
+	conv := NewConversation(42, 7)
+
+	assert.NoError(t, conv.Fire(EventGuestCar))
+	assert.Equal(t, StepCarPlate, conv.Step)
+	assert.NoError(t, conv.Fire(EventCarPlate))
+	assert.Equal(t, StepDuration, conv.Step)
+	assert.NoError(t, conv.Fire(EventDurationCustom))
+	assert.Equal(t, StepCustomTime, conv.Step)
+	assert.NoError(t, conv.Fire(EventCustomTime))
+	assert.Equal(t, StepGuestName, conv.Step)
+	assert.NoError(t, conv.Fire(EventGuestName))
+	assert.True(t, conv.Done())
+}
+
+func TestConversation_VisitFlow(t *testing.T) {
+	conv := NewVisitConversation(1)
+	assert.True(t, conv.IsVisit())
+
+	assert.NoError(t, conv.Fire(EventApartment))
+	assert.Equal(t, StepVisitCarPlate, conv.Step)
+	assert.NoError(t, conv.Fire(EventGuestPedestrian))
+	assert.Equal(t, StepVisitStartDate, conv.Step)
+	assert.NoError(t, conv.Fire(EventStartDate))
+	assert.Equal(t, StepVisitStartTime, conv.Step)
+	assert.NoError(t, conv.Fire(EventStartTime))
+	assert.Equal(t, StepVisitDuration, conv.Step)
+	assert.True(t, errors.Is(conv.Fire(EventDurationCustom), ErrInvalidTransition))
+	assert.NoError(t, conv.Fire(EventDurationPreset))
+	assert.True(t, conv.Done())
+
+	assert.False(t, NewConversation(42, 7).IsVisit())
+
+	now := NewVisitConversation(1)
+	assert.NoError(t, now.Fire(EventApartment))
+	assert.NoError(t, now.Fire(EventCarPlate))
+	assert.NoError(t, now.Fire(EventStartNow))
+	assert.Equal(t, StepVisitDuration, now.Step)
+}
+
+func TestConversation_EventFlow(t *testing.T) {
+	event := NewEventConversation(42, 7)
+	assert.NoError(t, event.Fire(EventEventDetails))
+	assert.Equal(t, StepEventGuestList, event.Step)
+	assert.True(t, errors.Is(event.Fire(EventEventDetails), ErrInvalidTransition))
+	assert.NoError(t, event.Fire(EventGuestList))
+	assert.True(t, event.Done())
+}
+
+func TestConversation_ExceptionFlow(t *testing.T) {
+	conv := &Conversation{Step: StepExceptionReason}
+
+	assert.True(t, errors.Is(conv.Fire(EventGuestList), ErrInvalidTransition))
+	assert.NoError(t, conv.Fire(EventExceptionReason))
+	assert.True(t, conv.Done())
+}
+
+func TestConversation_VehicleFlow(t *testing.T) {
+	conv := NewVehicleConversation(42, 7)
+
+	assert.True(t, errors.Is(conv.Fire(EventExceptionReason), ErrInvalidTransition))
+	assert.NoError(t, conv.Fire(EventVehiclePlate))
+	assert.True(t, conv.Done())
+}
+
+func TestConversation_RejectsEventsOfOtherSteps(t *testing.T) {
+	conv := NewConversation(42, 7)
+
+	err := conv.Fire(EventDurationPreset)
+	assert.True(t, errors.Is(err, ErrInvalidTransition))
+	assert.Equal(t, StepGuestType, conv.Step)
+	assert.Empty(t, conv.History)
+}
+
+func TestConversation_Back(t *testing.T) {
+	conv := NewConversation(42, 7)
+
+	assert.False(t, conv.Back())
+
+	assert.NoError(t, conv.Fire(EventGuestPedestrian))
+	assert.NoError(t, conv.Fire(EventDurationCustom))
+	assert.Equal(t, StepCustomTime, conv.Step)
+
+	assert.True(t, conv.Back())
+	assert.Equal(t, StepDuration, conv.Step)
+	assert.True(t, conv.Back())
+	assert.Equal(t, StepGuestType, conv.Step)
+	assert.False(t, conv.Back())
+
+	assert.NoError(t, conv.Fire(EventGuestCar))
+	assert.Equal(t, StepCarPlate, conv.Step)
+}
+
+func TestMemoryStateStore_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStateStore()
+
+	conv, err := store.Get(ctx, 1)
+	assert.NoError(t, err)
+	assert.Nil(t, conv)
+
+	validTo := time.Date(2025, 1, 1, 22, 0, 0, 0, time.UTC)
+	guestName := "Иван"
+	saved := NewConversation(42, 7)
+	assert.NoError(t, saved.Fire(EventGuestCar))
+	saved.CarPlate = "A123BC77"
+	saved.Duration = 2 * time.Hour
+	saved.ValidTo = &validTo
+	saved.GuestName = &guestName
+	assert.NoError(t, store.Set(ctx, 1, saved))
+
+	conv, err = store.Get(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, saved, conv)
+
+	assert.NoError(t, store.Delete(ctx, 1))
+	conv, err = store.Get(ctx, 1)
+	assert.NoError(t, err)
+	assert.Nil(t, conv)
+}
+
+func TestMemoryStateStore_Expires(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStateStore()
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	assert.NoError(t, store.Set(ctx, 1, NewConversation(42, 7)))
+
+	now = now.Add(conversationTTL + time.Second)
+	conv, err := store.Get(ctx, 1)
+	assert.NoError(t, err)
+	assert.Nil(t, conv)
+}
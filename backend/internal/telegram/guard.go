@@ -0,0 +1,161 @@
+package telegram
+
+import (
+	"context"
+	"strings"
+
+	"yardpass/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// guardCheckPrefix checks a suggested plate; the plate is normalized.
+const guardCheckPrefix = "gchk:"
+
+var guardReasonTexts = map[string]msgKey{
+	"PASS_NOT_FOUND":        msgReasonPassNotFound,
+	"PASS_EXPIRED":          msgReasonPassExpired,
+	"PASS_REVOKED":          msgReasonPassRevoked,
+	"PASS_NOT_YET_VALID":    msgReasonPassNotYetValid,
+	"QUIET_HOURS":           msgReasonQuietHours,
+	"INVALID_CAR_PLATE":     msgReasonInvalidCarPlate,
+	"PASS_USED":             msgReasonPassUsed,
+	"DYNAMIC_QR_REQUIRED":   msgReasonDynamicQRRequired,
+	"QR_CODE_INVALID":       msgReasonQRCodeInvalid,
+	"QR_CODE_EXPIRED":       msgReasonQRCodeExpired,
+	"OUTSIDE_ALLOWED_HOURS": msgReasonOutsideAllowedHours,
+	"BLACKLISTED":           msgReasonBlacklisted,
+}
+
+func (b *Bot) handleLink(ctx context.Context, msg Message, code string) {
+	if code == "" {
+		b.sendMessage(ctx, msg.Chat.ID, b.t(ctx, msgLinkUsage))
+		return
+	}
+
+	user, err := b.userService.LinkTelegram(ctx, code, msg.From.ID)
+	if err != nil {
+		b.sendMessage(ctx, msg.Chat.ID, b.t(ctx, msgLinkFailed, err.Error()))
+		b.logger.Warn("failed to link telegram", zap.Error(err), zap.Int64("telegram_id", msg.From.ID))
+		return
+	}
+
+	b.sendMessage(ctx, msg.Chat.ID, b.t(ctx, msgLinked, user.Username))
+}
+
+// getStaff returns the linked guard/admin for a Telegram user or nil.
+// It is looked up on every message so that deactivation in the web panel
+// takes effect immediately.
+func (b *Bot) getStaff(ctx context.Context, telegramID int64) *domain.User {
+	user, err := b.userService.GetStaffByTelegramID(ctx, telegramID)
+	if err != nil {
+		b.logger.Error("failed to get staff by telegram id", zap.Error(err), zap.Int64("telegram_id", telegramID))
+		return nil
+	}
+	return user
+}
+
+func (b *Bot) handleGuardCheck(ctx context.Context, msg Message, staff *domain.User, input string) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		b.sendMessage(ctx, msg.Chat.ID, b.t(ctx, msgGuardSendInput))
+		return
+	}
+
+	var result *domain.PassValidationResult
+	var err error
+
+	if code, parseErr := b.qrGen.Parse(ctx, input); parseErr == nil {
+		result, err = b.passService.ValidateQR(ctx, code, staff.ID)
+	} else {
+		result, err = b.passService.ValidatePassByCarPlate(ctx, input, staff.ID, staff.BuildingID)
+	}
+
+	if err != nil {
+		b.sendMessage(ctx, msg.Chat.ID, b.t(ctx, msgGuardCheckFailed))
+		b.logger.Error("guard validation failed", zap.Error(err), zap.Int64("guard_user_id", staff.ID))
+		return
+	}
+
+	text := b.formatValidationResult(ctx, result)
+	if len(result.Suggestions) == 0 {
+		b.sendMessage(ctx, msg.Chat.ID, text)
+		return
+	}
+
+	// A similar plate is never admitted on its own: the guard compares it
+	// with the car and checks it with a button.
+	var keyboardRows [][]map[string]interface{}
+	for _, suggestion := range result.Suggestions {
+		keyboardRows = append(keyboardRows, []map[string]interface{}{
+			{"text": b.t(ctx, msgGuardCheckSuggestion, suggestion.CarPlate), "callback_data": guardCheckPrefix + suggestion.CarPlate},
+		})
+	}
+	keyboard := map[string]interface{}{
+		"inline_keyboard": keyboardRows,
+	}
+	b.sendMessageWithKeyboard(ctx, msg.Chat.ID, text, keyboard)
+}
+
+// handleGuardCheckCallback checks the plate of a suggestion button.
+func (b *Bot) handleGuardCheckCallback(ctx context.Context, cb CallbackQuery) {
+	staff := b.getStaff(ctx, cb.From.ID)
+	if staff == nil {
+		b.sendMessage(ctx, cb.Message.Chat.ID, b.t(ctx, msgGuardOnly))
+		return
+	}
+
+	msg := Message{From: cb.From, Chat: cb.Message.Chat}
+	b.handleGuardCheck(ctx, msg, staff, strings.TrimPrefix(cb.Data, guardCheckPrefix))
+}
+
+// formatValidationResult describes the result to the guard. A watchlisted
+// plate is called out at the top, whatever the result.
+func (b *Bot) formatValidationResult(ctx context.Context, result *domain.PassValidationResult) string {
+	var alert string
+	if entry := result.Watchlist; entry != nil {
+		key := msgGuardWatchedAlert
+		if entry.Severity == domain.WatchlistSeverityBan {
+			key = msgGuardBannedAlert
+		}
+		alert = b.t(ctx, key, entry.CarPlate, entry.Reason)
+	}
+
+	if !result.Valid {
+		reason := result.Reason
+		if key, ok := guardReasonTexts[result.Reason]; ok {
+			reason = b.t(ctx, key)
+		}
+		text := alert + b.t(ctx, msgEntryDenied, reason)
+		if len(result.Suggestions) > 0 {
+			text += b.t(ctx, msgGuardSuggestionsHeader)
+			for i, suggestion := range result.Suggestions {
+				text += b.t(ctx, msgGuardSuggestionLine, i+1, suggestion.CarPlate, suggestion.Apartment, b.formatLocalTime(suggestion.ValidTo))
+			}
+		}
+		return text
+	}
+
+	text := alert + b.t(ctx, msgPassValid)
+	if result.Vehicle != nil {
+		text = alert + b.t(ctx, msgResidentVehicleValid)
+	}
+	if result.CarPlate != "" {
+		text += b.t(ctx, msgCarPlateLine, result.CarPlate)
+	} else {
+		text += b.t(ctx, msgPedestrianLine)
+	}
+	if result.Apartment != "" {
+		text += b.t(ctx, msgApartmentLine, result.Apartment)
+	}
+	if result.Pass != nil && result.Pass.GuestName != nil && *result.Pass.GuestName != "" {
+		text += b.t(ctx, msgGuestLine, *result.Pass.GuestName)
+	}
+	if result.Vehicle != nil && result.Vehicle.Label != nil {
+		text += b.t(ctx, msgVehicleLabelLine, *result.Vehicle.Label)
+	}
+	if result.ValidTo != nil {
+		text += b.t(ctx, msgValidUntilLine, b.formatLocalTime(*result.ValidTo))
+	}
+	return text
+}
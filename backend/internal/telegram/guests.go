@@ -0,0 +1,189 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"yardpass/internal/domain"
+	"yardpass/internal/service"
+
+	"go.uber.org/zap"
+)
+
+const (
+	guestPickPrefix   = "guest_pick:"
+	guestStarPrefix   = "guest_star:"
+	guestDeletePrefix = "guest_del:"
+	callbackSavedName = "guest_saved_name"
+
+	// quickPickLimit is how many saved guests are offered at the car plate
+	// step.
+	quickPickLimit = 6
+)
+
+func savedGuestLabel(g *domain.SavedGuest) string {
+	label := g.CarPlate
+	if g.GuestName != nil {
+		label = fmt.Sprintf("%s · %s", label, *g.GuestName)
+	}
+	if g.Starred {
+		label = "⭐ " + label
+	}
+	return truncateText(label, 64)
+}
+
+// quickPickRows offers the resident's starred and recent guests as buttons
+// at the car plate step.
+func (b *Bot) quickPickRows(ctx context.Context, conv *Conversation) [][]map[string]interface{} {
+	guests, err := b.guestService.ListGuests(ctx, conv.ResidentID, quickPickLimit)
+	if err != nil {
+		b.logger.Error("failed to list saved guests", zap.Error(err), zap.Int64("resident_id", conv.ResidentID))
+		return nil
+	}
+
+	var rows [][]map[string]interface{}
+	for _, g := range guests {
+		rows = append(rows, []map[string]interface{}{
+			{"text": savedGuestLabel(g), "callback_data": fmt.Sprintf("%s%d", guestPickPrefix, g.ID)},
+		})
+	}
+	return rows
+}
+
+// handleGuestPick fills the car plate (and offers the guest name later)
+// from a saved guest.
+func (b *Bot) handleGuestPick(ctx context.Context, cb CallbackQuery) {
+	chatID := cb.Message.Chat.ID
+	userID := cb.From.ID
+
+	conv := b.loadConversation(ctx, chatID, userID)
+	if conv == nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgSessionExpired))
+		return
+	}
+
+	guestID, err := strconv.ParseInt(strings.TrimPrefix(cb.Data, guestPickPrefix), 10, 64)
+	if err != nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgInvalidButtonData))
+		return
+	}
+
+	guest, err := b.guestService.GetGuest(ctx, conv.ResidentID, guestID)
+	if err != nil {
+		if errors.Is(err, service.ErrSavedGuestNotFound) {
+			b.sendMessage(ctx, chatID, b.t(ctx, msgGuestNotFoundEnterPlate))
+			return
+		}
+		b.sendMessage(ctx, chatID, b.t(ctx, msgError, err.Error()))
+		b.logger.Error("failed to get saved guest", zap.Error(err), zap.Int64("guest_id", guestID))
+		return
+	}
+
+	conv.CarPlate = guest.CarPlate
+	conv.SavedGuestName = guest.GuestName
+	b.advance(ctx, chatID, userID, conv, EventCarPlate)
+}
+
+func (b *Bot) showGuests(ctx context.Context, chatID int64, resident *domain.Resident) {
+	guests, err := b.guestService.ListGuests(ctx, resident.ID, 0)
+	if err != nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgListGuestsFailed, err.Error()))
+		b.logger.Error("failed to list saved guests", zap.Error(err), zap.Int64("resident_id", resident.ID))
+		return
+	}
+
+	if len(guests) == 0 {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgNoGuests))
+		return
+	}
+
+	text := b.t(ctx, msgGuestsHeader)
+	var keyboardRows [][]map[string]interface{}
+	for i, g := range guests {
+		text += b.t(ctx, msgGuestsItem, i+1, savedGuestLabel(g), g.UseCount)
+
+		starText := b.t(ctx, msgStarGuest, g.CarPlate)
+		if g.Starred {
+			starText = b.t(ctx, msgUnstarGuest, g.CarPlate)
+		}
+		keyboardRows = append(keyboardRows, []map[string]interface{}{
+			{"text": starText, "callback_data": fmt.Sprintf("%s%d:%d", guestStarPrefix, resident.ID, g.ID)},
+			{"text": b.t(ctx, msgDeleteGuest), "callback_data": fmt.Sprintf("%s%d:%d", guestDeletePrefix, resident.ID, g.ID)},
+		})
+	}
+	text += b.t(ctx, msgGuestsHint)
+
+	keyboard := map[string]interface{}{
+		"inline_keyboard": keyboardRows,
+	}
+
+	b.sendMessageWithKeyboard(ctx, chatID, text, keyboard)
+}
+
+// handleGuestsCallback serves the star/delete buttons of the guests screen.
+// The resident ID in the callback data is checked against the caller's own
+// resident rows.
+func (b *Bot) handleGuestsCallback(ctx context.Context, cb CallbackQuery) {
+	chatID := cb.Message.Chat.ID
+
+	prefix := guestStarPrefix
+	if strings.HasPrefix(cb.Data, guestDeletePrefix) {
+		prefix = guestDeletePrefix
+	}
+
+	residentIDStr, guestIDStr, ok := strings.Cut(strings.TrimPrefix(cb.Data, prefix), ":")
+	residentID, err1 := strconv.ParseInt(residentIDStr, 10, 64)
+	guestID, err2 := strconv.ParseInt(guestIDStr, 10, 64)
+	if !ok || err1 != nil || err2 != nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgInvalidButtonData))
+		return
+	}
+
+	var resident *domain.Resident
+	for _, r := range b.residentsFor(ctx, chatID, cb.From.ID) {
+		if r.ID == residentID {
+			resident = r
+			break
+		}
+	}
+	if resident == nil {
+		return
+	}
+
+	var err error
+	switch prefix {
+	case guestStarPrefix:
+		var guest *domain.SavedGuest
+		guest, err = b.guestService.GetGuest(ctx, resident.ID, guestID)
+		if err == nil {
+			_, err = b.guestService.SetStarred(ctx, resident.ID, guestID, !guest.Starred)
+		}
+	case guestDeletePrefix:
+		err = b.guestService.DeleteGuest(ctx, resident.ID, guestID)
+	}
+	if err != nil {
+		if errors.Is(err, service.ErrSavedGuestNotFound) {
+			b.sendMessage(ctx, chatID, b.t(ctx, msgGuestNotFound))
+		} else {
+			b.sendMessage(ctx, chatID, b.t(ctx, msgError, err.Error()))
+			b.logger.Error("failed to update saved guest", zap.Error(err), zap.Int64("guest_id", guestID))
+		}
+		return
+	}
+
+	b.showGuests(ctx, chatID, resident)
+}
+
+// rememberGuest saves the guest of a car pass for quick picks. Failures do
+// not affect the pass and are only logged.
+func (b *Bot) rememberGuest(ctx context.Context, pass *domain.Pass) {
+	if pass.CarPlate == nil || pass.ResidentID == nil {
+		return
+	}
+	if err := b.guestService.RememberGuest(ctx, *pass.ResidentID, *pass.CarPlate, pass.GuestName); err != nil {
+		b.logger.Error("failed to remember guest", zap.Error(err), zap.String("pass_id", pass.ID.String()))
+	}
+}
@@ -1,18 +1,13 @@
 package telegram
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"mime/multipart"
-	"net/http"
-	"strconv"
 	"strings"
 	"time"
 
 	"yardpass/internal/domain"
+	"yardpass/internal/service"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -25,18 +20,28 @@ type Update struct {
 }
 
 type Message struct {
-	MessageID int64  `json:"message_id"`
-	From      *User  `json:"from"`
-	Chat      *Chat  `json:"chat"`
-	Text      string `json:"text"`
-	Date      int64  `json:"date"`
+	MessageID int64     `json:"message_id"`
+	From      *User     `json:"from"`
+	Chat      *Chat     `json:"chat"`
+	Text      string    `json:"text"`
+	Caption   string    `json:"caption"`
+	Document  *Document `json:"document"`
+	Date      int64     `json:"date"`
+}
+
+// Document is a file a user sent to the bot.
+type Document struct {
+	FileID   string `json:"file_id"`
+	FileName string `json:"file_name"`
+	FileSize int64  `json:"file_size"`
 }
 
 type User struct {
-	ID        int64  `json:"id"`
-	FirstName string `json:"first_name"`
-	LastName  string `json:"last_name"`
-	Username  string `json:"username"`
+	ID           int64  `json:"id"`
+	FirstName    string `json:"first_name"`
+	LastName     string `json:"last_name"`
+	Username     string `json:"username"`
+	LanguageCode string `json:"language_code"`
 }
 
 type Chat struct {
@@ -50,10 +55,13 @@ type CallbackQuery struct {
 	Data    string   `json:"data"`
 }
 
+// ProcessUpdate handles an update in the language of its sender.
 func (b *Bot) ProcessUpdate(ctx context.Context, update Update) {
 	if update.Message != nil {
+		ctx = withLang(ctx, b.userLang(ctx, update.Message.From))
 		b.handleMessage(ctx, *update.Message)
 	} else if update.CallbackQuery != nil {
+		ctx = withLang(ctx, b.userLang(ctx, update.CallbackQuery.From))
 		b.handleCallbackQuery(ctx, *update.CallbackQuery)
 	}
 }
@@ -62,7 +70,43 @@ func (b *Bot) handleMessage(ctx context.Context, msg Message) {
 	userID := msg.From.ID
 	text := msg.Text
 
-	if text == "/start" || text == "/create" || text == "/list" || text == "/revoke" {
+	command, args, _ := strings.Cut(text, " ")
+	switch command {
+	case "/join":
+		b.handleJoin(ctx, msg, strings.TrimSpace(args))
+		return
+	case "/start":
+		if code, ok := strings.CutPrefix(strings.TrimSpace(args), joinStartPrefix); ok {
+			b.handleJoin(ctx, msg, code)
+			return
+		}
+		if building, ok := strings.CutPrefix(strings.TrimSpace(args), visitStartPrefix); ok {
+			b.startVisit(ctx, msg, building)
+			return
+		}
+	case "/cancel":
+		b.handleCancel(ctx, msg.Chat.ID, userID)
+		return
+	case "/back":
+		b.handleBack(ctx, msg.Chat.ID, userID)
+		return
+	case "/language":
+		b.showLanguages(ctx, msg.Chat.ID)
+		return
+	case "/link":
+		b.handleLink(ctx, msg, strings.TrimSpace(args))
+		return
+	case "/check":
+		staff := b.getStaff(ctx, userID)
+		if staff == nil {
+			b.sendMessage(ctx, msg.Chat.ID, b.t(ctx, msgGuardOnly))
+			return
+		}
+		b.handleGuardCheck(ctx, msg, staff, args)
+		return
+	}
+
+	if text == "/start" || text == "/create" || text == "/list" || text == "/revoke" || text == "/family" || text == "/guests" || text == "/event" || text == "/cars" {
 		switch text {
 		case "/start":
 			b.handleStart(ctx, msg)
@@ -90,57 +134,133 @@ func (b *Bot) handleMessage(ctx context.Context, msg Message) {
 				Data:    "revoke_pass",
 			}
 			b.handleCallbackQuery(ctx, cb)
+		case "/family":
+			cb := CallbackQuery{
+				ID:      "",
+				From:    msg.From,
+				Message: &msg,
+				Data:    "household",
+			}
+			b.handleCallbackQuery(ctx, cb)
+		case "/guests":
+			cb := CallbackQuery{
+				ID:      "",
+				From:    msg.From,
+				Message: &msg,
+				Data:    "guests",
+			}
+			b.handleCallbackQuery(ctx, cb)
+		case "/event":
+			cb := CallbackQuery{
+				ID:      "",
+				From:    msg.From,
+				Message: &msg,
+				Data:    "create_event",
+			}
+			b.handleCallbackQuery(ctx, cb)
+		case "/cars":
+			cb := CallbackQuery{
+				ID:      "",
+				From:    msg.From,
+				Message: &msg,
+				Data:    "vehicles",
+			}
+			b.handleCallbackQuery(ctx, cb)
 		}
 		return
 	}
 
-	state := b.getState(userID)
-	if state == nil {
-		b.sendMessage(ctx, msg.Chat.ID, "Используйте /start для начала работы")
+	conv, err := b.states.Get(ctx, userID)
+	if err != nil {
+		b.logger.Error("failed to load conversation", zap.Error(err), zap.Int64("user_id", userID))
+	}
+	if conv == nil {
+		if staff := b.getStaff(ctx, userID); staff != nil {
+			b.handleGuardCheck(ctx, msg, staff, text)
+			return
+		}
+		b.sendMessage(ctx, msg.Chat.ID, b.t(ctx, msgUseStart))
 		return
 	}
 
-	switch state.Step {
-	case StateWaitingGuestType:
-		b.sendMessage(ctx, msg.Chat.ID, "Используйте кнопки для выбора типа гостя")
-	case StateWaitingCarPlate:
-		b.handleCarPlate(ctx, msg, state)
-	case StateWaitingDuration:
-		b.handleDuration(ctx, msg, state)
-	case StateWaitingCustomTime:
-		b.handleCustomTime(ctx, msg, state)
-	case StateWaitingGuestName:
-		b.handleGuestName(ctx, msg, state)
+	switch conv.Step {
+	case StepGuestType:
+		b.sendMessage(ctx, msg.Chat.ID, b.t(ctx, msgUseButtonsGuestType))
+	case StepCarPlate:
+		b.handleCarPlate(ctx, msg, conv)
+	case StepDuration, StepStartDate, StepStartTime, StepEndTime, StepConfirm:
+		b.sendMessage(ctx, msg.Chat.ID, b.t(ctx, msgUseButtons))
+	case StepCustomTime:
+		b.handleCustomTime(ctx, msg, conv)
+	case StepGuestName:
+		b.handleGuestName(ctx, msg, conv)
+	case StepVisitApartment:
+		b.handleVisitApartment(ctx, msg, conv)
+	case StepVisitCarPlate:
+		b.handleVisitCarPlate(ctx, msg, conv)
+	case StepVisitStartDate, StepVisitStartTime, StepVisitDuration:
+		b.sendMessage(ctx, msg.Chat.ID, b.t(ctx, msgUseButtons))
+	case StepEventDetails:
+		b.handleEventDetails(ctx, msg, conv)
+	case StepEventGuestList:
+		b.handleEventGuestList(ctx, msg, conv)
+	case StepExceptionReason:
+		b.handleExceptionReason(ctx, msg, conv)
+	case StepVehiclePlate:
+		b.handleVehiclePlate(ctx, msg, conv)
 	default:
-		b.sendMessage(ctx, msg.Chat.ID, "Неизвестное состояние. Используйте /start")
-		b.clearState(userID)
+		b.sendMessage(ctx, msg.Chat.ID, b.t(ctx, msgUnknownState))
+		b.clearConversation(ctx, userID)
 	}
 }
 
 func (b *Bot) handleStart(ctx context.Context, msg Message) {
 	userID := msg.From.ID
 
-	resident, err := b.residentRepo.GetByTelegramID(ctx, userID)
-	if err != nil || resident == nil {
-		b.sendMessage(ctx, msg.Chat.ID, "Вы не зарегистрированы как житель. Обратитесь к администратору.")
+	residents, err := b.residentRepo.ListByTelegramID(ctx, userID)
+	if err != nil || len(residents) == 0 {
+		if staff := b.getStaff(ctx, userID); staff != nil {
+			b.sendMessage(ctx, msg.Chat.ID, b.t(ctx, msgGuardMode, staff.Username))
+			return
+		}
+		b.sendMessage(ctx, msg.Chat.ID, b.t(ctx, msgNotRegistered))
 		return
 	}
 
-	keyboard := map[string]interface{}{
-		"inline_keyboard": [][]map[string]interface{}{
-			{
-				{"text": "Выдать пропуск гостю", "callback_data": "create_pass"},
-			},
-			{
-				{"text": "Мои активные пропуска", "callback_data": "list_active"},
-			},
-			{
-				{"text": "Отозвать пропуск", "callback_data": "revoke_pass"},
-			},
+	keyboardRows := [][]map[string]interface{}{
+		{
+			{"text": b.t(ctx, msgMenuCreatePass), "callback_data": "create_pass"},
+		},
+		{
+			{"text": b.t(ctx, msgMenuActivePasses), "callback_data": "list_active"},
+		},
+		{
+			{"text": b.t(ctx, msgMenuRevokePass), "callback_data": "revoke_pass"},
+		},
+		{
+			{"text": b.t(ctx, msgMenuGuests), "callback_data": "guests"},
 		},
+		{
+			{"text": b.t(ctx, msgMenuEvent), "callback_data": "create_event"},
+		},
+		{
+			{"text": b.t(ctx, msgMenuVehicles), "callback_data": "vehicles"},
+		},
+	}
+	for _, r := range residents {
+		if r.Role == "primary" {
+			keyboardRows = append(keyboardRows, []map[string]interface{}{
+				{"text": b.t(ctx, msgMenuHousehold), "callback_data": "household"},
+			})
+			break
+		}
+	}
+
+	keyboard := map[string]interface{}{
+		"inline_keyboard": keyboardRows,
 	}
 
-	b.sendMessageWithKeyboard(ctx, msg.Chat.ID, "Добро пожаловать в YardPass!\n\nВыберите действие:", keyboard)
+	b.sendMessageWithKeyboard(ctx, msg.Chat.ID, b.t(ctx, msgWelcome), keyboard)
 }
 
 func (b *Bot) handleCallbackQuery(ctx context.Context, cb CallbackQuery) {
@@ -149,103 +269,232 @@ func (b *Bot) handleCallbackQuery(ctx context.Context, cb CallbackQuery) {
 
 	switch data {
 	case "create_pass":
-		keyboard := map[string]interface{}{
-			"inline_keyboard": [][]map[string]interface{}{
-				{
-					{"text": "🚗 На автомобиле", "callback_data": "guest_car"},
-				},
-				{
-					{"text": "🚶 Пеший гость", "callback_data": "guest_pedestrian"},
-				},
-			},
-		}
-		b.setState(userID, &UserState{
-			Step:      StateWaitingGuestType,
-			Data:      make(map[string]interface{}),
-			ExpiresAt: time.Now().Add(10 * time.Minute),
+		b.withResident(ctx, cb.Message.Chat.ID, userID, data, func(resident *domain.Resident) {
+			b.startCreatePass(ctx, cb.Message.Chat.ID, userID, resident)
 		})
-		b.sendMessageWithKeyboard(ctx, cb.Message.Chat.ID, "Выберите тип гостя:", keyboard)
 		b.answerCallbackQuery(ctx, cb.ID, "")
 
 	case "list_active":
-		b.listActivePasses(ctx, cb.Message.Chat.ID, userID)
+		b.withResident(ctx, cb.Message.Chat.ID, userID, data, func(resident *domain.Resident) {
+			b.listActivePasses(ctx, cb.Message.Chat.ID, resident)
+		})
 		b.answerCallbackQuery(ctx, cb.ID, "")
 
 	case "revoke_pass":
-		b.showPassesForRevoke(ctx, cb.Message.Chat.ID, userID)
+		b.withResident(ctx, cb.Message.Chat.ID, userID, data, func(resident *domain.Resident) {
+			b.showPassesForRevoke(ctx, cb.Message.Chat.ID, resident)
+		})
+		b.answerCallbackQuery(ctx, cb.ID, "")
+
+	case "household":
+		b.withResident(ctx, cb.Message.Chat.ID, userID, data, func(resident *domain.Resident) {
+			b.showHousehold(ctx, cb.Message.Chat.ID, resident)
+		})
+		b.answerCallbackQuery(ctx, cb.ID, "")
+
+	case "guests":
+		b.withResident(ctx, cb.Message.Chat.ID, userID, data, func(resident *domain.Resident) {
+			b.showGuests(ctx, cb.Message.Chat.ID, resident)
+		})
+		b.answerCallbackQuery(ctx, cb.ID, "")
+
+	case "create_event":
+		b.withResident(ctx, cb.Message.Chat.ID, userID, data, func(resident *domain.Resident) {
+			b.startEvent(ctx, cb.Message.Chat.ID, userID, resident)
+		})
+		b.answerCallbackQuery(ctx, cb.ID, "")
+
+	case "vehicles":
+		b.withResident(ctx, cb.Message.Chat.ID, userID, data, func(resident *domain.Resident) {
+			b.showVehicles(ctx, cb.Message.Chat.ID, resident)
+		})
 		b.answerCallbackQuery(ctx, cb.ID, "")
 
-	case "guest_car":
-		state := b.getState(userID)
-		if state == nil {
-			b.sendMessage(ctx, cb.Message.Chat.ID, "Сессия истекла. Начните заново с /start")
+	case callbackSavedName:
+		conv := b.loadConversation(ctx, cb.Message.Chat.ID, userID)
+		if conv == nil {
+			b.sendMessage(ctx, cb.Message.Chat.ID, b.t(ctx, msgSessionExpired))
 			b.answerCallbackQuery(ctx, cb.ID, "")
 			return
 		}
-		state.Step = StateWaitingCarPlate
-		b.setState(userID, state)
-		b.sendMessage(ctx, cb.Message.Chat.ID, "Введите номер автомобиля (на английском, например: A123BC77):")
+
+		conv.GuestName = conv.SavedGuestName
+		b.advance(ctx, cb.Message.Chat.ID, userID, conv, EventGuestName)
 		b.answerCallbackQuery(ctx, cb.ID, "")
 
-	case "guest_pedestrian":
-		state := b.getState(userID)
-		if state == nil {
-			b.sendMessage(ctx, cb.Message.Chat.ID, "Сессия истекла. Начните заново с /start")
+	case "guest_car", "guest_pedestrian", "guest_taxi", "guest_delivery":
+		conv := b.loadConversation(ctx, cb.Message.Chat.ID, userID)
+		if conv == nil {
+			b.sendMessage(ctx, cb.Message.Chat.ID, b.t(ctx, msgSessionExpired))
 			b.answerCallbackQuery(ctx, cb.ID, "")
 			return
 		}
-		state.Data["is_pedestrian"] = true
-		state.Step = StateWaitingDuration
-		b.setState(userID, state)
-		keyboard := map[string]interface{}{
-			"inline_keyboard": [][]map[string]interface{}{
-				{
-					{"text": "1 час", "callback_data": "duration_1h"},
-					{"text": "2 часа", "callback_data": "duration_2h"},
-				},
-				{
-					{"text": "4 часа", "callback_data": "duration_4h"},
-					{"text": "До времени", "callback_data": "duration_custom"},
-				},
-			},
-		}
-		b.sendMessageWithKeyboard(ctx, cb.Message.Chat.ID, "Выберите срок действия пропуска:", keyboard)
+
+		// Taxis and deliveries come by car and take the car plate next.
+		switch data {
+		case "guest_taxi":
+			conv.Category = domain.PassCategoryTaxi
+		case "guest_delivery":
+			conv.Category = domain.PassCategoryDelivery
+		default:
+			conv.Category = ""
+		}
+
+		if data == "guest_pedestrian" {
+			conv.IsPedestrian = true
+			b.advance(ctx, cb.Message.Chat.ID, userID, conv, EventGuestPedestrian)
+		} else {
+			conv.IsPedestrian = false
+			b.advance(ctx, cb.Message.Chat.ID, userID, conv, EventGuestCar)
+		}
 		b.answerCallbackQuery(ctx, cb.ID, "")
 
 	case "duration_1h", "duration_2h", "duration_4h", "duration_custom":
-		state := b.getState(userID)
-		if state == nil {
-			b.sendMessage(ctx, cb.Message.Chat.ID, "Сессия истекла. Начните заново с /start")
+		conv := b.loadConversation(ctx, cb.Message.Chat.ID, userID)
+		if conv == nil {
+			b.sendMessage(ctx, cb.Message.Chat.ID, b.t(ctx, msgSessionExpired))
 			b.answerCallbackQuery(ctx, cb.ID, "")
 			return
 		}
 
 		switch data {
 		case "duration_1h":
-			state.Data["duration"] = 1 * time.Hour
+			conv.Duration = 1 * time.Hour
 		case "duration_2h":
-			state.Data["duration"] = 2 * time.Hour
+			conv.Duration = 2 * time.Hour
 		case "duration_4h":
-			state.Data["duration"] = 4 * time.Hour
+			conv.Duration = 4 * time.Hour
 		case "duration_custom":
-			state.Step = StateWaitingCustomTime
-			b.setState(userID, state)
-			b.sendMessage(ctx, cb.Message.Chat.ID, "Введите время окончания действия пропуска в формате ЧЧ:ММ (например, 22:00):")
+			b.advance(ctx, cb.Message.Chat.ID, userID, conv, EventDurationCustom)
+			b.answerCallbackQuery(ctx, cb.ID, "")
+			return
+		}
+
+		// A visit keeps the start the guest picked.
+		if !conv.IsVisit() {
+			conv.ValidFrom = nil
+		}
+		conv.ValidTo = nil
+		b.advance(ctx, cb.Message.Chat.ID, userID, conv, EventDurationPreset)
+		b.answerCallbackQuery(ctx, cb.ID, "")
+
+	case callbackStartNow:
+		conv := b.loadConversation(ctx, cb.Message.Chat.ID, userID)
+		if conv == nil {
+			b.sendMessage(ctx, cb.Message.Chat.ID, b.t(ctx, msgSessionExpired))
+			b.answerCallbackQuery(ctx, cb.ID, "")
+			return
+		}
+
+		conv.ValidFrom = nil
+		b.advance(ctx, cb.Message.Chat.ID, userID, conv, EventStartNow)
+		b.answerCallbackQuery(ctx, cb.ID, "")
+
+	case callbackSchedule, callbackConfirm:
+		conv := b.loadConversation(ctx, cb.Message.Chat.ID, userID)
+		if conv == nil {
+			b.sendMessage(ctx, cb.Message.Chat.ID, b.t(ctx, msgSessionExpired))
 			b.answerCallbackQuery(ctx, cb.ID, "")
 			return
 		}
 
-		state.Step = StateWaitingGuestName
-		b.setState(userID, state)
-		b.sendMessage(ctx, cb.Message.Chat.ID, "Введите имя гостя (или отправьте '-' чтобы пропустить):")
+		if data == callbackSchedule {
+			conv.Duration = 0
+			conv.CalendarMonth = ""
+			b.advance(ctx, cb.Message.Chat.ID, userID, conv, EventSchedule)
+		} else if conv.ValidFrom != nil && conv.ValidTo != nil &&
+			b.checkWindow(ctx, cb.Message.Chat.ID, conv, *conv.ValidFrom, *conv.ValidTo) {
+			b.advance(ctx, cb.Message.Chat.ID, userID, conv, EventConfirm)
+		}
+		b.answerCallbackQuery(ctx, cb.ID, "")
+
+	case callbackNoop:
+		b.answerCallbackQuery(ctx, cb.ID, "")
+
+	case callbackBack:
+		b.handleBack(ctx, cb.Message.Chat.ID, userID)
+		b.answerCallbackQuery(ctx, cb.ID, "")
+
+	case callbackCancel:
+		b.handleCancel(ctx, cb.Message.Chat.ID, userID)
 		b.answerCallbackQuery(ctx, cb.ID, "")
 
 	default:
+		if strings.HasPrefix(data, selectApartmentPrefix) {
+			b.handleSelectApartment(ctx, cb)
+			b.answerCallbackQuery(ctx, cb.ID, "")
+			return
+		}
+		if strings.HasPrefix(data, calendarDatePrefix) || strings.HasPrefix(data, calendarMonthPrefix) || strings.HasPrefix(data, timeSlotPrefix) {
+			b.handlePickerCallback(ctx, cb)
+			b.answerCallbackQuery(ctx, cb.ID, "")
+			return
+		}
+		if strings.HasPrefix(data, languagePrefix) {
+			b.handleLanguageCallback(ctx, cb)
+			b.answerCallbackQuery(ctx, cb.ID, "")
+			return
+		}
+		if strings.HasPrefix(data, guestPickPrefix) {
+			b.handleGuestPick(ctx, cb)
+			b.answerCallbackQuery(ctx, cb.ID, "")
+			return
+		}
+		if strings.HasPrefix(data, guestStarPrefix) || strings.HasPrefix(data, guestDeletePrefix) {
+			b.handleGuestsCallback(ctx, cb)
+			b.answerCallbackQuery(ctx, cb.ID, "")
+			return
+		}
+		if strings.HasPrefix(data, visitApprovePrefix) || strings.HasPrefix(data, visitDeclinePrefix) {
+			b.handleVisitDecision(ctx, cb)
+			b.answerCallbackQuery(ctx, cb.ID, "")
+			return
+		}
+		if strings.HasPrefix(data, entryAllowPrefix) || strings.HasPrefix(data, entryDenyPrefix) {
+			b.handleEntryDecision(ctx, cb)
+			b.answerCallbackQuery(ctx, cb.ID, "")
+			return
+		}
+		if strings.HasPrefix(data, householdInvitePrefix) || strings.HasPrefix(data, householdTogglePrefix) || strings.HasPrefix(data, householdRemovePrefix) {
+			b.handleHouseholdCallback(ctx, cb)
+			b.answerCallbackQuery(ctx, cb.ID, "")
+			return
+		}
+		if strings.HasPrefix(data, dynamicQRPrefix) || strings.HasPrefix(data, refreshQRPrefix) {
+			b.handleDynamicQRCallback(ctx, cb)
+			b.answerCallbackQuery(ctx, cb.ID, "")
+			return
+		}
+		if strings.HasPrefix(data, printPassPrefix) || strings.HasPrefix(data, printAllPrefix) {
+			b.handlePrintCallback(ctx, cb)
+			b.answerCallbackQuery(ctx, cb.ID, "")
+			return
+		}
+		if strings.HasPrefix(data, eventRevokePrefix) || strings.HasPrefix(data, eventReportPrefix) {
+			b.handleEventCallback(ctx, cb)
+			b.answerCallbackQuery(ctx, cb.ID, "")
+			return
+		}
+		if strings.HasPrefix(data, sharePassPrefix) || strings.HasPrefix(data, unsharePassPrefix) {
+			b.handleShareCallback(ctx, cb)
+			b.answerCallbackQuery(ctx, cb.ID, "")
+			return
+		}
+		if strings.HasPrefix(data, vehicleAddPrefix) || strings.HasPrefix(data, vehicleDeletePrefix) {
+			b.handleVehiclesCallback(ctx, cb)
+			b.answerCallbackQuery(ctx, cb.ID, "")
+			return
+		}
+		if strings.HasPrefix(data, guardCheckPrefix) {
+			b.handleGuardCheckCallback(ctx, cb)
+			b.answerCallbackQuery(ctx, cb.ID, "")
+			return
+		}
 		if strings.HasPrefix(data, "revoke_pass_") {
 			passIDStr := strings.TrimPrefix(data, "revoke_pass_")
 			passID, err := uuid.Parse(passIDStr)
 			if err != nil {
-				b.sendMessage(ctx, cb.Message.Chat.ID, "Ошибка: неверный ID пропуска")
+				b.sendMessage(ctx, cb.Message.Chat.ID, b.t(ctx, msgInvalidPassID))
 				b.answerCallbackQuery(ctx, cb.ID, "")
 				return
 			}
@@ -256,39 +505,32 @@ func (b *Bot) handleCallbackQuery(ctx context.Context, cb CallbackQuery) {
 	}
 }
 
-func (b *Bot) handleCarPlate(ctx context.Context, msg Message, state *UserState) {
-	carPlate := msg.Text
-	state.Data["car_plate"] = carPlate
-
-	keyboard := map[string]interface{}{
-		"inline_keyboard": [][]map[string]interface{}{
-			{
-				{"text": "1 час", "callback_data": "duration_1h"},
-				{"text": "2 часа", "callback_data": "duration_2h"},
-			},
-			{
-				{"text": "4 часа", "callback_data": "duration_4h"},
-				{"text": "До времени", "callback_data": "duration_custom"},
-			},
-		},
+func (b *Bot) startCreatePass(ctx context.Context, chatID int64, userID int64, resident *domain.Resident) {
+	conv := NewConversation(resident.ID, resident.ApartmentID)
+	if b.saveConversation(ctx, chatID, userID, conv) {
+		b.promptStep(ctx, chatID, conv)
 	}
-
-	state.Step = StateWaitingDuration
-	b.setState(msg.From.ID, state)
-	b.sendMessageWithKeyboard(ctx, msg.Chat.ID, "Выберите срок действия пропуска:", keyboard)
 }
 
-func (b *Bot) handleDuration(ctx context.Context, msg Message, state *UserState) {
-	b.sendMessage(ctx, msg.Chat.ID, "Используйте кнопки для выбора")
+func (b *Bot) handleCarPlate(ctx context.Context, msg Message, conv *Conversation) {
+	// A malformed plate is asked for again rather than failing at the end.
+	if _, err := service.ParseCarPlate(msg.Text); err != nil {
+		b.sendMessage(ctx, msg.Chat.ID, b.t(ctx, msgInvalidCarPlateFormat, strings.TrimSpace(msg.Text)))
+		return
+	}
+
+	conv.CarPlate = strings.TrimSpace(msg.Text)
+	conv.SavedGuestName = nil
+	b.advance(ctx, msg.Chat.ID, msg.From.ID, conv, EventCarPlate)
 }
 
-func (b *Bot) handleCustomTime(ctx context.Context, msg Message, state *UserState) {
-	timeStr := msg.Text
+func (b *Bot) handleCustomTime(ctx context.Context, msg Message, conv *Conversation) {
+	timeStr := strings.TrimSpace(msg.Text)
 	now := time.Now().In(b.location)
 
 	parsedTime, err := time.Parse("15:04", timeStr)
 	if err != nil {
-		b.sendMessage(ctx, msg.Chat.ID, "Неверный формат времени. Введите в формате ЧЧ:ММ (например, 22:00)")
+		b.sendMessage(ctx, msg.Chat.ID, b.t(ctx, msgInvalidTimeFormat))
 		return
 	}
 
@@ -297,159 +539,137 @@ func (b *Bot) handleCustomTime(ctx context.Context, msg Message, state *UserStat
 		targetTime = targetTime.Add(24 * time.Hour)
 	}
 
-	state.Data["valid_to"] = targetTime.UTC()
+	b.setCustomTime(ctx, msg.Chat.ID, msg.From.ID, conv, targetTime)
+}
+
+// setCustomTime completes the "until time" step for a pass that starts now.
+func (b *Bot) setCustomTime(ctx context.Context, chatID int64, userID int64, conv *Conversation, validTo time.Time) {
+	if !b.checkWindow(ctx, chatID, conv, time.Now(), validTo) {
+		return
+	}
 
-	state.Data["valid_to"] = targetTime
-	state.Step = StateWaitingGuestName
-	b.setState(msg.From.ID, state)
-	b.sendMessage(ctx, msg.Chat.ID, "Введите имя гостя (или отправьте '-' чтобы пропустить):")
+	validTo = validTo.UTC()
+	conv.ValidFrom = nil
+	conv.ValidTo = &validTo
+	conv.Duration = 0
+	b.advance(ctx, chatID, userID, conv, EventCustomTime)
 }
 
-func (b *Bot) handleGuestName(ctx context.Context, msg Message, state *UserState) {
-	guestName := msg.Text
-	if guestName != "-" {
-		state.Data["guest_name"] = &guestName
+func (b *Bot) handleGuestName(ctx context.Context, msg Message, conv *Conversation) {
+	guestName := strings.TrimSpace(msg.Text)
+	if guestName != "-" && guestName != "" {
+		conv.GuestName = &guestName
+	} else {
+		conv.GuestName = nil
 	}
 
-	b.createPassFromState(ctx, msg.Chat.ID, msg.From.ID, state)
-	b.clearState(msg.From.ID)
+	b.advance(ctx, msg.Chat.ID, msg.From.ID, conv, EventGuestName)
 }
 
-func (b *Bot) createPassFromState(ctx context.Context, chatID int64, userID int64, state *UserState) {
-	resident, err := b.residentRepo.GetByTelegramID(ctx, userID)
-	if err != nil || resident == nil {
-		b.sendMessage(ctx, chatID, "Ошибка: житель не найден")
+func (b *Bot) createPassFromConversation(ctx context.Context, chatID int64, userID int64, conv *Conversation) {
+	var resident *domain.Resident
+	for _, r := range b.residentsFor(ctx, chatID, userID) {
+		if r.ID == conv.ResidentID {
+			resident = r
+			break
+		}
+	}
+	if resident == nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgApartmentNotFoundRestart))
 		return
 	}
 
 	var carPlate *string
-	isPedestrian, _ := state.Data["is_pedestrian"].(bool)
-	if !isPedestrian {
-		carPlateStr, ok := state.Data["car_plate"].(string)
-		if !ok || carPlateStr == "" {
-			b.sendMessage(ctx, chatID, "Ошибка: номер автомобиля не указан")
+	if !conv.IsPedestrian {
+		if conv.CarPlate == "" {
+			b.sendMessage(ctx, chatID, b.t(ctx, msgCarPlateMissing))
 			return
 		}
-		carPlate = &carPlateStr
+		carPlate = &conv.CarPlate
 	}
 
-	now := time.Now().UTC()
-	var validTo time.Time
-
-	var duration time.Duration
-	if d, ok := state.Data["duration"].(time.Duration); ok {
-		duration = d
-	} else if dNs, ok := state.Data["duration"].(float64); ok {
-		duration = time.Duration(dNs)
-	} else if dNs, ok := state.Data["duration"].(int64); ok {
-		duration = time.Duration(dNs)
+	validFrom := time.Now().UTC()
+	if conv.ValidFrom != nil {
+		validFrom = conv.ValidFrom.UTC()
 	}
 
-	if duration > 0 {
-		validTo = now.Add(duration)
-	} else if validToTime, ok := state.Data["valid_to"].(time.Time); ok {
-		if validToTime.Location() != time.UTC {
-			validTo = validToTime.UTC()
-		} else {
-			validTo = validToTime
-		}
-	} else if validToStr, ok := state.Data["valid_to"].(string); ok {
-		if parsedTime, err := time.Parse(time.RFC3339, validToStr); err == nil {
-			validTo = parsedTime.UTC()
-		} else {
-			b.sendMessage(ctx, chatID, "Ошибка: время действия не указано")
-			b.logger.Error("failed to parse valid_to", zap.String("valid_to_str", validToStr), zap.Error(err))
-			return
-		}
-	} else {
-		b.sendMessage(ctx, chatID, "Ошибка: время действия не указано")
-		b.logger.Error("duration not found in state", zap.Any("state_data", state.Data))
+	var validTo time.Time
+	switch {
+	case conv.Duration > 0:
+		validTo = validFrom.Add(conv.Duration)
+	case conv.ValidTo != nil:
+		validTo = *conv.ValidTo
+	default:
+		b.sendMessage(ctx, chatID, b.t(ctx, msgValidityMissing))
+		b.logger.Error("conversation has no validity period", zap.Int64("user_id", userID))
 		return
 	}
 
-	var guestName *string
-	if gn, ok := state.Data["guest_name"].(*string); ok {
-		guestName = gn
-	}
-
-	validFromUTC := now.UTC()
-	validToUTC := validTo.UTC()
-
 	req := domain.CreatePassRequest{
 		ApartmentID: resident.ApartmentID,
 		ResidentID:  &resident.ID,
 		CarPlate:    carPlate,
-		GuestName:   guestName,
-		ValidFrom:   validFromUTC,
-		ValidTo:     validToUTC,
+		GuestName:   conv.GuestName,
+		ValidFrom:   validFrom,
+		ValidTo:     validTo.UTC(),
+		Category:    conv.Category,
 	}
 
 	pass, err := b.passService.CreatePass(ctx, req)
 	if err != nil {
-		b.sendMessage(ctx, chatID, fmt.Sprintf("Ошибка при создании пропуска: %s", err.Error()))
+		b.sendMessage(ctx, chatID, b.t(ctx, msgCreatePassFailed, err.Error()))
+		if b.offerPassException(ctx, chatID, userID, conv, req, err) {
+			return
+		}
 		b.logger.Error("failed to create pass", zap.Error(err), zap.Int64("user_id", userID))
 		return
 	}
 
-	qrPNG, err := b.qrGen.GenerateQR(ctx, pass.ID)
-	if err != nil {
-		b.sendMessage(ctx, chatID, fmt.Sprintf("Пропуск создан, но не удалось сгенерировать QR: %s", err.Error()))
+	b.rememberGuest(ctx, pass)
+
+	if err := b.sendPassQR(ctx, chatID, pass, b.passCaption(ctx, pass)); err != nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgQRFailed, err.Error()))
 		b.logger.Error("failed to generate QR", zap.Error(err), zap.String("pass_id", pass.ID.String()))
-		return
 	}
+}
 
+// passCaption describes a newly created pass under its QR code.
+func (b *Bot) passCaption(ctx context.Context, pass *domain.Pass) string {
 	var caption string
 	if pass.CarPlate != nil {
-		caption = fmt.Sprintf(
-			"✅ Пропуск создан!\n\n"+
-				"Тип: Автомобиль\n"+
-				"Номер авто: %s\n"+
-				"Действует до: %s\n"+
-				"ID пропуска: %s",
-			*pass.CarPlate,
-			b.formatLocalTime(pass.ValidTo),
-			pass.ID.String(),
-		)
+		caption = b.t(ctx, msgPassCreatedCar, *pass.CarPlate, b.validity(ctx, pass), pass.ID.String())
 	} else {
-		caption = fmt.Sprintf(
-			"✅ Пропуск создан!\n\n"+
-				"Тип: Пеший гость\n"+
-				"Действует до: %s\n"+
-				"ID пропуска: %s",
-			b.formatLocalTime(pass.ValidTo),
-			pass.ID.String(),
-		)
+		caption = b.t(ctx, msgPassCreatedPedestrian, b.validity(ctx, pass), pass.ID.String())
 	}
 	if pass.GuestName != nil && *pass.GuestName != "" {
-		caption = fmt.Sprintf("%s\nГость: %s", caption, *pass.GuestName)
+		caption += b.t(ctx, msgGuestLine, *pass.GuestName)
 	}
-
-	err = b.sendPhoto(ctx, chatID, qrPNG, caption)
-	if err != nil {
-		b.logger.Error("failed to send photo", zap.Error(err))
+	if key, ok := categoryTexts[pass.Category]; ok {
+		caption += b.t(ctx, msgCategoryLine, b.t(ctx, key))
 	}
+	return caption
 }
 
-func (b *Bot) listActivePasses(ctx context.Context, chatID int64, userID int64) {
-	resident, err := b.residentRepo.GetByTelegramID(ctx, userID)
-	if err != nil || resident == nil {
-		b.sendMessage(ctx, chatID, "Ошибка: житель не найден")
-		return
-	}
+// categoryTexts names the categories residents choose besides guest.
+var categoryTexts = map[string]msgKey{
+	domain.PassCategoryTaxi:     msgCategoryTaxi,
+	domain.PassCategoryDelivery: msgCategoryDelivery,
+}
 
-	passes, err := b.passService.GetActivePassesByResident(ctx, resident.ID)
+func (b *Bot) listActivePasses(ctx context.Context, chatID int64, resident *domain.Resident) {
+	passes, err := b.visiblePasses(ctx, resident)
 	if err != nil {
-		b.sendMessage(ctx, chatID, fmt.Sprintf("Ошибка при получении пропусков: %s", err.Error()))
-		b.logger.Error("failed to get active passes", zap.Error(err), zap.Int64("user_id", userID))
+		b.sendMessage(ctx, chatID, b.t(ctx, msgGetPassesFailed, err.Error()))
+		b.logger.Error("failed to get active passes", zap.Error(err), zap.Int64("resident_id", resident.ID))
 		return
 	}
 
 	if len(passes) == 0 {
-		b.sendMessage(ctx, chatID, "У вас нет активных пропусков")
+		b.sendMessage(ctx, chatID, b.t(ctx, msgNoActivePasses))
 		return
 	}
 
-	text := "Ваши активные пропуска:\n\n"
+	text := b.t(ctx, msgActivePassesHeader)
 	for i, pass := range passes {
 		guestName := ""
 		if pass.GuestName != nil {
@@ -460,50 +680,82 @@ func (b *Bot) listActivePasses(ctx context.Context, chatID int64, userID int64)
 		if pass.CarPlate != nil {
 			passType = "🚗"
 			identifier = *pass.CarPlate
+			switch pass.Category {
+			case domain.PassCategoryTaxi:
+				passType = "🚕"
+			case domain.PassCategoryDelivery:
+				passType = "📦"
+			}
 		} else {
 			passType = "🚶"
-			identifier = "Пеший гость"
+			identifier = b.t(ctx, msgPedestrianGuest)
 		}
 
-		text += fmt.Sprintf("%d. %s %s%s\n   Действует до: %s\n   ID: %s\n\n",
+		text += fmt.Sprintf("%d. %s %s%s\n   %s\n   ID: %s\n\n",
 			i+1,
 			passType,
 			identifier,
 			guestName,
-			b.formatLocalTime(pass.ValidTo),
+			b.validity(ctx, pass),
 			pass.ID.String()[:8],
 		)
 	}
 
-	b.sendMessage(ctx, chatID, text)
+	// Each pass can be printed and, if links are configured, sent to the
+	// guest as a link.
+	var keyboardRows [][]map[string]interface{}
+	for _, pass := range passes {
+		info := b.passInfo(ctx, pass)
+		info = truncateText(info, 60)
+		// A rotating code cannot be printed, its current code is sent instead.
+		row := []map[string]interface{}{
+			{"text": "📄 " + info, "callback_data": printPassPrefix + pass.ID.String()},
+		}
+		if pass.Dynamic {
+			row[0] = map[string]interface{}{"text": "🔄 " + info, "callback_data": refreshQRPrefix + pass.ID.String()}
+		}
+		if b.passShareService.Enabled() {
+			row = append(row, map[string]interface{}{"text": "🔗 " + info, "callback_data": sharePassPrefix + pass.ID.String()})
+		}
+		keyboardRows = append(keyboardRows, row)
+	}
+	if len(passes) > 1 {
+		keyboardRows = append(keyboardRows, []map[string]interface{}{
+			{"text": b.t(ctx, msgPrintAllPasses), "callback_data": fmt.Sprintf("%s%d", printAllPrefix, resident.ID)},
+		})
+	}
+	b.sendMessageWithKeyboard(ctx, chatID, text, map[string]interface{}{"inline_keyboard": keyboardRows})
 }
 
 func (b *Bot) formatLocalTime(t time.Time) string {
 	return t.In(b.location).Format("15:04 02.01.2006")
 }
 
-func (b *Bot) showPassesForRevoke(ctx context.Context, chatID int64, userID int64) {
-	resident, err := b.residentRepo.GetByTelegramID(ctx, userID)
-	if err != nil || resident == nil {
-		b.sendMessage(ctx, chatID, "Ошибка: житель не найден")
-		return
+// validity describes the validity period, mentioning the start only for
+// passes scheduled for later.
+func (b *Bot) validity(ctx context.Context, pass *domain.Pass) string {
+	if pass.ValidFrom.After(time.Now()) {
+		return b.t(ctx, msgValidFromTo, b.formatLocalTime(pass.ValidFrom), b.formatLocalTime(pass.ValidTo))
 	}
+	return b.t(ctx, msgValidUntil, b.formatLocalTime(pass.ValidTo))
+}
 
-	passes, err := b.passService.GetActivePassesByResident(ctx, resident.ID)
+func (b *Bot) showPassesForRevoke(ctx context.Context, chatID int64, resident *domain.Resident) {
+	passes, err := b.visiblePasses(ctx, resident)
 	if err != nil {
-		b.sendMessage(ctx, chatID, fmt.Sprintf("Ошибка при получении пропусков: %s", err.Error()))
-		b.logger.Error("failed to get active passes for revoke", zap.Error(err), zap.Int64("user_id", userID))
+		b.sendMessage(ctx, chatID, b.t(ctx, msgGetPassesFailed, err.Error()))
+		b.logger.Error("failed to get active passes for revoke", zap.Error(err), zap.Int64("resident_id", resident.ID))
 		return
 	}
 
 	if len(passes) == 0 {
-		b.sendMessage(ctx, chatID, "У вас нет активных пропусков для отзыва")
+		b.sendMessage(ctx, chatID, b.t(ctx, msgNoPassesToRevoke))
 		return
 	}
 
 	var keyboardRows [][]map[string]interface{}
 
-	text := "Выберите пропуск для отзыва:\n\n"
+	text := b.t(ctx, msgChoosePassToRevoke)
 	for i, pass := range passes {
 		guestName := ""
 		if pass.GuestName != nil {
@@ -516,21 +768,18 @@ func (b *Bot) showPassesForRevoke(ctx context.Context, chatID int64, userID int6
 			identifier = *pass.CarPlate
 		} else {
 			passType = "🚶"
-			identifier = "Пеший гость"
+			identifier = b.t(ctx, msgPedestrianGuest)
 		}
 
-		text += fmt.Sprintf("%d. %s %s%s\n   Действует до: %s\n\n",
+		text += fmt.Sprintf("%d. %s %s%s\n   %s\n\n",
 			i+1,
 			passType,
 			identifier,
 			guestName,
-			b.formatLocalTime(pass.ValidTo),
+			b.validity(ctx, pass),
 		)
 
-		buttonText := fmt.Sprintf("%s %s", passType, identifier)
-		if len(buttonText) > 64 {
-			buttonText = buttonText[:61] + "..."
-		}
+		buttonText := truncateText(fmt.Sprintf("%s %s", passType, identifier), 64)
 		keyboardRows = append(keyboardRows, []map[string]interface{}{
 			{"text": buttonText, "callback_data": fmt.Sprintf("revoke_pass_%s", pass.ID.String())},
 		})
@@ -543,110 +792,60 @@ func (b *Bot) showPassesForRevoke(ctx context.Context, chatID int64, userID int6
 	b.sendMessageWithKeyboard(ctx, chatID, text, keyboard)
 }
 
-func (b *Bot) revokePass(ctx context.Context, chatID int64, userID int64, passID uuid.UUID) {
-	resident, err := b.residentRepo.GetByTelegramID(ctx, userID)
-	if err != nil || resident == nil {
-		b.sendMessage(ctx, chatID, "Ошибка: житель не найден")
-		return
-	}
-
-	activePasses, err := b.passService.GetActivePassesByResident(ctx, resident.ID)
-	if err != nil {
-		b.sendMessage(ctx, chatID, fmt.Sprintf("Ошибка при проверке пропуска: %s", err.Error()))
-		return
+// ownPass returns the active pass if the user may manage it. Otherwise it
+// reports the problem to the user and returns nil.
+func (b *Bot) ownPass(ctx context.Context, chatID int64, userID int64, passID uuid.UUID) *domain.Pass {
+	residents := b.residentsFor(ctx, chatID, userID)
+	if residents == nil {
+		return nil
 	}
 
-	var passInfo string
-	found := false
-	for _, p := range activePasses {
-		if p.ID == passID {
-			found = true
-			if p.CarPlate != nil {
-				passInfo = fmt.Sprintf("🚗 %s", *p.CarPlate)
-			} else {
-				passInfo = "🚶 Пеший гость"
-			}
-			if p.GuestName != nil {
-				passInfo += fmt.Sprintf(" (%s)", *p.GuestName)
+	for _, resident := range residents {
+		passes, err := b.visiblePasses(ctx, resident)
+		if err != nil {
+			b.sendMessage(ctx, chatID, b.t(ctx, msgCheckPassFailed, err.Error()))
+			return nil
+		}
+		for _, p := range passes {
+			if p.ID == passID {
+				return p
 			}
-			break
 		}
 	}
 
-	if !found {
-		b.sendMessage(ctx, chatID, "Ошибка: пропуск не найден или не принадлежит вам")
-		return
-	}
-
-	err = b.passService.RevokePass(ctx, passID, 0)
-	if err != nil {
-		b.sendMessage(ctx, chatID, fmt.Sprintf("Ошибка при отзыве пропуска: %s", err.Error()))
-		b.logger.Error("failed to revoke pass", zap.Error(err), zap.String("pass_id", passID.String()), zap.Int64("user_id", userID))
-		return
-	}
-
-	b.sendMessage(ctx, chatID, fmt.Sprintf("✅ Пропуск отозван:\n%s\n\nID: %s", passInfo, passID.String()[:8]))
+	b.sendMessage(ctx, chatID, b.t(ctx, msgPassNotYours))
+	return nil
 }
 
-func (b *Bot) getState(userID int64) *UserState {
-	key := fmt.Sprintf("bot_state:%d", userID)
-	stateJSON, err := b.redis.Get(context.Background(), key)
-	if err == nil && stateJSON != "" {
-		var state UserState
-		if json.Unmarshal([]byte(stateJSON), &state) == nil {
-			if time.Now().Before(state.ExpiresAt) {
-				if durationNs, ok := state.Data["duration"].(float64); ok {
-					state.Data["duration"] = time.Duration(durationNs)
-				}
-				if validToStr, ok := state.Data["valid_to"].(string); ok {
-					if validToTime, err := time.Parse(time.RFC3339, validToStr); err == nil {
-						state.Data["valid_to"] = validToTime
-					}
-				}
-				return &state
-			}
-		}
-	}
-
-	state, exists := b.states[userID]
-	if !exists {
-		return nil
+// passInfo is a one-line description of the pass.
+func (b *Bot) passInfo(ctx context.Context, p *domain.Pass) string {
+	var info string
+	if p.CarPlate != nil {
+		info = fmt.Sprintf("🚗 %s", *p.CarPlate)
+	} else {
+		info = "🚶 " + b.t(ctx, msgPedestrianGuest)
 	}
-
-	if time.Now().After(state.ExpiresAt) {
-		delete(b.states, userID)
-		return nil
+	if p.GuestName != nil {
+		info += fmt.Sprintf(" (%s)", *p.GuestName)
 	}
-
-	return state
+	return info
 }
 
-func (b *Bot) setState(userID int64, state *UserState) {
-	key := fmt.Sprintf("bot_state:%d", userID)
-
-	stateCopy := *state
-	stateCopyData := make(map[string]interface{})
-	for k, v := range state.Data {
-		if duration, ok := v.(time.Duration); ok {
-			stateCopyData[k] = int64(duration)
-		} else if validToTime, ok := v.(time.Time); ok {
-			stateCopyData[k] = validToTime.Format(time.RFC3339)
-		} else {
-			stateCopyData[k] = v
-		}
+func (b *Bot) revokePass(ctx context.Context, chatID int64, userID int64, passID uuid.UUID) {
+	pass := b.ownPass(ctx, chatID, userID, passID)
+	if pass == nil {
+		return
 	}
-	stateCopy.Data = stateCopyData
+	passInfo := b.passInfo(ctx, pass)
 
-	stateJSON, _ := json.Marshal(stateCopy)
-	b.redis.Set(context.Background(), key, stateJSON, 10*time.Minute)
-
-	b.states[userID] = state
-}
+	err := b.passService.RevokePass(ctx, passID, 0)
+	if err != nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgRevokeFailed, err.Error()))
+		b.logger.Error("failed to revoke pass", zap.Error(err), zap.String("pass_id", passID.String()), zap.Int64("user_id", userID))
+		return
+	}
 
-func (b *Bot) clearState(userID int64) {
-	key := fmt.Sprintf("bot_state:%d", userID)
-	b.redis.Delete(context.Background(), key)
-	delete(b.states, userID)
+	b.sendMessage(ctx, chatID, b.t(ctx, msgPassRevoked, passInfo, passID.String()[:8]))
 }
 
 func (b *Bot) sendMessage(ctx context.Context, chatID int64, text string) error {
@@ -663,45 +862,7 @@ func (b *Bot) sendMessageWithKeyboard(ctx context.Context, chatID int64, text st
 		payload["reply_markup"] = keyboard
 	}
 
-	return b.callAPI(ctx, "sendMessage", payload)
-}
-
-func (b *Bot) sendPhoto(ctx context.Context, chatID int64, photo []byte, caption string) error {
-	url := fmt.Sprintf("%s/sendPhoto", b.apiURL)
-
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
-
-	writer.WriteField("chat_id", strconv.FormatInt(chatID, 10))
-	writer.WriteField("caption", caption)
-
-	part, err := writer.CreateFormFile("photo", "qr.png")
-	if err != nil {
-		return fmt.Errorf("failed to create form file: %w", err)
-	}
-	if _, err := part.Write(photo); err != nil {
-		return fmt.Errorf("failed to write photo: %w", err)
-	}
-	writer.Close()
-
-	req, err := http.NewRequestWithContext(ctx, "POST", url, body)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("telegram API error (status %d): %s", resp.StatusCode, string(bodyBytes))
-	}
-
-	return nil
+	return b.api.Call(ctx, "sendMessage", payload)
 }
 
 func (b *Bot) answerCallbackQuery(ctx context.Context, callbackQueryID string, text string) error {
@@ -712,93 +873,43 @@ func (b *Bot) answerCallbackQuery(ctx context.Context, callbackQueryID string, t
 		payload["text"] = text
 	}
 
-	return b.callAPI(ctx, "answerCallbackQuery", payload)
+	return b.api.Call(ctx, "answerCallbackQuery", payload)
 }
 
-func (b *Bot) GetUpdates(ctx context.Context, offset int64) ([]Update, error) {
-	payload := map[string]interface{}{
-		"offset":  offset,
-		"timeout": 10,
-	}
-
-	url := fmt.Sprintf("%s/getUpdates", b.apiURL)
-	jsonPayload, err := json.Marshal(payload)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal payload: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonPayload))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("telegram API error (status %d): %s", resp.StatusCode, string(bodyBytes))
-	}
-
-	var result struct {
-		OK     bool     `json:"ok"`
-		Result []Update `json:"result"`
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+// SetMyCommands registers the command menu for every catalog language and
+// the default language for clients in other languages.
+func (b *Bot) SetMyCommands(ctx context.Context) error {
+	if err := b.setMyCommands(ctx, defaultLang, ""); err != nil {
+		return err
 	}
-
-	if !result.OK {
-		return nil, fmt.Errorf("telegram API returned not ok")
+	for _, lang := range supportedLangs {
+		if err := b.setMyCommands(ctx, lang, string(lang)); err != nil {
+			return err
+		}
 	}
-
-	return result.Result, nil
+	return nil
 }
 
-func (b *Bot) SetMyCommands(ctx context.Context) error {
+func (b *Bot) setMyCommands(ctx context.Context, lang Lang, languageCode string) error {
 	commands := []map[string]string{
-		{"command": "start", "description": "Главное меню"},
-		{"command": "create", "description": "Выдать пропуск гостю"},
-		{"command": "list", "description": "Мои активные пропуска"},
-		{"command": "revoke", "description": "Отозвать пропуск"},
+		{"command": "start", "description": translate(lang, msgCommandStart)},
+		{"command": "create", "description": translate(lang, msgMenuCreatePass)},
+		{"command": "list", "description": translate(lang, msgMenuActivePasses)},
+		{"command": "revoke", "description": translate(lang, msgMenuRevokePass)},
+		{"command": "guests", "description": translate(lang, msgMenuGuests)},
+		{"command": "event", "description": translate(lang, msgMenuEvent)},
+		{"command": "cars", "description": translate(lang, msgMenuVehicles)},
+		{"command": "family", "description": translate(lang, msgMenuHousehold)},
+		{"command": "language", "description": translate(lang, msgCommandLanguage)},
+		{"command": "cancel", "description": translate(lang, msgCommandCancel)},
 	}
 
 	payload := map[string]interface{}{
 		"commands": commands,
 	}
-
-	return b.callAPI(ctx, "setMyCommands", payload)
-}
-
-func (b *Bot) callAPI(ctx context.Context, method string, payload map[string]interface{}) error {
-	url := fmt.Sprintf("%s/%s", b.apiURL, method)
-
-	jsonPayload, err := json.Marshal(payload)
-	if err != nil {
-		return fmt.Errorf("failed to marshal payload: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonPayload))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+	if languageCode != "" {
+		payload["language_code"] = languageCode
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("telegram API error (status %d): %s", resp.StatusCode, string(bodyBytes))
-	}
-
-	return nil
+	return b.api.Call(ctx, "setMyCommands", payload)
 }
@@ -0,0 +1,207 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"yardpass/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+const (
+	householdInvitePrefix = "hh_invite:"
+	householdTogglePrefix = "hh_toggle:"
+	householdRemovePrefix = "hh_remove:"
+	joinStartPrefix       = "join_"
+)
+
+func (b *Bot) showHousehold(ctx context.Context, chatID int64, primary *domain.Resident) {
+	residents, err := b.residentService.ListHousehold(ctx, primary.ID)
+	if err != nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgHouseholdFailed, err.Error()))
+		b.logger.Error("failed to list household", zap.Error(err), zap.Int64("resident_id", primary.ID))
+		return
+	}
+
+	text := b.t(ctx, msgHouseholdHeader, b.apartmentLabel(ctx, primary.ApartmentID))
+	var keyboardRows [][]map[string]interface{}
+	members := 0
+	for _, r := range residents {
+		if r.Role != "member" {
+			continue
+		}
+		members++
+
+		name := b.residentName(ctx, r)
+		access := b.t(ctx, msgMemberCanIssue)
+		toggleText := b.t(ctx, msgForbidIssue, name)
+		if !r.CanIssuePasses {
+			access = b.t(ctx, msgMemberCannotIssue)
+			toggleText = b.t(ctx, msgAllowIssue, name)
+		}
+		text += b.t(ctx, msgHouseholdMember, members, name, access)
+
+		keyboardRows = append(keyboardRows, []map[string]interface{}{
+			{"text": toggleText, "callback_data": fmt.Sprintf("%s%d:%d", householdTogglePrefix, primary.ID, r.ID)},
+			{"text": b.t(ctx, msgRemoveMember), "callback_data": fmt.Sprintf("%s%d:%d", householdRemovePrefix, primary.ID, r.ID)},
+		})
+	}
+	if members == 0 {
+		text += b.t(ctx, msgNoMembers)
+	}
+	text += b.t(ctx, msgSharedLimit)
+
+	keyboardRows = append(keyboardRows,
+		[]map[string]interface{}{
+			{"text": b.t(ctx, msgInviteWithPasses), "callback_data": fmt.Sprintf("%s%d:1", householdInvitePrefix, primary.ID)},
+		},
+		[]map[string]interface{}{
+			{"text": b.t(ctx, msgInviteWithoutPasses), "callback_data": fmt.Sprintf("%s%d:0", householdInvitePrefix, primary.ID)},
+		},
+	)
+
+	keyboard := map[string]interface{}{
+		"inline_keyboard": keyboardRows,
+	}
+
+	b.sendMessageWithKeyboard(ctx, chatID, text, keyboard)
+}
+
+// handleHouseholdCallback serves the invite/toggle/remove buttons of the
+// household screen. The primary resident ID in the callback data is checked
+// against the caller's own resident rows.
+func (b *Bot) handleHouseholdCallback(ctx context.Context, cb CallbackQuery) {
+	chatID := cb.Message.Chat.ID
+
+	var prefix string
+	for _, p := range []string{householdInvitePrefix, householdTogglePrefix, householdRemovePrefix} {
+		if strings.HasPrefix(cb.Data, p) {
+			prefix = p
+			break
+		}
+	}
+
+	primaryIDStr, argStr, ok := strings.Cut(strings.TrimPrefix(cb.Data, prefix), ":")
+	primaryID, err1 := strconv.ParseInt(primaryIDStr, 10, 64)
+	arg, err2 := strconv.ParseInt(argStr, 10, 64)
+	if !ok || err1 != nil || err2 != nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgInvalidButtonData))
+		return
+	}
+
+	var primary *domain.Resident
+	for _, r := range b.residentsFor(ctx, chatID, cb.From.ID) {
+		if r.ID == primaryID && r.Role == "primary" {
+			primary = r
+			break
+		}
+	}
+	if primary == nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgPrimaryOnly))
+		return
+	}
+
+	switch prefix {
+	case householdInvitePrefix:
+		b.createHouseholdInvite(ctx, chatID, primary, arg == 1)
+
+	case householdTogglePrefix:
+		members, err := b.residentService.ListHousehold(ctx, primary.ID)
+		if err != nil {
+			b.sendMessage(ctx, chatID, b.t(ctx, msgError, err.Error()))
+			return
+		}
+		canIssue := true
+		for _, m := range members {
+			if m.ID == arg {
+				canIssue = !m.CanIssuePasses
+			}
+		}
+		member, err := b.residentService.SetMemberCanIssuePasses(ctx, primary.ID, arg, canIssue)
+		if err != nil {
+			b.sendMessage(ctx, chatID, b.t(ctx, msgError, err.Error()))
+			return
+		}
+		if member.CanIssuePasses {
+			b.sendMessage(ctx, chatID, b.t(ctx, msgMemberCanIssueNow, b.residentName(ctx, member)))
+		} else {
+			b.sendMessage(ctx, chatID, b.t(ctx, msgMemberCannotIssueNow, b.residentName(ctx, member)))
+		}
+		b.showHousehold(ctx, chatID, primary)
+
+	case householdRemovePrefix:
+		member, err := b.residentService.RemoveHouseholdMember(ctx, primary.ID, arg)
+		if err != nil {
+			b.sendMessage(ctx, chatID, b.t(ctx, msgError, err.Error()))
+			return
+		}
+		b.sendMessage(ctx, chatID, b.t(ctx, msgMemberRemoved, b.residentName(ctx, member)))
+		memberCtx := withLang(ctx, residentLang(member))
+		b.sendMessage(memberCtx, member.ChatID, b.t(memberCtx, msgRemovedFromHousehold, b.apartmentLabel(memberCtx, member.ApartmentID)))
+		b.showHousehold(ctx, chatID, primary)
+	}
+}
+
+func (b *Bot) createHouseholdInvite(ctx context.Context, chatID int64, primary *domain.Resident, canIssuePasses bool) {
+	code, ttl, err := b.residentService.CreateHouseholdInvite(ctx, primary.ID, canIssuePasses)
+	if err != nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgInviteFailed, err.Error()))
+		b.logger.Error("failed to create household invite", zap.Error(err), zap.Int64("resident_id", primary.ID))
+		return
+	}
+
+	text := b.t(ctx, msgInviteCreated, int(ttl.Hours()), code)
+	if b.botUsername != "" {
+		text += b.t(ctx, msgInviteLink, fmt.Sprintf("https://t.me/%s?start=%s%s", b.botUsername, joinStartPrefix, code))
+	}
+
+	b.sendMessage(ctx, chatID, text)
+}
+
+func (b *Bot) handleJoin(ctx context.Context, msg Message, code string) {
+	if code == "" {
+		b.sendMessage(ctx, msg.Chat.ID, b.t(ctx, msgJoinUsage))
+		return
+	}
+
+	name := strings.TrimSpace(strings.TrimSpace(msg.From.FirstName) + " " + strings.TrimSpace(msg.From.LastName))
+	var namePtr *string
+	if name != "" {
+		namePtr = &name
+	}
+
+	member, err := b.residentService.AcceptHouseholdInvite(ctx, code, msg.From.ID, msg.Chat.ID, namePtr)
+	if err != nil {
+		b.sendMessage(ctx, msg.Chat.ID, b.t(ctx, msgJoinFailed, err.Error()))
+		b.logger.Warn("failed to accept household invite", zap.Error(err), zap.Int64("telegram_id", msg.From.ID))
+		return
+	}
+
+	b.sendMessage(ctx, msg.Chat.ID, b.t(ctx, msgJoined, b.apartmentLabel(ctx, member.ApartmentID)))
+
+	if member.InvitedBy != nil {
+		if primary, err := b.residentRepo.GetByID(ctx, *member.InvitedBy); err == nil && primary != nil {
+			primaryCtx := withLang(ctx, residentLang(primary))
+			b.sendMessage(primaryCtx, primary.ChatID, b.t(primaryCtx, msgMemberJoined, b.residentName(primaryCtx, member), b.apartmentLabel(primaryCtx, member.ApartmentID)))
+		}
+	}
+}
+
+// visiblePasses returns the active passes a resident may see and revoke:
+// the whole apartment for the primary resident, own passes for members.
+func (b *Bot) visiblePasses(ctx context.Context, resident *domain.Resident) ([]*domain.Pass, error) {
+	if resident.Role == "primary" {
+		return b.passService.GetActivePasses(ctx, resident.ApartmentID)
+	}
+	return b.passService.GetActivePassesByResident(ctx, resident.ID)
+}
+
+func (b *Bot) residentName(ctx context.Context, r *domain.Resident) string {
+	if r.Name != nil && *r.Name != "" {
+		return *r.Name
+	}
+	return b.t(ctx, msgResidentFallback, r.ID)
+}
@@ -0,0 +1,134 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"yardpass/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// Lang is a bot interface language.
+type Lang string
+
+const (
+	LangRU Lang = "ru"
+	LangEN Lang = "en"
+
+	defaultLang = LangRU
+)
+
+// supportedLangs lists the interface languages in the order they are
+// offered by /language.
+var supportedLangs = []Lang{LangRU, LangEN}
+
+// catalogs holds the bot texts of every supported language. Each catalog
+// must define every msgKey, see TestCatalogsComplete.
+var catalogs = map[Lang]map[msgKey]string{
+	LangRU: messagesRU,
+	LangEN: messagesEN,
+}
+
+// langNames are shown on the /language buttons in the language itself.
+var langNames = map[Lang]string{
+	LangRU: "🇷🇺 Русский",
+	LangEN: "🇬🇧 English",
+}
+
+// cyrillicLanguages get the Russian interface when the user has not chosen
+// a language; any other Telegram language gets English.
+var cyrillicLanguages = map[string]bool{"ru": true, "be": true, "uk": true, "kk": true}
+
+type langContextKey struct{}
+
+func withLang(ctx context.Context, lang Lang) context.Context {
+	return context.WithValue(ctx, langContextKey{}, lang)
+}
+
+func langFrom(ctx context.Context) Lang {
+	if lang, ok := ctx.Value(langContextKey{}).(Lang); ok {
+		return lang
+	}
+	return defaultLang
+}
+
+// parseLang returns the supported language of a code like "en" or "en-US".
+func parseLang(code string) (Lang, bool) {
+	base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(code)), "-")
+	lang := Lang(base)
+	_, ok := catalogs[lang]
+	return lang, ok
+}
+
+// languageFor maps a Telegram language_code to the interface language.
+func languageFor(languageCode string) Lang {
+	if lang, ok := parseLang(languageCode); ok {
+		return lang
+	}
+	base, _, _ := strings.Cut(strings.ToLower(languageCode), "-")
+	if base == "" || cyrillicLanguages[base] {
+		return defaultLang
+	}
+	return LangEN
+}
+
+// translate formats the message of key in lang, falling back to the default
+// language for keys missing from a catalog.
+func translate(lang Lang, key msgKey, args ...interface{}) string {
+	text, ok := catalogs[lang][key]
+	if !ok {
+		text, ok = catalogs[defaultLang][key]
+	}
+	if !ok {
+		return string(key)
+	}
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+// t translates key into the language of the update being processed.
+func (b *Bot) t(ctx context.Context, key msgKey, args ...interface{}) string {
+	return translate(langFrom(ctx), key, args...)
+}
+
+// userLang picks the language of a Telegram user: the one stored with
+// /language, otherwise the language of their Telegram client.
+func (b *Bot) userLang(ctx context.Context, user *User) Lang {
+	if user == nil {
+		return defaultLang
+	}
+
+	residents, err := b.residentRepo.ListByTelegramID(ctx, user.ID)
+	if err != nil {
+		b.logger.Error("failed to get resident language", zap.Error(err), zap.Int64("user_id", user.ID))
+	}
+	for _, r := range residents {
+		if r.Language != nil {
+			if lang, ok := parseLang(*r.Language); ok {
+				return lang
+			}
+		}
+	}
+	return languageFor(user.LanguageCode)
+}
+
+// residentLang is the language for messages sent to a resident outside of
+// their own updates, e.g. household notifications.
+func residentLang(r *domain.Resident) Lang {
+	return storedLang(r.Language)
+}
+
+// storedLang is the language saved for a resident, or the default one when
+// they have not picked any.
+func storedLang(language *string) Lang {
+	if language != nil {
+		if lang, ok := parseLang(*language); ok {
+			return lang
+		}
+	}
+	return defaultLang
+}
@@ -0,0 +1,91 @@
+package telegram
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// declaredMessageKeys parses messages.go for every msgKey constant, so that
+// a key added there without texts fails the completeness test.
+func declaredMessageKeys(t *testing.T) []msgKey {
+	t.Helper()
+
+	file, err := parser.ParseFile(token.NewFileSet(), "messages.go", nil, 0)
+	require.NoError(t, err)
+
+	var keys []msgKey
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			value := spec.(*ast.ValueSpec)
+			if ident, ok := value.Type.(*ast.Ident); !ok || ident.Name != "msgKey" {
+				continue
+			}
+			for _, lit := range value.Values {
+				keys = append(keys, msgKey(strings.Trim(lit.(*ast.BasicLit).Value, `"`)))
+			}
+		}
+	}
+	require.NotEmpty(t, keys)
+	return keys
+}
+
+var formatVerb = regexp.MustCompile(`%[-+# 0]*\d*(\.\d+)?[a-zA-Z%]`)
+
+func TestCatalogsComplete(t *testing.T) {
+	keys := declaredMessageKeys(t)
+
+	for _, lang := range supportedLangs {
+		catalog, ok := catalogs[lang]
+		require.True(t, ok, "no catalog for %s", lang)
+
+		for _, key := range keys {
+			text, ok := catalog[key]
+			if assert.True(t, ok, "%s: missing %q", lang, key) {
+				assert.NotEmpty(t, strings.TrimSpace(text), "%s: empty %q", lang, key)
+			}
+		}
+		assert.Len(t, catalog, len(keys), "%s has texts for undeclared keys", lang)
+	}
+}
+
+func TestCatalogsFormatVerbsMatch(t *testing.T) {
+	for key, text := range catalogs[defaultLang] {
+		want := formatVerb.FindAllString(text, -1)
+		for _, lang := range supportedLangs {
+			assert.Equal(t, want, formatVerb.FindAllString(catalogs[lang][key], -1), "%s: verbs of %q", lang, key)
+		}
+	}
+}
+
+func TestCatalogsCalendarLabels(t *testing.T) {
+	for _, lang := range supportedLangs {
+		assert.Len(t, strings.Split(translate(lang, msgMonthNames), ","), 12, lang)
+		assert.Len(t, strings.Split(translate(lang, msgWeekdayNames), ","), 7, lang)
+	}
+}
+
+func TestLanguageFor(t *testing.T) {
+	tests := map[string]Lang{
+		"":      LangRU,
+		"ru":    LangRU,
+		"en":    LangEN,
+		"en-GB": LangEN,
+		"uk":    LangRU,
+		"kk":    LangRU,
+		"de":    LangEN,
+	}
+	for code, want := range tests {
+		assert.Equal(t, want, languageFor(code), code)
+	}
+}
@@ -0,0 +1,76 @@
+package telegram
+
+import (
+	"context"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+const (
+	languagePrefix = "lang:"
+	languageAuto   = "auto"
+)
+
+func (b *Bot) showLanguages(ctx context.Context, chatID int64) {
+	var keyboardRows [][]map[string]interface{}
+	for _, lang := range supportedLangs {
+		keyboardRows = append(keyboardRows, []map[string]interface{}{
+			{"text": langNames[lang], "callback_data": languagePrefix + string(lang)},
+		})
+	}
+	keyboardRows = append(keyboardRows, []map[string]interface{}{
+		{"text": b.t(ctx, msgLanguageAuto), "callback_data": languagePrefix + languageAuto},
+	})
+
+	keyboard := map[string]interface{}{
+		"inline_keyboard": keyboardRows,
+	}
+
+	b.sendMessageWithKeyboard(ctx, chatID, b.t(ctx, msgChooseLanguage), keyboard)
+}
+
+// handleLanguageCallback stores the chosen language for all resident rows of
+// the user and confirms in the new language. Users who are not residents
+// keep the language of their Telegram client.
+func (b *Bot) handleLanguageCallback(ctx context.Context, cb CallbackQuery) {
+	chatID := cb.Message.Chat.ID
+	choice := strings.TrimPrefix(cb.Data, languagePrefix)
+
+	var language *string
+	lang := languageFor(cb.From.LanguageCode)
+	if choice != languageAuto {
+		parsed, ok := parseLang(choice)
+		if !ok {
+			b.sendMessage(ctx, chatID, b.t(ctx, msgInvalidButtonData))
+			return
+		}
+		lang = parsed
+		code := string(parsed)
+		language = &code
+	}
+
+	residents, err := b.residentRepo.ListByTelegramID(ctx, cb.From.ID)
+	if err != nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgLanguageSaveFailed, err.Error()))
+		b.logger.Error("failed to list residents", zap.Error(err), zap.Int64("user_id", cb.From.ID))
+		return
+	}
+	if len(residents) == 0 {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgLanguageNotSaved))
+		return
+	}
+
+	if err := b.residentService.SetLanguage(ctx, cb.From.ID, language); err != nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgLanguageSaveFailed, err.Error()))
+		b.logger.Error("failed to set language", zap.Error(err), zap.Int64("user_id", cb.From.ID))
+		return
+	}
+
+	ctx = withLang(ctx, lang)
+	if language == nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgLanguageAutoSet))
+		return
+	}
+	b.sendMessage(ctx, chatID, b.t(ctx, msgLanguageSet, langNames[lang]))
+}
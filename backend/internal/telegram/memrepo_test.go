@@ -0,0 +1,998 @@
+package telegram
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"yardpass/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// In-memory repositories for the bot scenario tests. They embed the
+// interfaces so that methods the bot does not use panic if called.
+
+type memBuildingRepo struct {
+	domain.BuildingRepository
+	buildings map[int64]*domain.Building
+}
+
+func (r *memBuildingRepo) GetByID(ctx context.Context, id int64) (*domain.Building, error) {
+	return r.buildings[id], nil
+}
+
+func (r *memBuildingRepo) GetByVisitToken(ctx context.Context, token string) (*domain.Building, error) {
+	for _, b := range r.buildings {
+		if b.VisitToken != nil && *b.VisitToken == token {
+			return b, nil
+		}
+	}
+	return nil, nil
+}
+
+type memApartmentRepo struct {
+	domain.ApartmentRepository
+	apartments map[int64]*domain.Apartment
+}
+
+func (r *memApartmentRepo) GetByID(ctx context.Context, id int64) (*domain.Apartment, error) {
+	return r.apartments[id], nil
+}
+
+func (r *memApartmentRepo) GetByBuildingID(ctx context.Context, buildingID int64) ([]*domain.Apartment, error) {
+	var result []*domain.Apartment
+	for _, a := range r.apartments {
+		if a.BuildingID == buildingID {
+			result = append(result, a)
+		}
+	}
+	return result, nil
+}
+
+type memRuleRepo struct {
+	domain.RuleRepository
+	rule *domain.Rule
+}
+
+func (r *memRuleRepo) GetByBuildingID(ctx context.Context, buildingID int64) (*domain.Rule, error) {
+	return r.rule, nil
+}
+
+type memUserRepo struct {
+	domain.UserRepository
+
+	users []*domain.User
+}
+
+func (r *memUserRepo) GetByTelegramID(ctx context.Context, telegramID int64) (*domain.User, error) {
+	return nil, nil
+}
+
+func (r *memUserRepo) List(ctx context.Context, filters domain.UserFilters) ([]*domain.User, error) {
+	var users []*domain.User
+	for _, u := range r.users {
+		if filters.Role != nil && u.Role != *filters.Role {
+			continue
+		}
+		if filters.BuildingID != nil && (u.BuildingID == nil || *u.BuildingID != *filters.BuildingID) {
+			continue
+		}
+		if filters.Status != nil && u.Status != *filters.Status {
+			continue
+		}
+		users = append(users, u)
+	}
+	return users, nil
+}
+
+type memResidentRepo struct {
+	domain.ResidentRepository
+
+	mu        sync.Mutex
+	residents []*domain.Resident
+}
+
+func (r *memResidentRepo) GetByID(ctx context.Context, id int64) (*domain.Resident, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, res := range r.residents {
+		if res.ID == id {
+			return res, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memResidentRepo) ListByTelegramID(ctx context.Context, telegramID int64) ([]*domain.Resident, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var result []*domain.Resident
+	for _, res := range r.residents {
+		if res.TelegramID == telegramID && res.Status == "active" {
+			result = append(result, res)
+		}
+	}
+	return result, nil
+}
+
+func (r *memResidentRepo) List(ctx context.Context, filters domain.ResidentFilters) ([]*domain.Resident, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var result []*domain.Resident
+	for _, res := range r.residents {
+		if filters.ApartmentID != nil && res.ApartmentID != *filters.ApartmentID {
+			continue
+		}
+		if filters.Status != nil && res.Status != *filters.Status {
+			continue
+		}
+		result = append(result, res)
+	}
+	return result, nil
+}
+
+func (r *memResidentRepo) SetLanguageByTelegramID(ctx context.Context, telegramID int64, language *string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, res := range r.residents {
+		if res.TelegramID == telegramID {
+			res.Language = language
+		}
+	}
+	return nil
+}
+
+type memPassRepo struct {
+	domain.PassRepository
+
+	mu     sync.Mutex
+	passes []*domain.Pass
+	tokens map[uuid.UUID]string
+}
+
+func (r *memPassRepo) all() []*domain.Pass {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]*domain.Pass(nil), r.passes...)
+}
+
+func (r *memPassRepo) Create(ctx context.Context, pass *domain.Pass) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pass.CreatedAt = time.Now()
+	pass.UpdatedAt = pass.CreatedAt
+	r.passes = append(r.passes, pass)
+	return nil
+}
+
+func (r *memPassRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Pass, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, p := range r.passes {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memPassRepo) GetActiveByApartmentID(ctx context.Context, apartmentID int64) ([]*domain.Pass, error) {
+	return r.active(func(p *domain.Pass) bool { return p.ApartmentID == apartmentID }), nil
+}
+
+// GetActiveByCarPlate ignores buildingID; the harness has one building.
+func (r *memPassRepo) GetActiveByCarPlate(ctx context.Context, normalizedCarPlate string, buildingID *int64) (*domain.Pass, error) {
+	passes := r.active(func(p *domain.Pass) bool { return p.CarPlate != nil && *p.CarPlate == normalizedCarPlate })
+	if len(passes) == 0 {
+		return nil, nil
+	}
+	return passes[len(passes)-1], nil
+}
+
+// ListActiveByCarPlateLength ignores buildingID; the harness has one
+// building.
+func (r *memPassRepo) ListActiveByCarPlateLength(ctx context.Context, buildingID *int64, minLength, maxLength int) ([]*domain.Pass, error) {
+	return r.active(func(p *domain.Pass) bool {
+		return p.CarPlate != nil && len(*p.CarPlate) >= minLength && len(*p.CarPlate) <= maxLength
+	}), nil
+}
+
+func (r *memPassRepo) GetActiveByResidentID(ctx context.Context, residentID int64) ([]*domain.Pass, error) {
+	return r.active(func(p *domain.Pass) bool { return p.ResidentID != nil && *p.ResidentID == residentID }), nil
+}
+
+func (r *memPassRepo) CountActiveTodayByApartmentID(ctx context.Context, apartmentID int64) (int, error) {
+	return len(r.active(func(p *domain.Pass) bool { return p.ApartmentID == apartmentID })), nil
+}
+
+func (r *memPassRepo) Revoke(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, p := range r.passes {
+		if p.ID == id {
+			p.Status = "revoked"
+		}
+	}
+	return nil
+}
+
+func (r *memPassRepo) SetShareToken(ctx context.Context, id uuid.UUID, token string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.tokens == nil {
+		r.tokens = make(map[uuid.UUID]string)
+	}
+	if current, ok := r.tokens[id]; ok {
+		return current, nil
+	}
+	r.tokens[id] = token
+	return token, nil
+}
+
+func (r *memPassRepo) SetQRSecret(ctx context.Context, id uuid.UUID, secret []byte) ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, p := range r.passes {
+		if p.ID == id {
+			if p.QRSecret == nil {
+				p.QRSecret = secret
+			}
+			p.Dynamic = true
+			return p.QRSecret, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memPassRepo) ClearShareToken(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.tokens, id)
+	return nil
+}
+
+func (r *memPassRepo) active(match func(p *domain.Pass) bool) []*domain.Pass {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	var result []*domain.Pass
+	for _, p := range r.passes {
+		if p.Status == "active" && p.ValidTo.After(now) && match(p) {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+type memSavedGuestRepo struct {
+	mu     sync.Mutex
+	nextID int64
+	guests []*domain.SavedGuest
+}
+
+func (r *memSavedGuestRepo) GetByID(ctx context.Context, id int64) (*domain.SavedGuest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, g := range r.guests {
+		if g.ID == id {
+			copied := *g
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memSavedGuestRepo) ListByResidentID(ctx context.Context, residentID int64, limit int) ([]*domain.SavedGuest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var result []*domain.SavedGuest
+	for _, g := range r.guests {
+		if g.ResidentID == residentID {
+			copied := *g
+			result = append(result, &copied)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Starred != result[j].Starred {
+			return result[i].Starred
+		}
+		return result[i].LastUsedAt.After(result[j].LastUsedAt)
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (r *memSavedGuestRepo) Touch(ctx context.Context, residentID int64, carPlate string, guestName *string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, g := range r.guests {
+		if g.ResidentID == residentID && g.CarPlate == carPlate {
+			if guestName != nil {
+				g.GuestName = guestName
+			}
+			g.UseCount++
+			g.LastUsedAt = now
+			return nil
+		}
+	}
+	r.nextID++
+	r.guests = append(r.guests, &domain.SavedGuest{
+		ID:         r.nextID,
+		ResidentID: residentID,
+		CarPlate:   carPlate,
+		GuestName:  guestName,
+		UseCount:   1,
+		LastUsedAt: now,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	})
+	return nil
+}
+
+func (r *memSavedGuestRepo) SetStarred(ctx context.Context, id int64, starred bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, g := range r.guests {
+		if g.ID == id {
+			g.Starred = starred
+		}
+	}
+	return nil
+}
+
+func (r *memSavedGuestRepo) Delete(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, g := range r.guests {
+		if g.ID == id {
+			r.guests = append(r.guests[:i], r.guests[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *memSavedGuestRepo) PruneRecent(ctx context.Context, residentID int64, keep int) error {
+	return nil
+}
+
+type memBroadcastRepo struct {
+	domain.BroadcastRepository
+
+	mu         sync.Mutex
+	recipients []*domain.BroadcastRecipient
+	texts      map[int64]string
+	languages  map[int64]*string
+	claimedAt  map[int64]time.Time
+}
+
+// queue adds a pending recipient of broadcast 1.
+func (r *memBroadcastRepo) queue(chatID int64, text string, language *string) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.texts == nil {
+		r.texts = make(map[int64]string)
+		r.languages = make(map[int64]*string)
+	}
+	id := int64(len(r.recipients) + 1)
+	r.recipients = append(r.recipients, &domain.BroadcastRecipient{
+		ID:          id,
+		BroadcastID: 1,
+		ChatID:      chatID,
+		Status:      domain.RecipientStatusPending,
+	})
+	r.texts[id] = text
+	r.languages[id] = language
+	return id
+}
+
+func (r *memBroadcastRepo) recipient(id int64) domain.BroadcastRecipient {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return *r.recipients[id-1]
+}
+
+func (r *memBroadcastRepo) ClaimPending(ctx context.Context, limit int) ([]*domain.BroadcastDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deliveries []*domain.BroadcastDelivery
+	for _, rec := range r.recipients {
+		if len(deliveries) == limit {
+			break
+		}
+		if rec.Status != domain.RecipientStatusPending {
+			continue
+		}
+		rec.Status = domain.RecipientStatusSending
+		rec.Attempts++
+		if r.claimedAt == nil {
+			r.claimedAt = make(map[int64]time.Time)
+		}
+		r.claimedAt[rec.ID] = time.Now()
+		deliveries = append(deliveries, &domain.BroadcastDelivery{
+			RecipientID: rec.ID,
+			BroadcastID: rec.BroadcastID,
+			ChatID:      rec.ChatID,
+			Text:        r.texts[rec.ID],
+			Language:    r.languages[rec.ID],
+			Attempts:    rec.Attempts,
+		})
+	}
+	return deliveries, nil
+}
+
+func (r *memBroadcastRepo) ReleaseSending(ctx context.Context, lease time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, rec := range r.recipients {
+		if rec.Status == domain.RecipientStatusSending && time.Since(r.claimedAt[rec.ID]) > lease {
+			rec.Status = domain.RecipientStatusPending
+		}
+	}
+	return nil
+}
+
+func (r *memBroadcastRepo) MarkSent(ctx context.Context, recipientID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	rec := r.recipients[recipientID-1]
+	rec.Status = domain.RecipientStatusSent
+	rec.SentAt = &now
+	return nil
+}
+
+func (r *memBroadcastRepo) MarkFailed(ctx context.Context, recipientID int64, reason, errorText string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rec := r.recipients[recipientID-1]
+	rec.Status = domain.RecipientStatusFailed
+	rec.FailureReason = &reason
+	rec.Error = &errorText
+	return nil
+}
+
+func (r *memBroadcastRepo) Requeue(ctx context.Context, recipientID int64, errorText string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rec := r.recipients[recipientID-1]
+	rec.Status = domain.RecipientStatusPending
+	rec.Error = &errorText
+	return nil
+}
+
+type memPassRequestRepo struct {
+	domain.PassRequestRepository
+
+	mu       sync.Mutex
+	requests []*domain.PassRequest
+}
+
+func (r *memPassRequestRepo) all() []domain.PassRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make([]domain.PassRequest, 0, len(r.requests))
+	for _, req := range r.requests {
+		result = append(result, *req)
+	}
+	return result
+}
+
+func (r *memPassRequestRepo) Create(ctx context.Context, request *domain.PassRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	request.ID = int64(len(r.requests) + 1)
+	request.CreatedAt = time.Now()
+	request.UpdatedAt = request.CreatedAt
+	stored := *request
+	r.requests = append(r.requests, &stored)
+	return nil
+}
+
+func (r *memPassRequestRepo) GetByID(ctx context.Context, id int64) (*domain.PassRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, req := range r.requests {
+		if req.ID == id {
+			found := *req
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memPassRequestRepo) CountRecentByGuest(ctx context.Context, guestTelegramID int64, window time.Duration) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, req := range r.requests {
+		if req.GuestTelegramID == guestTelegramID && time.Since(req.CreatedAt) < window {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *memPassRequestRepo) Resolve(ctx context.Context, id int64, status string, decidedBy *int64, passID *uuid.UUID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, req := range r.requests {
+		if req.ID == id && req.Status == domain.PassRequestStatusPending {
+			now := time.Now()
+			req.Status = status
+			req.DecidedBy = decidedBy
+			req.DecidedAt = &now
+			req.PassID = passID
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type memEntryRequestRepo struct {
+	domain.EntryRequestRepository
+
+	mu       sync.Mutex
+	requests []*domain.EntryRequest
+}
+
+func (r *memEntryRequestRepo) Create(ctx context.Context, request *domain.EntryRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	request.ID = int64(len(r.requests) + 1)
+	request.CreatedAt = time.Now()
+	request.UpdatedAt = request.CreatedAt
+	stored := *request
+	r.requests = append(r.requests, &stored)
+	return nil
+}
+
+func (r *memEntryRequestRepo) GetByID(ctx context.Context, id int64) (*domain.EntryRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, req := range r.requests {
+		if req.ID == id {
+			found := *req
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memEntryRequestRepo) ClaimUnnotified(ctx context.Context, limit int) ([]*domain.EntryRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var claimed []*domain.EntryRequest
+	for _, req := range r.requests {
+		if len(claimed) == limit {
+			break
+		}
+		if req.NotifiedAt == nil && req.Status == domain.EntryRequestStatusPending {
+			now := time.Now()
+			req.NotifiedAt = &now
+			found := *req
+			claimed = append(claimed, &found)
+		}
+	}
+	return claimed, nil
+}
+
+func (r *memEntryRequestRepo) Resolve(ctx context.Context, id int64, status string, decidedBy *int64, passID *uuid.UUID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, req := range r.requests {
+		if req.ID == id && req.Status == domain.EntryRequestStatusPending {
+			now := time.Now()
+			req.Status = status
+			req.DecidedBy = decidedBy
+			req.DecidedAt = &now
+			req.PassID = passID
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memEntryRequestRepo) ExpireOverdue(ctx context.Context, now time.Time) ([]*domain.EntryRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var expired []*domain.EntryRequest
+	for _, req := range r.requests {
+		if req.Status == domain.EntryRequestStatusPending && req.ExpiresAt.Before(now) {
+			req.Status = domain.EntryRequestStatusExpired
+			req.DecidedAt = &now
+			found := *req
+			expired = append(expired, &found)
+		}
+	}
+	return expired, nil
+}
+
+type memScanEventRepo struct {
+	domain.ScanEventRepository
+
+	mu     sync.Mutex
+	events []domain.ScanEvent
+}
+
+func (r *memScanEventRepo) all() []domain.ScanEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]domain.ScanEvent(nil), r.events...)
+}
+
+func (r *memScanEventRepo) Create(ctx context.Context, event *domain.ScanEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	event.ID = int64(len(r.events) + 1)
+	r.events = append(r.events, *event)
+	return nil
+}
+
+// memEventRepo stores event passes in the pass repository, like the
+// passes table does.
+type memEventRepo struct {
+	domain.EventRepository
+
+	passes *memPassRepo
+	scans  *memScanEventRepo
+
+	mu      sync.Mutex
+	events  []*domain.Event
+	members map[int64][]uuid.UUID
+}
+
+func (r *memEventRepo) Create(ctx context.Context, event *domain.Event, passes []*domain.Pass) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.members == nil {
+		r.members = make(map[int64][]uuid.UUID)
+	}
+	event.ID = int64(len(r.events) + 1)
+	event.CreatedAt = time.Now()
+	event.UpdatedAt = event.CreatedAt
+	event.PassCount = len(passes)
+	for _, pass := range passes {
+		r.passes.Create(ctx, pass)
+		r.members[event.ID] = append(r.members[event.ID], pass.ID)
+	}
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *memEventRepo) GetByID(ctx context.Context, id int64) (*domain.Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, e := range r.events {
+		if e.ID == id {
+			return e, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memEventRepo) GetPasses(ctx context.Context, eventID int64) ([]*domain.Pass, error) {
+	r.mu.Lock()
+	ids := r.members[eventID]
+	r.mu.Unlock()
+
+	var passes []*domain.Pass
+	for _, id := range ids {
+		pass, _ := r.passes.GetByID(ctx, id)
+		passes = append(passes, pass)
+	}
+	return passes, nil
+}
+
+func (r *memEventRepo) Revoke(ctx context.Context, id int64) (int, error) {
+	event, _ := r.GetByID(ctx, id)
+	passes, _ := r.GetPasses(ctx, id)
+
+	r.mu.Lock()
+	event.Status = domain.EventStatusRevoked
+	r.mu.Unlock()
+
+	revoked := 0
+	for _, pass := range passes {
+		if pass.Status == "active" {
+			r.passes.Revoke(ctx, pass.ID)
+			revoked++
+		}
+	}
+	return revoked, nil
+}
+
+func (r *memEventRepo) Attendance(ctx context.Context, eventID int64) ([]*domain.EventGuestAttendance, error) {
+	passes, _ := r.GetPasses(ctx, eventID)
+	scans := r.scans.all()
+
+	var guests []*domain.EventGuestAttendance
+	for _, pass := range passes {
+		guest := &domain.EventGuestAttendance{PassID: pass.ID, CarPlate: pass.CarPlate, GuestName: pass.GuestName, PassStatus: pass.Status}
+		for _, scan := range scans {
+			if scan.PassID != nil && *scan.PassID == pass.ID && scan.Result == "valid" {
+				guest.Entries++
+				if guest.FirstEntryAt == nil {
+					at := scan.ScannedAt
+					guest.FirstEntryAt = &at
+				}
+			}
+		}
+		guests = append(guests, guest)
+	}
+	return guests, nil
+}
+
+type memPassExceptionRepo struct {
+	domain.PassExceptionRepository
+
+	mu         sync.Mutex
+	exceptions []*domain.PassException
+	delivered  map[int64]bool
+}
+
+func (r *memPassExceptionRepo) Create(ctx context.Context, exception *domain.PassException) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	exception.ID = int64(len(r.exceptions) + 1)
+	exception.CreatedAt = time.Now()
+	exception.UpdatedAt = exception.CreatedAt
+	stored := *exception
+	r.exceptions = append(r.exceptions, &stored)
+	return nil
+}
+
+func (r *memPassExceptionRepo) GetByID(ctx context.Context, id int64) (*domain.PassException, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, e := range r.exceptions {
+		if e.ID == id {
+			found := *e
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memPassExceptionRepo) CountPendingByResident(ctx context.Context, residentID int64) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, e := range r.exceptions {
+		if e.ResidentID == residentID && e.Status == domain.PassExceptionStatusPending {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *memPassExceptionRepo) Resolve(ctx context.Context, id int64, status string, decidedBy int64, comment *string, passID *uuid.UUID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, e := range r.exceptions {
+		if e.ID == id && e.Status == domain.PassExceptionStatusPending {
+			now := time.Now()
+			e.Status = status
+			e.DecidedBy = &decidedBy
+			e.DecidedAt = &now
+			e.Comment = comment
+			e.PassID = passID
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memPassExceptionRepo) ClaimUndelivered(ctx context.Context, limit int) ([]*domain.PassException, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.delivered == nil {
+		r.delivered = make(map[int64]bool)
+	}
+	var claimed []*domain.PassException
+	for _, e := range r.exceptions {
+		if len(claimed) == limit {
+			break
+		}
+		if e.Status != domain.PassExceptionStatusPending && !r.delivered[e.ID] {
+			r.delivered[e.ID] = true
+			found := *e
+			claimed = append(claimed, &found)
+		}
+	}
+	return claimed, nil
+}
+
+type memResidentVehicleRepo struct {
+	domain.ResidentVehicleRepository
+
+	mu       sync.Mutex
+	vehicles []*domain.ResidentVehicle
+	nextID   int64
+}
+
+func (r *memResidentVehicleRepo) Create(ctx context.Context, vehicle *domain.ResidentVehicle) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	vehicle.ID = r.nextID
+	vehicle.CreatedAt = time.Now()
+	vehicle.UpdatedAt = vehicle.CreatedAt
+	stored := *vehicle
+	r.vehicles = append(r.vehicles, &stored)
+	return nil
+}
+
+func (r *memResidentVehicleRepo) GetByID(ctx context.Context, id int64) (*domain.ResidentVehicle, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, v := range r.vehicles {
+		if v.ID == id {
+			found := *v
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memResidentVehicleRepo) GetByCarPlate(ctx context.Context, normalizedCarPlate string, buildingID *int64) (*domain.ResidentVehicle, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, v := range r.vehicles {
+		if v.CarPlate == normalizedCarPlate && (buildingID == nil || v.BuildingID == *buildingID) {
+			found := *v
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memResidentVehicleRepo) List(ctx context.Context, buildingID *int64, apartmentID *int64) ([]*domain.ResidentVehicle, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var result []*domain.ResidentVehicle
+	for _, v := range r.vehicles {
+		if (buildingID == nil || v.BuildingID == *buildingID) && (apartmentID == nil || v.ApartmentID == *apartmentID) {
+			found := *v
+			result = append(result, &found)
+		}
+	}
+	return result, nil
+}
+
+func (r *memResidentVehicleRepo) CountByApartmentID(ctx context.Context, apartmentID int64) (int, error) {
+	vehicles, err := r.List(ctx, nil, &apartmentID)
+	return len(vehicles), err
+}
+
+func (r *memResidentVehicleRepo) Delete(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, v := range r.vehicles {
+		if v.ID == id {
+			r.vehicles = append(r.vehicles[:i], r.vehicles[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+type memPlateWatchlistRepo struct {
+	domain.PlateWatchlistRepository
+
+	mu      sync.Mutex
+	entries []*domain.WatchlistEntry
+	alerts  []*domain.PlateAlert
+	nextID  int64
+}
+
+func (r *memPlateWatchlistRepo) Create(ctx context.Context, entry *domain.WatchlistEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	entry.ID = r.nextID
+	entry.CreatedAt = time.Now()
+	entry.UpdatedAt = entry.CreatedAt
+	stored := *entry
+	r.entries = append(r.entries, &stored)
+	return nil
+}
+
+func (r *memPlateWatchlistRepo) GetByCarPlate(ctx context.Context, normalizedCarPlate string, buildingID *int64) (*domain.WatchlistEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, e := range r.entries {
+		if e.CarPlate == normalizedCarPlate && (buildingID == nil || e.BuildingID == *buildingID) {
+			found := *e
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memPlateWatchlistRepo) CreateAlert(ctx context.Context, alert *domain.PlateAlert) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	alert.ID = r.nextID
+	alert.CreatedAt = time.Now()
+	for _, e := range r.entries {
+		if e.ID == alert.WatchlistID {
+			alert.BuildingID = e.BuildingID
+			alert.CarPlate = e.CarPlate
+			alert.Reason = e.Reason
+			alert.Severity = e.Severity
+		}
+	}
+	stored := *alert
+	r.alerts = append(r.alerts, &stored)
+	return nil
+}
+
+func (r *memPlateWatchlistRepo) ClaimUnnotifiedAlerts(ctx context.Context, limit int) ([]*domain.PlateAlert, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if limit > len(r.alerts) {
+		limit = len(r.alerts)
+	}
+	claimed := r.alerts[:limit]
+	r.alerts = r.alerts[limit:]
+	return claimed, nil
+}
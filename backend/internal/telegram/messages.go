@@ -0,0 +1,331 @@
+package telegram
+
+// msgKey identifies a bot text in the message catalogs.
+type msgKey string
+
+// General.
+const (
+	msgSessionExpired      msgKey = "session_expired"
+	msgSessionError        msgKey = "session_error"
+	msgActionUnavailable   msgKey = "action_unavailable"
+	msgUseStart            msgKey = "use_start"
+	msgUseButtons          msgKey = "use_buttons"
+	msgUseButtonsGuestType msgKey = "use_buttons_guest_type"
+	msgUnknownState        msgKey = "unknown_state"
+	msgUnknownAction       msgKey = "unknown_action"
+	msgError               msgKey = "error"
+	msgInvalidButtonData   msgKey = "invalid_button_data"
+	msgNoActiveAction      msgKey = "no_active_action"
+)
+
+// Main menu and commands.
+const (
+	msgWelcome            msgKey = "welcome"
+	msgNotRegistered      msgKey = "not_registered"
+	msgMenuCreatePass     msgKey = "menu_create_pass"
+	msgMenuActivePasses   msgKey = "menu_active_passes"
+	msgMenuRevokePass     msgKey = "menu_revoke_pass"
+	msgMenuGuests         msgKey = "menu_guests"
+	msgMenuHousehold      msgKey = "menu_household"
+	msgMenuEvent          msgKey = "menu_event"
+	msgMenuVehicles       msgKey = "menu_vehicles"
+	msgCommandStart       msgKey = "command_start"
+	msgCommandCancel      msgKey = "command_cancel"
+	msgCommandLanguage    msgKey = "command_language"
+	msgChooseLanguage     msgKey = "choose_language"
+	msgLanguageAuto       msgKey = "language_auto"
+	msgLanguageSet        msgKey = "language_set"
+	msgLanguageAutoSet    msgKey = "language_auto_set"
+	msgLanguageNotSaved   msgKey = "language_not_saved"
+	msgLanguageSaveFailed msgKey = "language_save_failed"
+)
+
+// Pass creation dialog.
+const (
+	msgChooseGuestType       msgKey = "choose_guest_type"
+	msgGuestByCar            msgKey = "guest_by_car"
+	msgGuestOnFoot           msgKey = "guest_on_foot"
+	msgGuestTaxi             msgKey = "guest_taxi"
+	msgGuestDelivery         msgKey = "guest_delivery"
+	msgEnterCarPlate         msgKey = "enter_car_plate"
+	msgPickGuestOrEnterPlate msgKey = "pick_guest_or_enter_plate"
+	msgInvalidCarPlateFormat msgKey = "invalid_car_plate_format"
+	msgChooseDuration        msgKey = "choose_duration"
+	msgDuration1h            msgKey = "duration_1h"
+	msgDuration2h            msgKey = "duration_2h"
+	msgDuration4h            msgKey = "duration_4h"
+	msgDurationUntil         msgKey = "duration_until"
+	msgSchedule              msgKey = "schedule"
+	msgChooseEndOrType       msgKey = "choose_end_or_type"
+	msgInvalidTimeFormat     msgKey = "invalid_time_format"
+	msgChooseStartDate       msgKey = "choose_start_date"
+	msgNoTimeLeftOnDate      msgKey = "no_time_left_on_date"
+	msgChooseStartTime       msgKey = "choose_start_time"
+	msgChooseEndTime         msgKey = "choose_end_time"
+	msgConfirmWindow         msgKey = "confirm_window"
+	msgConfirm               msgKey = "confirm"
+	msgEnterGuestName        msgKey = "enter_guest_name"
+	msgBack                  msgKey = "back"
+	msgCancel                msgKey = "cancel"
+	msgWindowRejected        msgKey = "window_rejected"
+	msgFirstStep             msgKey = "first_step"
+	msgPassCreationCancelled msgKey = "pass_creation_cancelled"
+	msgDateOutOfRange        msgKey = "date_out_of_range"
+	msgTimePassed            msgKey = "time_passed"
+	msgNextDaySlot           msgKey = "next_day_slot"
+	msgMonthNames            msgKey = "month_names"
+	msgWeekdayNames          msgKey = "weekday_names"
+)
+
+// Passes.
+const (
+	msgApartmentNotFoundRestart msgKey = "apartment_not_found_restart"
+	msgCarPlateMissing          msgKey = "car_plate_missing"
+	msgValidityMissing          msgKey = "validity_missing"
+	msgCreatePassFailed         msgKey = "create_pass_failed"
+	msgQRFailed                 msgKey = "qr_failed"
+	msgPassCreatedCar           msgKey = "pass_created_car"
+	msgPassCreatedPedestrian    msgKey = "pass_created_pedestrian"
+	msgGuestLine                msgKey = "guest_line"
+	msgCategoryLine             msgKey = "category_line"
+	msgCategoryTaxi             msgKey = "category_taxi"
+	msgCategoryDelivery         msgKey = "category_delivery"
+	msgGetPassesFailed          msgKey = "get_passes_failed"
+	msgNoActivePasses           msgKey = "no_active_passes"
+	msgActivePassesHeader       msgKey = "active_passes_header"
+	msgPedestrianGuest          msgKey = "pedestrian_guest"
+	msgValidFromTo              msgKey = "valid_from_to"
+	msgValidUntil               msgKey = "valid_until"
+	msgNoPassesToRevoke         msgKey = "no_passes_to_revoke"
+	msgChoosePassToRevoke       msgKey = "choose_pass_to_revoke"
+	msgInvalidPassID            msgKey = "invalid_pass_id"
+	msgCheckPassFailed          msgKey = "check_pass_failed"
+	msgPassNotYours             msgKey = "pass_not_yours"
+	msgRevokeFailed             msgKey = "revoke_failed"
+	msgPassRevoked              msgKey = "pass_revoked"
+)
+
+// Apartments.
+const (
+	msgResidentNotFound       msgKey = "resident_not_found"
+	msgNoIssueRight           msgKey = "no_issue_right"
+	msgPrimaryOnly            msgKey = "primary_only"
+	msgChooseApartment        msgKey = "choose_apartment"
+	msgInvalidApartmentChoice msgKey = "invalid_apartment_choice"
+	msgApartmentNotFound      msgKey = "apartment_not_found"
+	msgApartmentFallback      msgKey = "apartment_fallback"
+	msgApartmentShort         msgKey = "apartment_short"
+)
+
+// Saved guests.
+const (
+	msgGuestNotFoundEnterPlate msgKey = "guest_not_found_enter_plate"
+	msgGuestNotFound           msgKey = "guest_not_found"
+	msgListGuestsFailed        msgKey = "list_guests_failed"
+	msgNoGuests                msgKey = "no_guests"
+	msgGuestsHeader            msgKey = "guests_header"
+	msgGuestsItem              msgKey = "guests_item"
+	msgStarGuest               msgKey = "star_guest"
+	msgUnstarGuest             msgKey = "unstar_guest"
+	msgDeleteGuest             msgKey = "delete_guest"
+	msgGuestsHint              msgKey = "guests_hint"
+)
+
+// Household.
+const (
+	msgHouseholdFailed      msgKey = "household_failed"
+	msgHouseholdHeader      msgKey = "household_header"
+	msgHouseholdMember      msgKey = "household_member"
+	msgMemberCanIssue       msgKey = "member_can_issue"
+	msgMemberCannotIssue    msgKey = "member_cannot_issue"
+	msgForbidIssue          msgKey = "forbid_issue"
+	msgAllowIssue           msgKey = "allow_issue"
+	msgRemoveMember         msgKey = "remove_member"
+	msgNoMembers            msgKey = "no_members"
+	msgSharedLimit          msgKey = "shared_limit"
+	msgInviteWithPasses     msgKey = "invite_with_passes"
+	msgInviteWithoutPasses  msgKey = "invite_without_passes"
+	msgMemberCanIssueNow    msgKey = "member_can_issue_now"
+	msgMemberCannotIssueNow msgKey = "member_cannot_issue_now"
+	msgMemberRemoved        msgKey = "member_removed"
+	msgRemovedFromHousehold msgKey = "removed_from_household"
+	msgInviteFailed         msgKey = "invite_failed"
+	msgInviteCreated        msgKey = "invite_created"
+	msgInviteLink           msgKey = "invite_link"
+	msgJoinUsage            msgKey = "join_usage"
+	msgJoinFailed           msgKey = "join_failed"
+	msgJoined               msgKey = "joined"
+	msgMemberJoined         msgKey = "member_joined"
+	msgResidentFallback     msgKey = "resident_fallback"
+)
+
+// Security staff.
+const (
+	msgGuardOnly                 msgKey = "guard_only"
+	msgGuardMode                 msgKey = "guard_mode"
+	msgLinkUsage                 msgKey = "link_usage"
+	msgLinkFailed                msgKey = "link_failed"
+	msgLinked                    msgKey = "linked"
+	msgGuardSendInput            msgKey = "guard_send_input"
+	msgGuardCheckFailed          msgKey = "guard_check_failed"
+	msgEntryDenied               msgKey = "entry_denied"
+	msgPassValid                 msgKey = "pass_valid"
+	msgCarPlateLine              msgKey = "car_plate_line"
+	msgPedestrianLine            msgKey = "pedestrian_line"
+	msgApartmentLine             msgKey = "apartment_line"
+	msgValidUntilLine            msgKey = "valid_until_line"
+	msgReasonPassNotFound        msgKey = "reason_pass_not_found"
+	msgReasonPassExpired         msgKey = "reason_pass_expired"
+	msgReasonPassRevoked         msgKey = "reason_pass_revoked"
+	msgReasonPassNotYetValid     msgKey = "reason_pass_not_yet_valid"
+	msgReasonQuietHours          msgKey = "reason_quiet_hours"
+	msgReasonInvalidCarPlate     msgKey = "reason_invalid_car_plate"
+	msgReasonPassUsed            msgKey = "reason_pass_used"
+	msgReasonDynamicQRRequired   msgKey = "reason_dynamic_qr_required"
+	msgReasonQRCodeInvalid       msgKey = "reason_qr_code_invalid"
+	msgReasonOutsideAllowedHours msgKey = "reason_outside_allowed_hours"
+	msgReasonQRCodeExpired       msgKey = "reason_qr_code_expired"
+	msgResidentVehicleValid      msgKey = "resident_vehicle_valid"
+	msgVehicleLabelLine          msgKey = "vehicle_label_line"
+	msgReasonBlacklisted         msgKey = "reason_blacklisted"
+	msgGuardBannedAlert          msgKey = "guard_banned_alert"
+	msgGuardWatchedAlert         msgKey = "guard_watched_alert"
+	msgGuardSuggestionsHeader    msgKey = "guard_suggestions_header"
+	msgGuardSuggestionLine       msgKey = "guard_suggestion_line"
+	msgGuardCheckSuggestion      msgKey = "guard_check_suggestion"
+)
+
+// Announcements from the building administration.
+const (
+	msgBroadcast msgKey = "broadcast"
+)
+
+// Guest pass requests.
+const (
+	msgVisitStart             msgKey = "visit_start"
+	msgVisitInvalidLink       msgKey = "visit_invalid_link"
+	msgVisitEnterApartment    msgKey = "visit_enter_apartment"
+	msgVisitApartmentNotFound msgKey = "visit_apartment_not_found"
+	msgVisitEnterCarPlate     msgKey = "visit_enter_car_plate"
+	msgVisitChooseStart       msgKey = "visit_choose_start"
+	msgStartNow               msgKey = "start_now"
+	msgVisitChooseDuration    msgKey = "visit_choose_duration"
+	msgVisitStartPassed       msgKey = "visit_start_passed"
+	msgVisitStartsOnApproval  msgKey = "visit_starts_on_approval"
+	msgVisitRequestSent       msgKey = "visit_request_sent"
+	msgVisitRequestFailed     msgKey = "visit_request_failed"
+	msgVisitTooMany           msgKey = "visit_too_many"
+	msgVisitNoApprovers       msgKey = "visit_no_approvers"
+	msgVisitRequestReceived   msgKey = "visit_request_received"
+	msgGuestFallback          msgKey = "guest_fallback"
+	msgApprove                msgKey = "approve"
+	msgDecline                msgKey = "decline"
+	msgVisitApproved          msgKey = "visit_approved"
+	msgVisitDeclined          msgKey = "visit_declined"
+	msgVisitApproveFailed     msgKey = "visit_approve_failed"
+	msgVisitDeclineFailed     msgKey = "visit_decline_failed"
+	msgVisitApprovedGuest     msgKey = "visit_approved_guest"
+	msgVisitDeclinedGuest     msgKey = "visit_declined_guest"
+	msgVisitAlreadyDecided    msgKey = "visit_already_decided"
+	msgVisitExpired           msgKey = "visit_expired"
+)
+
+// Guard calls to residents about visitors without a pass.
+const (
+	msgEntryCallPrompt  msgKey = "entry_call_prompt"
+	msgEntryCallAllow   msgKey = "entry_call_allow"
+	msgEntryCallDeny    msgKey = "entry_call_deny"
+	msgEntryCallAllowed msgKey = "entry_call_allowed"
+	msgEntryCallDenied  msgKey = "entry_call_denied"
+	msgEntryCallFailed  msgKey = "entry_call_failed"
+	msgEntryCallDecided msgKey = "entry_call_decided"
+	msgEntryCallExpired msgKey = "entry_call_expired"
+)
+
+// Public pass links.
+const (
+	msgPassLink        msgKey = "pass_link"
+	msgRevokePassLink  msgKey = "revoke_pass_link"
+	msgPassLinkRevoked msgKey = "pass_link_revoked"
+	msgShareFailed     msgKey = "share_failed"
+)
+
+// Rotating QR codes.
+const (
+	msgMakeQRDynamic    msgKey = "make_qr_dynamic"
+	msgRefreshQR        msgKey = "refresh_qr"
+	msgDynamicQRCaption msgKey = "dynamic_qr_caption"
+	msgDynamicQRFailed  msgKey = "dynamic_qr_failed"
+)
+
+// Printable passes.
+const (
+	msgPrintAllPasses msgKey = "print_all_passes"
+	msgPassPDF        msgKey = "pass_pdf"
+	msgPassesPDF      msgKey = "passes_pdf"
+	msgPrintFailed    msgKey = "print_failed"
+)
+
+// Events with a guest list.
+const (
+	msgEventEnterDetails     msgKey = "event_enter_details"
+	msgEventInvalidDetails   msgKey = "event_invalid_details"
+	msgEventSendGuestList    msgKey = "event_send_guest_list"
+	msgEventSendFile         msgKey = "event_send_file"
+	msgEventFileTooLarge     msgKey = "event_file_too_large"
+	msgEventFileFailed       msgKey = "event_file_failed"
+	msgEventGuestListInvalid msgKey = "event_guest_list_invalid"
+	msgEventGuestListRow     msgKey = "event_guest_list_row"
+	msgEventGuestListErrors  msgKey = "event_guest_list_errors"
+	msgEventCreateFailed     msgKey = "event_create_failed"
+	msgEventCreated          msgKey = "event_created"
+	msgEventPDF              msgKey = "event_pdf"
+	msgEventAttendanceButton msgKey = "event_attendance_button"
+	msgEventRevokeButton     msgKey = "event_revoke_button"
+	msgEventNotFound         msgKey = "event_not_found"
+	msgEventRevoked          msgKey = "event_revoked"
+	msgEventAlreadyRevoked   msgKey = "event_already_revoked"
+	msgEventAttendance       msgKey = "event_attendance"
+)
+
+// Exceptions to the pass rules.
+const (
+	msgExceptionEnterReason     msgKey = "exception_enter_reason"
+	msgExceptionReasonInvalid   msgKey = "exception_reason_invalid"
+	msgExceptionTooMany         msgKey = "exception_too_many"
+	msgExceptionFailed          msgKey = "exception_failed"
+	msgExceptionSubmitted       msgKey = "exception_submitted"
+	msgExceptionAdminNotice     msgKey = "exception_admin_notice"
+	msgExceptionRuleMaxDuration msgKey = "exception_rule_max_duration"
+	msgExceptionRuleDailyLimit  msgKey = "exception_rule_daily_limit"
+	msgExceptionApproved        msgKey = "exception_approved"
+	msgExceptionRejected        msgKey = "exception_rejected"
+	msgExceptionComment         msgKey = "exception_comment"
+)
+
+// Registered cars of residents.
+const (
+	msgListVehiclesFailed msgKey = "list_vehicles_failed"
+	msgVehiclesHeader     msgKey = "vehicles_header"
+	msgVehiclesItem       msgKey = "vehicles_item"
+	msgNoVehicles         msgKey = "no_vehicles"
+	msgVehiclesHint       msgKey = "vehicles_hint"
+	msgAddVehicle         msgKey = "add_vehicle"
+	msgDeleteVehicle      msgKey = "delete_vehicle"
+	msgVehicleEnterPlate  msgKey = "vehicle_enter_plate"
+	msgVehicleInvalid     msgKey = "vehicle_invalid"
+	msgVehicleAddFailed   msgKey = "vehicle_add_failed"
+	msgVehicleAdded       msgKey = "vehicle_added"
+	msgVehicleNotFound    msgKey = "vehicle_not_found"
+)
+
+// Alerts about watchlisted plates at the gate.
+const (
+	msgPlateAlert         msgKey = "plate_alert"
+	msgPlateAlertBanned   msgKey = "plate_alert_banned"
+	msgPlateAlertWatched  msgKey = "plate_alert_watched"
+	msgPlateAlertAdmitted msgKey = "plate_alert_admitted"
+	msgPlateAlertDenied   msgKey = "plate_alert_denied"
+	msgPlateAlertOpened   msgKey = "plate_alert_opened"
+	msgPlateAlertCamera   msgKey = "plate_alert_camera"
+)
@@ -0,0 +1,276 @@
+package telegram
+
+var messagesEN = map[msgKey]string{
+	msgSessionExpired:      "Your session has expired. Start again with /start",
+	msgSessionError:        "Session error. Start again with /start",
+	msgActionUnavailable:   "This action is not available now. Continue with the current step or send /cancel",
+	msgUseStart:            "Use /start to get started",
+	msgUseButtons:          "Please use the buttons",
+	msgUseButtonsGuestType: "Please use the buttons to choose the guest type",
+	msgUnknownState:        "Unknown state. Use /start",
+	msgUnknownAction:       "Unknown action. Use /start",
+	msgError:               "Error: %s",
+	msgInvalidButtonData:   "Error: invalid button data",
+	msgNoActiveAction:      "Nothing is in progress. Use /start",
+
+	msgWelcome:            "Welcome to YardPass!\n\nChoose an action:",
+	msgNotRegistered:      "You are not registered as a resident. Please contact the administrator.\n\nSecurity staff can link their account with /link <code>.",
+	msgMenuCreatePass:     "Issue a guest pass",
+	msgMenuActivePasses:   "My active passes",
+	msgMenuRevokePass:     "Revoke a pass",
+	msgMenuGuests:         "My guests",
+	msgMenuHousehold:      "Household",
+	msgMenuEvent:          "Event with a guest list",
+	msgMenuVehicles:       "My cars",
+	msgCommandStart:       "Main menu",
+	msgCommandCancel:      "Cancel the current action",
+	msgCommandLanguage:    "Language / Язык",
+	msgChooseLanguage:     "Choose a language:",
+	msgLanguageAuto:       "🌐 Same as Telegram",
+	msgLanguageSet:        "✅ Language: %s",
+	msgLanguageAutoSet:    "✅ The language will follow your Telegram settings",
+	msgLanguageNotSaved:   "The language can only be saved for residents. For now it follows your Telegram settings.",
+	msgLanguageSaveFailed: "Failed to save the language: %s",
+
+	msgChooseGuestType:       "Choose the guest type:",
+	msgGuestByCar:            "🚗 By car",
+	msgGuestOnFoot:           "🚶 On foot",
+	msgGuestTaxi:             "🚕 Taxi",
+	msgGuestDelivery:         "📦 Delivery",
+	msgEnterCarPlate:         "Enter the car plate in Latin letters (for example, A123BC77):",
+	msgPickGuestOrEnterPlate: "Pick a guest from the list or enter the car plate in Latin letters (for example, A123BC77):",
+	msgInvalidCarPlateFormat: "Could not read the plate \"%s\". Enter it in full, with the region, for example: A123BC77, 1234 AB-7 (Belarus) or 123 ABC 02 (Kazakhstan):",
+	msgChooseDuration:        "Choose how long the pass is valid:",
+	msgDuration1h:            "1 hour",
+	msgDuration2h:            "2 hours",
+	msgDuration4h:            "4 hours",
+	msgDurationUntil:         "Until a time",
+	msgSchedule:              "📅 Schedule",
+	msgChooseEndOrType:       "Choose when the pass ends or type the time as HH:MM (for example, 22:00):",
+	msgInvalidTimeFormat:     "Invalid time format. Use HH:MM (for example, 22:00)",
+	msgChooseStartDate:       "Choose the date the pass starts:",
+	msgNoTimeLeftOnDate:      "There is no time left on this date. Go back and choose another date.",
+	msgChooseStartTime:       "Choose the start time (%s):",
+	msgChooseEndTime:         "Choose the end time (starts %s):",
+	msgConfirmWindow:         "The pass will be valid\nfrom %s\nto %s\n\nIs that correct?",
+	msgConfirm:               "✅ Confirm",
+	msgEnterGuestName:        "Enter the guest's name (or send '-' to skip):",
+	msgBack:                  "⬅️ Back",
+	msgCancel:                "✖️ Cancel",
+	msgWindowRejected:        "⛔ This time is not allowed: %s",
+	msgFirstStep:             "This is the first step. Send /cancel to stop creating the pass",
+	msgPassCreationCancelled: "Pass creation cancelled",
+	msgDateOutOfRange:        "Choose a date within %d days from today",
+	msgTimePassed:            "This time has already passed, choose another one",
+	msgNextDaySlot:           "%s (+%dd)",
+	msgMonthNames:            "January,February,March,April,May,June,July,August,September,October,November,December",
+	msgWeekdayNames:          "Mo,Tu,We,Th,Fr,Sa,Su",
+
+	msgApartmentNotFoundRestart: "Error: apartment not found. Start again with /create",
+	msgCarPlateMissing:          "Error: the car plate is missing",
+	msgValidityMissing:          "Error: the validity period is missing",
+	msgCreatePassFailed:         "Failed to create the pass: %s",
+	msgQRFailed:                 "The pass was created, but the QR code could not be generated: %s",
+	msgPassCreatedCar:           "✅ Pass created!\n\nType: Car\nCar plate: %s\n%s\nPass ID: %s",
+	msgPassCreatedPedestrian:    "✅ Pass created!\n\nType: Pedestrian guest\n%s\nPass ID: %s",
+	msgGuestLine:                "\nGuest: %s",
+	msgCategoryLine:             "\nCategory: %s",
+	msgCategoryTaxi:             "Taxi",
+	msgCategoryDelivery:         "Delivery",
+	msgGetPassesFailed:          "Failed to get passes: %s",
+	msgNoActivePasses:           "You have no active passes",
+	msgActivePassesHeader:       "Your active passes:\n\n",
+	msgPedestrianGuest:          "Pedestrian guest",
+	msgValidFromTo:              "Valid from %s to %s",
+	msgValidUntil:               "Valid until: %s",
+	msgNoPassesToRevoke:         "You have no active passes to revoke",
+	msgChoosePassToRevoke:       "Choose a pass to revoke:\n\n",
+	msgInvalidPassID:            "Error: invalid pass ID",
+	msgCheckPassFailed:          "Failed to check the pass: %s",
+	msgPassNotYours:             "Error: the pass was not found or does not belong to you",
+	msgRevokeFailed:             "Failed to revoke the pass: %s",
+	msgPassRevoked:              "✅ Pass revoked:\n%s\n\nID: %s",
+
+	msgResidentNotFound:       "Error: resident not found",
+	msgNoIssueRight:           "You are not allowed to issue passes. Please ask the primary resident of the apartment.",
+	msgPrimaryOnly:            "Only the primary resident of the apartment can manage the household",
+	msgChooseApartment:        "Choose an apartment:",
+	msgInvalidApartmentChoice: "Error: invalid apartment choice",
+	msgApartmentNotFound:      "Error: apartment not found",
+	msgApartmentFallback:      "Apartment #%d",
+	msgApartmentShort:         "Apt. %s",
+
+	msgGuestNotFoundEnterPlate: "Guest not found. Enter the car plate",
+	msgGuestNotFound:           "Guest not found",
+	msgListGuestsFailed:        "Failed to get your guests: %s",
+	msgNoGuests:                "Your guest list is empty. Guests appear here after you issue car passes.",
+	msgGuestsHeader:            "Your guests:\n\n",
+	msgGuestsItem:              "%d. %s (passes: %d)\n",
+	msgStarGuest:               "⭐ %s",
+	msgUnstarGuest:             "☆ Unstar %s",
+	msgDeleteGuest:             "🗑 Delete",
+	msgGuestsHint:              "\n⭐ Starred guests are always shown first when you issue a pass.",
+
+	msgHouseholdFailed:      "Failed to get the household: %s",
+	msgHouseholdHeader:      "Household (%s):\n\n",
+	msgHouseholdMember:      "%d. %s — %s\n",
+	msgMemberCanIssue:       "can issue passes",
+	msgMemberCannotIssue:    "cannot issue passes",
+	msgForbidIssue:          "🔒 Disallow: %s",
+	msgAllowIssue:           "🔓 Allow: %s",
+	msgRemoveMember:         "❌ Remove",
+	msgNoMembers:            "You have not invited anyone yet.\n",
+	msgSharedLimit:          "\nThe daily pass limit is shared by the whole apartment.",
+	msgInviteWithPasses:     "➕ Invite (can issue passes)",
+	msgInviteWithoutPasses:  "➕ Invite (cannot issue passes)",
+	msgMemberCanIssueNow:    "✅ %s can now issue passes",
+	msgMemberCannotIssueNow: "✅ %s can no longer issue passes",
+	msgMemberRemoved:        "✅ %s was removed from the household, their active passes are revoked",
+	msgRemovedFromHousehold: "You are no longer a household member of %s. Your active passes are revoked",
+	msgInviteFailed:         "Failed to create the invitation: %s",
+	msgInviteCreated:        "Invitation created (valid for %d h).\n\nForward this message to your household member. They need to send the bot the command:\n/join %s",
+	msgInviteLink:           "\n\nor open the link:\n%s",
+	msgJoinUsage:            "Send the invitation code: /join CODE",
+	msgJoinFailed:           "Failed to accept the invitation: %s",
+	msgJoined:               "✅ You have joined the household of %s.\n\nUse /start to get started",
+	msgMemberJoined:         "%s joined the household of %s",
+	msgResidentFallback:     "Resident #%d",
+
+	msgGuardOnly:                 "Pass checks are available to security staff only",
+	msgGuardMode:                 "Security mode (%s).\n\nSend a car plate or a pass ID to check it.",
+	msgLinkUsage:                 "Send the link code: /link ABCD2345\n\nThe code is issued in the security web panel.",
+	msgLinkFailed:                "Failed to link the account: %s",
+	msgLinked:                    "✅ Staff account %s is linked.\n\nSend a car plate or a pass ID to check it.",
+	msgGuardSendInput:            "Send a car plate or a pass ID",
+	msgGuardCheckFailed:          "Failed to check the pass, please try again",
+	msgEntryDenied:               "⛔ Entry denied\n\nReason: %s",
+	msgPassValid:                 "✅ Pass is valid\n",
+	msgCarPlateLine:              "\nCar plate: %s",
+	msgPedestrianLine:            "\nType: Pedestrian guest",
+	msgApartmentLine:             "\nApartment: %s",
+	msgValidUntilLine:            "\nValid until: %s",
+	msgReasonPassNotFound:        "pass not found",
+	msgReasonPassExpired:         "the pass has expired",
+	msgReasonPassRevoked:         "the pass was revoked",
+	msgReasonPassNotYetValid:     "the pass is not valid yet",
+	msgReasonQuietHours:          "entry is not allowed during quiet hours",
+	msgReasonInvalidCarPlate:     "invalid car plate",
+	msgReasonPassUsed:            "the single-use pass has already been used",
+	msgReasonDynamicQRRequired:   "the pass is only accepted with its dynamic QR, ask the guest to refresh the code in the bot",
+	msgReasonQRCodeInvalid:       "the QR code is not valid",
+	msgReasonOutsideAllowedHours: "the contractor pass does not admit on this day or at this hour",
+	msgReasonQRCodeExpired:       "the QR code is outdated, ask the guest to refresh it",
+	msgResidentVehicleValid:      "✅ Resident's car\n",
+	msgVehicleLabelLine:          "\nCar: %s",
+	msgReasonBlacklisted:         "the car is blacklisted",
+	msgGuardBannedAlert:          "🚨🚨 ATTENTION: plate %s is blacklisted\nReason: %s\nThe administration has been notified.\n\n",
+	msgGuardWatchedAlert:         "🚨 ATTENTION: plate %s is on the watchlist\nReason: %s\nThe administration has been notified.\n\n",
+	msgGuardSuggestionsHeader:    "\n\nSimilar plates with valid passes, compare them with the car:",
+	msgGuardSuggestionLine:       "\n%d. %s — apt. %s, until %s",
+	msgGuardCheckSuggestion:      "🔎 Check %s",
+
+	msgBroadcast: "📢 Announcement\n\n%s",
+
+	msgVisitStart:             "Pass request for %s.",
+	msgVisitInvalidLink:       "This link is invalid. Ask the resident for a new one.",
+	msgVisitEnterApartment:    "Enter the number of the apartment you are visiting:",
+	msgVisitApartmentNotFound: "Apartment not found. Check the number and enter it again:",
+	msgVisitEnterCarPlate:     "Enter the car plate (e.g. A123BC77) or choose \"Pedestrian guest\":",
+	msgVisitChooseStart:       "When will you arrive? Choose \"Now\" or the date of the visit:",
+	msgStartNow:               "▶️ Now",
+	msgVisitChooseDuration:    "Arrival at %s. Choose how long the pass is valid:",
+	msgVisitStartPassed:       "The chosen start time has passed. Open the link again to send a new request.",
+	msgVisitStartsOnApproval:  "when approved",
+	msgVisitRequestSent:       "✅ Your request has been sent to the residents (%s). The pass will arrive here once they approve it.",
+	msgVisitRequestFailed:     "Failed to send the request: %s",
+	msgVisitTooMany:           "Too many requests. Please try again later.",
+	msgVisitNoApprovers:       "Nobody in this apartment can approve the request. Please contact the resident directly.",
+	msgVisitRequestReceived:   "🔔 A guest asks for a pass (%s)\n\nGuest: %s%s\nStart: %s\nDuration: %s",
+	msgGuestFallback:          "not given",
+	msgApprove:                "✅ Approve",
+	msgDecline:                "❌ Decline",
+	msgVisitApproved:          "✅ Request approved, the pass has been sent to the guest",
+	msgVisitDeclined:          "Request declined",
+	msgVisitApproveFailed:     "Failed to approve the request: %s",
+	msgVisitDeclineFailed:     "Failed to decline the request: %s",
+	msgVisitApprovedGuest:     "The residents approved your request (%s).\n\n",
+	msgVisitDeclinedGuest:     "❌ The residents declined your pass request (%s)",
+	msgVisitAlreadyDecided:    "This request has already been decided",
+	msgVisitExpired:           "This request has expired",
+
+	msgEntryCallPrompt:  "🚗 Security asks: let %s in (%s)?",
+	msgEntryCallAllow:   "✅ Let in",
+	msgEntryCallDeny:    "❌ Deny",
+	msgEntryCallAllowed: "✅ Security will let %s in",
+	msgEntryCallDenied:  "Security will not let %s in",
+	msgEntryCallFailed:  "Failed to send your answer to security: %s",
+	msgEntryCallDecided: "Security has already got an answer",
+	msgEntryCallExpired: "The time to answer has run out",
+
+	msgPassLink:        "🔗 Link to the pass %s:\n%s\n\nSend it to your guest, it opens the QR code.",
+	msgRevokePassLink:  "Revoke link",
+	msgPassLinkRevoked: "The link to the pass %s has been revoked. The pass itself stays valid.",
+	msgShareFailed:     "Failed to create the link: %s",
+
+	msgMakeQRDynamic:    "🔒 Make the QR dynamic",
+	msgRefreshQR:        "🔄 Refresh QR",
+	msgDynamicQRCaption: "🔄 Dynamic QR of the pass %s.\n\nThe code changes every 30 seconds, so a forwarded screenshot stops working. Tap \"Refresh QR\" before entering.",
+	msgDynamicQRFailed:  "Failed to show the dynamic QR: %s",
+
+	msgPrintAllPasses: "📄 All passes as PDF",
+	msgPassPDF:        "📄 Printable pass %s",
+	msgPassesPDF:      "📄 Printable passes: %d",
+	msgPrintFailed:    "Failed to prepare the PDF: %s",
+
+	msgEventEnterDetails:     "🎉 Send the event name and time in one message, for example:\n\nBirthday; 25.04 18:00-23:00",
+	msgEventInvalidDetails:   "Could not read the name and time. Example: Birthday; 25.04 18:00-23:00",
+	msgEventSendGuestList:    "🎉 %s\n%s – %s\n\nSend the guest list as a .csv or .xlsx file. The first row is a header with the car_plate and/or guest_name columns, every next row becomes a separate pass.",
+	msgEventSendFile:         "Please send the guest list as a .csv or .xlsx file",
+	msgEventFileTooLarge:     "The file is too large, the guest list must be under 1 MB",
+	msgEventFileFailed:       "Failed to download the file: %s",
+	msgEventGuestListInvalid: "Failed to read the guest list: %s",
+	msgEventGuestListRow:     "Row %d: %s",
+	msgEventGuestListErrors:  "The guest list has errors, no passes were issued:\n\n%s\n\nFix the file and send it again.",
+	msgEventCreateFailed:     "Failed to create the event: %s",
+	msgEventCreated:          "✅ Event «%s» created\n%s – %s\nPasses issued: %d\n\nThe PDF with all passes follows.",
+	msgEventPDF:              "📄 Passes of the event «%s»",
+	msgEventAttendanceButton: "📊 Attendance",
+	msgEventRevokeButton:     "❌ Revoke all passes",
+	msgEventNotFound:         "Event not found",
+	msgEventRevoked:          "Event «%s» revoked. Passes revoked: %d",
+	msgEventAlreadyRevoked:   "The event has already been revoked",
+	msgEventAttendance:       "📊 %s: %d of %d guests arrived\n\n%s",
+
+	msgExceptionEnterReason:     "The building admins can make an exception. Write in one message why you need this pass and the request will be sent to them.",
+	msgExceptionReasonInvalid:   "The reason does not fit: %s. Please write it again.",
+	msgExceptionTooMany:         "You already have requests waiting for the admins. Please wait for their answer.",
+	msgExceptionFailed:          "Failed to send the request: %s",
+	msgExceptionSubmitted:       "📨 Request #%d has been sent to the building admins. The bot will let you know their decision.",
+	msgExceptionAdminNotice:     "📨 Exception request #%d\nApartment: %s\nGuest: %s\nValid: %s – %s\nRule broken: %s\nReason: %s\n\nApprove or reject the request in the admin panel.",
+	msgExceptionRuleMaxDuration: "maximum pass duration",
+	msgExceptionRuleDailyLimit:  "daily pass limit",
+	msgExceptionApproved:        "✅ The admins approved request #%d, the pass has been issued.",
+	msgExceptionRejected:        "❌ The admins rejected request #%d.",
+	msgExceptionComment:         "\nComment: %s",
+
+	msgListVehiclesFailed: "Failed to get the cars: %s",
+	msgVehiclesHeader:     "Residents' cars, %s:\n\n",
+	msgVehiclesItem:       "%d. %s\n",
+	msgNoVehicles:         "No cars yet.\n",
+	msgVehiclesHint:       "\nGuards let these cars in by plate without a pass.",
+	msgAddVehicle:         "➕ Add a car",
+	msgDeleteVehicle:      "🗑 %s",
+	msgVehicleEnterPlate:  "Send the car plate. You can add a description for the guards after \";\", for example:\nA123BC777; white Kia Rio",
+	msgVehicleInvalid:     "Could not add the car: %s. Please send the plate again.",
+	msgVehicleAddFailed:   "Could not add the car: %s",
+	msgVehicleAdded:       "✅ Car %s added",
+	msgVehicleNotFound:    "Car not found",
+
+	msgPlateAlert:         "🚨 %s at the gate\nPlate: %s\nReason: %s\nChecked by: %s\nTime: %s\n\n%s",
+	msgPlateAlertBanned:   "Blacklisted car",
+	msgPlateAlertWatched:  "Watchlisted car",
+	msgPlateAlertAdmitted: "The pass is valid, the guard let the car in.",
+	msgPlateAlertDenied:   "Entry was not allowed.",
+	msgPlateAlertOpened:   "The pass is valid, the gate camera opened the barrier.",
+	msgPlateAlertCamera:   "camera %s",
+}
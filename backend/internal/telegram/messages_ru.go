@@ -0,0 +1,276 @@
+package telegram
+
+var messagesRU = map[msgKey]string{
+	msgSessionExpired:      "Сессия истекла. Начните заново с /start",
+	msgSessionError:        "Ошибка сессии. Начните заново с /start",
+	msgActionUnavailable:   "Это действие сейчас недоступно. Продолжите с текущего шага или отправьте /cancel",
+	msgUseStart:            "Используйте /start для начала работы",
+	msgUseButtons:          "Используйте кнопки для выбора",
+	msgUseButtonsGuestType: "Используйте кнопки для выбора типа гостя",
+	msgUnknownState:        "Неизвестное состояние. Используйте /start",
+	msgUnknownAction:       "Неизвестное действие. Используйте /start",
+	msgError:               "Ошибка: %s",
+	msgInvalidButtonData:   "Ошибка: неверные данные кнопки",
+	msgNoActiveAction:      "Нет активного действия. Используйте /start",
+
+	msgWelcome:            "Добро пожаловать в YardPass!\n\nВыберите действие:",
+	msgNotRegistered:      "Вы не зарегистрированы как житель. Обратитесь к администратору.\n\nСотрудники охраны могут привязать аккаунт командой /link <код>.",
+	msgMenuCreatePass:     "Выдать пропуск гостю",
+	msgMenuActivePasses:   "Мои активные пропуска",
+	msgMenuRevokePass:     "Отозвать пропуск",
+	msgMenuGuests:         "Мои гости",
+	msgMenuHousehold:      "Семья",
+	msgMenuEvent:          "Мероприятие со списком гостей",
+	msgMenuVehicles:       "Мои автомобили",
+	msgCommandStart:       "Главное меню",
+	msgCommandCancel:      "Отменить текущее действие",
+	msgCommandLanguage:    "Язык / Language",
+	msgChooseLanguage:     "Выберите язык:",
+	msgLanguageAuto:       "🌐 Как в Telegram",
+	msgLanguageSet:        "✅ Язык: %s",
+	msgLanguageAutoSet:    "✅ Язык будет выбираться по настройкам Telegram",
+	msgLanguageNotSaved:   "Язык сохраняется только для жителей. Сейчас он выбирается по настройкам Telegram.",
+	msgLanguageSaveFailed: "Не удалось сохранить язык: %s",
+
+	msgChooseGuestType:       "Выберите тип гостя:",
+	msgGuestByCar:            "🚗 На автомобиле",
+	msgGuestOnFoot:           "🚶 Пеший гость",
+	msgGuestTaxi:             "🚕 Такси",
+	msgGuestDelivery:         "📦 Доставка",
+	msgEnterCarPlate:         "Введите номер автомобиля (на английском, например: A123BC77):",
+	msgPickGuestOrEnterPlate: "Выберите гостя из списка или введите номер автомобиля (на английском, например: A123BC77):",
+	msgInvalidCarPlateFormat: "Не удалось разобрать номер «%s». Введите его полностью, с регионом, например: A123BC77, 1234 AB-7 (Беларусь) или 123 ABC 02 (Казахстан):",
+	msgChooseDuration:        "Выберите срок действия пропуска:",
+	msgDuration1h:            "1 час",
+	msgDuration2h:            "2 часа",
+	msgDuration4h:            "4 часа",
+	msgDurationUntil:         "До времени",
+	msgSchedule:              "📅 Запланировать",
+	msgChooseEndOrType:       "Выберите время окончания действия пропуска или введите его в формате ЧЧ:ММ (например, 22:00):",
+	msgInvalidTimeFormat:     "Неверный формат времени. Введите в формате ЧЧ:ММ (например, 22:00)",
+	msgChooseStartDate:       "Выберите дату начала действия пропуска:",
+	msgNoTimeLeftOnDate:      "На эту дату время уже прошло. Вернитесь назад и выберите другую дату.",
+	msgChooseStartTime:       "Выберите время начала (%s):",
+	msgChooseEndTime:         "Выберите время окончания (начало %s):",
+	msgConfirmWindow:         "Пропуск будет действовать\nс %s\nпо %s\n\nВсё верно?",
+	msgConfirm:               "✅ Подтвердить",
+	msgEnterGuestName:        "Введите имя гостя (или отправьте '-' чтобы пропустить):",
+	msgBack:                  "⬅️ Назад",
+	msgCancel:                "✖️ Отмена",
+	msgWindowRejected:        "⛔ Это время не подходит: %s",
+	msgFirstStep:             "Это первый шаг. Отправьте /cancel, чтобы отменить создание пропуска",
+	msgPassCreationCancelled: "Создание пропуска отменено",
+	msgDateOutOfRange:        "Выберите дату в пределах %d дней от сегодняшней",
+	msgTimePassed:            "Это время уже прошло, выберите другое",
+	msgNextDaySlot:           "%s (+%dд)",
+	msgMonthNames:            "Январь,Февраль,Март,Апрель,Май,Июнь,Июль,Август,Сентябрь,Октябрь,Ноябрь,Декабрь",
+	msgWeekdayNames:          "Пн,Вт,Ср,Чт,Пт,Сб,Вс",
+
+	msgApartmentNotFoundRestart: "Ошибка: квартира не найдена. Начните заново с /create",
+	msgCarPlateMissing:          "Ошибка: номер автомобиля не указан",
+	msgValidityMissing:          "Ошибка: время действия не указано",
+	msgCreatePassFailed:         "Ошибка при создании пропуска: %s",
+	msgQRFailed:                 "Пропуск создан, но не удалось сгенерировать QR: %s",
+	msgPassCreatedCar:           "✅ Пропуск создан!\n\nТип: Автомобиль\nНомер авто: %s\n%s\nID пропуска: %s",
+	msgPassCreatedPedestrian:    "✅ Пропуск создан!\n\nТип: Пеший гость\n%s\nID пропуска: %s",
+	msgGuestLine:                "\nГость: %s",
+	msgCategoryLine:             "\nКатегория: %s",
+	msgCategoryTaxi:             "Такси",
+	msgCategoryDelivery:         "Доставка",
+	msgGetPassesFailed:          "Ошибка при получении пропусков: %s",
+	msgNoActivePasses:           "У вас нет активных пропусков",
+	msgActivePassesHeader:       "Ваши активные пропуска:\n\n",
+	msgPedestrianGuest:          "Пеший гость",
+	msgValidFromTo:              "Действует с %s до %s",
+	msgValidUntil:               "Действует до: %s",
+	msgNoPassesToRevoke:         "У вас нет активных пропусков для отзыва",
+	msgChoosePassToRevoke:       "Выберите пропуск для отзыва:\n\n",
+	msgInvalidPassID:            "Ошибка: неверный ID пропуска",
+	msgCheckPassFailed:          "Ошибка при проверке пропуска: %s",
+	msgPassNotYours:             "Ошибка: пропуск не найден или не принадлежит вам",
+	msgRevokeFailed:             "Ошибка при отзыве пропуска: %s",
+	msgPassRevoked:              "✅ Пропуск отозван:\n%s\n\nID: %s",
+
+	msgResidentNotFound:       "Ошибка: житель не найден",
+	msgNoIssueRight:           "У вас нет права выдавать пропуска. Обратитесь к основному жителю квартиры.",
+	msgPrimaryOnly:            "Управлять семьёй может только основной житель квартиры",
+	msgChooseApartment:        "Выберите квартиру:",
+	msgInvalidApartmentChoice: "Ошибка: неверный выбор квартиры",
+	msgApartmentNotFound:      "Ошибка: квартира не найдена",
+	msgApartmentFallback:      "Квартира #%d",
+	msgApartmentShort:         "Кв. %s",
+
+	msgGuestNotFoundEnterPlate: "Гость не найден. Введите номер автомобиля",
+	msgGuestNotFound:           "Гость не найден",
+	msgListGuestsFailed:        "Ошибка при получении списка гостей: %s",
+	msgNoGuests:                "Список гостей пуст. Гости появятся здесь после выдачи пропусков на автомобиль.",
+	msgGuestsHeader:            "Ваши гости:\n\n",
+	msgGuestsItem:              "%d. %s (пропусков: %d)\n",
+	msgStarGuest:               "⭐ %s",
+	msgUnstarGuest:             "☆ Убрать %s",
+	msgDeleteGuest:             "🗑 Удалить",
+	msgGuestsHint:              "\n⭐ Избранные гости всегда показываются первыми при выдаче пропуска.",
+
+	msgHouseholdFailed:      "Ошибка при получении списка семьи: %s",
+	msgHouseholdHeader:      "Семья (%s):\n\n",
+	msgHouseholdMember:      "%d. %s — %s\n",
+	msgMemberCanIssue:       "может выдавать пропуска",
+	msgMemberCannotIssue:    "без права выдачи пропусков",
+	msgForbidIssue:          "🔒 Запретить: %s",
+	msgAllowIssue:           "🔓 Разрешить: %s",
+	msgRemoveMember:         "❌ Удалить",
+	msgNoMembers:            "Вы пока никого не пригласили.\n",
+	msgSharedLimit:          "\nЛимит пропусков в день общий для всей квартиры.",
+	msgInviteWithPasses:     "➕ Пригласить (с выдачей пропусков)",
+	msgInviteWithoutPasses:  "➕ Пригласить (без выдачи пропусков)",
+	msgMemberCanIssueNow:    "✅ %s теперь может выдавать пропуска",
+	msgMemberCannotIssueNow: "✅ %s больше не может выдавать пропуска",
+	msgMemberRemoved:        "✅ %s удалён(а) из семьи, активные пропуска отозваны",
+	msgRemovedFromHousehold: "Вы больше не состоите в семье квартиры %s. Ваши активные пропуска отозваны",
+	msgInviteFailed:         "Ошибка при создании приглашения: %s",
+	msgInviteCreated:        "Приглашение создано (действует %d ч).\n\nПерешлите члену семьи это сообщение. Ему нужно отправить боту команду:\n/join %s",
+	msgInviteLink:           "\n\nили открыть ссылку:\n%s",
+	msgJoinUsage:            "Укажите код приглашения: /join КОД",
+	msgJoinFailed:           "Не удалось принять приглашение: %s",
+	msgJoined:               "✅ Вы добавлены в семью квартиры %s.\n\nИспользуйте /start для начала работы",
+	msgMemberJoined:         "%s присоединился(ась) к семье квартиры %s",
+	msgResidentFallback:     "Житель #%d",
+
+	msgGuardOnly:                 "Проверка пропусков доступна только сотрудникам охраны",
+	msgGuardMode:                 "Режим охраны (%s).\n\nОтправьте номер автомобиля или ID пропуска, чтобы проверить его.",
+	msgLinkUsage:                 "Укажите код привязки: /link ABCD2345\n\nКод выдаётся в веб-панели охраны.",
+	msgLinkFailed:                "Не удалось привязать аккаунт: %s",
+	msgLinked:                    "✅ Аккаунт сотрудника %s привязан.\n\nОтправьте номер автомобиля или ID пропуска, чтобы проверить его.",
+	msgGuardSendInput:            "Отправьте номер автомобиля или ID пропуска",
+	msgGuardCheckFailed:          "Ошибка проверки пропуска, попробуйте ещё раз",
+	msgEntryDenied:               "⛔ Проезд запрещён\n\nПричина: %s",
+	msgPassValid:                 "✅ Пропуск действителен\n",
+	msgCarPlateLine:              "\nНомер авто: %s",
+	msgPedestrianLine:            "\nТип: Пеший гость",
+	msgApartmentLine:             "\nКвартира: %s",
+	msgValidUntilLine:            "\nДействует до: %s",
+	msgReasonPassNotFound:        "пропуск не найден",
+	msgReasonPassExpired:         "срок действия пропуска истёк",
+	msgReasonPassRevoked:         "пропуск отозван",
+	msgReasonPassNotYetValid:     "пропуск ещё не начал действовать",
+	msgReasonQuietHours:          "въезд запрещён в тихие часы",
+	msgReasonInvalidCarPlate:     "неверный номер автомобиля",
+	msgReasonPassUsed:            "одноразовый пропуск уже использован",
+	msgReasonDynamicQRRequired:   "пропуск принимается только по динамическому QR, попросите гостя обновить код в боте",
+	msgReasonQRCodeInvalid:       "QR код недействителен",
+	msgReasonOutsideAllowedHours: "пропуск подрядчика не действует в этот день или час",
+	msgReasonQRCodeExpired:       "QR код устарел, попросите гостя обновить его",
+	msgResidentVehicleValid:      "✅ Автомобиль жителя\n",
+	msgVehicleLabelLine:          "\nАвтомобиль: %s",
+	msgReasonBlacklisted:         "автомобиль в чёрном списке",
+	msgGuardBannedAlert:          "🚨🚨 ВНИМАНИЕ: номер %s в чёрном списке\nПричина: %s\nАдминистрация уведомлена.\n\n",
+	msgGuardWatchedAlert:         "🚨 ВНИМАНИЕ: номер %s в списке наблюдения\nПричина: %s\nАдминистрация уведомлена.\n\n",
+	msgGuardSuggestionsHeader:    "\n\nПохожие номера с действующими пропусками, сверьте с автомобилем:",
+	msgGuardSuggestionLine:       "\n%d. %s — кв. %s, до %s",
+	msgGuardCheckSuggestion:      "🔎 Проверить %s",
+
+	msgBroadcast: "📢 Объявление\n\n%s",
+
+	msgVisitStart:             "Запрос пропуска в %s.",
+	msgVisitInvalidLink:       "Ссылка недействительна. Попросите жителя прислать новую.",
+	msgVisitEnterApartment:    "Введите номер квартиры, в которую вы едете:",
+	msgVisitApartmentNotFound: "Квартира не найдена. Проверьте номер и введите его ещё раз:",
+	msgVisitEnterCarPlate:     "Введите номер автомобиля (например: A123BC77) или выберите «Пеший гость»:",
+	msgVisitChooseStart:       "Когда вы приедете? Выберите «Сейчас» или дату визита:",
+	msgStartNow:               "▶️ Сейчас",
+	msgVisitChooseDuration:    "Приезд в %s. Выберите срок действия пропуска:",
+	msgVisitStartPassed:       "Выбранное время начала уже прошло. Откройте ссылку ещё раз, чтобы отправить новую заявку.",
+	msgVisitStartsOnApproval:  "с момента одобрения",
+	msgVisitRequestSent:       "✅ Заявка отправлена жителям (%s). Пропуск придёт сюда, как только её одобрят.",
+	msgVisitRequestFailed:     "Не удалось отправить заявку: %s",
+	msgVisitTooMany:           "Слишком много заявок. Попробуйте позже.",
+	msgVisitNoApprovers:       "В этой квартире некому одобрить заявку. Свяжитесь с жителем напрямую.",
+	msgVisitRequestReceived:   "🔔 Гость просит пропуск (%s)\n\nГость: %s%s\nНачало: %s\nСрок: %s",
+	msgGuestFallback:          "не указан",
+	msgApprove:                "✅ Одобрить",
+	msgDecline:                "❌ Отклонить",
+	msgVisitApproved:          "✅ Заявка одобрена, пропуск отправлен гостю",
+	msgVisitDeclined:          "Заявка отклонена",
+	msgVisitApproveFailed:     "Не удалось одобрить заявку: %s",
+	msgVisitDeclineFailed:     "Не удалось отклонить заявку: %s",
+	msgVisitApprovedGuest:     "Жители одобрили вашу заявку (%s).\n\n",
+	msgVisitDeclinedGuest:     "❌ Жители отклонили вашу заявку на пропуск (%s)",
+	msgVisitAlreadyDecided:    "По этой заявке уже принято решение",
+	msgVisitExpired:           "Срок заявки истёк",
+
+	msgEntryCallPrompt:  "🚗 Охрана спрашивает: пропустить %s (%s)?",
+	msgEntryCallAllow:   "✅ Пропустить",
+	msgEntryCallDeny:    "❌ Не пускать",
+	msgEntryCallAllowed: "✅ Охрана пропустит %s",
+	msgEntryCallDenied:  "Охрана не пропустит %s",
+	msgEntryCallFailed:  "Не удалось передать ответ охране: %s",
+	msgEntryCallDecided: "Ответ охране уже дан",
+	msgEntryCallExpired: "Время ответа истекло",
+
+	msgPassLink:        "🔗 Ссылка на пропуск %s:\n%s\n\nОтправьте её гостю — по ней откроется QR код.",
+	msgRevokePassLink:  "Отозвать ссылку",
+	msgPassLinkRevoked: "Ссылка на пропуск %s отозвана. Сам пропуск продолжает действовать.",
+	msgShareFailed:     "Не удалось создать ссылку: %s",
+
+	msgMakeQRDynamic:    "🔒 Сделать QR динамическим",
+	msgRefreshQR:        "🔄 Обновить QR",
+	msgDynamicQRCaption: "🔄 Динамический QR пропуска %s.\n\nКод меняется каждые 30 секунд, пересланный скриншот перестаёт работать. Нажмите «Обновить QR» перед въездом.",
+	msgDynamicQRFailed:  "Не удалось показать динамический QR: %s",
+
+	msgPrintAllPasses: "📄 Все пропуска в PDF",
+	msgPassPDF:        "📄 Пропуск %s для печати",
+	msgPassesPDF:      "📄 Пропуска для печати: %d",
+	msgPrintFailed:    "Не удалось подготовить PDF: %s",
+
+	msgEventEnterDetails:     "🎉 Отправьте название и время мероприятия одним сообщением, например:\n\nДень рождения; 25.04 18:00-23:00",
+	msgEventInvalidDetails:   "Не удалось разобрать название и время. Пример: День рождения; 25.04 18:00-23:00",
+	msgEventSendGuestList:    "🎉 %s\n%s – %s\n\nОтправьте список гостей файлом .csv или .xlsx. В первой строке заголовок со столбцами car_plate и/или guest_name (можно «номер» и «имя»), каждая следующая строка станет отдельным пропуском.",
+	msgEventSendFile:         "Отправьте список гостей файлом .csv или .xlsx",
+	msgEventFileTooLarge:     "Файл слишком большой, список гостей должен быть меньше 1 МБ",
+	msgEventFileFailed:       "Не удалось скачать файл: %s",
+	msgEventGuestListInvalid: "Не удалось прочитать список гостей: %s",
+	msgEventGuestListRow:     "Строка %d: %s",
+	msgEventGuestListErrors:  "В списке гостей есть ошибки, пропуска не выданы:\n\n%s\n\nИсправьте файл и отправьте его снова.",
+	msgEventCreateFailed:     "Не удалось создать мероприятие: %s",
+	msgEventCreated:          "✅ Мероприятие «%s» создано\n%s – %s\nВыдано пропусков: %d\n\nPDF со всеми пропусками следом.",
+	msgEventPDF:              "📄 Пропуска мероприятия «%s»",
+	msgEventAttendanceButton: "📊 Кто пришёл",
+	msgEventRevokeButton:     "❌ Отозвать все пропуска",
+	msgEventNotFound:         "Мероприятие не найдено",
+	msgEventRevoked:          "Мероприятие «%s» отменено. Отозвано пропусков: %d",
+	msgEventAlreadyRevoked:   "Мероприятие уже отменено",
+	msgEventAttendance:       "📊 %s: пришли %d из %d гостей\n\n%s",
+
+	msgExceptionEnterReason:     "Администрация может сделать исключение. Напишите одним сообщением, зачем нужен такой пропуск, и заявка уйдёт администратору.",
+	msgExceptionReasonInvalid:   "Причина не подходит: %s. Напишите её ещё раз.",
+	msgExceptionTooMany:         "У вас уже есть заявки, которые ждут решения администрации. Дождитесь ответа.",
+	msgExceptionFailed:          "Не удалось отправить заявку: %s",
+	msgExceptionSubmitted:       "📨 Заявка №%d отправлена администрации. Когда её рассмотрят, бот пришлёт ответ.",
+	msgExceptionAdminNotice:     "📨 Заявка на исключение №%d\nКвартира: %s\nГость: %s\nСрок: %s – %s\nНарушено правило: %s\nПричина: %s\n\nОдобрить или отклонить заявку можно в панели администратора.",
+	msgExceptionRuleMaxDuration: "максимальный срок пропуска",
+	msgExceptionRuleDailyLimit:  "дневной лимит пропусков",
+	msgExceptionApproved:        "✅ Администрация одобрила заявку №%d, пропуск выдан.",
+	msgExceptionRejected:        "❌ Администрация отклонила заявку №%d.",
+	msgExceptionComment:         "\nКомментарий: %s",
+
+	msgListVehiclesFailed: "Ошибка при получении списка автомобилей: %s",
+	msgVehiclesHeader:     "Автомобили жителей, %s:\n\n",
+	msgVehiclesItem:       "%d. %s\n",
+	msgNoVehicles:         "Автомобилей пока нет.\n",
+	msgVehiclesHint:       "\nОхрана пропускает эти автомобили по номеру без пропуска.",
+	msgAddVehicle:         "➕ Добавить автомобиль",
+	msgDeleteVehicle:      "🗑 %s",
+	msgVehicleEnterPlate:  "Отправьте номер автомобиля. Через «;» можно добавить описание для охраны, например:\nА123ВС777; белая Kia Rio",
+	msgVehicleInvalid:     "Не удалось добавить автомобиль: %s. Отправьте номер ещё раз.",
+	msgVehicleAddFailed:   "Не удалось добавить автомобиль: %s",
+	msgVehicleAdded:       "✅ Автомобиль %s добавлен",
+	msgVehicleNotFound:    "Автомобиль не найден",
+
+	msgPlateAlert:         "🚨 %s у ворот\nНомер: %s\nПричина: %s\nПроверил: %s\nВремя: %s\n\n%s",
+	msgPlateAlertBanned:   "Автомобиль из чёрного списка",
+	msgPlateAlertWatched:  "Автомобиль из списка наблюдения",
+	msgPlateAlertAdmitted: "Пропуск действителен, охрана пропустила автомобиль.",
+	msgPlateAlertDenied:   "Въезд не разрешён.",
+	msgPlateAlertOpened:   "Пропуск действителен, камера открыла шлагбаум.",
+	msgPlateAlertCamera:   "камера %s",
+}
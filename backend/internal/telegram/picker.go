@@ -0,0 +1,153 @@
+package telegram
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	calendarDatePrefix  = "date:"
+	calendarMonthPrefix = "month:"
+	timeSlotPrefix      = "time:"
+	callbackNoop        = "noop"
+
+	dateLayout  = "2006-01-02"
+	monthLayout = "2006-01"
+
+	// maxScheduleDays limits how far ahead a pass can be scheduled.
+	maxScheduleDays = 30
+	// maxTimeSlots keeps time pickers well below Telegram's limit of 100
+	// inline buttons; the slot step grows for long maximum durations.
+	maxTimeSlots = 48
+	minSlotStep  = 30 * time.Minute
+)
+
+func noopButton(text string) map[string]interface{} {
+	return map[string]interface{}{"text": text, "callback_data": callbackNoop}
+}
+
+// calendarRows renders month as an inline keyboard. Only the days from
+// today up to maxScheduleDays ahead can be picked.
+func calendarRows(lang Lang, month, now time.Time) [][]map[string]interface{} {
+	monthNames := strings.Split(translate(lang, msgMonthNames), ",")
+	weekdayLabels := strings.Split(translate(lang, msgWeekdayNames), ",")
+
+	loc := now.Location()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	lastDay := today.AddDate(0, 0, maxScheduleDays)
+	first := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, loc)
+
+	header := []map[string]interface{}{}
+	if first.After(today) {
+		header = append(header, map[string]interface{}{
+			"text":          "‹",
+			"callback_data": calendarMonthPrefix + first.AddDate(0, -1, 0).Format(monthLayout),
+		})
+	} else {
+		header = append(header, noopButton(" "))
+	}
+	header = append(header, noopButton(fmt.Sprintf("%s %d", monthNames[first.Month()-1], first.Year())))
+	if next := first.AddDate(0, 1, 0); !next.After(lastDay) {
+		header = append(header, map[string]interface{}{
+			"text":          "›",
+			"callback_data": calendarMonthPrefix + next.Format(monthLayout),
+		})
+	} else {
+		header = append(header, noopButton(" "))
+	}
+
+	weekdays := []map[string]interface{}{}
+	for _, label := range weekdayLabels {
+		weekdays = append(weekdays, noopButton(label))
+	}
+
+	rows := [][]map[string]interface{}{header, weekdays}
+
+	// Monday-based column of the first day.
+	offset := (int(first.Weekday()) + 6) % 7
+	week := []map[string]interface{}{}
+	for i := 0; i < offset; i++ {
+		week = append(week, noopButton(" "))
+	}
+	for day := first; day.Month() == first.Month(); day = day.AddDate(0, 0, 1) {
+		if day.Before(today) || day.After(lastDay) {
+			week = append(week, noopButton("·"))
+		} else {
+			week = append(week, map[string]interface{}{
+				"text":          strconv.Itoa(day.Day()),
+				"callback_data": calendarDatePrefix + day.Format(dateLayout),
+			})
+		}
+		if len(week) == 7 {
+			rows = append(rows, week)
+			week = []map[string]interface{}{}
+		}
+	}
+	if len(week) > 0 {
+		for len(week) < 7 {
+			week = append(week, noopButton(" "))
+		}
+		rows = append(rows, week)
+	}
+
+	return rows
+}
+
+// startSlots returns the start times on date that are still ahead of now.
+func startSlots(date, now time.Time) []time.Time {
+	var slots []time.Time
+	end := date.AddDate(0, 0, 1)
+	for t := date; t.Before(end); t = t.Add(minSlotStep) {
+		if t.After(now) {
+			slots = append(slots, t)
+		}
+	}
+	return slots
+}
+
+// endSlots returns end times after from, up to from+maxDuration. The first
+// slot is aligned to the slot grid.
+func endSlots(from time.Time, maxDuration time.Duration) []time.Time {
+	step := minSlotStep
+	for maxDuration/step > maxTimeSlots {
+		step += minSlotStep
+	}
+
+	limit := from.Add(maxDuration)
+	var slots []time.Time
+	for t := from.Truncate(minSlotStep).Add(step); !t.After(limit); t = t.Add(step) {
+		slots = append(slots, t)
+	}
+	return slots
+}
+
+// timeSlotRows renders slots as buttons. Slots on a later day than base are
+// marked with the day offset.
+func timeSlotRows(lang Lang, slots []time.Time, base time.Time, perRow int) [][]map[string]interface{} {
+	baseDay := time.Date(base.Year(), base.Month(), base.Day(), 0, 0, 0, 0, base.Location())
+
+	var rows [][]map[string]interface{}
+	row := []map[string]interface{}{}
+	for _, slot := range slots {
+		label := slot.Format("15:04")
+		slotDay := time.Date(slot.Year(), slot.Month(), slot.Day(), 0, 0, 0, 0, slot.Location())
+		if days := int(slotDay.Sub(baseDay).Hours() / 24); days > 0 {
+			label = translate(lang, msgNextDaySlot, label, days)
+		}
+
+		row = append(row, map[string]interface{}{
+			"text":          label,
+			"callback_data": timeSlotPrefix + strconv.FormatInt(slot.Unix(), 10),
+		})
+		if len(row) == perRow {
+			rows = append(rows, row)
+			row = []map[string]interface{}{}
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+	return rows
+}
@@ -0,0 +1,74 @@
+package telegram
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalendarRows(t *testing.T) {
+	now := time.Date(2025, 3, 20, 15, 0, 0, 0, time.UTC)
+
+	rows := calendarRows(LangRU, now, now)
+
+	require.GreaterOrEqual(t, len(rows), 6)
+	assert.Equal(t, callbackNoop, rows[0][0]["callback_data"], "no way back from the current month")
+	assert.Equal(t, "Март 2025", rows[0][1]["text"])
+	assert.Equal(t, calendarMonthPrefix+"2025-04", rows[0][2]["callback_data"])
+	assert.Equal(t, "Пн", rows[1][0]["text"])
+
+	var selectable []string
+	for _, row := range rows[2:] {
+		assert.Len(t, row, 7)
+		for _, cell := range row {
+			if data := cell["callback_data"].(string); strings.HasPrefix(data, calendarDatePrefix) {
+				selectable = append(selectable, data)
+			}
+		}
+	}
+	assert.Equal(t, calendarDatePrefix+"2025-03-20", selectable[0])
+	assert.Equal(t, calendarDatePrefix+"2025-03-31", selectable[len(selectable)-1])
+
+	// 1 March 2025 is a Saturday.
+	assert.Equal(t, "·", rows[2][5]["text"])
+
+	april := calendarRows(LangRU, time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), now)
+	assert.Equal(t, calendarMonthPrefix+"2025-03", april[0][0]["callback_data"])
+	assert.Equal(t, callbackNoop, april[0][2]["callback_data"], "May is beyond the scheduling horizon")
+}
+
+func TestStartSlots(t *testing.T) {
+	date := time.Date(2025, 3, 20, 0, 0, 0, 0, time.UTC)
+
+	assert.Len(t, startSlots(date, date.Add(-time.Hour)), 48)
+
+	slots := startSlots(date, date.Add(22*time.Hour+10*time.Minute))
+	require.Len(t, slots, 3)
+	assert.Equal(t, date.Add(22*time.Hour+30*time.Minute), slots[0])
+}
+
+func TestEndSlots(t *testing.T) {
+	from := time.Date(2025, 3, 20, 10, 10, 0, 0, time.UTC)
+
+	slots := endSlots(from, 2*time.Hour)
+	require.Len(t, slots, 4)
+	assert.Equal(t, time.Date(2025, 3, 20, 10, 30, 0, 0, time.UTC), slots[0])
+	assert.Equal(t, time.Date(2025, 3, 20, 12, 0, 0, 0, time.UTC), slots[3])
+
+	long := endSlots(from, 72*time.Hour)
+	assert.LessOrEqual(t, len(long), maxTimeSlots)
+	assert.False(t, long[len(long)-1].After(from.Add(72*time.Hour)))
+}
+
+func TestTimeSlotRows_MarksNextDay(t *testing.T) {
+	base := time.Date(2025, 3, 20, 23, 0, 0, 0, time.UTC)
+
+	rows := timeSlotRows(LangRU, []time.Time{base.Add(30 * time.Minute), base.Add(time.Hour)}, base, 4)
+
+	require.Len(t, rows, 1)
+	assert.Equal(t, "23:30", rows[0][0]["text"])
+	assert.Equal(t, "00:00 (+1д)", rows[0][1]["text"])
+}
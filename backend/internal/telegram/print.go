@@ -0,0 +1,97 @@
+package telegram
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"yardpass/internal/domain"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	printPassPrefix = "print_pass_"
+	printAllPrefix  = "print_all_"
+)
+
+// handlePrintCallback sends a single pass, or all visible passes of one of
+// the caller's apartments, as a printable PDF.
+func (b *Bot) handlePrintCallback(ctx context.Context, cb CallbackQuery) {
+	chatID := cb.Message.Chat.ID
+
+	if strings.HasPrefix(cb.Data, printAllPrefix) {
+		b.printAllPasses(ctx, chatID, cb.From.ID, strings.TrimPrefix(cb.Data, printAllPrefix))
+		return
+	}
+
+	passID, err := uuid.Parse(strings.TrimPrefix(cb.Data, printPassPrefix))
+	if err != nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgInvalidPassID))
+		return
+	}
+
+	pass := b.ownPass(ctx, chatID, cb.From.ID, passID)
+	if pass == nil {
+		return
+	}
+
+	b.sendPassesPDF(ctx, chatID, []*domain.Pass{pass}, "pass_"+passID.String()[:8]+".pdf", b.t(ctx, msgPassPDF, b.passInfo(ctx, pass)))
+}
+
+func (b *Bot) printAllPasses(ctx context.Context, chatID, userID int64, residentIDStr string) {
+	residentID, err := strconv.ParseInt(residentIDStr, 10, 64)
+	if err != nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgInvalidButtonData))
+		return
+	}
+
+	residents := b.residentsFor(ctx, chatID, userID)
+	if residents == nil {
+		return
+	}
+
+	var resident *domain.Resident
+	for _, r := range residents {
+		if r.ID == residentID {
+			resident = r
+			break
+		}
+	}
+	if resident == nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgInvalidButtonData))
+		return
+	}
+
+	passes, err := b.visiblePasses(ctx, resident)
+	if err != nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgGetPassesFailed, err.Error()))
+		b.logger.Error("failed to get active passes", zap.Error(err), zap.Int64("resident_id", resident.ID))
+		return
+	}
+	if len(passes) == 0 {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgNoActivePasses))
+		return
+	}
+
+	b.sendPassesPDF(ctx, chatID, passes, "passes.pdf", b.t(ctx, msgPassesPDF, len(passes)))
+}
+
+func (b *Bot) sendPassesPDF(ctx context.Context, chatID int64, passes []*domain.Pass, fileName, caption string) {
+	ids := make([]uuid.UUID, 0, len(passes))
+	for _, pass := range passes {
+		ids = append(ids, pass.ID)
+	}
+
+	document, err := b.passPrintService.PrintPasses(ctx, ids, nil)
+	if err != nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgPrintFailed, err.Error()))
+		b.logger.Error("failed to print passes", zap.Error(err), zap.Int("passes", len(ids)))
+		return
+	}
+
+	if err := b.api.SendDocument(ctx, chatID, fileName, document, caption); err != nil {
+		b.logger.Error("failed to send document", zap.Error(err), zap.String("file", fileName))
+	}
+}
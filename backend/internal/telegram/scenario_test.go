@@ -0,0 +1,1072 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"yardpass/internal/config"
+	"yardpass/internal/domain"
+	"yardpass/internal/pdf"
+	"yardpass/internal/qr"
+	"yardpass/internal/service"
+	"yardpass/internal/telegram/telegramtest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const (
+	residentTelegramID = int64(1001)
+	strangerTelegramID = int64(2002)
+	testVisitToken     = "hV3kq9Zt2WcXbN4p"
+)
+
+// harness runs the bot against the fake Bot API. Updates are fetched with
+// getUpdates and processed synchronously, so every step is complete when
+// send/press return.
+type harness struct {
+	t          *testing.T
+	fake       *telegramtest.Server
+	bot        *Bot
+	passes     *memPassRepo
+	rules      *memRuleRepo
+	guests     *memSavedGuestRepo
+	broadcasts *memBroadcastRepo
+	requests   *memPassRequestRepo
+	entries    *memEntryRequestRepo
+	scans      *memScanEventRepo
+	events     *memEventRepo
+	exceptions *memPassExceptionRepo
+	vehicles   *memResidentVehicleRepo
+	watchlist  *memPlateWatchlistRepo
+	users      *memUserRepo
+	offset     int64
+}
+
+func newHarness(t *testing.T) *harness {
+	t.Helper()
+
+	fake := telegramtest.NewServer(t)
+	logger := zap.NewNop()
+
+	cfg := &config.Config{
+		Server: config.ServerConfig{PublicURL: "https://yardpass.example/"},
+		Telegram: config.TelegramConfig{
+			BotToken:   telegramtest.Token,
+			APIBaseURL: fake.URL(),
+			ServerHost: "127.0.0.1",
+			ServerPort: "0",
+			Workers:    2,
+			QueueSize:  10,
+		},
+	}
+
+	api := NewAPIClient(cfg, logger)
+	api.sleep = func(ctx context.Context, d time.Duration) error { return nil }
+
+	visitToken := testVisitToken
+	buildings := &memBuildingRepo{buildings: map[int64]*domain.Building{
+		1: {ID: 1, Name: "ЖК Тест", VisitToken: &visitToken},
+	}}
+	apartments := &memApartmentRepo{apartments: map[int64]*domain.Apartment{
+		10: {ID: 10, BuildingID: 1, Number: "42"},
+	}}
+	residents := &memResidentRepo{residents: []*domain.Resident{
+		{ID: 100, ApartmentID: 10, TelegramID: residentTelegramID, ChatID: residentTelegramID, Status: "active", Role: "primary", CanIssuePasses: true},
+	}}
+	passes := &memPassRepo{}
+	rules := &memRuleRepo{}
+	guests := &memSavedGuestRepo{}
+	broadcasts := &memBroadcastRepo{}
+	requests := &memPassRequestRepo{}
+	entries := &memEntryRequestRepo{}
+	scans := &memScanEventRepo{}
+	users := &memUserRepo{}
+	events := &memEventRepo{passes: passes, scans: scans}
+	exceptions := &memPassExceptionRepo{}
+	vehicles := &memResidentVehicleRepo{}
+	watchlist := &memPlateWatchlistRepo{}
+
+	passService := service.NewPassService(passes, apartments, rules, scans, vehicles, watchlist, residents, time.UTC, logger)
+	pdfGen, err := pdf.NewGenerator(cfg, qr.NewGenerator(), time.UTC, logger)
+	require.NoError(t, err)
+	passPrintService := service.NewPassPrintService(passes, apartments, buildings, pdfGen, logger)
+	bot := &Bot{
+		serverHost:          cfg.Telegram.ServerHost,
+		serverPort:          cfg.Telegram.ServerPort,
+		api:                 api,
+		passService:         passService,
+		userService:         service.NewUserService(users, buildings, nil, logger),
+		residentService:     service.NewResidentService(residents, apartments, nil, logger),
+		guestService:        service.NewGuestService(guests, logger),
+		broadcastService:    service.NewBroadcastService(broadcasts, buildings, apartments, logger),
+		passRequestService:  service.NewPassRequestService(requests, apartments, residents, passService, logger),
+		entryRequestService: service.NewEntryRequestService(entries, apartments, residents, scans, passService, logger),
+		passShareService:    service.NewPassShareService(cfg, passes, apartments, buildings, residents, logger),
+		passPrintService:    passPrintService,
+		eventService:        service.NewEventService(events, apartments, passService, passPrintService, qr.NewGenerator(), logger),
+		exceptionService:    service.NewPassExceptionService(exceptions, passes, apartments, users, passService, logger),
+		vehicleService:      service.NewResidentVehicleService(vehicles, apartments, logger),
+		watchlistService:    service.NewWatchlistService(watchlist, users, logger),
+		residentRepo:        residents,
+		apartmentRepo:       apartments,
+		buildingRepo:        buildings,
+		qrGen:               qr.NewGenerator(),
+		logger:              logger,
+		states:              NewMemoryStateStore(),
+		location:            time.UTC,
+	}
+	bot.dispatcher = newDispatcher(cfg.Telegram.Workers, cfg.Telegram.QueueSize, bot.ProcessUpdate, logger)
+
+	return &harness{t: t, fake: fake, bot: bot, passes: passes, rules: rules, guests: guests, broadcasts: broadcasts, requests: requests, entries: entries, scans: scans, events: events, exceptions: exceptions, users: users, vehicles: vehicles, watchlist: watchlist}
+}
+
+func (h *harness) deliver() {
+	h.t.Helper()
+
+	updates, err := h.bot.api.GetUpdates(context.Background(), h.offset, 0)
+	require.NoError(h.t, err)
+	for _, update := range updates {
+		h.bot.ProcessUpdate(context.Background(), update)
+		h.offset = update.UpdateID + 1
+	}
+}
+
+func (h *harness) send(userID int64, text string) telegramtest.Message {
+	h.t.Helper()
+	h.fake.SendText(userID, text)
+	h.deliver()
+	return h.last(userID)
+}
+
+func (h *harness) sendDocument(userID int64, fileName string, data []byte) telegramtest.Message {
+	h.t.Helper()
+	h.fake.SendDocument(userID, fileName, data, "")
+	h.deliver()
+	return h.last(userID)
+}
+
+func (h *harness) press(userID int64, data string) telegramtest.Message {
+	h.t.Helper()
+	h.fake.PressButton(userID, data)
+	h.deliver()
+	return h.last(userID)
+}
+
+// pressButton presses the button of the last message whose text contains
+// label.
+func (h *harness) pressButton(userID int64, label string) telegramtest.Message {
+	h.t.Helper()
+	button, ok := h.last(userID).Button(label)
+	require.True(h.t, ok, "no button %q in %+v", label, h.last(userID))
+	return h.press(userID, button.Data)
+}
+
+func (h *harness) last(userID int64) telegramtest.Message {
+	h.t.Helper()
+	msg, ok := h.fake.LastMessage(userID)
+	require.True(h.t, ok, "bot sent nothing to %d", userID)
+	return msg
+}
+
+func TestScenario_CreateCarPass(t *testing.T) {
+	h := newHarness(t)
+
+	msg := h.send(residentTelegramID, "/start")
+	assert.Contains(t, msg.Text, "Добро пожаловать")
+
+	msg = h.pressButton(residentTelegramID, "Выдать пропуск")
+	assert.Contains(t, msg.Text, "Выберите тип гостя")
+
+	msg = h.pressButton(residentTelegramID, "На автомобиле")
+	assert.Contains(t, msg.Text, "Введите номер автомобиля")
+
+	msg = h.send(residentTelegramID, "a123bc77")
+	assert.Contains(t, msg.Text, "Выберите срок действия")
+
+	msg = h.pressButton(residentTelegramID, "2 часа")
+	assert.Contains(t, msg.Text, "Введите имя гостя")
+
+	msg = h.send(residentTelegramID, "Иван")
+	assert.True(t, msg.Photo)
+	assert.Contains(t, msg.Text, "Пропуск создан")
+	assert.Contains(t, msg.Text, "A123BC77")
+	assert.Contains(t, msg.Text, "Иван")
+
+	passes := h.passes.all()
+	require.Len(t, passes, 1)
+	assert.Equal(t, int64(10), passes[0].ApartmentID)
+	assert.Equal(t, int64(100), *passes[0].ResidentID)
+	assert.Equal(t, "A123BC77", *passes[0].CarPlate)
+	assert.WithinDuration(t, time.Now().Add(2*time.Hour), passes[0].ValidTo, time.Minute)
+
+	photos := h.fake.Requests("sendPhoto")
+	require.Len(t, photos, 1)
+	assert.NotEmpty(t, photos[0].Photo)
+}
+
+func TestScenario_CreateTaxiPass(t *testing.T) {
+	h := newHarness(t)
+	taxiHours := 1
+	h.rules.rule = &domain.Rule{
+		BuildingID:                 1,
+		DailyPassLimitPerApartment: 5,
+		MaxPassDurationHours:       24,
+		Categories:                 []domain.CategoryRule{{Category: domain.PassCategoryTaxi, MaxDurationHours: &taxiHours}},
+	}
+
+	h.send(residentTelegramID, "/create")
+	msg := h.pressButton(residentTelegramID, "Такси")
+	assert.Contains(t, msg.Text, "Введите номер автомобиля")
+
+	h.send(residentTelegramID, "a123bc77")
+	h.pressButton(residentTelegramID, "2 часа")
+	msg = h.send(residentTelegramID, "-")
+	assert.Contains(t, msg.Text, "Администрация может сделать исключение")
+	messages := h.fake.Messages(residentTelegramID)
+	require.GreaterOrEqual(t, len(messages), 2)
+	assert.Contains(t, messages[len(messages)-2].Text, "exceeds maximum of 1 hours")
+	assert.Empty(t, h.passes.all())
+
+	h.send(residentTelegramID, "/create")
+	h.pressButton(residentTelegramID, "Такси")
+	h.send(residentTelegramID, "a123bc77")
+	h.pressButton(residentTelegramID, "1 час")
+	msg = h.send(residentTelegramID, "-")
+	assert.True(t, msg.Photo)
+	assert.Contains(t, msg.Text, "Категория: Такси")
+
+	passes := h.passes.all()
+	require.Len(t, passes, 1)
+	assert.Equal(t, domain.PassCategoryTaxi, passes[0].Category)
+	assert.Equal(t, "A123BC77", *passes[0].CarPlate)
+}
+
+func TestScenario_PassException(t *testing.T) {
+	h := newHarness(t)
+	ctx := context.Background()
+	buildingID := int64(1)
+	adminTelegramID := int64(3003)
+	h.users.users = []*domain.User{
+		{ID: 7, Role: "admin", BuildingID: &buildingID, TelegramID: &adminTelegramID, Status: "active"},
+	}
+	h.rules.rule = &domain.Rule{BuildingID: 1, DailyPassLimitPerApartment: 5, MaxPassDurationHours: 1}
+
+	h.send(residentTelegramID, "/create")
+	h.pressButton(residentTelegramID, "На автомобиле")
+	h.send(residentTelegramID, "a123bc77")
+	h.pressButton(residentTelegramID, "4 часа")
+	msg := h.send(residentTelegramID, "Иван")
+	assert.Contains(t, msg.Text, "Администрация может сделать исключение")
+	assert.Empty(t, h.passes.all())
+
+	msg = h.send(residentTelegramID, "Переезд, грузчики до вечера")
+	assert.Contains(t, msg.Text, "Заявка №1 отправлена")
+
+	notice := h.last(adminTelegramID)
+	assert.Contains(t, notice.Text, "Заявка на исключение №1")
+	assert.Contains(t, notice.Text, "A123BC77")
+	assert.Contains(t, notice.Text, "максимальный срок пропуска")
+	assert.Contains(t, notice.Text, "Переезд, грузчики до вечера")
+
+	_, pass, err := h.bot.exceptionService.Approve(ctx, 1, 7, &buildingID, nil)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(4*time.Hour), pass.ValidTo, time.Minute)
+
+	n, err := h.bot.notifyPassExceptionDecisions(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	msg = h.last(residentTelegramID)
+	assert.True(t, msg.Photo)
+	assert.Contains(t, msg.Text, "Пропуск создан")
+	assert.Contains(t, msg.Text, "Иван")
+
+	var approved bool
+	for _, m := range h.fake.Messages(residentTelegramID) {
+		approved = approved || strings.Contains(m.Text, "одобрила заявку №1")
+	}
+	assert.True(t, approved)
+
+	n, err = h.bot.notifyPassExceptionDecisions(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
+
+func TestScenario_CreatePedestrianPassUntilTime(t *testing.T) {
+	h := newHarness(t)
+
+	h.send(residentTelegramID, "/create")
+	h.pressButton(residentTelegramID, "Пеший гость")
+	msg := h.pressButton(residentTelegramID, "До времени")
+	assert.Contains(t, msg.Text, "ЧЧ:ММ")
+
+	msg = h.send(residentTelegramID, "25:99")
+	assert.Contains(t, msg.Text, "Неверный формат времени")
+
+	validTo := time.Now().UTC().Add(3 * time.Hour).Truncate(time.Minute)
+	h.send(residentTelegramID, validTo.Format("15:04"))
+	msg = h.send(residentTelegramID, "-")
+	assert.True(t, msg.Photo)
+	assert.Contains(t, msg.Text, "Пеший гость")
+
+	passes := h.passes.all()
+	require.Len(t, passes, 1)
+	assert.Nil(t, passes[0].CarPlate)
+	assert.Nil(t, passes[0].GuestName)
+	assert.Equal(t, validTo, passes[0].ValidTo)
+}
+
+func TestScenario_ScheduleFuturePass(t *testing.T) {
+	h := newHarness(t)
+
+	h.send(residentTelegramID, "/create")
+	h.pressButton(residentTelegramID, "Пеший гость")
+	msg := h.pressButton(residentTelegramID, "Запланировать")
+	assert.Contains(t, msg.Text, "Выберите дату")
+
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1)
+	date := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 0, 0, 0, 0, time.UTC)
+	if date.Month() != time.Now().UTC().Month() {
+		msg = h.press(residentTelegramID, calendarMonthPrefix+date.Format(monthLayout))
+	}
+	msg = h.press(residentTelegramID, calendarDatePrefix+date.Format(dateLayout))
+	assert.Contains(t, msg.Text, "Выберите время начала")
+
+	start := date.Add(10 * time.Hour)
+	_, ok := msg.Button("10:00")
+	require.True(t, ok)
+	msg = h.press(residentTelegramID, timeSlotPrefix+strconv.FormatInt(start.Unix(), 10))
+	assert.Contains(t, msg.Text, "Выберите время окончания")
+
+	msg = h.pressButton(residentTelegramID, "14:00")
+	assert.Contains(t, msg.Text, "Всё верно")
+
+	msg = h.pressButton(residentTelegramID, "Подтвердить")
+	assert.Contains(t, msg.Text, "Введите имя гостя")
+
+	msg = h.send(residentTelegramID, "Пётр")
+	assert.True(t, msg.Photo)
+	assert.Contains(t, msg.Text, "Действует с")
+
+	passes := h.passes.all()
+	require.Len(t, passes, 1)
+	assert.Equal(t, start, passes[0].ValidFrom)
+	assert.Equal(t, start.Add(4*time.Hour), passes[0].ValidTo)
+
+	msg = h.send(residentTelegramID, "/list")
+	assert.Contains(t, msg.Text, "Действует с")
+}
+
+func TestScenario_ScheduledWindowViolatesQuietHours(t *testing.T) {
+	h := newHarness(t)
+	quietStart, quietEnd := "22:00", "07:00"
+	h.rules.rule = &domain.Rule{
+		DailyPassLimitPerApartment: 5,
+		MaxPassDurationHours:       24,
+		QuietHoursStart:            &quietStart,
+		QuietHoursEnd:              &quietEnd,
+	}
+
+	h.send(residentTelegramID, "/create")
+	h.pressButton(residentTelegramID, "Пеший гость")
+	h.pressButton(residentTelegramID, "Запланировать")
+
+	date := time.Now().UTC().AddDate(0, 0, 2)
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	h.press(residentTelegramID, calendarMonthPrefix+date.Format(monthLayout))
+	h.press(residentTelegramID, calendarDatePrefix+date.Format(dateLayout))
+	h.press(residentTelegramID, timeSlotPrefix+strconv.FormatInt(date.Add(20*time.Hour).Unix(), 10))
+
+	msg := h.press(residentTelegramID, timeSlotPrefix+strconv.FormatInt(date.Add(23*time.Hour).Unix(), 10))
+	assert.Contains(t, msg.Text, "Это время не подходит")
+	assert.Contains(t, msg.Text, "quiet hours")
+
+	msg = h.press(residentTelegramID, timeSlotPrefix+strconv.FormatInt(date.Add(21*time.Hour).Unix(), 10))
+	assert.Contains(t, msg.Text, "Всё верно")
+}
+
+func TestScenario_QuickPickSavedGuest(t *testing.T) {
+	h := newHarness(t)
+
+	h.send(residentTelegramID, "/create")
+	msg := h.pressButton(residentTelegramID, "На автомобиле")
+	assert.Contains(t, msg.Text, "Введите номер автомобиля")
+	h.send(residentTelegramID, "a 123 bc 77")
+	h.pressButton(residentTelegramID, "1 час")
+	h.send(residentTelegramID, "Иван")
+
+	h.send(residentTelegramID, "/create")
+	msg = h.pressButton(residentTelegramID, "На автомобиле")
+	assert.Contains(t, msg.Text, "Выберите гостя из списка")
+
+	msg = h.pressButton(residentTelegramID, "A123BC77 · Иван")
+	assert.Contains(t, msg.Text, "Выберите срок действия")
+	msg = h.pressButton(residentTelegramID, "2 часа")
+	_, ok := msg.Button("Иван")
+	require.True(t, ok, "saved guest name is offered")
+
+	msg = h.pressButton(residentTelegramID, "Иван")
+	assert.True(t, msg.Photo)
+
+	passes := h.passes.all()
+	require.Len(t, passes, 2)
+	assert.Equal(t, "A123BC77", *passes[1].CarPlate)
+	assert.Equal(t, "Иван", *passes[1].GuestName)
+
+	guests, _ := h.guests.ListByResidentID(context.Background(), 100, 0)
+	require.Len(t, guests, 1)
+	assert.Equal(t, 2, guests[0].UseCount)
+}
+
+func TestScenario_ManageGuests(t *testing.T) {
+	h := newHarness(t)
+	ctx := context.Background()
+	anna := "Анна"
+	require.NoError(t, h.guests.Touch(ctx, 100, "A123BC77", nil))
+	require.NoError(t, h.guests.Touch(ctx, 100, "B456CM99", &anna))
+
+	msg := h.send(residentTelegramID, "/guests")
+	assert.Contains(t, msg.Text, "Ваши гости")
+	assert.Contains(t, msg.Text, "1. B456CM99 · Анна")
+
+	msg = h.pressButton(residentTelegramID, "⭐ A123BC77")
+	assert.Contains(t, msg.Text, "1. ⭐ A123BC77")
+
+	msg = h.pressButton(residentTelegramID, "Удалить")
+	assert.NotContains(t, msg.Text, "A123BC77")
+
+	// Guests of other residents cannot be changed.
+	h.press(residentTelegramID, guestDeletePrefix+"999:2")
+	guests, _ := h.guests.ListByResidentID(ctx, 100, 0)
+	assert.Len(t, guests, 1)
+
+	h.send(residentTelegramID, "/create")
+	msg = h.pressButton(residentTelegramID, "На автомобиле")
+	_, ok := msg.Button("B456CM99")
+	assert.True(t, ok)
+}
+
+func TestScenario_ResidentVehicles(t *testing.T) {
+	h := newHarness(t)
+	ctx := context.Background()
+
+	msg := h.send(residentTelegramID, "/cars")
+	assert.Contains(t, msg.Text, "Автомобилей пока нет")
+
+	msg = h.pressButton(residentTelegramID, "Добавить автомобиль")
+	assert.Contains(t, msg.Text, "Отправьте номер автомобиля")
+
+	msg = h.send(residentTelegramID, "; без номера")
+	assert.Contains(t, msg.Text, "Отправьте номер ещё раз")
+
+	h.send(residentTelegramID, "а123вс77; белая Kia")
+	msg = h.last(residentTelegramID)
+	assert.Contains(t, msg.Text, "1. A123BC77 · белая Kia")
+
+	// Guards let the car in without a pass.
+	result, err := h.bot.passService.ValidatePassByCarPlate(ctx, "A 123 BC 77", 7, nil)
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, "RESIDENT_VEHICLE", result.Reason)
+	assert.Equal(t, "42", result.Apartment)
+	assert.Contains(t, h.bot.formatValidationResult(ctx, result), "Автомобиль жителя")
+
+	events := h.scans.all()
+	require.Len(t, events, 1)
+	assert.Nil(t, events[0].PassID)
+	assert.Equal(t, int64(10), *events[0].ApartmentID)
+	assert.Equal(t, "RESIDENT_VEHICLE", *events[0].Reason)
+
+	// The apartment is capped at three cars.
+	residentID := int64(100)
+	for _, plate := range []string{"B111BB77", "C222CC77"} {
+		_, err := h.bot.vehicleService.AddVehicle(ctx, domain.CreateResidentVehicleRequest{ApartmentID: 10, CarPlate: plate, ResidentID: &residentID}, nil)
+		require.NoError(t, err)
+	}
+	h.send(residentTelegramID, "/cars")
+	h.pressButton(residentTelegramID, "Добавить автомобиль")
+	msg = h.send(residentTelegramID, "E333EE77")
+	assert.Contains(t, msg.Text, "at most 3 cars")
+
+	h.send(residentTelegramID, "/cars")
+	msg = h.pressButton(residentTelegramID, "🗑 A123BC77")
+	assert.NotContains(t, msg.Text, "A123BC77")
+
+	result, err = h.bot.passService.ValidatePassByCarPlate(ctx, "A123BC77", 7, nil)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+}
+
+func TestScenario_PlateWatchlist(t *testing.T) {
+	h := newHarness(t)
+	ctx := context.Background()
+	buildingID := int64(1)
+	adminTelegramID := int64(3003)
+	h.users.users = []*domain.User{
+		{ID: 7, Role: "admin", BuildingID: &buildingID, TelegramID: &adminTelegramID, Status: "active"},
+	}
+
+	_, err := h.bot.watchlistService.AddEntry(ctx, domain.CreateWatchlistEntryRequest{BuildingID: 1, CarPlate: "а123вс77", Reason: "долг за парковку"})
+	require.NoError(t, err)
+	_, err = h.bot.watchlistService.AddEntry(ctx, domain.CreateWatchlistEntryRequest{BuildingID: 1, CarPlate: "B111BB77", Reason: "ДТП во дворе", Severity: domain.WatchlistSeverityWatch})
+	require.NoError(t, err)
+
+	// Residents cannot issue passes to banned cars.
+	h.send(residentTelegramID, "/create")
+	h.pressButton(residentTelegramID, "На автомобиле")
+	h.send(residentTelegramID, "A123BC77")
+	h.pressButton(residentTelegramID, "4 часа")
+	msg := h.send(residentTelegramID, "Иван")
+	assert.Contains(t, msg.Text, "banned")
+	assert.Empty(t, h.passes.all())
+
+	// Watched cars get passes and are let in, with an alert.
+	h.send(residentTelegramID, "/create")
+	h.pressButton(residentTelegramID, "На автомобиле")
+	h.send(residentTelegramID, "B111BB77")
+	h.pressButton(residentTelegramID, "4 часа")
+	h.send(residentTelegramID, "Пётр")
+	require.Len(t, h.passes.all(), 1)
+
+	result, err := h.bot.passService.ValidatePassByCarPlate(ctx, "B111BB77", 7, &buildingID)
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	text := h.bot.formatValidationResult(ctx, result)
+	assert.Contains(t, text, "номер B111BB77 в списке наблюдения")
+	assert.Contains(t, text, "Пропуск действителен")
+
+	result, err = h.bot.passService.ValidatePassByCarPlate(ctx, "A123BC77", 7, &buildingID)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, "BLACKLISTED", result.Reason)
+	text = h.bot.formatValidationResult(ctx, result)
+	assert.Contains(t, text, "номер A123BC77 в чёрном списке")
+	assert.Contains(t, text, "Причина: долг за парковку")
+
+	n, err := h.bot.notifyPlateAlerts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	alerts := h.fake.Messages(adminTelegramID)
+	require.Len(t, alerts, 2)
+	assert.Contains(t, alerts[0].Text, "Автомобиль из списка наблюдения у ворот")
+	assert.Contains(t, alerts[0].Text, "охрана пропустила автомобиль")
+	assert.Contains(t, alerts[1].Text, "Автомобиль из чёрного списка у ворот")
+	assert.Contains(t, alerts[1].Text, "A123BC77")
+	assert.Contains(t, alerts[1].Text, "Въезд не разрешён")
+}
+
+func TestScenario_GuestRequestApproved(t *testing.T) {
+	h := newHarness(t)
+	h.fake.SetLanguage(strangerTelegramID, "en")
+
+	msg := h.send(strangerTelegramID, "/start visit_"+testVisitToken)
+	assert.Contains(t, msg.Text, "apartment you are visiting")
+
+	msg = h.send(strangerTelegramID, "13")
+	assert.Contains(t, msg.Text, "Apartment not found")
+
+	msg = h.send(strangerTelegramID, "42")
+	assert.Contains(t, msg.Text, "Enter the car plate")
+
+	msg = h.send(strangerTelegramID, "a123bc77")
+	assert.Contains(t, msg.Text, "When will you arrive")
+
+	msg = h.pressButton(strangerTelegramID, "Now")
+	assert.Contains(t, msg.Text, "Choose how long")
+
+	msg = h.pressButton(strangerTelegramID, "1 hour")
+	assert.Contains(t, msg.Text, "has been sent to the residents")
+
+	msg = h.last(residentTelegramID)
+	assert.Contains(t, msg.Text, "Гость просит пропуск")
+	assert.Contains(t, msg.Text, "User2002")
+	assert.Contains(t, msg.Text, "A123BC77")
+	assert.Contains(t, msg.Text, "Начало: с момента одобрения")
+	assert.Empty(t, h.passes.all())
+
+	msg = h.pressButton(residentTelegramID, "Одобрить")
+	assert.Contains(t, msg.Text, "пропуск отправлен гостю")
+
+	msg = h.last(strangerTelegramID)
+	assert.True(t, msg.Photo)
+	assert.Contains(t, msg.Text, "The residents approved your request")
+	assert.Contains(t, msg.Text, "A123BC77")
+
+	passes := h.passes.all()
+	require.Len(t, passes, 1)
+	assert.Equal(t, int64(10), passes[0].ApartmentID)
+	assert.Equal(t, int64(100), *passes[0].ResidentID)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), passes[0].ValidTo, time.Minute)
+
+	requests := h.requests.all()
+	require.Len(t, requests, 1)
+	assert.Equal(t, domain.PassRequestStatusApproved, requests[0].Status)
+	assert.Equal(t, passes[0].ID, *requests[0].PassID)
+
+	// The buttons of a decided request do nothing.
+	msg = h.press(residentTelegramID, "visit_no:100:1")
+	assert.Contains(t, msg.Text, "уже принято решение")
+	assert.Len(t, h.passes.all(), 1)
+}
+
+func TestScenario_GuestRequestDeclined(t *testing.T) {
+	h := newHarness(t)
+
+	h.send(strangerTelegramID, "/start visit_"+testVisitToken)
+	h.send(strangerTelegramID, "42")
+	h.pressButton(strangerTelegramID, "Пеший гость")
+	h.pressButton(strangerTelegramID, "Сейчас")
+	h.pressButton(strangerTelegramID, "4 часа")
+
+	// Only residents of the apartment may decide.
+	msg := h.press(strangerTelegramID, "visit_ok:100:1")
+	assert.Contains(t, msg.Text, "житель не найден")
+
+	msg = h.last(residentTelegramID)
+	assert.Contains(t, msg.Text, "Пеший гость")
+	msg = h.pressButton(residentTelegramID, "Отклонить")
+	assert.Contains(t, msg.Text, "Заявка отклонена")
+
+	msg = h.last(strangerTelegramID)
+	assert.Contains(t, msg.Text, "отклонили вашу заявку")
+	assert.Empty(t, h.passes.all())
+	assert.Equal(t, domain.PassRequestStatusDeclined, h.requests.all()[0].Status)
+}
+
+func TestScenario_GuestRequestScheduled(t *testing.T) {
+	h := newHarness(t)
+
+	h.send(strangerTelegramID, "/start visit_"+testVisitToken)
+	h.send(strangerTelegramID, "42")
+	msg := h.pressButton(strangerTelegramID, "Пеший гость")
+	assert.Contains(t, msg.Text, "Когда вы приедете")
+
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1)
+	date := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 0, 0, 0, 0, time.UTC)
+	if date.Month() != time.Now().UTC().Month() {
+		h.press(strangerTelegramID, calendarMonthPrefix+date.Format(monthLayout))
+	}
+	msg = h.press(strangerTelegramID, calendarDatePrefix+date.Format(dateLayout))
+	assert.Contains(t, msg.Text, "Выберите время начала")
+
+	start := date.Add(18 * time.Hour)
+	msg = h.press(strangerTelegramID, timeSlotPrefix+strconv.FormatInt(start.Unix(), 10))
+	assert.Contains(t, msg.Text, "Приезд в 18:00")
+
+	h.pressButton(strangerTelegramID, "2 часа")
+	assert.Equal(t, start, *h.requests.all()[0].ValidFrom)
+
+	msg = h.last(residentTelegramID)
+	assert.Contains(t, msg.Text, "Начало: 18:00")
+	h.pressButton(residentTelegramID, "Одобрить")
+
+	passes := h.passes.all()
+	require.Len(t, passes, 1)
+	assert.Equal(t, start, passes[0].ValidFrom)
+	assert.Equal(t, start.Add(2*time.Hour), passes[0].ValidTo)
+}
+
+func TestScenario_GuestRequestInvalidLink(t *testing.T) {
+	h := newHarness(t)
+
+	msg := h.send(strangerTelegramID, "/start visit_nosuchtoken")
+	assert.Contains(t, msg.Text, "Ссылка недействительна")
+
+	// Building IDs are not accepted in place of the token.
+	msg = h.send(strangerTelegramID, "/start visit_1")
+	assert.Contains(t, msg.Text, "Ссылка недействительна")
+}
+
+func TestScenario_GuardCallAllowed(t *testing.T) {
+	h := newHarness(t)
+	ctx := context.Background()
+
+	request, err := h.bot.entryRequestService.CreateRequest(ctx, 7, 1, "42", "a123bc77")
+	require.NoError(t, err)
+
+	handled, err := h.bot.notifyEntryRequests(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, handled)
+
+	msg := h.last(residentTelegramID)
+	assert.Contains(t, msg.Text, "пропустить A123BC77")
+
+	msg = h.pressButton(residentTelegramID, "Пропустить")
+	assert.Contains(t, msg.Text, "Охрана пропустит A123BC77")
+
+	passes := h.passes.all()
+	require.Len(t, passes, 1)
+	assert.True(t, passes[0].SingleUse)
+	assert.WithinDuration(t, time.Now().Add(15*time.Minute), passes[0].ValidTo, time.Minute)
+
+	request, err = h.bot.entryRequestService.GetRequest(ctx, request.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.EntryRequestStatusAllowed, request.Status)
+
+	events := h.scans.all()
+	require.Len(t, events, 1)
+	assert.Equal(t, "valid", events[0].Result)
+	assert.Equal(t, int64(7), *events[0].GuardUserID)
+	assert.Equal(t, passes[0].ID, *events[0].PassID)
+
+	// A second answer is not accepted.
+	msg = h.press(residentTelegramID, "entry_no:100:1")
+	assert.Contains(t, msg.Text, "уже дан")
+}
+
+func TestScenario_GuardCallDenied(t *testing.T) {
+	h := newHarness(t)
+	ctx := context.Background()
+
+	_, err := h.bot.entryRequestService.CreateRequest(ctx, 7, 1, "42", "A123BC77")
+	require.NoError(t, err)
+	_, err = h.bot.notifyEntryRequests(ctx)
+	require.NoError(t, err)
+
+	msg := h.pressButton(residentTelegramID, "Не пускать")
+	assert.Contains(t, msg.Text, "Охрана не пропустит A123BC77")
+	assert.Empty(t, h.passes.all())
+
+	events := h.scans.all()
+	require.Len(t, events, 1)
+	assert.Equal(t, "invalid", events[0].Result)
+	assert.Equal(t, "RESIDENT_DENIED", *events[0].Reason)
+	assert.Nil(t, events[0].PassID)
+	assert.Equal(t, int64(10), *events[0].ApartmentID)
+}
+
+func TestScenario_BackAndCancel(t *testing.T) {
+	h := newHarness(t)
+
+	h.send(residentTelegramID, "/create")
+	msg := h.pressButton(residentTelegramID, "Пеший гость")
+	assert.Contains(t, msg.Text, "Выберите срок действия")
+
+	msg = h.pressButton(residentTelegramID, "Назад")
+	assert.Contains(t, msg.Text, "Выберите тип гостя")
+
+	msg = h.pressButton(residentTelegramID, "На автомобиле")
+	assert.Contains(t, msg.Text, "Введите номер автомобиля")
+
+	msg = h.send(residentTelegramID, "/back")
+	assert.Contains(t, msg.Text, "Выберите тип гостя")
+
+	msg = h.pressButton(residentTelegramID, "Отмена")
+	assert.Contains(t, msg.Text, "отменено")
+
+	msg = h.send(residentTelegramID, "A123BC77")
+	assert.Contains(t, msg.Text, "/start")
+	assert.Empty(t, h.passes.all())
+}
+
+func TestScenario_StaleButtonsAreRejected(t *testing.T) {
+	h := newHarness(t)
+
+	msg := h.press(residentTelegramID, "duration_1h")
+	assert.Contains(t, msg.Text, "Сессия истекла")
+
+	h.send(residentTelegramID, "/create")
+	msg = h.press(residentTelegramID, "duration_1h")
+	assert.Contains(t, msg.Text, "недоступно")
+	assert.Empty(t, h.passes.all())
+}
+
+func TestScenario_ListAndRevoke(t *testing.T) {
+	h := newHarness(t)
+
+	msg := h.send(residentTelegramID, "/list")
+	assert.Contains(t, msg.Text, "нет активных пропусков")
+
+	h.send(residentTelegramID, "/create")
+	h.pressButton(residentTelegramID, "На автомобиле")
+
+	// A plate without its region is asked for again.
+	msg = h.send(residentTelegramID, "в456см")
+	assert.Contains(t, msg.Text, "Не удалось разобрать номер «в456см»")
+
+	h.send(residentTelegramID, "B456CM99")
+	h.pressButton(residentTelegramID, "1 час")
+	h.send(residentTelegramID, "-")
+
+	msg = h.send(residentTelegramID, "/list")
+	assert.Contains(t, msg.Text, "B456CM99")
+
+	msg = h.send(residentTelegramID, "/revoke")
+	assert.Contains(t, msg.Text, "Выберите пропуск для отзыва")
+
+	msg = h.pressButton(residentTelegramID, "B456CM99")
+	assert.Contains(t, msg.Text, "Пропуск отозван")
+
+	passes := h.passes.all()
+	require.Len(t, passes, 1)
+	assert.Equal(t, "revoked", passes[0].Status)
+
+	msg = h.send(residentTelegramID, "/list")
+	assert.Contains(t, msg.Text, "нет активных пропусков")
+}
+
+func TestScenario_SharePassLink(t *testing.T) {
+	h := newHarness(t)
+
+	h.send(residentTelegramID, "/create")
+	h.pressButton(residentTelegramID, "На автомобиле")
+	h.send(residentTelegramID, "B456CM99")
+	h.pressButton(residentTelegramID, "1 час")
+	h.send(residentTelegramID, "-")
+	passID := h.passes.all()[0].ID
+
+	msg := h.send(residentTelegramID, "/list")
+	_, ok := msg.Button("🔗")
+	require.True(t, ok)
+
+	msg = h.pressButton(residentTelegramID, "🔗")
+	assert.Contains(t, msg.Text, "https://yardpass.example/p/")
+	link := msg.Text
+
+	// Sharing again returns the link already sent.
+	msg = h.press(residentTelegramID, "share_pass_"+passID.String())
+	assert.Equal(t, link, msg.Text)
+
+	msg = h.pressButton(residentTelegramID, "Отозвать ссылку")
+	assert.Contains(t, msg.Text, "Ссылка на пропуск 🚗 B456CM99 отозвана")
+	assert.Equal(t, "active", h.passes.all()[0].Status)
+
+	msg = h.press(residentTelegramID, "share_pass_"+passID.String())
+	assert.Contains(t, msg.Text, "https://yardpass.example/p/")
+	assert.NotEqual(t, link, msg.Text)
+
+	msg = h.press(strangerTelegramID, "share_pass_"+passID.String())
+	assert.Contains(t, msg.Text, "житель не найден")
+}
+
+func TestScenario_DynamicQR(t *testing.T) {
+	h := newHarness(t)
+
+	h.send(residentTelegramID, "/create")
+	h.pressButton(residentTelegramID, "На автомобиле")
+	h.send(residentTelegramID, "B456CM99")
+	h.pressButton(residentTelegramID, "1 час")
+	msg := h.send(residentTelegramID, "-")
+	require.True(t, msg.Photo)
+	passID := h.passes.all()[0].ID
+
+	msg = h.pressButton(residentTelegramID, "Сделать QR динамическим")
+	require.True(t, msg.Photo)
+	assert.Contains(t, msg.Text, "Динамический QR пропуска 🚗 B456CM99")
+	_, ok := msg.Button("Обновить QR")
+	assert.True(t, ok)
+
+	pass := h.passes.all()[0]
+	assert.True(t, pass.Dynamic)
+	require.Len(t, pass.QRSecret, 20)
+	secret := append([]byte(nil), pass.QRSecret...)
+
+	// The static code already sent no longer admits the guest.
+	result, err := h.bot.passService.ValidatePass(context.Background(), passID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "DYNAMIC_QR_REQUIRED", result.Reason)
+
+	msg = h.send(residentTelegramID, "/list")
+	_, ok = msg.Button("📄")
+	assert.False(t, ok, "print button for a dynamic pass")
+
+	msg = h.pressButton(residentTelegramID, "🔄")
+	require.True(t, msg.Photo)
+	assert.Len(t, h.fake.Requests("sendPhoto"), 3)
+	assert.Equal(t, secret, h.passes.all()[0].QRSecret, "refreshing keeps the secret")
+
+	msg = h.press(strangerTelegramID, "refresh_qr_"+passID.String())
+	assert.Contains(t, msg.Text, "житель не найден")
+}
+
+func TestScenario_PrintPasses(t *testing.T) {
+	h := newHarness(t)
+
+	h.send(residentTelegramID, "/create")
+	h.pressButton(residentTelegramID, "На автомобиле")
+	h.send(residentTelegramID, "B456CM99")
+	h.pressButton(residentTelegramID, "1 час")
+	h.send(residentTelegramID, "-")
+	passID := h.passes.all()[0].ID
+
+	msg := h.send(residentTelegramID, "/list")
+	_, ok := msg.Button("Все пропуска в PDF")
+	assert.False(t, ok, "batch button for a single pass")
+
+	msg = h.pressButton(residentTelegramID, "📄")
+	assert.Equal(t, "pass_"+passID.String()[:8]+".pdf", msg.Document)
+	assert.Contains(t, msg.Text, "B456CM99")
+	documents := h.fake.Requests("sendDocument")
+	require.Len(t, documents, 1)
+	assert.True(t, bytes.HasPrefix(documents[0].Document, []byte("%PDF")))
+
+	h.send(residentTelegramID, "/create")
+	h.pressButton(residentTelegramID, "Пеший гость")
+	h.pressButton(residentTelegramID, "4 часа")
+	h.send(residentTelegramID, "-")
+
+	h.send(residentTelegramID, "/list")
+	msg = h.pressButton(residentTelegramID, "Все пропуска в PDF")
+	assert.Equal(t, "passes.pdf", msg.Document)
+	assert.Contains(t, msg.Text, "2")
+
+	msg = h.press(strangerTelegramID, "print_pass_"+passID.String())
+	assert.Contains(t, msg.Text, "житель не найден")
+	msg = h.press(strangerTelegramID, "print_all_100")
+	assert.Contains(t, msg.Text, "житель не найден")
+	assert.Len(t, h.fake.Requests("sendDocument"), 2)
+}
+
+func TestScenario_EventGuestList(t *testing.T) {
+	h := newHarness(t)
+	day := time.Now().AddDate(0, 0, 1).Format("02.01")
+
+	msg := h.send(residentTelegramID, "/event")
+	assert.Contains(t, msg.Text, "название и время")
+
+	msg = h.send(residentTelegramID, "День рождения")
+	assert.Contains(t, msg.Text, "Не удалось разобрать")
+
+	msg = h.send(residentTelegramID, "День рождения; "+day+" 22:00-02:00")
+	assert.Contains(t, msg.Text, "Отправьте список гостей")
+	assert.Contains(t, msg.Text, "02:00")
+
+	msg = h.send(residentTelegramID, "гости")
+	assert.Contains(t, msg.Text, "файлом .csv или .xlsx")
+
+	msg = h.sendDocument(residentTelegramID, "guests.csv", []byte("номер;имя\nА123ВС77;Иван\nA123BC77;Пётр\n!!!;Мария\n"))
+	assert.Contains(t, msg.Text, "Строка 3")
+	assert.Contains(t, msg.Text, "Строка 4")
+	assert.Empty(t, h.passes.all())
+
+	h.sendDocument(residentTelegramID, "guests.csv", []byte("\xef\xbb\xbfномер;имя\nА123ВС77;Иван\n;Мария\n"))
+	messages := h.fake.Messages(residentTelegramID)
+	created := messages[len(messages)-2]
+	assert.Contains(t, created.Text, "Мероприятие «День рождения» создано")
+	assert.Contains(t, created.Text, "Выдано пропусков: 2")
+	assert.Equal(t, "event_1.pdf", h.last(residentTelegramID).Document)
+
+	passes := h.passes.all()
+	require.Len(t, passes, 2)
+	assert.Equal(t, "A123BC77", *passes[0].CarPlate)
+	assert.Nil(t, passes[1].CarPlate)
+	assert.Equal(t, "Мария", *passes[1].GuestName)
+	assert.Equal(t, 4*time.Hour, passes[0].ValidTo.Sub(passes[0].ValidFrom))
+
+	msg = h.press(strangerTelegramID, eventRevokePrefix+"1")
+	assert.Contains(t, msg.Text, "житель не найден")
+
+	h.scans.Create(context.Background(), &domain.ScanEvent{PassID: &passes[0].ID, Result: "valid", ScannedAt: time.Now()})
+	msg = h.press(residentTelegramID, eventReportPrefix+"1")
+	assert.Contains(t, msg.Text, "пришли 1 из 2")
+	assert.Contains(t, msg.Text, "✅ 🚗 A123BC77 (Иван)")
+
+	msg = h.press(residentTelegramID, eventRevokePrefix+"1")
+	assert.Contains(t, msg.Text, "Отозвано пропусков: 2")
+	for _, p := range h.passes.all() {
+		assert.Equal(t, "revoked", p.Status)
+	}
+
+	msg = h.press(residentTelegramID, eventRevokePrefix+"1")
+	assert.Contains(t, msg.Text, "уже отменено")
+}
+
+func TestScenario_CannotRevokeForeignPass(t *testing.T) {
+	h := newHarness(t)
+
+	h.send(residentTelegramID, "/create")
+	h.pressButton(residentTelegramID, "Пеший гость")
+	h.pressButton(residentTelegramID, "4 часа")
+	h.send(residentTelegramID, "-")
+	passID := h.passes.all()[0].ID
+
+	msg := h.press(strangerTelegramID, "revoke_pass_"+passID.String())
+	assert.Contains(t, msg.Text, "житель не найден")
+	assert.Equal(t, "active", h.passes.all()[0].Status)
+}
+
+func TestScenario_UnknownUser(t *testing.T) {
+	h := newHarness(t)
+
+	msg := h.send(strangerTelegramID, "/start")
+	assert.Contains(t, msg.Text, "не зарегистрированы")
+}
+
+func TestScenario_EnglishFromTelegramLanguage(t *testing.T) {
+	h := newHarness(t)
+	h.fake.SetLanguage(residentTelegramID, "en-US")
+
+	msg := h.send(residentTelegramID, "/start")
+	assert.Contains(t, msg.Text, "Welcome to YardPass")
+
+	msg = h.pressButton(residentTelegramID, "Issue a guest pass")
+	assert.Contains(t, msg.Text, "Choose the guest type")
+	h.pressButton(residentTelegramID, "On foot")
+	msg = h.pressButton(residentTelegramID, "Schedule")
+	assert.Contains(t, msg.Text, "Choose the date")
+	_, ok := msg.Button("Mo")
+	assert.True(t, ok, "weekdays are translated")
+
+	msg = h.send(residentTelegramID, "/cancel")
+	assert.Equal(t, "Pass creation cancelled", msg.Text)
+
+	h.fake.SetLanguage(strangerTelegramID, "de")
+	msg = h.send(strangerTelegramID, "/start")
+	assert.Contains(t, msg.Text, "not registered")
+}
+
+func TestScenario_LanguageOverride(t *testing.T) {
+	h := newHarness(t)
+	h.fake.SetLanguage(residentTelegramID, "en")
+
+	msg := h.send(residentTelegramID, "/language")
+	assert.Contains(t, msg.Text, "Choose a language")
+
+	msg = h.pressButton(residentTelegramID, "Русский")
+	assert.Equal(t, "✅ Язык: 🇷🇺 Русский", msg.Text)
+
+	msg = h.send(residentTelegramID, "/start")
+	assert.Contains(t, msg.Text, "Добро пожаловать", "stored language wins over language_code")
+
+	h.send(residentTelegramID, "/language")
+	msg = h.pressButton(residentTelegramID, "Telegram")
+	assert.Contains(t, msg.Text, "follow your Telegram settings")
+
+	msg = h.send(residentTelegramID, "/start")
+	assert.Contains(t, msg.Text, "Welcome to YardPass")
+
+	h.send(strangerTelegramID, "/language")
+	msg = h.pressButton(strangerTelegramID, "English")
+	assert.Contains(t, msg.Text, "только для жителей")
+}
+
+func TestBot_PollingEndToEnd(t *testing.T) {
+	h := newHarness(t)
+
+	require.NoError(t, h.bot.Start(context.Background()))
+	commands := h.fake.Requests("setMyCommands")
+	require.Len(t, commands, 3, "default menu and one per language")
+	assert.Nil(t, commands[0].Params["language_code"])
+	assert.Equal(t, "en", commands[2].Params["language_code"])
+	assert.Len(t, h.fake.Requests("deleteWebhook"), 1)
+
+	h.fake.SendText(residentTelegramID, "/start")
+	h.fake.SendText(residentTelegramID, "/list")
+
+	messages, ok := h.fake.WaitMessages(residentTelegramID, 2, 5*time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, h.bot.Stop(ctx))
+
+	require.True(t, ok, "got %d messages", len(messages))
+	assert.True(t, strings.Contains(messages[0].Text, "Добро пожаловать"))
+	assert.True(t, strings.Contains(messages[1].Text, "нет активных пропусков"))
+}
@@ -0,0 +1,61 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"yardpass/internal/service"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	sharePassPrefix   = "share_pass_"
+	unsharePassPrefix = "unshare_pass_"
+)
+
+// handleShareCallback sends the public link of a pass or revokes it.
+func (b *Bot) handleShareCallback(ctx context.Context, cb CallbackQuery) {
+	chatID := cb.Message.Chat.ID
+
+	share := strings.HasPrefix(cb.Data, sharePassPrefix)
+	passIDStr := strings.TrimPrefix(strings.TrimPrefix(cb.Data, sharePassPrefix), unsharePassPrefix)
+	passID, err := uuid.Parse(passIDStr)
+	if err != nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgInvalidPassID))
+		return
+	}
+
+	pass := b.ownPass(ctx, chatID, cb.From.ID, passID)
+	if pass == nil {
+		return
+	}
+
+	if !share {
+		if err := b.passShareService.Unshare(ctx, passID, service.PassScope{}); err != nil {
+			b.sendMessage(ctx, chatID, b.t(ctx, msgShareFailed, err.Error()))
+			b.logger.Error("failed to revoke pass link", zap.Error(err), zap.String("pass_id", passID.String()))
+			return
+		}
+		b.sendMessage(ctx, chatID, b.t(ctx, msgPassLinkRevoked, b.passInfo(ctx, pass)))
+		return
+	}
+
+	url, err := b.passShareService.Share(ctx, passID, service.PassScope{})
+	if err != nil {
+		if !errors.Is(err, service.ErrPassNotShareable) && !errors.Is(err, service.ErrPassSharingDisabled) {
+			b.logger.Error("failed to share pass", zap.Error(err), zap.String("pass_id", passID.String()))
+		}
+		b.sendMessage(ctx, chatID, b.t(ctx, msgShareFailed, err.Error()))
+		return
+	}
+
+	keyboard := map[string]interface{}{
+		"inline_keyboard": [][]map[string]interface{}{
+			{{"text": b.t(ctx, msgRevokePassLink), "callback_data": unsharePassPrefix + passID.String()}},
+		},
+	}
+	b.sendMessageWithKeyboard(ctx, chatID, b.t(ctx, msgPassLink, b.passInfo(ctx, pass), url), keyboard)
+}
@@ -0,0 +1,130 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"yardpass/internal/redis"
+)
+
+const conversationTTL = 10 * time.Minute
+
+// StateStore keeps conversations between updates. The bot holds no state of
+// its own, so any replica may handle any update.
+type StateStore interface {
+	// Get returns the conversation of the user or nil if there is none.
+	Get(ctx context.Context, userID int64) (*Conversation, error)
+	Set(ctx context.Context, userID int64, conv *Conversation) error
+	Delete(ctx context.Context, userID int64) error
+}
+
+type RedisStateStore struct {
+	redis *redis.Client
+}
+
+func NewRedisStateStore(redisClient *redis.Client) *RedisStateStore {
+	return &RedisStateStore{redis: redisClient}
+}
+
+func (s *RedisStateStore) Get(ctx context.Context, userID int64) (*Conversation, error) {
+	payload, err := s.redis.Get(ctx, conversationKey(userID))
+	if err != nil {
+		if redis.IsNil(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get conversation: %w", err)
+	}
+
+	var conv Conversation
+	if err := json.Unmarshal([]byte(payload), &conv); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal conversation: %w", err)
+	}
+	return &conv, nil
+}
+
+func (s *RedisStateStore) Set(ctx context.Context, userID int64, conv *Conversation) error {
+	payload, err := json.Marshal(conv)
+	if err != nil {
+		return fmt.Errorf("failed to marshal conversation: %w", err)
+	}
+
+	if err := s.redis.Set(ctx, conversationKey(userID), payload, conversationTTL); err != nil {
+		return fmt.Errorf("failed to set conversation: %w", err)
+	}
+	return nil
+}
+
+func (s *RedisStateStore) Delete(ctx context.Context, userID int64) error {
+	return s.redis.Delete(ctx, conversationKey(userID))
+}
+
+// MemoryStateStore is a StateStore for tests. Conversations go through the
+// same JSON encoding as in Redis.
+type MemoryStateStore struct {
+	mu      sync.Mutex
+	entries map[int64]memoryEntry
+	now     func() time.Time
+}
+
+type memoryEntry struct {
+	payload   []byte
+	expiresAt time.Time
+}
+
+func NewMemoryStateStore() *MemoryStateStore {
+	return &MemoryStateStore{
+		entries: make(map[int64]memoryEntry),
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryStateStore) Get(ctx context.Context, userID int64) (*Conversation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[userID]
+	if !ok {
+		return nil, nil
+	}
+	if s.now().After(entry.expiresAt) {
+		delete(s.entries, userID)
+		return nil, nil
+	}
+
+	var conv Conversation
+	if err := json.Unmarshal(entry.payload, &conv); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal conversation: %w", err)
+	}
+	return &conv, nil
+}
+
+func (s *MemoryStateStore) Set(ctx context.Context, userID int64, conv *Conversation) error {
+	payload, err := json.Marshal(conv)
+	if err != nil {
+		return fmt.Errorf("failed to marshal conversation: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[userID] = memoryEntry{
+		payload:   payload,
+		expiresAt: s.now().Add(conversationTTL),
+	}
+	return nil
+}
+
+func (s *MemoryStateStore) Delete(ctx context.Context, userID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, userID)
+	return nil
+}
+
+func conversationKey(userID int64) string {
+	return fmt.Sprintf("bot_conversation:%d", userID)
+}
@@ -0,0 +1,470 @@
+// Package telegramtest provides an in-process fake of the Telegram Bot API
+// for tests. Tests enqueue updates as if users wrote to the bot and inspect
+// what the bot sent back.
+package telegramtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const Token = "test-token"
+
+// Request is a Bot API call received by the fake server.
+type Request struct {
+	Method string
+	Params map[string]interface{}
+	// Photo holds the uploaded file of sendPhoto.
+	Photo []byte
+	// Document holds the uploaded file of sendDocument.
+	Document []byte
+
+	fileName string
+}
+
+// Message is a message the bot sent to a chat, via sendMessage or as the
+// caption of sendPhoto or sendDocument.
+type Message struct {
+	ChatID int64
+	Text   string
+	Photo  bool
+	// Document is the file name of a sendDocument upload.
+	Document string
+	Buttons  []Button
+}
+
+type Button struct {
+	Text string
+	Data string
+}
+
+// Button returns the first button whose text contains substr.
+func (m Message) Button(substr string) (Button, bool) {
+	for _, b := range m.Buttons {
+		if strings.Contains(b.Text, substr) {
+			return b, true
+		}
+	}
+	return Button{}, false
+}
+
+type failure struct {
+	status     int
+	retryAfter int
+}
+
+type Server struct {
+	srv *httptest.Server
+
+	mu           sync.Mutex
+	updates      []map[string]interface{}
+	nextUpdateID int64
+	nextMsgID    int64
+	requests     []Request
+	messages     []Message
+	failures     map[string][]failure
+	languages    map[int64]string
+	files        map[string][]byte
+	newUpdate    chan struct{}
+}
+
+// NewServer starts a fake Bot API server that is closed with the test.
+func NewServer(t testing.TB) *Server {
+	s := &Server{
+		nextUpdateID: 1,
+		nextMsgID:    1,
+		failures:     make(map[string][]failure),
+		languages:    make(map[int64]string),
+		files:        make(map[string][]byte),
+		newUpdate:    make(chan struct{}),
+	}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.srv.Close)
+	return s
+}
+
+// URL is the base URL to configure instead of https://api.telegram.org.
+func (s *Server) URL() string {
+	return s.srv.URL
+}
+
+// SetLanguage sets the language_code of the user's client sent with later
+// updates.
+func (s *Server) SetLanguage(userID int64, languageCode string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.languages[userID] = languageCode
+}
+
+// SendText enqueues a private message from the user.
+func (s *Server) SendText(userID int64, text string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.enqueue(map[string]interface{}{
+		"message": s.message(userID, text),
+	})
+}
+
+// SendDocument enqueues a private message from the user with a file the
+// bot can download via getFile.
+func (s *Server) SendDocument(userID int64, fileName string, data []byte, caption string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fileID := fmt.Sprintf("file%d", len(s.files)+1)
+	s.files[fileID] = data
+
+	msg := s.message(userID, "")
+	delete(msg, "text")
+	msg["caption"] = caption
+	msg["document"] = map[string]interface{}{
+		"file_id":   fileID,
+		"file_name": fileName,
+		"file_size": len(data),
+	}
+	s.enqueue(map[string]interface{}{
+		"message": msg,
+	})
+}
+
+// PressButton enqueues a callback query for an inline button.
+func (s *Server) PressButton(userID int64, data string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.enqueue(map[string]interface{}{
+		"callback_query": map[string]interface{}{
+			"id":      strconv.FormatInt(s.nextUpdateID, 10),
+			"from":    s.user(userID),
+			"message": s.message(userID, ""),
+			"data":    data,
+		},
+	})
+}
+
+// FailNext makes the next call of method fail with status. For 429 the
+// response carries retry_after like the real API does.
+func (s *Server) FailNext(method string, status int, retryAfter int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures[method] = append(s.failures[method], failure{status: status, retryAfter: retryAfter})
+}
+
+// Requests returns the calls of method received so far.
+func (s *Server) Requests(method string) []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var requests []Request
+	for _, r := range s.requests {
+		if r.Method == method {
+			requests = append(requests, r)
+		}
+	}
+	return requests
+}
+
+// Messages returns the messages sent to the chat so far.
+func (s *Server) Messages(chatID int64) []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var messages []Message
+	for _, m := range s.messages {
+		if m.ChatID == chatID {
+			messages = append(messages, m)
+		}
+	}
+	return messages
+}
+
+// LastMessage returns the latest message sent to the chat.
+func (s *Server) LastMessage(chatID int64) (Message, bool) {
+	messages := s.Messages(chatID)
+	if len(messages) == 0 {
+		return Message{}, false
+	}
+	return messages[len(messages)-1], true
+}
+
+// WaitMessages waits until the chat has received at least n messages.
+func (s *Server) WaitMessages(chatID int64, n int, timeout time.Duration) ([]Message, bool) {
+	deadline := time.Now().Add(timeout)
+	for {
+		messages := s.Messages(chatID)
+		if len(messages) >= n {
+			return messages, true
+		}
+		if time.Now().After(deadline) {
+			return messages, false
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func (s *Server) enqueue(update map[string]interface{}) {
+	update["update_id"] = s.nextUpdateID
+	s.nextUpdateID++
+	s.updates = append(s.updates, update)
+
+	close(s.newUpdate)
+	s.newUpdate = make(chan struct{})
+}
+
+func (s *Server) message(userID int64, text string) map[string]interface{} {
+	msg := map[string]interface{}{
+		"message_id": s.nextMsgID,
+		"from":       s.user(userID),
+		"chat":       map[string]interface{}{"id": userID},
+		"text":       text,
+		"date":       time.Now().Unix(),
+	}
+	s.nextMsgID++
+	return msg
+}
+
+func (s *Server) user(userID int64) map[string]interface{} {
+	u := map[string]interface{}{
+		"id":         userID,
+		"first_name": fmt.Sprintf("User%d", userID),
+	}
+	if code := s.languages[userID]; code != "" {
+		u["language_code"] = code
+	}
+	return u
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	if fileID, ok := strings.CutPrefix(r.URL.Path, "/file/bot"+Token+"/documents/"); ok {
+		s.mu.Lock()
+		data, found := s.files[fileID]
+		s.mu.Unlock()
+		if !found {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/bot"+Token+"/")
+	if path == r.URL.Path || path == "" {
+		writeJSON(w, http.StatusNotFound, map[string]interface{}{"ok": false, "error_code": 404, "description": "Not Found"})
+		return
+	}
+	method := path
+
+	req, err := parseRequest(method, r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{"ok": false, "error_code": 400, "description": err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	if queued := s.failures[method]; len(queued) > 0 {
+		f := queued[0]
+		s.failures[method] = queued[1:]
+		s.mu.Unlock()
+
+		resp := map[string]interface{}{
+			"ok":          false,
+			"error_code":  f.status,
+			"description": http.StatusText(f.status),
+		}
+		if f.retryAfter > 0 {
+			resp["parameters"] = map[string]interface{}{"retry_after": f.retryAfter}
+		}
+		writeJSON(w, f.status, resp)
+		return
+	}
+	s.mu.Unlock()
+
+	switch method {
+	case "getUpdates":
+		s.handleGetUpdates(w, r, req)
+	case "getFile":
+		fileID, _ := req.Params["file_id"].(string)
+		s.mu.Lock()
+		data, found := s.files[fileID]
+		s.mu.Unlock()
+		if !found {
+			writeJSON(w, http.StatusBadRequest, map[string]interface{}{"ok": false, "error_code": 400, "description": "Bad Request: invalid file_id"})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"ok": true,
+			"result": map[string]interface{}{
+				"file_id":   fileID,
+				"file_size": len(data),
+				"file_path": "documents/" + fileID,
+			},
+		})
+	case "sendMessage", "sendPhoto", "sendDocument":
+		s.mu.Lock()
+		msg := Message{
+			ChatID:  toInt64(req.Params["chat_id"]),
+			Photo:   method == "sendPhoto",
+			Buttons: buttons(req.Params["reply_markup"]),
+		}
+		if method == "sendDocument" {
+			msg.Document = req.fileName
+		}
+		if method != "sendMessage" {
+			msg.Text, _ = req.Params["caption"].(string)
+		} else {
+			msg.Text, _ = req.Params["text"].(string)
+		}
+		s.messages = append(s.messages, msg)
+		id := s.nextMsgID
+		s.nextMsgID++
+		s.mu.Unlock()
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"ok": true,
+			"result": map[string]interface{}{
+				"message_id": id,
+				"chat":       map[string]interface{}{"id": msg.ChatID},
+				"text":       msg.Text,
+			},
+		})
+	default:
+		writeJSON(w, http.StatusOK, map[string]interface{}{"ok": true, "result": true})
+	}
+}
+
+// handleGetUpdates confirms updates below offset and returns the rest,
+// waiting up to timeout seconds for new ones.
+func (s *Server) handleGetUpdates(w http.ResponseWriter, r *http.Request, req Request) {
+	offset := toInt64(req.Params["offset"])
+	timeout := time.Duration(toInt64(req.Params["timeout"])) * time.Second
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	for {
+		s.mu.Lock()
+		pending := s.updates[:0:0]
+		kept := s.updates[:0]
+		for _, u := range s.updates {
+			if toInt64(u["update_id"]) >= offset {
+				pending = append(pending, u)
+				kept = append(kept, u)
+			}
+		}
+		s.updates = kept
+		newUpdate := s.newUpdate
+		s.mu.Unlock()
+
+		if len(pending) > 0 || timeout == 0 {
+			writeJSON(w, http.StatusOK, map[string]interface{}{"ok": true, "result": pending})
+			return
+		}
+
+		select {
+		case <-newUpdate:
+		case <-deadline.C:
+			timeout = 0
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func parseRequest(method string, r *http.Request) (Request, error) {
+	req := Request{Method: method, Params: map[string]interface{}{}}
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(10 << 20); err != nil {
+			return req, err
+		}
+		for k, v := range r.MultipartForm.Value {
+			if len(v) > 0 {
+				req.Params[k] = v[0]
+			}
+		}
+		for field, files := range r.MultipartForm.File {
+			f, err := files[0].Open()
+			if err != nil {
+				return req, err
+			}
+			data, err := io.ReadAll(f)
+			f.Close()
+			if err != nil {
+				return req, err
+			}
+			if field == "document" {
+				req.Document = data
+				req.fileName = files[0].Filename
+			} else {
+				req.Photo = data
+			}
+		}
+		return req, nil
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return req, err
+	}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &req.Params); err != nil {
+			return req, err
+		}
+	}
+	return req, nil
+}
+
+func buttons(markup interface{}) []Button {
+	// Multipart requests carry the keyboard as a JSON string.
+	if raw, isString := markup.(string); isString {
+		json.Unmarshal([]byte(raw), &markup)
+	}
+	m, ok := markup.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	rows, _ := m["inline_keyboard"].([]interface{})
+
+	var result []Button
+	for _, row := range rows {
+		cells, _ := row.([]interface{})
+		for _, cell := range cells {
+			c, _ := cell.(map[string]interface{})
+			text, _ := c["text"].(string)
+			data, _ := c["callback_data"].(string)
+			result = append(result, Button{Text: text, Data: data})
+		}
+	}
+	return result
+}
+
+func toInt64(v interface{}) int64 {
+	switch n := v.(type) {
+	case float64:
+		return int64(n)
+	case int64:
+		return n
+	case string:
+		i, _ := strconv.ParseInt(n, 10, 64)
+		return i
+	}
+	return 0
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
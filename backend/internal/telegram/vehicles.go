@@ -0,0 +1,167 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"yardpass/internal/domain"
+	"yardpass/internal/service"
+
+	"go.uber.org/zap"
+)
+
+const (
+	vehicleAddPrefix    = "veh_add:"
+	vehicleDeletePrefix = "veh_del:"
+)
+
+func vehicleLabel(v *domain.ResidentVehicle) string {
+	if v.Label == nil {
+		return v.CarPlate
+	}
+	return fmt.Sprintf("%s · %s", v.CarPlate, *v.Label)
+}
+
+// showVehicles lists the registered cars of the resident's apartment with
+// buttons to remove them and to add another.
+func (b *Bot) showVehicles(ctx context.Context, chatID int64, resident *domain.Resident) {
+	vehicles, err := b.vehicleService.ListVehicles(ctx, nil, &resident.ApartmentID)
+	if err != nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgListVehiclesFailed, err.Error()))
+		b.logger.Error("failed to list resident vehicles", zap.Error(err), zap.Int64("apartment_id", resident.ApartmentID))
+		return
+	}
+
+	text := b.t(ctx, msgVehiclesHeader, b.apartmentLabel(ctx, resident.ApartmentID))
+	var keyboardRows [][]map[string]interface{}
+	for i, v := range vehicles {
+		text += b.t(ctx, msgVehiclesItem, i+1, vehicleLabel(v))
+		keyboardRows = append(keyboardRows, []map[string]interface{}{
+			{"text": b.t(ctx, msgDeleteVehicle, v.CarPlate), "callback_data": fmt.Sprintf("%s%d:%d", vehicleDeletePrefix, resident.ID, v.ID)},
+		})
+	}
+	if len(vehicles) == 0 {
+		text += b.t(ctx, msgNoVehicles)
+	}
+	text += b.t(ctx, msgVehiclesHint)
+
+	keyboardRows = append(keyboardRows, []map[string]interface{}{
+		{"text": b.t(ctx, msgAddVehicle), "callback_data": fmt.Sprintf("%s%d", vehicleAddPrefix, resident.ID)},
+	})
+
+	keyboard := map[string]interface{}{
+		"inline_keyboard": keyboardRows,
+	}
+
+	b.sendMessageWithKeyboard(ctx, chatID, text, keyboard)
+}
+
+// handleVehiclesCallback serves the add/delete buttons of the cars screen.
+// The resident ID in the callback data is checked against the caller's own
+// resident rows.
+func (b *Bot) handleVehiclesCallback(ctx context.Context, cb CallbackQuery) {
+	chatID := cb.Message.Chat.ID
+
+	prefix := vehicleAddPrefix
+	if strings.HasPrefix(cb.Data, vehicleDeletePrefix) {
+		prefix = vehicleDeletePrefix
+	}
+
+	residentIDStr, vehicleIDStr, _ := strings.Cut(strings.TrimPrefix(cb.Data, prefix), ":")
+	residentID, err := strconv.ParseInt(residentIDStr, 10, 64)
+	if err != nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgInvalidButtonData))
+		return
+	}
+
+	var resident *domain.Resident
+	for _, r := range b.residentsFor(ctx, chatID, cb.From.ID) {
+		if r.ID == residentID && residentCan(r, "vehicles") {
+			resident = r
+			break
+		}
+	}
+	if resident == nil {
+		return
+	}
+
+	if prefix == vehicleAddPrefix {
+		conv := NewVehicleConversation(resident.ID, resident.ApartmentID)
+		if b.saveConversation(ctx, chatID, cb.From.ID, conv) {
+			b.promptStep(ctx, chatID, conv)
+		}
+		return
+	}
+
+	vehicleID, err := strconv.ParseInt(vehicleIDStr, 10, 64)
+	if err != nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgInvalidButtonData))
+		return
+	}
+
+	if err := b.vehicleService.RemoveApartmentVehicle(ctx, resident.ApartmentID, vehicleID); err != nil {
+		if errors.Is(err, service.ErrVehicleNotFound) {
+			b.sendMessage(ctx, chatID, b.t(ctx, msgVehicleNotFound))
+		} else {
+			b.sendMessage(ctx, chatID, b.t(ctx, msgError, err.Error()))
+			b.logger.Error("failed to remove resident vehicle", zap.Error(err), zap.Int64("vehicle_id", vehicleID))
+		}
+		return
+	}
+
+	b.showVehicles(ctx, chatID, resident)
+}
+
+// handleVehiclePlate registers the car from "plate" or "plate; label".
+// A plate that does not parse is asked for again.
+func (b *Bot) handleVehiclePlate(ctx context.Context, msg Message, conv *Conversation) {
+	chatID := msg.Chat.ID
+
+	var resident *domain.Resident
+	for _, r := range b.residentsFor(ctx, chatID, msg.From.ID) {
+		if r.ID == conv.ResidentID && residentCan(r, "vehicles") {
+			resident = r
+			break
+		}
+	}
+	if resident == nil {
+		b.clearConversation(ctx, msg.From.ID)
+		b.sendMessage(ctx, chatID, b.t(ctx, msgApartmentNotFoundRestart))
+		return
+	}
+
+	plate, label, _ := strings.Cut(msg.Text, ";")
+	req := domain.CreateResidentVehicleRequest{
+		ApartmentID: resident.ApartmentID,
+		CarPlate:    plate,
+		ResidentID:  &resident.ID,
+	}
+	if label = strings.TrimSpace(label); label != "" {
+		req.Label = &label
+	}
+
+	if !b.finishStep(ctx, chatID, msg.From.ID, conv, EventVehiclePlate) {
+		return
+	}
+
+	vehicle, err := b.vehicleService.AddVehicle(ctx, req, nil)
+	switch {
+	case errors.Is(err, service.ErrInvalidCarPlate), errors.Is(err, service.ErrVehicleLabelTooLong):
+		b.sendMessage(ctx, chatID, b.t(ctx, msgVehicleInvalid, err.Error()))
+		return
+	case err != nil:
+		b.clearConversation(ctx, msg.From.ID)
+		b.sendMessage(ctx, chatID, b.t(ctx, msgVehicleAddFailed, err.Error()))
+		if !errors.Is(err, service.ErrVehicleExists) && !errors.Is(err, service.ErrTooManyVehicles) {
+			b.logger.Error("failed to add resident vehicle", zap.Error(err), zap.Int64("resident_id", resident.ID))
+		}
+		return
+	}
+
+	b.clearConversation(ctx, msg.From.ID)
+	b.sendMessage(ctx, chatID, b.t(ctx, msgVehicleAdded, vehicle.CarPlate))
+	b.showVehicles(ctx, chatID, resident)
+}
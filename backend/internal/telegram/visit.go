@@ -0,0 +1,248 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"yardpass/internal/domain"
+	"yardpass/internal/service"
+
+	"go.uber.org/zap"
+)
+
+const (
+	visitStartPrefix   = service.VisitStartPrefix
+	visitApprovePrefix = "visit_ok:"
+	visitDeclinePrefix = "visit_no:"
+)
+
+// startVisit begins a guest's pass request from the building's deep link
+// https://t.me/<bot>?start=visit_<token>; the random token comes from the
+// building's visit link in the admin API.
+func (b *Bot) startVisit(ctx context.Context, msg Message, token string) {
+	building, err := b.buildingRepo.GetByVisitToken(ctx, token)
+	if err != nil || building == nil {
+		b.sendMessage(ctx, msg.Chat.ID, b.t(ctx, msgVisitInvalidLink))
+		return
+	}
+
+	conv := NewVisitConversation(building.ID)
+	if name := strings.TrimSpace(strings.TrimSpace(msg.From.FirstName) + " " + strings.TrimSpace(msg.From.LastName)); name != "" {
+		conv.GuestName = &name
+	}
+	if !b.saveConversation(ctx, msg.Chat.ID, msg.From.ID, conv) {
+		return
+	}
+
+	b.sendMessage(ctx, msg.Chat.ID, b.t(ctx, msgVisitStart, building.Name))
+	b.promptStep(ctx, msg.Chat.ID, conv)
+}
+
+func (b *Bot) handleVisitApartment(ctx context.Context, msg Message, conv *Conversation) {
+	apartment, err := b.passRequestService.FindApartment(ctx, conv.BuildingID, msg.Text)
+	if errors.Is(err, service.ErrApartmentNotFound) {
+		b.sendMessage(ctx, msg.Chat.ID, b.t(ctx, msgVisitApartmentNotFound))
+		return
+	}
+	if err != nil {
+		b.sendMessage(ctx, msg.Chat.ID, b.t(ctx, msgError, err.Error()))
+		b.logger.Error("failed to find apartment", zap.Error(err), zap.Int64("building_id", conv.BuildingID))
+		return
+	}
+
+	conv.ApartmentID = apartment.ID
+	b.advance(ctx, msg.Chat.ID, msg.From.ID, conv, EventApartment)
+}
+
+// handleVisitCarPlate asks for a malformed plate again, before the
+// residents are asked to approve it.
+func (b *Bot) handleVisitCarPlate(ctx context.Context, msg Message, conv *Conversation) {
+	if _, err := service.ParseCarPlate(msg.Text); err != nil {
+		b.sendMessage(ctx, msg.Chat.ID, b.t(ctx, msgInvalidCarPlateFormat, strings.TrimSpace(msg.Text)))
+		return
+	}
+
+	conv.CarPlate = strings.TrimSpace(msg.Text)
+	conv.IsPedestrian = false
+	b.advance(ctx, msg.Chat.ID, msg.From.ID, conv, EventCarPlate)
+}
+
+// submitVisitRequest stores the finished request and asks every resident of
+// the apartment who can issue passes, each in their own language.
+func (b *Bot) submitVisitRequest(ctx context.Context, chatID int64, userID int64, conv *Conversation) {
+	lang := string(langFrom(ctx))
+	request := &domain.PassRequest{
+		ApartmentID:     conv.ApartmentID,
+		GuestTelegramID: userID,
+		GuestChatID:     chatID,
+		GuestName:       conv.GuestName,
+		GuestLanguage:   &lang,
+		ValidFrom:       conv.ValidFrom,
+		DurationMinutes: int(conv.Duration / time.Minute),
+	}
+	if !conv.IsPedestrian {
+		request.CarPlate = &conv.CarPlate
+	}
+
+	approvers, err := b.passRequestService.SubmitRequest(ctx, request)
+	switch {
+	case errors.Is(err, service.ErrTooManyPassRequests):
+		b.sendMessage(ctx, chatID, b.t(ctx, msgVisitTooMany))
+		return
+	case errors.Is(err, service.ErrNoPassRequestApprovers):
+		b.sendMessage(ctx, chatID, b.t(ctx, msgVisitNoApprovers))
+		return
+	case errors.Is(err, service.ErrPassRequestStartPassed):
+		b.sendMessage(ctx, chatID, b.t(ctx, msgVisitStartPassed))
+		return
+	case err != nil:
+		b.sendMessage(ctx, chatID, b.t(ctx, msgVisitRequestFailed, err.Error()))
+		b.logger.Error("failed to submit pass request", zap.Error(err), zap.Int64("user_id", userID))
+		return
+	}
+
+	b.sendMessage(ctx, chatID, b.t(ctx, msgVisitRequestSent, b.apartmentLabel(ctx, request.ApartmentID)))
+
+	for _, approver := range approvers {
+		approverCtx := withLang(ctx, residentLang(approver))
+
+		guest := b.t(approverCtx, msgGuestFallback)
+		if request.GuestName != nil {
+			guest = *request.GuestName
+		}
+		vehicle := b.t(approverCtx, msgPedestrianLine)
+		if request.CarPlate != nil {
+			vehicle = b.t(approverCtx, msgCarPlateLine, *request.CarPlate)
+		}
+		start := b.t(approverCtx, msgVisitStartsOnApproval)
+		if request.ValidFrom != nil {
+			start = b.formatLocalTime(*request.ValidFrom)
+		}
+
+		text := b.t(approverCtx, msgVisitRequestReceived,
+			b.apartmentLabel(approverCtx, request.ApartmentID),
+			guest,
+			vehicle,
+			start,
+			b.durationLabel(approverCtx, request.DurationMinutes),
+		)
+		keyboard := map[string]interface{}{
+			"inline_keyboard": [][]map[string]interface{}{
+				{
+					{"text": b.t(approverCtx, msgApprove), "callback_data": fmt.Sprintf("%s%d:%d", visitApprovePrefix, approver.ID, request.ID)},
+					{"text": b.t(approverCtx, msgDecline), "callback_data": fmt.Sprintf("%s%d:%d", visitDeclinePrefix, approver.ID, request.ID)},
+				},
+			},
+		}
+		if err := b.sendMessageWithKeyboard(approverCtx, approver.ChatID, text, keyboard); err != nil {
+			b.logger.Error("failed to notify resident of pass request", zap.Error(err), zap.Int64("resident_id", approver.ID))
+		}
+	}
+}
+
+func (b *Bot) durationLabel(ctx context.Context, minutes int) string {
+	switch minutes {
+	case 60:
+		return b.t(ctx, msgDuration1h)
+	case 120:
+		return b.t(ctx, msgDuration2h)
+	case 240:
+		return b.t(ctx, msgDuration4h)
+	}
+	return (time.Duration(minutes) * time.Minute).String()
+}
+
+// handleVisitDecision serves the approve/decline buttons of a pass request.
+// The resident ID in the callback data is checked against the caller's own
+// resident rows.
+func (b *Bot) handleVisitDecision(ctx context.Context, cb CallbackQuery) {
+	chatID := cb.Message.Chat.ID
+
+	prefix := visitApprovePrefix
+	if strings.HasPrefix(cb.Data, visitDeclinePrefix) {
+		prefix = visitDeclinePrefix
+	}
+
+	residentIDStr, requestIDStr, ok := strings.Cut(strings.TrimPrefix(cb.Data, prefix), ":")
+	residentID, err1 := strconv.ParseInt(residentIDStr, 10, 64)
+	requestID, err2 := strconv.ParseInt(requestIDStr, 10, 64)
+	if !ok || err1 != nil || err2 != nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgInvalidButtonData))
+		return
+	}
+
+	residents := b.residentsFor(ctx, chatID, cb.From.ID)
+	if residents == nil {
+		return
+	}
+
+	var resident *domain.Resident
+	for _, r := range residents {
+		if r.ID == residentID {
+			resident = r
+			break
+		}
+	}
+	if resident == nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgInvalidButtonData))
+		return
+	}
+
+	if prefix == visitApprovePrefix {
+		b.approveVisit(ctx, chatID, resident, requestID)
+	} else {
+		b.declineVisit(ctx, chatID, resident, requestID)
+	}
+}
+
+func (b *Bot) approveVisit(ctx context.Context, chatID int64, resident *domain.Resident, requestID int64) {
+	request, pass, err := b.passRequestService.Approve(ctx, requestID, resident)
+	if err != nil {
+		b.reportVisitError(ctx, chatID, err, msgVisitApproveFailed)
+		return
+	}
+
+	b.rememberGuest(ctx, pass)
+
+	guestCtx := withLang(ctx, storedLang(request.GuestLanguage))
+	qrPNG, err := b.qrGen.GenerateQR(ctx, pass.ID)
+	if err != nil {
+		b.sendMessage(ctx, chatID, b.t(ctx, msgQRFailed, err.Error()))
+		b.logger.Error("failed to generate QR", zap.Error(err), zap.String("pass_id", pass.ID.String()))
+		return
+	}
+	caption := b.t(guestCtx, msgVisitApprovedGuest, b.apartmentLabel(guestCtx, request.ApartmentID)) + b.passCaption(guestCtx, pass)
+	if err := b.api.SendPhoto(guestCtx, request.GuestChatID, qrPNG, caption); err != nil {
+		b.logger.Error("failed to send pass to guest", zap.Error(err), zap.Int64("request_id", request.ID))
+	}
+
+	b.sendMessage(ctx, chatID, b.t(ctx, msgVisitApproved))
+}
+
+func (b *Bot) declineVisit(ctx context.Context, chatID int64, resident *domain.Resident, requestID int64) {
+	request, err := b.passRequestService.Decline(ctx, requestID, resident)
+	if err != nil {
+		b.reportVisitError(ctx, chatID, err, msgVisitDeclineFailed)
+		return
+	}
+
+	guestCtx := withLang(ctx, storedLang(request.GuestLanguage))
+	b.sendMessage(guestCtx, request.GuestChatID, b.t(guestCtx, msgVisitDeclinedGuest, b.apartmentLabel(guestCtx, request.ApartmentID)))
+	b.sendMessage(ctx, chatID, b.t(ctx, msgVisitDeclined))
+}
+
+func (b *Bot) reportVisitError(ctx context.Context, chatID int64, err error, failed msgKey) {
+	switch {
+	case errors.Is(err, service.ErrPassRequestDecided):
+		b.sendMessage(ctx, chatID, b.t(ctx, msgVisitAlreadyDecided))
+	case errors.Is(err, service.ErrPassRequestExpired):
+		b.sendMessage(ctx, chatID, b.t(ctx, msgVisitExpired))
+	default:
+		b.sendMessage(ctx, chatID, b.t(ctx, failed, err.Error()))
+		b.logger.Warn("failed to decide pass request", zap.Error(err))
+	}
+}
@@ -0,0 +1,89 @@
+package telegram
+
+import (
+	"context"
+	"time"
+
+	"yardpass/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// plateAlertBatchSize is how many sightings are sent at once.
+	plateAlertBatchSize = 20
+	// plateAlertPollInterval is short because the car is at the gate.
+	plateAlertPollInterval = time.Second
+)
+
+// runPlateAlerts sends the sightings of watchlisted plates to the building
+// admins until ctx is cancelled.
+func (b *Bot) runPlateAlerts(ctx context.Context) {
+	defer b.wg.Done()
+
+	for {
+		if _, err := b.notifyPlateAlerts(ctx); err != nil && ctx.Err() == nil {
+			b.logger.Error("Failed to deliver plate alerts", zap.Error(err))
+		}
+
+		if err := sleepContext(ctx, plateAlertPollInterval); err != nil {
+			b.logger.Info("Plate alerts stopped by context")
+			return
+		}
+	}
+}
+
+// notifyPlateAlerts sends one batch of sightings to the admins and returns
+// how many it handled.
+func (b *Bot) notifyPlateAlerts(ctx context.Context) (int, error) {
+	alerts, err := b.watchlistService.ClaimAlerts(ctx, plateAlertBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	// Staff accounts have no language of their own.
+	adminCtx := withLang(ctx, defaultLang)
+	for _, alert := range alerts {
+		admins, err := b.watchlistService.Admins(ctx, alert.BuildingID)
+		if err != nil {
+			b.logger.Error("Failed to get admins for plate alert", zap.Error(err), zap.Int64("alert_id", alert.ID))
+			continue
+		}
+
+		title := b.t(adminCtx, msgPlateAlertWatched)
+		if alert.Severity == domain.WatchlistSeverityBan {
+			title = b.t(adminCtx, msgPlateAlertBanned)
+		}
+		outcome := b.t(adminCtx, msgPlateAlertDenied)
+		if alert.Admitted {
+			outcome = b.t(adminCtx, msgPlateAlertAdmitted)
+		}
+		guard := "—"
+		switch {
+		case alert.GuardUsername != nil:
+			guard = *alert.GuardUsername
+		case alert.CameraName != nil:
+			// Gate cameras open the barrier themselves.
+			guard = b.t(adminCtx, msgPlateAlertCamera, *alert.CameraName)
+			if alert.Admitted {
+				outcome = b.t(adminCtx, msgPlateAlertOpened)
+			}
+		}
+		text := b.t(adminCtx, msgPlateAlert,
+			title,
+			alert.CarPlate,
+			alert.Reason,
+			guard,
+			b.formatLocalTime(alert.CreatedAt),
+			outcome,
+		)
+
+		for _, admin := range admins {
+			if err := b.sendMessage(adminCtx, *admin.TelegramID, text); err != nil {
+				b.logger.Error("failed to send plate alert to admin", zap.Error(err), zap.Int64("user_id", admin.ID))
+			}
+		}
+	}
+
+	return len(alerts), nil
+}
@@ -0,0 +1,140 @@
+package telegram
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	webhookPath         = "/webhook"
+	healthPath          = "/health"
+	secretTokenHeader   = "X-Telegram-Bot-Api-Secret-Token"
+	processedUpdatesTTL = 24 * time.Hour
+	maxWebhookBodySize  = 1 << 20
+)
+
+// webhookSecretFor returns the configured secret or derives a stable one
+// from the bot token, so that all replicas agree on it without extra setup.
+func webhookSecretFor(configured, botToken string) string {
+	if configured != "" {
+		return configured
+	}
+	mac := hmac.New(sha256.New, []byte(botToken))
+	mac.Write([]byte("yardpass-webhook-secret"))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// startServer serves the webhook (in webhook mode) and the health endpoint.
+// The listener is opened synchronously so that a busy port fails Start.
+func (b *Bot) startServer() error {
+	mux := http.NewServeMux()
+	mux.HandleFunc(healthPath, b.handleHealth)
+	if b.webhookURL != "" {
+		mux.HandleFunc(webhookPath, b.handleWebhook)
+	}
+
+	addr := fmt.Sprintf("%s:%s", b.serverHost, b.serverPort)
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("listen %s: %w", addr, err)
+	}
+
+	b.server = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       15 * time.Second,
+		WriteTimeout:      15 * time.Second,
+		IdleTimeout:       60 * time.Second,
+	}
+
+	go func() {
+		b.logger.Info("Bot HTTP server listening", zap.String("address", addr))
+		if err := b.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			b.logger.Error("Bot HTTP server failed", zap.Error(err))
+		}
+	}()
+
+	return nil
+}
+
+func (b *Bot) handleHealth(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
+
+func (b *Bot) handleWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get(secretTokenHeader)), []byte(b.webhookSecret)) != 1 {
+		b.logger.Warn("Rejected webhook request with invalid secret token", zap.String("remote_addr", r.RemoteAddr))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var update Update
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxWebhookBodySize)).Decode(&update); err != nil {
+		b.logger.Error("Failed to decode update", zap.Error(err))
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	if b.isDuplicateUpdate(r.Context(), update.UpdateID) {
+		b.logger.Debug("Skipping duplicate update", zap.Int64("update_id", update.UpdateID))
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if err := b.dispatcher.submit(r.Context(), update); err != nil {
+		b.logger.Warn("Failed to queue update", zap.Error(err), zap.Int64("update_id", update.UpdateID))
+		// Let Telegram redeliver the update later.
+		b.redis.Delete(context.Background(), processedUpdateKey(update.UpdateID))
+		http.Error(w, "Service unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// isDuplicateUpdate marks the update as seen and reports whether it had been
+// seen before. Telegram redelivers updates it considers unanswered, and with
+// several replicas the retry may reach another instance. If Redis is
+// unavailable the update is processed.
+func (b *Bot) isDuplicateUpdate(ctx context.Context, updateID int64) bool {
+	first, err := b.redis.SetNX(ctx, processedUpdateKey(updateID), 1, processedUpdatesTTL)
+	if err != nil {
+		b.logger.Error("Failed to check update for duplicates", zap.Error(err), zap.Int64("update_id", updateID))
+		return false
+	}
+	return !first
+}
+
+func processedUpdateKey(updateID int64) string {
+	return fmt.Sprintf("bot_update:%d", updateID)
+}
+
+func (b *Bot) setWebhook(ctx context.Context) error {
+	return b.api.Call(ctx, "setWebhook", map[string]interface{}{
+		"url":             b.webhookURL,
+		"secret_token":    b.webhookSecret,
+		"allowed_updates": []string{"message", "callback_query"},
+	})
+}
+
+// deleteWebhook switches Telegram back to getUpdates, which fails while a
+// webhook is registered.
+func (b *Bot) deleteWebhook(ctx context.Context) error {
+	return b.api.Call(ctx, "deleteWebhook", map[string]interface{}{})
+}
@@ -0,0 +1,58 @@
+package telegram
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestWebhookSecretFor(t *testing.T) {
+	assert.Equal(t, "configured", webhookSecretFor("configured", "token"))
+
+	derived := webhookSecretFor("", "token")
+	assert.Len(t, derived, 64)
+	assert.Equal(t, derived, webhookSecretFor("", "token"))
+	assert.NotEqual(t, derived, webhookSecretFor("", "other-token"))
+}
+
+func TestHandleWebhook_RejectsInvalidSecret(t *testing.T) {
+	b := &Bot{webhookSecret: "secret", logger: zap.NewNop()}
+
+	for _, header := range []string{"", "wrong"} {
+		req := httptest.NewRequest(http.MethodPost, webhookPath, strings.NewReader(`{"update_id":1}`))
+		if header != "" {
+			req.Header.Set(secretTokenHeader, header)
+		}
+		rec := httptest.NewRecorder()
+
+		b.handleWebhook(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	}
+}
+
+func TestHandleWebhook_RejectsNonPost(t *testing.T) {
+	b := &Bot{webhookSecret: "secret", logger: zap.NewNop()}
+
+	req := httptest.NewRequest(http.MethodGet, webhookPath, nil)
+	req.Header.Set(secretTokenHeader, "secret")
+	rec := httptest.NewRecorder()
+
+	b.handleWebhook(rec, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestHandleHealth(t *testing.T) {
+	b := &Bot{logger: zap.NewNop()}
+
+	rec := httptest.NewRecorder()
+	b.handleHealth(rec, httptest.NewRequest(http.MethodGet, healthPath, nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, rec.Body.String())
+}
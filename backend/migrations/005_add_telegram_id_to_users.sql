@@ -0,0 +1,8 @@
+-- Migration: Link staff users to Telegram accounts for the bot guard mode
+-- Date: 2026-02-02
+
+ALTER TABLE users ADD COLUMN telegram_id BIGINT;
+
+CREATE UNIQUE INDEX idx_users_telegram_id ON users(telegram_id) WHERE telegram_id IS NOT NULL;
+
+COMMENT ON COLUMN users.telegram_id IS 'Telegram user ID linked via one-time code (guard mode in the bot)';
@@ -0,0 +1,9 @@
+-- Migration: Allow one Telegram account to be linked to several apartments
+-- Date: 2026-02-09
+-- A resident row now represents (telegram account, apartment) pair
+
+ALTER TABLE residents DROP CONSTRAINT IF EXISTS residents_telegram_id_key;
+
+ALTER TABLE residents ADD CONSTRAINT residents_telegram_id_apartment_id_key UNIQUE (telegram_id, apartment_id);
+
+COMMENT ON COLUMN residents.telegram_id IS 'Telegram user ID. One Telegram account may have a row per apartment';
@@ -0,0 +1,16 @@
+-- Migration: Household members managed by the primary resident
+-- Date: 2026-02-16
+-- Existing residents become primary residents of their apartments
+
+ALTER TABLE residents
+ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'primary',
+ADD COLUMN can_issue_passes BOOLEAN NOT NULL DEFAULT TRUE,
+ADD COLUMN invited_by BIGINT REFERENCES residents(id) ON DELETE SET NULL;
+
+ALTER TABLE residents ADD CONSTRAINT check_resident_role CHECK (role IN ('primary', 'member'));
+
+CREATE INDEX idx_residents_role ON residents(role);
+
+COMMENT ON COLUMN residents.role IS 'primary - added by admin, member - invited by the primary resident from the bot';
+COMMENT ON COLUMN residents.can_issue_passes IS 'Whether the household member may create passes (quota is shared per apartment)';
+COMMENT ON COLUMN residents.invited_by IS 'Primary resident who invited this household member';
@@ -0,0 +1,25 @@
+-- Migration: Recent and starred guests of residents
+-- Date: 2026-03-02
+-- Remembered per resident and offered as quick picks when creating passes
+
+CREATE TABLE saved_guests (
+    id BIGSERIAL PRIMARY KEY,
+    resident_id BIGINT NOT NULL REFERENCES residents(id) ON DELETE CASCADE,
+    car_plate VARCHAR(20) NOT NULL,
+    guest_name VARCHAR(255),
+    starred BOOLEAN NOT NULL DEFAULT FALSE,
+    use_count INTEGER NOT NULL DEFAULT 0,
+    last_used_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT saved_guests_resident_id_car_plate_key UNIQUE (resident_id, car_plate)
+);
+
+CREATE INDEX idx_saved_guests_resident_id ON saved_guests(resident_id, starred DESC, last_used_at DESC);
+
+CREATE TRIGGER update_saved_guests_updated_at BEFORE UPDATE ON saved_guests
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE saved_guests IS 'Guests a resident issued passes to. Non-starred entries are pruned to the most recent ones';
+COMMENT ON COLUMN saved_guests.car_plate IS 'Normalized car plate';
+COMMENT ON COLUMN saved_guests.starred IS 'Pinned by the resident, never pruned';
@@ -0,0 +1,9 @@
+-- Migration: Bot language chosen by the resident
+-- Date: 2026-03-09
+-- NULL means the language follows the Telegram client settings
+
+ALTER TABLE residents ADD COLUMN language VARCHAR(5);
+
+ALTER TABLE residents ADD CONSTRAINT check_resident_language CHECK (language IN ('ru', 'en'));
+
+COMMENT ON COLUMN residents.language IS 'Bot language set with /language (NULL - from Telegram language_code)';
@@ -0,0 +1,47 @@
+-- Migration: Broadcast announcements to residents
+-- Date: 2026-03-16
+-- Recipients are resolved when the broadcast is created and delivered by the bot
+
+CREATE TABLE broadcasts (
+    id BIGSERIAL PRIMARY KEY,
+    building_id BIGINT NOT NULL REFERENCES buildings(id) ON DELETE CASCADE,
+    created_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    text TEXT NOT NULL,
+    floors INTEGER[],
+    apartment_ids BIGINT[],
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_broadcasts_building_id ON broadcasts(building_id, created_at DESC);
+
+CREATE TRIGGER update_broadcasts_updated_at BEFORE UPDATE ON broadcasts
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE broadcast_recipients (
+    id BIGSERIAL PRIMARY KEY,
+    broadcast_id BIGINT NOT NULL REFERENCES broadcasts(id) ON DELETE CASCADE,
+    resident_id BIGINT REFERENCES residents(id) ON DELETE SET NULL,
+    chat_id BIGINT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    failure_reason VARCHAR(32),
+    error TEXT,
+    sent_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT broadcast_recipients_broadcast_id_chat_id_key UNIQUE (broadcast_id, chat_id),
+    CONSTRAINT check_broadcast_recipient_status CHECK (status IN ('pending', 'sending', 'sent', 'failed'))
+);
+
+CREATE INDEX idx_broadcast_recipients_pending ON broadcast_recipients(id) WHERE status = 'pending';
+
+CREATE TRIGGER update_broadcast_recipients_updated_at BEFORE UPDATE ON broadcast_recipients
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE broadcasts IS 'Announcements sent by admins to the residents of a building';
+COMMENT ON COLUMN broadcasts.floors IS 'Only apartments on these floors; NULL means all floors';
+COMMENT ON COLUMN broadcasts.apartment_ids IS 'Only these apartments; NULL means all apartments';
+COMMENT ON TABLE broadcast_recipients IS 'Delivery of a broadcast to one chat';
+COMMENT ON COLUMN broadcast_recipients.status IS 'pending, sending (claimed by the bot), sent or failed';
+COMMENT ON COLUMN broadcast_recipients.failure_reason IS 'blocked, chat_not_found, user_deactivated, rejected or error';
@@ -0,0 +1,33 @@
+-- Migration: Pass requests submitted by guests
+-- Date: 2026-03-23
+-- A guest asks for a pass through the bot and a resident of the apartment approves or declines it
+
+CREATE TABLE pass_requests (
+    id BIGSERIAL PRIMARY KEY,
+    apartment_id BIGINT NOT NULL REFERENCES apartments(id) ON DELETE CASCADE,
+    guest_telegram_id BIGINT NOT NULL,
+    guest_chat_id BIGINT NOT NULL,
+    guest_name VARCHAR(255),
+    guest_language VARCHAR(5),
+    car_plate VARCHAR(20),
+    duration_minutes INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    decided_by BIGINT REFERENCES residents(id) ON DELETE SET NULL,
+    decided_at TIMESTAMP,
+    pass_id UUID REFERENCES passes(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT check_pass_request_status CHECK (status IN ('pending', 'approved', 'declined', 'expired')),
+    CONSTRAINT check_pass_request_duration CHECK (duration_minutes > 0)
+);
+
+CREATE INDEX idx_pass_requests_guest_telegram_id ON pass_requests(guest_telegram_id, created_at DESC);
+CREATE INDEX idx_pass_requests_apartment_id ON pass_requests(apartment_id, status);
+
+CREATE TRIGGER update_pass_requests_updated_at BEFORE UPDATE ON pass_requests
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE pass_requests IS 'Passes requested by guests, waiting for a resident decision';
+COMMENT ON COLUMN pass_requests.car_plate IS 'Normalized car plate, NULL for pedestrian guests';
+COMMENT ON COLUMN pass_requests.duration_minutes IS 'Requested validity; the pass starts when approved';
+COMMENT ON COLUMN pass_requests.decided_by IS 'Resident who approved or declined the request';
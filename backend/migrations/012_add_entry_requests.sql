@@ -0,0 +1,43 @@
+-- Migration: Guards asking residents to let in visitors without a pass
+-- Date: 2026-03-30
+-- The resident allows or denies from Telegram; allowing issues a short single-use pass
+
+CREATE TABLE entry_requests (
+    id BIGSERIAL PRIMARY KEY,
+    apartment_id BIGINT NOT NULL REFERENCES apartments(id) ON DELETE CASCADE,
+    guard_user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    car_plate VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    notified_at TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    decided_by BIGINT REFERENCES residents(id) ON DELETE SET NULL,
+    decided_at TIMESTAMP,
+    pass_id UUID REFERENCES passes(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT check_entry_request_status CHECK (status IN ('pending', 'allowed', 'denied', 'expired'))
+);
+
+CREATE INDEX idx_entry_requests_unnotified ON entry_requests(created_at) WHERE notified_at IS NULL;
+CREATE INDEX idx_entry_requests_apartment_id ON entry_requests(apartment_id, status);
+
+CREATE TRIGGER update_entry_requests_updated_at BEFORE UPDATE ON entry_requests
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE entry_requests IS 'Guards asking residents whether to let in a visitor without a pass';
+COMMENT ON COLUMN entry_requests.notified_at IS 'When the bot sent the prompt to the residents';
+COMMENT ON COLUMN entry_requests.decided_by IS 'Resident who allowed or denied the entry';
+
+-- Single-use passes become 'used' after their first valid scan
+ALTER TABLE passes ADD COLUMN single_use BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN passes.single_use IS 'Pass is valid for one entry only';
+
+-- Entries decided without a pass are recorded against the apartment
+ALTER TABLE scan_events ALTER COLUMN pass_id DROP NOT NULL;
+ALTER TABLE scan_events ADD COLUMN apartment_id BIGINT REFERENCES apartments(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_scan_events_apartment_id ON scan_events(apartment_id);
+
+COMMENT ON COLUMN scan_events.pass_id IS 'Scanned pass, NULL for entries decided without one';
+COMMENT ON COLUMN scan_events.apartment_id IS 'Apartment of an entry without a pass';
@@ -0,0 +1,9 @@
+-- Migration: Shareable pass links
+-- Date: 2026-04-06
+-- A resident can send a guest a link to a public page of the pass instead of the QR image
+
+ALTER TABLE passes ADD COLUMN share_token VARCHAR(64);
+
+CREATE UNIQUE INDEX idx_passes_share_token ON passes(share_token) WHERE share_token IS NOT NULL;
+
+COMMENT ON COLUMN passes.share_token IS 'Unguessable token of the public pass page, NULL when the pass is not shared';
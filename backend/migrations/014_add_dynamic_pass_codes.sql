@@ -0,0 +1,9 @@
+-- Migration: Rotating QR codes
+-- Date: 2026-04-13
+-- A dynamic pass shows a code that changes every 30 seconds, so a forwarded screenshot stops working
+
+ALTER TABLE passes ADD COLUMN dynamic BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE passes ADD COLUMN qr_secret BYTEA;
+
+COMMENT ON COLUMN passes.dynamic IS 'The pass is admitted only by its rotating code, not by the static QR';
+COMMENT ON COLUMN passes.qr_secret IS 'HMAC key of the rotating code, NULL for static passes';
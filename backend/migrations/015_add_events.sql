@@ -0,0 +1,37 @@
+-- Migration: Event passes
+-- Date: 2026-04-20
+-- Parties and building events get their passes from an uploaded guest list in one go
+
+CREATE TABLE events (
+    id BIGSERIAL PRIMARY KEY,
+    building_id BIGINT NOT NULL REFERENCES buildings(id) ON DELETE CASCADE,
+    apartment_id BIGINT NOT NULL REFERENCES apartments(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    valid_from TIMESTAMP NOT NULL,
+    valid_to TIMESTAMP NOT NULL,
+    capacity INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    created_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    resident_id BIGINT REFERENCES residents(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT check_event_status CHECK (status IN ('active', 'revoked')),
+    CONSTRAINT check_event_capacity CHECK (capacity > 0),
+    CONSTRAINT check_event_window CHECK (valid_to > valid_from)
+);
+
+CREATE INDEX idx_events_building_id ON events(building_id, valid_from DESC);
+
+CREATE TRIGGER update_events_updated_at BEFORE UPDATE ON events
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE events IS 'Parties and building events with passes issued from a guest list';
+COMMENT ON COLUMN events.capacity IS 'Maximum number of guests on the list';
+COMMENT ON COLUMN events.created_by IS 'Admin who created the event, NULL if a resident did';
+COMMENT ON COLUMN events.resident_id IS 'Resident who created the event from the bot';
+
+ALTER TABLE passes ADD COLUMN event_id BIGINT REFERENCES events(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_passes_event_id ON passes(event_id) WHERE event_id IS NOT NULL;
+
+COMMENT ON COLUMN passes.event_id IS 'Event whose guest list the pass was issued from';
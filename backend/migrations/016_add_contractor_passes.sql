@@ -0,0 +1,22 @@
+-- Migration: Contractor passes
+-- Date: 2026-04-27
+-- Renovation crews and service companies get admin-issued passes that last for weeks but admit only on set weekdays and hours
+
+ALTER TABLE passes ADD COLUMN category VARCHAR(20) NOT NULL DEFAULT 'guest' CHECK (category IN ('guest', 'contractor'));
+ALTER TABLE passes ADD COLUMN company_name VARCHAR(255);
+ALTER TABLE passes ADD COLUMN weekdays INTEGER[];
+ALTER TABLE passes ADD COLUMN daily_from VARCHAR(5);
+ALTER TABLE passes ADD COLUMN daily_to VARCHAR(5);
+ALTER TABLE passes ADD COLUMN issued_by BIGINT REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_passes_category ON passes(category) WHERE category <> 'guest';
+
+ALTER TABLE rules ADD COLUMN max_contractor_pass_days INTEGER NOT NULL DEFAULT 30;
+
+COMMENT ON COLUMN passes.category IS 'guest for passes issued by residents, contractor for admin-issued passes of crews and service companies';
+COMMENT ON COLUMN passes.company_name IS 'Company of a contractor pass';
+COMMENT ON COLUMN passes.weekdays IS 'ISO weekdays (1 = Monday) a contractor pass admits on, NULL for every day';
+COMMENT ON COLUMN passes.daily_from IS 'Start of the daily window of a contractor pass (HH:MM)';
+COMMENT ON COLUMN passes.daily_to IS 'End of the daily window of a contractor pass (HH:MM)';
+COMMENT ON COLUMN passes.issued_by IS 'Admin who issued a contractor pass';
+COMMENT ON COLUMN rules.max_contractor_pass_days IS 'Longest contractor pass in days';
@@ -0,0 +1,36 @@
+-- Migration: Pass categories
+-- Date: 2026-05-04
+-- Taxis, deliveries and service companies get their own limits on duration, daily count and allowed hours per building
+
+ALTER TABLE passes DROP CONSTRAINT IF EXISTS passes_category_check;
+ALTER TABLE passes ADD CONSTRAINT check_pass_category
+    CHECK (category IN ('guest', 'taxi', 'delivery', 'contractor', 'service'));
+
+CREATE TABLE category_rules (
+    id BIGSERIAL PRIMARY KEY,
+    building_id BIGINT NOT NULL REFERENCES buildings(id) ON DELETE CASCADE,
+    category VARCHAR(20) NOT NULL,
+    max_duration_hours INTEGER,
+    daily_limit INTEGER,
+    allowed_from VARCHAR(5),
+    allowed_to VARCHAR(5),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT check_category_rule_category CHECK (category IN ('guest', 'taxi', 'delivery', 'contractor', 'service')),
+    CONSTRAINT check_category_rule_duration CHECK (max_duration_hours > 0),
+    CONSTRAINT check_category_rule_daily_limit CHECK (daily_limit >= 0),
+    CONSTRAINT check_category_rule_hours CHECK ((allowed_from IS NULL) = (allowed_to IS NULL)),
+    CONSTRAINT unique_category_rule UNIQUE (building_id, category)
+);
+
+CREATE TRIGGER update_category_rules_updated_at BEFORE UPDATE ON category_rules
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE INDEX idx_passes_apartment_category ON passes(apartment_id, category, created_at);
+
+COMMENT ON TABLE category_rules IS 'Per-category limits of a building, NULL columns fall back to the building rules';
+COMMENT ON COLUMN category_rules.max_duration_hours IS 'Longest pass of the category in hours';
+COMMENT ON COLUMN category_rules.daily_limit IS 'Passes of the category an apartment may issue per day';
+COMMENT ON COLUMN category_rules.allowed_from IS 'Start of the hours passes of the category admit (HH:MM)';
+COMMENT ON COLUMN category_rules.allowed_to IS 'End of the hours passes of the category admit (HH:MM)';
+COMMENT ON COLUMN passes.category IS 'guest, taxi and delivery passes are issued by residents, contractor and service passes by admins';
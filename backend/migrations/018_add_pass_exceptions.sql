@@ -0,0 +1,41 @@
+-- Migration: Exceptions to the pass rules
+-- Date: 2026-05-11
+-- A resident whose pass breaks the duration or daily limit asks the building admins; approval issues the pass without that rule
+
+CREATE TABLE pass_exceptions (
+    id BIGSERIAL PRIMARY KEY,
+    apartment_id BIGINT NOT NULL REFERENCES apartments(id) ON DELETE CASCADE,
+    resident_id BIGINT NOT NULL REFERENCES residents(id) ON DELETE CASCADE,
+    car_plate VARCHAR(20),
+    guest_name VARCHAR(255),
+    category VARCHAR(20) NOT NULL DEFAULT 'guest',
+    valid_from TIMESTAMP NOT NULL,
+    valid_to TIMESTAMP NOT NULL,
+    rule VARCHAR(20) NOT NULL,
+    reason TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    decided_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    decided_at TIMESTAMP,
+    comment TEXT,
+    pass_id UUID REFERENCES passes(id) ON DELETE SET NULL,
+    resident_notified_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT check_pass_exception_status CHECK (status IN ('pending', 'approved', 'rejected')),
+    CONSTRAINT check_pass_exception_rule CHECK (rule IN ('max_duration', 'daily_limit')),
+    CONSTRAINT check_pass_exception_window CHECK (valid_to > valid_from)
+);
+
+CREATE INDEX idx_pass_exceptions_apartment_id ON pass_exceptions(apartment_id, status);
+CREATE INDEX idx_pass_exceptions_resident_id ON pass_exceptions(resident_id, status);
+CREATE INDEX idx_pass_exceptions_undelivered ON pass_exceptions(decided_at)
+    WHERE status <> 'pending' AND resident_notified_at IS NULL;
+
+CREATE TRIGGER update_pass_exceptions_updated_at BEFORE UPDATE ON pass_exceptions
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE pass_exceptions IS 'Passes the building rules reject, waiting for an admin decision';
+COMMENT ON COLUMN pass_exceptions.rule IS 'Rule the pass breaks: max_duration or daily_limit';
+COMMENT ON COLUMN pass_exceptions.decided_by IS 'Admin who approved or rejected the exception';
+COMMENT ON COLUMN pass_exceptions.comment IS 'Admin comment sent to the resident with the decision';
+COMMENT ON COLUMN pass_exceptions.resident_notified_at IS 'When the bot told the resident about the decision';
@@ -0,0 +1,26 @@
+-- Migration: Registered cars of residents
+-- Date: 2026-05-18
+-- Admitted by car plate without a pass and counted in parking occupancy
+
+CREATE TABLE resident_vehicles (
+    id BIGSERIAL PRIMARY KEY,
+    apartment_id BIGINT NOT NULL REFERENCES apartments(id) ON DELETE CASCADE,
+    car_plate VARCHAR(20) NOT NULL,
+    label VARCHAR(100),
+    resident_id BIGINT REFERENCES residents(id) ON DELETE SET NULL,
+    created_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT resident_vehicles_apartment_id_car_plate_key UNIQUE (apartment_id, car_plate)
+);
+
+CREATE INDEX idx_resident_vehicles_car_plate ON resident_vehicles(car_plate);
+
+CREATE TRIGGER update_resident_vehicles_updated_at BEFORE UPDATE ON resident_vehicles
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE resident_vehicles IS 'Cars of residents, admitted by car plate without a pass';
+COMMENT ON COLUMN resident_vehicles.car_plate IS 'Normalized car plate';
+COMMENT ON COLUMN resident_vehicles.label IS 'Make, color or owner, shown to the guard';
+COMMENT ON COLUMN resident_vehicles.resident_id IS 'Resident who registered the car in the bot';
+COMMENT ON COLUMN resident_vehicles.created_by IS 'Admin who registered the car';
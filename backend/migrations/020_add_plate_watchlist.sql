@@ -0,0 +1,43 @@
+-- Migration: Plate watchlist
+-- Date: 2026-05-25
+-- Banned plates get no passes and are stopped at the gate; every sighting of a listed plate alerts the building admins
+
+CREATE TABLE plate_watchlist (
+    id BIGSERIAL PRIMARY KEY,
+    building_id BIGINT NOT NULL REFERENCES buildings(id) ON DELETE CASCADE,
+    car_plate VARCHAR(20) NOT NULL,
+    reason TEXT NOT NULL,
+    severity VARCHAR(20) NOT NULL DEFAULT 'ban',
+    created_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT plate_watchlist_building_id_car_plate_key UNIQUE (building_id, car_plate),
+    CONSTRAINT check_plate_watchlist_severity CHECK (severity IN ('watch', 'ban'))
+);
+
+CREATE INDEX idx_plate_watchlist_car_plate ON plate_watchlist(car_plate);
+
+CREATE TRIGGER update_plate_watchlist_updated_at BEFORE UPDATE ON plate_watchlist
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE plate_alerts (
+    id BIGSERIAL PRIMARY KEY,
+    watchlist_id BIGINT NOT NULL REFERENCES plate_watchlist(id) ON DELETE CASCADE,
+    guard_user_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    pass_id UUID REFERENCES passes(id) ON DELETE SET NULL,
+    admitted BOOLEAN NOT NULL DEFAULT FALSE,
+    notified_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_plate_alerts_watchlist_id ON plate_alerts(watchlist_id);
+CREATE INDEX idx_plate_alerts_unnotified ON plate_alerts(created_at)
+    WHERE notified_at IS NULL;
+
+COMMENT ON TABLE plate_watchlist IS 'Plates management watches for at the gate, e.g. after incidents or debts';
+COMMENT ON COLUMN plate_watchlist.car_plate IS 'Normalized car plate';
+COMMENT ON COLUMN plate_watchlist.severity IS 'watch: alert only; ban: no passes and no entry';
+COMMENT ON COLUMN plate_watchlist.created_by IS 'Admin who listed the plate';
+COMMENT ON TABLE plate_alerts IS 'Sightings of listed plates at the gate, sent to the building admins';
+COMMENT ON COLUMN plate_alerts.admitted IS 'Whether the guard was told to let the car in';
+COMMENT ON COLUMN plate_alerts.notified_at IS 'When the bot sent the alert to the admins';
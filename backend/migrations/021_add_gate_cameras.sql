@@ -0,0 +1,32 @@
+-- Migration: Gate cameras
+-- Date: 2026-06-01
+-- License plate recognition cameras post recognized plates with their own API key; their scans have no guard
+
+CREATE TABLE gate_cameras (
+    id BIGSERIAL PRIMARY KEY,
+    building_id BIGINT NOT NULL REFERENCES buildings(id) ON DELETE CASCADE,
+    gate_id VARCHAR(50) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    api_key_hash VARCHAR(64) NOT NULL,
+    min_confidence REAL,
+    last_seen_at TIMESTAMP,
+    created_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT gate_cameras_api_key_hash_key UNIQUE (api_key_hash),
+    CONSTRAINT gate_cameras_building_id_name_key UNIQUE (building_id, name),
+    CONSTRAINT check_gate_cameras_min_confidence CHECK (min_confidence IS NULL OR (min_confidence >= 0 AND min_confidence <= 1))
+);
+
+CREATE INDEX idx_gate_cameras_building_id ON gate_cameras(building_id);
+
+ALTER TABLE scan_events ALTER COLUMN guard_user_id DROP NOT NULL;
+
+ALTER TABLE plate_alerts ADD COLUMN camera_id BIGINT REFERENCES gate_cameras(id) ON DELETE SET NULL;
+
+COMMENT ON TABLE gate_cameras IS 'License plate recognition cameras at the gates';
+COMMENT ON COLUMN gate_cameras.gate_id IS 'Gate the camera watches, as named by the building';
+COMMENT ON COLUMN gate_cameras.api_key_hash IS 'SHA-256 of the camera API key, hex; the key is shown once';
+COMMENT ON COLUMN gate_cameras.last_seen_at IS 'When the camera last posted an event';
+COMMENT ON COLUMN gate_cameras.min_confidence IS 'Recognition confidence below which the gate stays closed, NULL for the server default';
+COMMENT ON COLUMN scan_events.guard_user_id IS 'Guard who scanned, NULL for gate camera scans (camera in meta)';
+COMMENT ON COLUMN plate_alerts.camera_id IS 'Gate camera that saw the plate, NULL for guard checks';
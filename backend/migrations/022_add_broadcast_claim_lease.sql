@@ -0,0 +1,9 @@
+-- Migration: Broadcast claim lease
+-- Date: 2026-06-08
+-- Recipients in sending belong to the bot that claimed them until their lease expires, so a restarting replica does not resend another replica's batch
+
+ALTER TABLE broadcast_recipients ADD COLUMN claimed_at TIMESTAMP;
+
+CREATE INDEX idx_broadcast_recipients_sending ON broadcast_recipients(claimed_at) WHERE status = 'sending';
+
+COMMENT ON COLUMN broadcast_recipients.claimed_at IS 'When the bot claimed the recipient; sending rows with an older claim than the lease are queued again';
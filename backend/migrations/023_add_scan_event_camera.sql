@@ -0,0 +1,16 @@
+-- Migration: Scan event camera
+-- Date: 2026-06-15
+-- Gate camera scans name their camera, so that camera scans of plates without a pass or apartment still count for the camera's building
+
+ALTER TABLE scan_events ADD COLUMN camera_id BIGINT REFERENCES gate_cameras(id) ON DELETE SET NULL;
+
+UPDATE scan_events se
+SET camera_id = gc.id
+FROM gate_cameras gc
+WHERE se.guard_user_id IS NULL
+  AND se.meta ? 'camera'
+  AND gc.id = (se.meta->'camera'->>'id')::BIGINT;
+
+CREATE INDEX idx_scan_events_camera_id ON scan_events(camera_id) WHERE camera_id IS NOT NULL;
+
+COMMENT ON COLUMN scan_events.camera_id IS 'Gate camera that scanned, NULL for guard scans';
@@ -0,0 +1,8 @@
+-- Migration: Pass request start time
+-- Date: 2026-06-22
+-- A guest picks when the visit starts; requests without a start still start when approved
+
+ALTER TABLE pass_requests ADD COLUMN valid_from TIMESTAMP;
+
+COMMENT ON COLUMN pass_requests.valid_from IS 'Start the guest picked, NULL to start when approved';
+COMMENT ON COLUMN pass_requests.duration_minutes IS 'Requested validity from valid_from or, without it, from approval';
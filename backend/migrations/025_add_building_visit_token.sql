@@ -0,0 +1,7 @@
+-- Migration: Building visit token
+-- Date: 2026-06-23
+-- Guest visit links carry a random per-building token instead of the building ID
+
+ALTER TABLE buildings ADD COLUMN visit_token VARCHAR(32) UNIQUE;
+
+COMMENT ON COLUMN buildings.visit_token IS 'Random token of the guest visit link, NULL until an admin requests the link';
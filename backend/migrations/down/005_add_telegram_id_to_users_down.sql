@@ -0,0 +1,8 @@
+-- Rollback for 005_add_telegram_id_to_users.sql
+-- This script removes the Telegram link from users
+
+-- Drop the index
+DROP INDEX IF EXISTS idx_users_telegram_id;
+
+-- Remove the column
+ALTER TABLE users DROP COLUMN IF EXISTS telegram_id;
@@ -0,0 +1,11 @@
+-- Rollback for 006_allow_multiple_apartments_per_resident.sql
+-- This script makes telegram_id unique again in residents table
+
+-- Note: This will fail if a Telegram account is linked to several apartments
+-- Keep only the most recent row per telegram_id first:
+-- DELETE FROM residents r USING residents newer
+-- WHERE r.telegram_id = newer.telegram_id AND r.created_at < newer.created_at;
+
+ALTER TABLE residents DROP CONSTRAINT IF EXISTS residents_telegram_id_apartment_id_key;
+
+ALTER TABLE residents ADD CONSTRAINT residents_telegram_id_key UNIQUE (telegram_id);
@@ -0,0 +1,13 @@
+-- Rollback for 007_add_household_to_residents.sql
+-- This script removes household member fields from residents table
+
+-- Note: household members stay as regular residents of the apartment
+
+DROP INDEX IF EXISTS idx_residents_role;
+
+ALTER TABLE residents DROP CONSTRAINT IF EXISTS check_resident_role;
+
+ALTER TABLE residents
+DROP COLUMN IF EXISTS invited_by,
+DROP COLUMN IF EXISTS can_issue_passes,
+DROP COLUMN IF EXISTS role;
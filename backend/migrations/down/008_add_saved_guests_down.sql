@@ -0,0 +1,8 @@
+-- Rollback for 008_add_saved_guests.sql
+-- This script removes saved guests of residents
+
+DROP TRIGGER IF EXISTS update_saved_guests_updated_at ON saved_guests;
+
+DROP INDEX IF EXISTS idx_saved_guests_resident_id;
+
+DROP TABLE IF EXISTS saved_guests;
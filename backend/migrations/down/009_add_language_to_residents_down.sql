@@ -0,0 +1,6 @@
+-- Rollback for 009_add_language_to_residents.sql
+-- This script removes the bot language of residents
+
+ALTER TABLE residents DROP CONSTRAINT IF EXISTS check_resident_language;
+
+ALTER TABLE residents DROP COLUMN IF EXISTS language;
@@ -0,0 +1,11 @@
+-- Rollback for 010_add_broadcasts.sql
+-- This script removes broadcast announcements
+
+DROP TRIGGER IF EXISTS update_broadcast_recipients_updated_at ON broadcast_recipients;
+DROP TRIGGER IF EXISTS update_broadcasts_updated_at ON broadcasts;
+
+DROP INDEX IF EXISTS idx_broadcast_recipients_pending;
+DROP INDEX IF EXISTS idx_broadcasts_building_id;
+
+DROP TABLE IF EXISTS broadcast_recipients;
+DROP TABLE IF EXISTS broadcasts;
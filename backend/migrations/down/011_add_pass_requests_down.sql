@@ -0,0 +1,9 @@
+-- Rollback for 011_add_pass_requests.sql
+-- This script removes guest pass requests
+
+DROP TRIGGER IF EXISTS update_pass_requests_updated_at ON pass_requests;
+
+DROP INDEX IF EXISTS idx_pass_requests_apartment_id;
+DROP INDEX IF EXISTS idx_pass_requests_guest_telegram_id;
+
+DROP TABLE IF EXISTS pass_requests;
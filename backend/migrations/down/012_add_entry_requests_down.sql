@@ -0,0 +1,16 @@
+-- Rollback for 012_add_entry_requests.sql
+-- This script removes entry requests, single-use passes and pass-less scan events
+
+DROP INDEX IF EXISTS idx_scan_events_apartment_id;
+DELETE FROM scan_events WHERE pass_id IS NULL;
+ALTER TABLE scan_events DROP COLUMN IF EXISTS apartment_id;
+ALTER TABLE scan_events ALTER COLUMN pass_id SET NOT NULL;
+
+ALTER TABLE passes DROP COLUMN IF EXISTS single_use;
+
+DROP TRIGGER IF EXISTS update_entry_requests_updated_at ON entry_requests;
+
+DROP INDEX IF EXISTS idx_entry_requests_apartment_id;
+DROP INDEX IF EXISTS idx_entry_requests_unnotified;
+
+DROP TABLE IF EXISTS entry_requests;
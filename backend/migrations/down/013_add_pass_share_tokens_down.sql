@@ -0,0 +1,6 @@
+-- Rollback for 013_add_pass_share_tokens.sql
+-- This script removes shareable pass links
+
+DROP INDEX IF EXISTS idx_passes_share_token;
+
+ALTER TABLE passes DROP COLUMN IF EXISTS share_token;
@@ -0,0 +1,5 @@
+-- Rollback for 014_add_dynamic_pass_codes.sql
+-- This script removes rotating QR codes
+
+ALTER TABLE passes DROP COLUMN IF EXISTS qr_secret;
+ALTER TABLE passes DROP COLUMN IF EXISTS dynamic;
@@ -0,0 +1,12 @@
+-- Rollback for 015_add_events.sql
+-- This script removes events and their passes
+
+DELETE FROM passes WHERE event_id IS NOT NULL;
+DROP INDEX IF EXISTS idx_passes_event_id;
+ALTER TABLE passes DROP COLUMN IF EXISTS event_id;
+
+DROP TRIGGER IF EXISTS update_events_updated_at ON events;
+
+DROP INDEX IF EXISTS idx_events_building_id;
+
+DROP TABLE IF EXISTS events;
@@ -0,0 +1,15 @@
+-- Rollback for 016_add_contractor_passes.sql
+-- This script removes contractor passes
+
+DELETE FROM passes WHERE category = 'contractor';
+
+ALTER TABLE rules DROP COLUMN IF EXISTS max_contractor_pass_days;
+
+DROP INDEX IF EXISTS idx_passes_category;
+
+ALTER TABLE passes DROP COLUMN IF EXISTS issued_by;
+ALTER TABLE passes DROP COLUMN IF EXISTS daily_to;
+ALTER TABLE passes DROP COLUMN IF EXISTS daily_from;
+ALTER TABLE passes DROP COLUMN IF EXISTS weekdays;
+ALTER TABLE passes DROP COLUMN IF EXISTS company_name;
+ALTER TABLE passes DROP COLUMN IF EXISTS category;
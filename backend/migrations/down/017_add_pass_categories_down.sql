@@ -0,0 +1,16 @@
+-- Rollback for 017_add_pass_categories.sql
+-- This script removes pass categories other than guest and contractor
+
+DROP INDEX IF EXISTS idx_passes_apartment_category;
+
+DROP TRIGGER IF EXISTS update_category_rules_updated_at ON category_rules;
+
+DROP TABLE IF EXISTS category_rules;
+
+UPDATE passes SET category = 'guest' WHERE category IN ('taxi', 'delivery');
+UPDATE passes SET category = 'contractor' WHERE category = 'service';
+
+ALTER TABLE passes DROP CONSTRAINT IF EXISTS check_pass_category;
+ALTER TABLE passes ADD CONSTRAINT passes_category_check CHECK (category IN ('guest', 'contractor'));
+
+COMMENT ON COLUMN passes.category IS 'guest for passes issued by residents, contractor for admin-issued passes of crews and service companies';
@@ -0,0 +1,10 @@
+-- Rollback for 018_add_pass_exceptions.sql
+-- This script removes exception requests; passes issued by approved ones stay
+
+DROP TRIGGER IF EXISTS update_pass_exceptions_updated_at ON pass_exceptions;
+
+DROP INDEX IF EXISTS idx_pass_exceptions_undelivered;
+DROP INDEX IF EXISTS idx_pass_exceptions_resident_id;
+DROP INDEX IF EXISTS idx_pass_exceptions_apartment_id;
+
+DROP TABLE IF EXISTS pass_exceptions;
@@ -0,0 +1,8 @@
+-- Rollback for 019_add_resident_vehicles.sql
+-- This script removes the registered cars of residents
+
+DROP TRIGGER IF EXISTS update_resident_vehicles_updated_at ON resident_vehicles;
+
+DROP INDEX IF EXISTS idx_resident_vehicles_car_plate;
+
+DROP TABLE IF EXISTS resident_vehicles;
@@ -0,0 +1,13 @@
+-- Rollback for 020_add_plate_watchlist.sql
+-- This script removes the plate watchlist and its alerts
+
+DROP INDEX IF EXISTS idx_plate_alerts_unnotified;
+DROP INDEX IF EXISTS idx_plate_alerts_watchlist_id;
+
+DROP TABLE IF EXISTS plate_alerts;
+
+DROP TRIGGER IF EXISTS update_plate_watchlist_updated_at ON plate_watchlist;
+
+DROP INDEX IF EXISTS idx_plate_watchlist_car_plate;
+
+DROP TABLE IF EXISTS plate_watchlist;
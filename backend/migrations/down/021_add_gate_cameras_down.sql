@@ -0,0 +1,11 @@
+-- Rollback for 021_add_gate_cameras.sql
+-- This script removes gate cameras and their scans
+
+ALTER TABLE plate_alerts DROP COLUMN IF EXISTS camera_id;
+
+DELETE FROM scan_events WHERE guard_user_id IS NULL;
+ALTER TABLE scan_events ALTER COLUMN guard_user_id SET NOT NULL;
+
+DROP INDEX IF EXISTS idx_gate_cameras_building_id;
+
+DROP TABLE IF EXISTS gate_cameras;
@@ -0,0 +1,6 @@
+-- Rollback for 022_add_broadcast_claim_lease.sql
+-- This script removes the claim lease of broadcast recipients
+
+DROP INDEX IF EXISTS idx_broadcast_recipients_sending;
+
+ALTER TABLE broadcast_recipients DROP COLUMN IF EXISTS claimed_at;
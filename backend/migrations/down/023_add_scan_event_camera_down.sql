@@ -0,0 +1,6 @@
+-- Rollback for 023_add_scan_event_camera.sql
+-- This script removes the camera of scan events; it stays in their meta
+
+DROP INDEX IF EXISTS idx_scan_events_camera_id;
+
+ALTER TABLE scan_events DROP COLUMN IF EXISTS camera_id;
@@ -0,0 +1,6 @@
+-- Rollback for 024_add_pass_request_valid_from.sql
+-- This script removes the start time of guest pass requests
+
+ALTER TABLE pass_requests DROP COLUMN IF EXISTS valid_from;
+
+COMMENT ON COLUMN pass_requests.duration_minutes IS 'Requested validity; the pass starts when approved';
@@ -0,0 +1,4 @@
+-- Rollback for 025_add_building_visit_token.sql
+-- This script removes the visit token of buildings
+
+ALTER TABLE buildings DROP COLUMN IF EXISTS visit_token;
@@ -33,7 +33,7 @@ export function SecurityPage() {
   const inputRef = useRef<HTMLInputElement>(null);
 
   const validateMutation = useMutation({
-    mutationFn: (params: { qr_uuid?: string; car_plate?: string }) => passesApi.validate(params),
+    mutationFn: (params: { qr_data?: string; car_plate?: string }) => passesApi.validate(params),
     onSuccess: (data) => {
       setValidationResult(data);
       setErrorMsg('');
@@ -87,7 +87,7 @@ export function SecurityPage() {
       // Clear previous error when starting new scan
       setErrorMsg('');
       setValidationResult(null);
-      validateMutation.mutate({ qr_uuid: qrInput.trim() });
+      validateMutation.mutate({ qr_data: qrInput.trim() });
     }
   };
 
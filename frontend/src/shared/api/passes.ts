@@ -8,7 +8,7 @@ import type {
 } from '@/shared/types/api';
 
 export const passesApi = {
-  validate: async (params: { qr_uuid?: string; car_plate?: string }): Promise<ValidatePassResponse> => {
+  validate: async (params: { qr_data?: string; qr_uuid?: string; car_plate?: string }): Promise<ValidatePassResponse> => {
     const response = await apiClient.post<ValidatePassResponse>(
       API_ENDPOINTS.VALIDATE_PASS,
       params as ValidatePassRequest
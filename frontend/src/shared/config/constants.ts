@@ -22,6 +22,7 @@ export const API_ENDPOINTS = {
   REVOKE_PASS: (id: string) => `/api/v1/passes/${id}/revoke`,
   VALIDATE_PASS: '/api/v1/passes/validate',
   ACTIVE_PASSES: '/api/v1/passes/active',
+  CONTRACTOR_PASSES: '/api/v1/passes/contractor',
   
   // Rules
   RULES: '/api/v1/rules',
@@ -31,6 +32,32 @@ export const API_ENDPOINTS = {
   RESIDENTS_BULK: '/api/v1/residents/bulk',
   RESIDENTS_IMPORT: '/api/v1/residents/import',
   
+  // Residents' own cars
+  RESIDENT_VEHICLES: '/api/v1/resident-vehicles',
+  RESIDENT_VEHICLE_BY_ID: (id: number) => `/api/v1/resident-vehicles/${id}`,
+  
+  // Plate watchlist
+  WATCHLIST: '/api/v1/watchlist',
+  WATCHLIST_ENTRY_BY_ID: (id: number) => `/api/v1/watchlist/${id}`,
+  
+  // Gate cameras
+  CAMERAS: '/api/v1/cameras',
+  CAMERA_BY_ID: (id: number) => `/api/v1/cameras/${id}`,
+  
+  // Events with a guest list
+  EVENTS: '/api/v1/events',
+  EVENT_BY_ID: (id: number) => `/api/v1/events/${id}`,
+  EVENT_PDF: (id: number) => `/api/v1/events/${id}/pdf`,
+  EVENT_QR: (id: number) => `/api/v1/events/${id}/qr`,
+  REVOKE_EVENT: (id: number) => `/api/v1/events/${id}/revoke`,
+  EVENT_ATTENDANCE: (id: number) => `/api/v1/events/${id}/attendance`,
+  
+  // Pass exceptions
+  PASS_EXCEPTIONS: '/api/v1/pass-exceptions',
+  PASS_EXCEPTION_BY_ID: (id: number) => `/api/v1/pass-exceptions/${id}`,
+  APPROVE_PASS_EXCEPTION: (id: number) => `/api/v1/pass-exceptions/${id}/approve`,
+  REJECT_PASS_EXCEPTION: (id: number) => `/api/v1/pass-exceptions/${id}/reject`,
+  
   // Scan Events & Reports
   SCAN_EVENTS: '/api/v1/scan-events',
   STATISTICS: '/api/v1/reports/statistics',
@@ -45,7 +72,12 @@ export const ERROR_MESSAGES: Record<string, string> = {
   PASS_EXPIRED: 'Срок действия пропуска истек',
   PASS_REVOKED: 'Пропуск отозван',
   PASS_NOT_YET_VALID: 'Пропуск еще не действителен',
+  PASS_USED: 'Пропуск уже использован',
+  DYNAMIC_QR_REQUIRED: 'Пропуск принимается только по динамическому QR',
+  QR_CODE_EXPIRED: 'QR код устарел, попросите гостя обновить его',
+  QR_CODE_INVALID: 'QR код недействителен',
   QUIET_HOURS: 'Действие запрещено в тихие часы',
+  OUTSIDE_ALLOWED_HOURS: 'Пропуск не действует в этот день или час',
   RATE_LIMIT_EXCEEDED: 'Превышен лимит запросов',
   INVALID_CREDENTIALS: 'Неверные учетные данные',
   INVALID_TOKEN: 'Неверный или истекший токен',
@@ -61,6 +93,27 @@ export const ERROR_MESSAGES: Record<string, string> = {
   // Rules
   RULE_NOT_FOUND: 'Правила не найдены',
   MISSING_BUILDING_ID: 'Не указан ID здания',
+  INVALID_CATEGORY_RULE: 'Некорректные ограничения категории',
+  // Events
+  EVENT_NOT_FOUND: 'Мероприятие не найдено',
+  EVENT_REVOKED: 'Мероприятие уже отменено',
+  INVALID_GUEST_LIST: 'Ошибка в списке гостей',
+  // Pass exceptions
+  PASS_EXCEPTION_NOT_FOUND: 'Заявка не найдена',
+  PASS_EXCEPTION_DECIDED: 'По заявке уже принято решение',
+  // Resident vehicles
+  VEHICLE_EXISTS: 'Автомобиль с таким номером уже зарегистрирован',
+  VEHICLE_NOT_FOUND: 'Автомобиль не найден',
+  // Car plates
+  INVALID_CAR_PLATE: 'Номер не распознан. Введите его полностью, с регионом',
+  // Plate watchlist
+  BLACKLISTED: 'Автомобиль в чёрном списке, проезд запрещён',
+  CAR_PLATE_BANNED: 'Номер в чёрном списке, пропуск не выдаётся',
+  WATCHLIST_ENTRY_EXISTS: 'Номер уже в списке',
+  WATCHLIST_ENTRY_NOT_FOUND: 'Запись не найдена',
+  // Gate cameras
+  CAMERA_EXISTS: 'Камера с таким названием уже есть в здании',
+  CAMERA_NOT_FOUND: 'Камера не найдена',
 };
 
 export const STORAGE_KEYS = {
@@ -27,6 +27,8 @@ export interface Apartment {
   updated_at: string;
 }
 
+export type PassCategory = 'guest' | 'taxi' | 'delivery' | 'contractor' | 'service';
+
 export interface Pass {
   id: string; // UUID
   apartment_id: number;
@@ -34,15 +36,23 @@ export interface Pass {
   guest_name?: string;
   valid_from: string; // ISO datetime
   valid_to: string; // ISO datetime
-  status: 'active' | 'revoked' | 'expired';
+  status: 'active' | 'revoked' | 'expired' | 'used';
+  single_use?: boolean;
+  dynamic?: boolean;
+  category: PassCategory;
+  company_name?: string;
+  weekdays?: number[]; // ISO, 1 = Monday
+  daily_from?: string; // HH:mm format
+  daily_to?: string; // HH:mm format
+  issued_by?: number;
   created_at: string;
   updated_at: string;
 }
 
 export interface ScanEvent {
   id: number;
-  pass_id: string; // UUID
-  guard_user_id: number;
+  pass_id?: string; // UUID, absent when a resident was called without a pass
+  guard_user_id?: number; // Absent for gate camera scans
   scanned_at: string; // ISO datetime
   result: 'valid' | 'invalid';
   reason?: string;
@@ -56,6 +66,8 @@ export interface Rule {
   quiet_hours_end?: string; // HH:mm format
   daily_pass_limit_per_apartment: number;
   max_pass_duration_hours: number;
+  max_contractor_pass_days: number;
+  categories: CategoryRule[];
   created_at: string;
   updated_at: string;
 }
@@ -72,6 +84,106 @@ export interface Resident {
   updated_at: string;
 }
 
+export interface Event {
+  id: number;
+  building_id: number;
+  apartment_id: number;
+  name: string;
+  valid_from: string; // ISO datetime
+  valid_to: string; // ISO datetime
+  capacity: number;
+  status: 'active' | 'revoked';
+  created_by?: number;
+  resident_id?: number;
+  pass_count: number;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface EventGuestAttendance {
+  pass_id: string;
+  car_plate?: string;
+  guest_name?: string;
+  pass_status: string;
+  entries: number;
+  first_entry_at?: string; // ISO datetime
+}
+
+export interface EventAttendance {
+  event: Event;
+  attended: number;
+  guests: EventGuestAttendance[];
+}
+
+export interface ResidentVehicle {
+  id: number;
+  apartment_id: number;
+  building_id: number;
+  car_plate: string;
+  label?: string;
+  resident_id?: number; // Added by a resident in the bot
+  created_by?: number; // Added by an admin
+  created_at: string;
+  updated_at: string;
+}
+
+export interface WatchlistEntry {
+  id: number;
+  building_id: number;
+  car_plate: string;
+  reason: string;
+  severity: 'ban' | 'watch'; // ban: no passes and no entry; watch: alert only
+  created_by?: number;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface GateCamera {
+  id: number;
+  building_id: number;
+  gate_id: string;
+  name: string;
+  min_confidence?: number; // Defaults to the server's ANPR_MIN_CONFIDENCE
+  last_seen_at?: string;
+  created_by?: number;
+  created_at: string;
+}
+
+export interface CreateGateCameraResponse {
+  camera: GateCamera;
+  api_key: string; // Shown once, only its hash is stored
+}
+
+export interface PlateSuggestion {
+  pass_id: string;
+  car_plate: string;
+  apartment?: string;
+  guest_name?: string;
+  valid_to: string; // ISO datetime
+  distance: number; // Typos; a lookalike character (0/O, 8/B) counts 0.5
+}
+
+export interface PassException {
+  id: number;
+  apartment_id: number;
+  building_id: number;
+  resident_id: number;
+  car_plate?: string;
+  guest_name?: string;
+  category: PassCategory;
+  valid_from: string; // ISO datetime
+  valid_to: string; // ISO datetime
+  rule: 'max_duration' | 'daily_limit';
+  reason: string;
+  status: 'pending' | 'approved' | 'rejected';
+  decided_by?: number;
+  decided_at?: string;
+  comment?: string;
+  pass_id?: string;
+  created_at: string;
+  updated_at: string;
+}
+
 // API Request/Response DTOs
 
 export interface LoginRequest {
@@ -102,9 +214,12 @@ export interface CreatePassRequest {
   guest_name?: string;
   valid_from?: string; // ISO datetime
   valid_to: string; // ISO datetime
+  dynamic?: boolean; // Rotating QR code that changes every 30 seconds
+  category?: 'guest' | 'taxi' | 'delivery'; // Defaults to guest
 }
 
 export interface ValidatePassRequest {
+  qr_data?: string; // Scanned QR text, static or dynamic
   qr_uuid?: string; // UUID from QR code (optional if car_plate is provided)
   car_plate?: string; // Car plate number (optional if qr_uuid is provided)
 }
@@ -114,7 +229,10 @@ export interface ValidatePassResponse {
   car_plate?: string;
   apartment?: string;
   valid_to?: string; // ISO datetime
-  reason?: 'PASS_NOT_FOUND' | 'PASS_EXPIRED' | 'PASS_REVOKED' | 'PASS_NOT_YET_VALID' | 'QUIET_HOURS';
+  vehicle?: ResidentVehicle; // Set with reason RESIDENT_VEHICLE
+  alert?: WatchlistEntry; // Set when the plate is on the building's watchlist
+  suggestions?: PlateSuggestion[]; // Similar plates with active passes, set with reason PASS_NOT_FOUND
+  reason?: 'RESIDENT_VEHICLE' | 'BLACKLISTED' | 'PASS_NOT_FOUND' | 'PASS_EXPIRED' | 'PASS_REVOKED' | 'PASS_NOT_YET_VALID' | 'PASS_USED' | 'QUIET_HOURS' | 'OUTSIDE_ALLOWED_HOURS' | 'DYNAMIC_QR_REQUIRED' | 'QR_CODE_EXPIRED' | 'QR_CODE_INVALID';
 }
 
 export interface GetActivePassesResponse {
@@ -126,6 +244,34 @@ export interface UpdateRuleRequest {
   quiet_hours_end?: string; // HH:mm
   daily_pass_limit_per_apartment?: number;
   max_pass_duration_hours?: number;
+  max_contractor_pass_days?: number;
+  categories?: CategoryRule[]; // Replaces all category limits of the building
+}
+
+export interface CategoryRule {
+  category: PassCategory;
+  max_duration_hours?: number;
+  daily_limit?: number;
+  allowed_from?: string; // HH:mm format
+  allowed_to?: string; // HH:mm format
+}
+
+export interface CreateContractorPassRequest {
+  apartment_id: number;
+  category?: 'contractor' | 'service'; // Defaults to contractor
+  company_name: string;
+  car_plate?: string;
+  guest_name?: string;
+  valid_from?: string; // ISO datetime
+  valid_to: string; // ISO datetime
+  weekdays?: number[]; // ISO, 1 = Monday
+  daily_from?: string; // HH:mm
+  daily_to?: string; // HH:mm
+}
+
+export interface ListContractorPassesResponse {
+  passes: Pass[];
+  count: number;
 }
 
 export interface CreateResidentRequest {
@@ -153,6 +299,16 @@ export interface RevokePassResponse {
   pass_id: string;
 }
 
+export interface GetEventsResponse {
+  events: Event[];
+  count: number;
+}
+
+export interface RevokeEventResponse {
+  message: string;
+  revoked_passes: number;
+}
+
 // Error response from backend
 
 export interface ErrorResponse {
@@ -185,6 +341,7 @@ export interface ScanEventWithDetails {
   Result: 'valid' | 'invalid';
   Reason?: string;
   CarPlate?: string;
+  PassCategory?: PassCategory;
   GuestName?: string;
   ApartmentNumber?: string;
   BuildingID?: number;
@@ -213,6 +370,7 @@ export interface Statistics {
   valid_scans: number;
   invalid_scans: number;
   unique_passes: number;
+  contractor_scans: number;
   top_reasons?: Array<{
     reason: string;
     count: number;